@@ -8,7 +8,10 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
+	"github.com/bsisduck/octo/internal/config"
 	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/logsize"
+	"github.com/bsisduck/octo/internal/ports"
 	"github.com/bsisduck/octo/internal/tui/analyze"
 	"github.com/bsisduck/octo/internal/ui/format"
 )
@@ -19,6 +22,8 @@ type AnalyzeOutput struct {
 	Images     []docker.ImageInfo     `json:"images" yaml:"images"`
 	Volumes    []docker.VolumeInfo    `json:"volumes" yaml:"volumes"`
 	Networks   []docker.NetworkInfo   `json:"networks" yaml:"networks"`
+	Ports      []ports.Entry          `json:"ports" yaml:"ports"`
+	Logs       []logsize.Usage        `json:"logs" yaml:"logs"`
 	DiskUsage  *docker.DiskUsageInfo  `json:"diskUsage" yaml:"diskUsage"`
 }
 
@@ -26,15 +31,18 @@ var analyzeCmd = &cobra.Command{
 	Use:   "analyze",
 	Short: "Analyze Docker resource usage",
 	Long: `Analyze Docker resources with an interactive tree view:
-- Explore containers, images, volumes, and networks
+- Explore containers, images, volumes, networks, published ports and
+  container log sizes
 - View size breakdown and usage patterns
 - Identify large or unused resources
-- Navigate with arrow keys, delete with 'd'`,
+- Navigate with arrow keys, delete with 'd' (truncates in the logs view)
+- Sort with 'o' (reverse with 'O') and pick columns with 'c'; both are
+  saved in the analyze section of the config file`,
 	RunE: runAnalyze,
 }
 
 func init() {
-	analyzeCmd.Flags().StringP("type", "t", "", "Filter by type: containers, images, volumes, networks")
+	analyzeCmd.Flags().StringP("type", "t", "", "Filter by type: containers, images, volumes, networks, ports, logs")
 	analyzeCmd.Flags().BoolP("dangling", "d", false, "Show only dangling/unused resources")
 }
 
@@ -54,10 +62,19 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 		return runAnalyzeCLI(client, outputFormat, typeFilter, dangling)
 	}
 
+	cfg, err := loadTUIConfig()
+	if err != nil {
+		return err
+	}
+
 	// TUI path (existing behavior)
 	model := analyze.New(client, analyze.Options{
 		TypeFilter: typeFilter,
 		Dangling:   dangling,
+		View:       cfg.Analyze,
+		Persist: func(view config.AnalyzeConfig) error {
+			return config.SaveAnalyze(configPath, view)
+		},
 	})
 
 	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
@@ -116,6 +133,29 @@ func runAnalyzeCLI(client docker.DockerService, outputFormat, typeFilter string,
 		output.Networks = networks
 	}
 
+	if typeFilter == "" || typeFilter == "ports" || typeFilter == "port" || typeFilter == "p" {
+		containers, err := client.ListContainers(ctx, true)
+		if err != nil {
+			return fmt.Errorf("listing containers: %w", err)
+		}
+		if containers, err = ports.WithConfigured(ctx, client, containers); err != nil {
+			return err
+		}
+		listeners, _ := ports.LocalListeners()
+		output.Ports = ports.Map(containers, listeners)
+	}
+
+	if typeFilter == "" || typeFilter == "logs" || typeFilter == "log" {
+		usages, err := logsize.List(ctx, client)
+		if err != nil {
+			return fmt.Errorf("measuring container logs: %w", err)
+		}
+		if dangling {
+			usages = logsize.Unbounded(usages)
+		}
+		output.Logs = usages
+	}
+
 	if typeFilter == "" {
 		diskUsage, err := client.GetDiskUsage(ctx)
 		if err != nil {
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/spf13/cobra"
+
+	"github.com/bsisduck/octo/internal/audit"
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/ui/format"
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit containers for risky security settings",
+	Long: `Inspect running containers for risky settings:
+- Privileged mode
+- Host network or PID namespace
+- Docker socket bind-mounted
+- Running as root
+- Added capabilities
+- Writable root filesystem
+- No memory limit
+- Ports bound to all interfaces (0.0.0.0)
+
+Findings are ranked by severity (critical, high, medium, low). Use --sarif
+to write a SARIF 2.1.0 report for code scanning dashboards. Results point
+at the container's Compose file, relative to the current directory when it
+is under it (run from the repository root in CI), or at container/<name>:
+
+  octo audit
+  octo audit --min-severity high
+  octo audit --sarif octo-audit.sarif
+  octo audit --output-format json`,
+	RunE: runAudit,
+}
+
+func init() {
+	auditCmd.Flags().BoolP("all", "a", false, "Include stopped containers")
+	auditCmd.Flags().String("min-severity", "low", "Only report findings at or above: low, medium, high, critical")
+	auditCmd.Flags().String("sarif", "", "Write a SARIF report to this file ('-' for stdout)")
+}
+
+// AuditOutput holds structured audit data for JSON/YAML output
+type AuditOutput struct {
+	Containers int             `json:"containers" yaml:"containers"`
+	Findings   []audit.Finding `json:"findings" yaml:"findings"`
+	Summary    audit.Summary   `json:"summary" yaml:"summary"`
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	minName, _ := cmd.Flags().GetString("min-severity")
+	sarifPath, _ := cmd.Flags().GetString("sarif")
+	outputFormat, _ := cmd.Flags().GetString("output-format")
+
+	minSeverity, err := audit.ParseSeverity(minName)
+	if err != nil {
+		return err
+	}
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("connecting to Docker: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	containers, err := inspectContainers(context.Background(), client, all)
+	if err != nil {
+		return err
+	}
+
+	rules := audit.Rules()
+	findings := audit.Run(containers, rules, minSeverity)
+
+	if sarifPath != "" {
+		if err := writeAuditSARIF(sarifPath, findings, rules); err != nil {
+			return err
+		}
+		if sarifPath == "-" {
+			return nil
+		}
+	}
+
+	output := AuditOutput{
+		Containers: len(containers),
+		Findings:   findings,
+		Summary:    audit.Summarize(findings),
+	}
+	switch outputFormat {
+	case "json":
+		return format.FormatJSON(os.Stdout, output)
+	case "yaml":
+		return format.FormatYAML(os.Stdout, output)
+	}
+
+	printAudit(output)
+	if sarifPath != "" {
+		fmt.Println(styles.Info.Render("SARIF report written to " + sarifPath))
+	}
+	return nil
+}
+
+// inspectContainers lists containers (running only unless all) and returns
+// their inspect data. Containers removed in between are skipped.
+func inspectContainers(ctx context.Context, client docker.DockerService, all bool) ([]types.ContainerJSON, error) {
+	listCtx, cancel := context.WithTimeout(ctx, docker.TimeoutList)
+	list, err := client.ListContainers(listCtx, all)
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("listing containers: %w", err)
+	}
+
+	containers := make([]types.ContainerJSON, 0, len(list))
+	for _, c := range list {
+		inspectCtx, cancel := context.WithTimeout(ctx, docker.TimeoutList)
+		details, err := client.InspectContainer(inspectCtx, c.ID)
+		cancel()
+		if errdefs.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("inspecting %s: %w", c.Name, err)
+		}
+		containers = append(containers, details)
+	}
+	return containers, nil
+}
+
+func writeAuditSARIF(path string, findings []audit.Finding, rules []audit.Rule) error {
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("creating SARIF report: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	if err := audit.WriteSARIF(w, findings, rules, Version); err != nil {
+		return fmt.Errorf("writing SARIF report: %w", err)
+	}
+	return nil
+}
+
+func severityStyle(s audit.Severity) string {
+	label := fmt.Sprintf("%-8s", strings.ToUpper(s.String()))
+	switch s {
+	case audit.SeverityCritical, audit.SeverityHigh:
+		return styles.Error.Render(label)
+	case audit.SeverityMedium:
+		return styles.Warning.Render(label)
+	}
+	return styles.Info.Render(label)
+}
+
+func printAudit(output AuditOutput) {
+	fmt.Println()
+	fmt.Println(styles.Title.Render("🐙 Octo Security Audit"))
+	fmt.Println(strings.Repeat("─", 50))
+
+	// Findings are sorted worst first, so containers appear in order of
+	// their most severe finding.
+	var order []string
+	byContainer := make(map[string][]audit.Finding)
+	for _, f := range output.Findings {
+		if _, seen := byContainer[f.Container]; !seen {
+			order = append(order, f.Container)
+		}
+		byContainer[f.Container] = append(byContainer[f.Container], f)
+	}
+
+	for _, name := range order {
+		findings := byContainer[name]
+		fmt.Println()
+		fmt.Printf("%s %s\n", styles.Title.Render(name), styles.Help.Render(shortID(findings[0].ContainerID)))
+		for _, f := range findings {
+			fmt.Printf("  %s %s: %s\n", severityStyle(f.Severity), f.Rule, f.Message)
+			fmt.Printf("  %8s %s\n", "", styles.Info.Render("→ "+f.Remediation))
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(strings.Repeat("─", 50))
+	if len(output.Findings) == 0 {
+		fmt.Println(styles.Success.Render(fmt.Sprintf("✓ No findings in %d containers", output.Containers)))
+		fmt.Println()
+		return
+	}
+	s := output.Summary
+	fmt.Printf("%d findings in %d of %d containers: %d critical, %d high, %d medium, %d low\n",
+		len(output.Findings), len(order), output.Containers, s.Critical, s.High, s.Medium, s.Low)
+	fmt.Println()
+}
+
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
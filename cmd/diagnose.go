@@ -3,15 +3,17 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
-	"runtime"
 	"strings"
 	"time"
 
-	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 
+	"github.com/bsisduck/octo/internal/config"
+	"github.com/bsisduck/octo/internal/diagnose"
 	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/hostdisk"
 	"github.com/bsisduck/octo/internal/ui/format"
 	"github.com/bsisduck/octo/internal/ui/styles"
 )
@@ -24,430 +26,295 @@ var diagnoseCmd = &cobra.Command{
 - Daemon configuration
 - Resource usage
 - Potential issues
-- Performance recommendations`,
+- Performance recommendations
+
+Checks are selected by ID or category:
+
+  octo diagnose --list
+  octo diagnose --check storage,disk
+  octo diagnose --skip api-response
+
+With --fix, checks that failed and have an automatic fix (pruning dangling
+images, restarting unhealthy containers, ...) are offered as a checklist.
+Selected fixes are applied and the checks re-run to show before/after
+status. --dry-run shows the fixes without applying them; --force applies
+all of them without prompting.
+
+For CI pipelines, --fail-on makes the command exit non-zero when any check
+reaches the given status, and --junit writes a JUnit XML report with one
+test case per check ('-' writes it to stdout instead of the normal output):
+
+  octo diagnose --fail-on warn --junit diagnose.xml
+
+Custom checks can be added under diagnose.checks in ~/.octo/config.yaml,
+either as a shell command that must exit 0 or as an expectation on a
+daemon info field:
+
+  diagnose:
+    checks:
+      - id: registry-mirror
+        name: Registry mirror reachable
+        command: curl -sf https://mirror.internal/v2/
+        remediation: Check the mirror service
+      - id: cgroup-v2
+        field: CgroupVersion
+        equals: "2"
+        severity: error`,
 	RunE: runDiagnose,
 }
 
 func init() {
 	diagnoseCmd.Flags().Bool("verbose", false, "Show detailed diagnostic information")
+	diagnoseCmd.Flags().StringSlice("check", nil, "Run only these checks or categories (comma-separated)")
+	diagnoseCmd.Flags().StringSlice("skip", nil, "Skip these checks or categories (comma-separated)")
+	diagnoseCmd.Flags().Bool("list", false, "List available checks and exit")
+	diagnoseCmd.Flags().Bool("fix", false, "Offer and apply automatic fixes for failed checks")
+	diagnoseCmd.Flags().BoolP("force", "f", false, "With --fix, apply all fixes without prompting")
+	diagnoseCmd.Flags().String("fail-on", "", "Exit non-zero when a check reports this status or worse: warn, error")
+	diagnoseCmd.Flags().String("junit", "", "Write a JUnit XML report to this file ('-' for stdout)")
 }
 
-type DiagnosticResult struct {
-	Name    string `json:"name" yaml:"name"`
-	Status  string `json:"status" yaml:"status"`
-	Message string `json:"message" yaml:"message"`
-	Details string `json:"details,omitempty" yaml:"details,omitempty"`
-}
+// DiagnosticResult is the outcome of one diagnostic check.
+type DiagnosticResult = diagnose.Result
 
 // DiagnoseOutput holds structured diagnostic data for JSON/YAML output
 type DiagnoseOutput struct {
-	Results []DiagnosticResult `json:"results" yaml:"results"`
-	Summary DiagnoseSummary    `json:"summary" yaml:"summary"`
+	Results      []DiagnosticResult `json:"results" yaml:"results"`
+	Summary      DiagnoseSummary    `json:"summary" yaml:"summary"`
+	Fixes        []DiagnoseFix      `json:"fixes,omitempty" yaml:"fixes,omitempty"`
+	After        []DiagnosticResult `json:"after,omitempty" yaml:"after,omitempty"`
+	AfterSummary *DiagnoseSummary   `json:"after_summary,omitempty" yaml:"after_summary,omitempty"`
 }
 
 // DiagnoseSummary holds pass/warn/error counts
-type DiagnoseSummary struct {
-	Passed   int `json:"passed" yaml:"passed"`
-	Warnings int `json:"warnings" yaml:"warnings"`
-	Errors   int `json:"errors" yaml:"errors"`
+type DiagnoseSummary = diagnose.Summary
+
+// DiagnoseCheckInfo describes a registered check for --list output
+type DiagnoseCheckInfo struct {
+	ID          string `json:"id" yaml:"id"`
+	Name        string `json:"name" yaml:"name"`
+	Category    string `json:"category" yaml:"category"`
+	Severity    string `json:"severity" yaml:"severity"`
+	Remediation string `json:"remediation,omitempty" yaml:"remediation,omitempty"`
+	Fixable     bool   `json:"fixable" yaml:"fixable"`
+}
+
+// diagnoseRegistry builds the registry of built-in checks plus the custom
+// checks defined in the config file.
+func diagnoseRegistry(cfg *config.Config) (*diagnose.Registry, error) {
+	registry := diagnose.NewRegistry(diagnose.Builtin()...)
+
+	custom, err := diagnose.CustomChecks(cfg.Diagnose.Checks)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	for _, c := range custom {
+		if err := registry.Register(c); err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+	}
+	return registry, nil
 }
 
 func runDiagnose(cmd *cobra.Command, args []string) error {
 	verbose, _ := cmd.Flags().GetBool("verbose")
 	outputFormat, _ := cmd.Flags().GetString("output-format")
-	textMode := outputFormat != "json" && outputFormat != "yaml"
-
-	// Styles (defined in internal/ui/styles/theme.go)
-	titleStyle := styles.Title
-	okStyle := styles.Success
-	warnStyle := styles.Warning
-	errorStyle := styles.Error
-	infoStyle := styles.Info
-
-	if textMode {
-		fmt.Println()
-		fmt.Println(titleStyle.Render("🐙 Octo Docker Diagnostics"))
-		fmt.Println(strings.Repeat("─", 50))
-		fmt.Println()
+	include, _ := cmd.Flags().GetStringSlice("check")
+	exclude, _ := cmd.Flags().GetStringSlice("skip")
+	list, _ := cmd.Flags().GetBool("list")
+	fix, _ := cmd.Flags().GetBool("fix")
+	force, _ := cmd.Flags().GetBool("force")
+	failOnName, _ := cmd.Flags().GetString("fail-on")
+	junitPath, _ := cmd.Flags().GetString("junit")
+	textMode := outputFormat != "json" && outputFormat != "yaml" && junitPath != "-"
+
+	// Without --fail-on the JUnit report still marks errors as failures.
+	threshold := diagnose.StatusError
+	if failOnName != "" {
+		var err error
+		if threshold, err = diagnose.ParseThreshold(failOnName); err != nil {
+			return fmt.Errorf("--fail-on: %w", err)
+		}
 	}
 
-	results := []DiagnosticResult{}
-	ctx := context.Background()
+	if fix && !textMode && !force && !IsDryRun() {
+		return fmt.Errorf("--fix without text output cannot prompt; add --force or --dry-run")
+	}
 
-	// Check 1: Docker connection
-	if textMode {
-		fmt.Print("Checking Docker connection... ")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
 	}
-	client, err := docker.NewClient()
+	registry, err := diagnoseRegistry(cfg)
 	if err != nil {
-		if textMode {
-			fmt.Println(errorStyle.Render("FAILED"))
-		}
-		results = append(results, DiagnosticResult{
-			Name:    "Docker Connection",
-			Status:  "error",
-			Message: "Cannot connect to Docker daemon",
-			Details: err.Error(),
-		})
-		if !textMode {
-			return outputDiagnoseStructured(outputFormat, results)
-		}
-		printDiagnosticSummary(results, verbose)
-		return fmt.Errorf("docker connection failed: %w", err)
+		return err
 	}
-	if textMode {
-		fmt.Println(okStyle.Render("OK"))
+	if list {
+		return listDiagnosticChecks(registry, outputFormat)
 	}
-	results = append(results, DiagnosticResult{
-		Name:    "Docker Connection",
-		Status:  "ok",
-		Message: "Connected to Docker daemon",
-	})
-	defer func() { _ = client.Close() }()
-
-	// Check 2: Docker version
-	if textMode {
-		fmt.Print("Checking Docker version... ")
-	}
-	info, err := client.GetServerInfo(ctx)
+	checks, err := registry.Select(include, exclude)
 	if err != nil {
-		if textMode {
-			fmt.Println(errorStyle.Render("FAILED"))
-		}
-		results = append(results, DiagnosticResult{
-			Name:    "Docker Version",
-			Status:  "error",
-			Message: "Cannot get Docker version",
-			Details: err.Error(),
-		})
-	} else {
-		if textMode {
-			fmt.Println(okStyle.Render(info.ServerVersion))
-		}
-		results = append(results, DiagnosticResult{
-			Name:    "Docker Version",
-			Status:  "ok",
-			Message: fmt.Sprintf("Docker %s", info.ServerVersion),
-			Details: fmt.Sprintf("OS: %s, Arch: %s", info.OperatingSystem, info.Architecture),
-		})
+		return err
 	}
 
-	// Check 3: Docker daemon mode
-	if textMode {
-		fmt.Print("Checking daemon mode... ")
-	}
-	if info.Swarm.LocalNodeState == "active" {
-		if textMode {
-			fmt.Println(warnStyle.Render("SWARM MODE"))
-		}
-		results = append(results, DiagnosticResult{
-			Name:    "Daemon Mode",
-			Status:  "warn",
-			Message: "Docker is running in Swarm mode",
-			Details: "Some operations may behave differently in Swarm mode",
-		})
-	} else {
-		if textMode {
-			fmt.Println(okStyle.Render("STANDALONE"))
+	env := diagnose.NewEnv(func() (docker.DockerService, error) {
+		client, err := docker.NewClient()
+		if err != nil {
+			return nil, err
 		}
-		results = append(results, DiagnosticResult{
-			Name:    "Daemon Mode",
-			Status:  "ok",
-			Message: "Docker is running in standalone mode",
-		})
-	}
+		return client, nil
+	})
+	defer func() { _ = env.Close() }()
+	env.SetDaemonConfigPath(cfg.Diagnose.DaemonConfig)
+	env.SetHostDiskOptions(hostdisk.OptionsFrom(cfg.Disk))
 
-	// Check 4: Storage driver
+	var progress func(diagnose.Check, *diagnose.Result)
 	if textMode {
-		fmt.Print("Checking storage driver... ")
+		fmt.Println()
+		fmt.Println(styles.Title.Render("🐙 Octo Docker Diagnostics"))
+		fmt.Println(strings.Repeat("─", 50))
+		fmt.Println()
+		progress = printDiagnosticProgress
 	}
-	recommendedDrivers := map[string]bool{"overlay2": true, "btrfs": true}
-	if recommendedDrivers[info.Driver] {
-		if textMode {
-			fmt.Println(okStyle.Render(info.Driver))
-		}
-		results = append(results, DiagnosticResult{
-			Name:    "Storage Driver",
-			Status:  "ok",
-			Message: fmt.Sprintf("Using %s (recommended)", info.Driver),
-		})
-	} else {
-		if textMode {
-			fmt.Println(warnStyle.Render(info.Driver))
-		}
-		results = append(results, DiagnosticResult{
-			Name:    "Storage Driver",
-			Status:  "warn",
-			Message: fmt.Sprintf("Using %s", info.Driver),
-			Details: "Consider using overlay2 for better performance",
-		})
+
+	ctx := context.Background()
+	started := time.Now()
+	results := diagnose.Run(ctx, env, checks, progress)
+	output := DiagnoseOutput{
+		Results: results,
+		Summary: diagnose.Summarize(results),
 	}
 
-	// Check 5: Disk space
 	if textMode {
-		fmt.Print("Checking disk usage... ")
+		printDiagnosticSummary(results, verbose)
 	}
-	diskUsage, err := client.GetDiskUsage(ctx)
-	if err != nil {
-		if textMode {
-			fmt.Println(errorStyle.Render("FAILED"))
-		}
-		results = append(results, DiagnosticResult{
-			Name:    "Disk Usage",
-			Status:  "error",
-			Message: "Cannot get disk usage",
-			Details: err.Error(),
-		})
-	} else {
-		var reclaimablePercent float64
-		if diskUsage.Total > 0 {
-			reclaimablePercent = float64(diskUsage.TotalReclaimable) / float64(diskUsage.Total) * 100
-		}
-		if reclaimablePercent > 50 {
-			if textMode {
-				fmt.Println(warnStyle.Render(fmt.Sprintf("%.0f%% reclaimable", reclaimablePercent)))
-			}
-			results = append(results, DiagnosticResult{
-				Name:    "Disk Usage",
-				Status:  "warn",
-				Message: fmt.Sprintf("%s used, %s reclaimable (%.0f%%)", humanize.Bytes(uint64(diskUsage.Total)), humanize.Bytes(uint64(diskUsage.TotalReclaimable)), reclaimablePercent),
-				Details: "Consider running 'octo cleanup' or 'octo prune'",
-			})
-		} else {
-			if textMode {
-				fmt.Println(okStyle.Render(humanize.Bytes(uint64(diskUsage.Total))))
-			}
-			results = append(results, DiagnosticResult{
-				Name:    "Disk Usage",
-				Status:  "ok",
-				Message: fmt.Sprintf("%s used, %s reclaimable", humanize.Bytes(uint64(diskUsage.Total)), humanize.Bytes(uint64(diskUsage.TotalReclaimable))),
-			})
+	if fix {
+		if err := runDiagnoseFixes(ctx, env, checks, &output, textMode, force); err != nil {
+			return err
 		}
 	}
 
-	// Check 6: Container count
-	if textMode {
-		fmt.Print("Checking containers... ")
+	// After --fix, the re-run results are the ones that count.
+	final := output.Results
+	if output.After != nil {
+		final = output.After
 	}
-	containers, err := client.ListContainers(ctx, true)
-	if err != nil {
-		if textMode {
-			fmt.Println(errorStyle.Render("FAILED"))
-		}
-	} else {
-		running := 0
-		stopped := 0
-		for _, c := range containers {
-			if c.State == "running" {
-				running++
-			} else {
-				stopped++
-			}
-		}
 
-		if stopped > 10 {
-			if textMode {
-				fmt.Println(warnStyle.Render(fmt.Sprintf("%d stopped", stopped)))
-			}
-			results = append(results, DiagnosticResult{
-				Name:    "Containers",
-				Status:  "warn",
-				Message: fmt.Sprintf("%d running, %d stopped", running, stopped),
-				Details: "Consider removing unused stopped containers with 'octo cleanup --containers'",
-			})
-		} else {
-			if textMode {
-				fmt.Println(okStyle.Render(fmt.Sprintf("%d running", running)))
-			}
-			results = append(results, DiagnosticResult{
-				Name:    "Containers",
-				Status:  "ok",
-				Message: fmt.Sprintf("%d running, %d stopped", running, stopped),
-			})
+	if junitPath != "" {
+		if err := writeDiagnoseJUnit(junitPath, final, threshold, started); err != nil {
+			return err
 		}
 	}
 
-	// Check 7: Dangling images
-	if textMode {
-		fmt.Print("Checking dangling images... ")
-	}
-	danglingImages, err := client.GetDanglingImages(ctx)
-	if err != nil {
-		if textMode {
-			fmt.Println(errorStyle.Render("FAILED"))
-		}
-	} else {
-		if len(danglingImages) > 5 {
-			var totalSize int64
-			for _, img := range danglingImages {
-				totalSize += img.Size
-			}
-			if textMode {
-				fmt.Println(warnStyle.Render(fmt.Sprintf("%d (%s)", len(danglingImages), humanize.Bytes(uint64(totalSize)))))
-			}
-			results = append(results, DiagnosticResult{
-				Name:    "Dangling Images",
-				Status:  "warn",
-				Message: fmt.Sprintf("%d dangling images (%s)", len(danglingImages), humanize.Bytes(uint64(totalSize))),
-				Details: "Run 'octo cleanup --images' to remove dangling images",
-			})
-		} else {
-			if textMode {
-				fmt.Println(okStyle.Render(fmt.Sprintf("%d", len(danglingImages))))
-			}
-			results = append(results, DiagnosticResult{
-				Name:    "Dangling Images",
-				Status:  "ok",
-				Message: fmt.Sprintf("%d dangling images", len(danglingImages)),
-			})
+	// Structured output for JSON/YAML
+	if junitPath != "-" && (outputFormat == "json" || outputFormat == "yaml") {
+		if err := outputDiagnoseStructured(outputFormat, output); err != nil {
+			return err
 		}
 	}
 
-	// Check 8: Unused volumes
-	if textMode {
-		fmt.Print("Checking unused volumes... ")
-	}
-	unusedVolumes, err := client.GetUnusedVolumes(ctx)
-	if err != nil {
-		if textMode {
-			fmt.Println(errorStyle.Render("FAILED"))
-		}
-	} else {
-		if len(unusedVolumes) > 5 {
-			if textMode {
-				fmt.Println(warnStyle.Render(fmt.Sprintf("%d", len(unusedVolumes))))
-			}
-			results = append(results, DiagnosticResult{
-				Name:    "Unused Volumes",
-				Status:  "warn",
-				Message: fmt.Sprintf("%d unused volumes", len(unusedVolumes)),
-				Details: "Run 'octo cleanup --volumes' to remove unused volumes",
-			})
-		} else {
-			if textMode {
-				fmt.Println(okStyle.Render(fmt.Sprintf("%d", len(unusedVolumes))))
+	if failOnName != "" {
+		if failed := diagnose.Failed(final, threshold); len(failed) > 0 {
+			names := make([]string, len(failed))
+			for i, r := range failed {
+				names[i] = r.ID
 			}
-			results = append(results, DiagnosticResult{
-				Name:    "Unused Volumes",
-				Status:  "ok",
-				Message: fmt.Sprintf("%d unused volumes", len(unusedVolumes)),
-			})
+			return fmt.Errorf("checks at or above %s: %s", threshold, strings.Join(names, ", "))
 		}
 	}
 
-	// Check 9: API response time
-	if textMode {
-		fmt.Print("Checking API responsiveness... ")
+	if _, err := env.Docker(); textMode && err != nil && len(results) > 0 && results[0].ID == "connection" {
+		return fmt.Errorf("docker connection failed: %w", err)
 	}
-	start := time.Now()
-	pingCtx, pingCancel := context.WithTimeout(context.Background(), 5*time.Second)
-	err = client.Ping(pingCtx)
-	pingCancel()
-	elapsed := time.Since(start)
-	if err != nil {
-		if textMode {
-			fmt.Println(errorStyle.Render("TIMEOUT"))
-		}
-		results = append(results, DiagnosticResult{
-			Name:    "API Response",
-			Status:  "error",
-			Message: "Docker API timed out",
-			Details: "The Docker daemon may be overloaded or unresponsive",
-		})
-	} else if elapsed > 500*time.Millisecond {
-		if textMode {
-			fmt.Println(warnStyle.Render(fmt.Sprintf("%dms", elapsed.Milliseconds())))
-		}
-		results = append(results, DiagnosticResult{
-			Name:    "API Response",
-			Status:  "warn",
-			Message: fmt.Sprintf("API response time: %dms", elapsed.Milliseconds()),
-			Details: "Docker daemon may be under heavy load",
-		})
-	} else {
-		if textMode {
-			fmt.Println(okStyle.Render(fmt.Sprintf("%dms", elapsed.Milliseconds())))
+	return nil
+}
+
+func writeDiagnoseJUnit(path string, results []DiagnosticResult, threshold diagnose.Status, started time.Time) error {
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("creating JUnit report: %w", err)
 		}
-		results = append(results, DiagnosticResult{
-			Name:    "API Response",
-			Status:  "ok",
-			Message: fmt.Sprintf("API response time: %dms", elapsed.Milliseconds()),
-		})
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	if err := diagnose.WriteJUnit(w, results, threshold, started); err != nil {
+		return fmt.Errorf("writing JUnit report: %w", err)
 	}
+	return nil
+}
 
-	// Check 10: Memory limits (Docker Desktop)
-	if textMode {
-		fmt.Print("Checking memory configuration... ")
+// printDiagnosticProgress prints "Checking <name>... " before a check runs
+// and its short status once it has.
+func printDiagnosticProgress(c diagnose.Check, r *diagnose.Result) {
+	if r == nil {
+		fmt.Printf("Checking %s... ", strings.ToLower(c.Name))
+		return
 	}
-	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
-		if info.MemTotal > 0 {
-			memGB := float64(info.MemTotal) / (1024 * 1024 * 1024)
-			if memGB < 2 {
-				if textMode {
-					fmt.Println(warnStyle.Render(fmt.Sprintf("%.1fGB allocated", memGB)))
-				}
-				results = append(results, DiagnosticResult{
-					Name:    "Memory",
-					Status:  "warn",
-					Message: fmt.Sprintf("%.1fGB allocated to Docker", memGB),
-					Details: "Consider increasing Docker Desktop memory allocation",
-				})
-			} else {
-				if textMode {
-					fmt.Println(okStyle.Render(fmt.Sprintf("%.1fGB", memGB)))
-				}
-				results = append(results, DiagnosticResult{
-					Name:    "Memory",
-					Status:  "ok",
-					Message: fmt.Sprintf("%.1fGB allocated to Docker", memGB),
-				})
-			}
-		} else {
-			if textMode {
-				fmt.Println(infoStyle.Render("N/A"))
-			}
-		}
-	} else {
-		if textMode {
-			fmt.Println(okStyle.Render("Native"))
-		}
-		results = append(results, DiagnosticResult{
-			Name:    "Memory",
-			Status:  "ok",
-			Message: "Running on native Linux (no VM overhead)",
-		})
+	short := r.Short
+	if short == "" {
+		short = strings.ToUpper(string(r.Status))
 	}
+	fmt.Println(diagnosticStyle(r.Status).Render(short))
+}
 
-	// Structured output for JSON/YAML
-	if !textMode {
-		return outputDiagnoseStructured(outputFormat, results)
+func diagnosticStyle(status diagnose.Status) interface{ Render(...string) string } {
+	switch status {
+	case diagnose.StatusWarn:
+		return styles.Warning
+	case diagnose.StatusError:
+		return styles.Error
+	default:
+		return styles.Success
 	}
+}
 
-	// Print summary
-	printDiagnosticSummary(results, verbose)
-	return nil
+func diagnosticIcon(status diagnose.Status) string {
+	switch status {
+	case diagnose.StatusWarn:
+		return styles.Warning.Render("!")
+	case diagnose.StatusError:
+		return styles.Error.Render("✗")
+	default:
+		return styles.Success.Render("✓")
+	}
 }
 
-func outputDiagnoseStructured(outputFormat string, results []DiagnosticResult) error {
-	passed, warnings, errors := 0, 0, 0
-	for _, r := range results {
-		switch r.Status {
-		case "ok":
-			passed++
-		case "warn":
-			warnings++
-		case "error":
-			errors++
+func listDiagnosticChecks(registry *diagnose.Registry, outputFormat string) error {
+	checks := registry.Checks()
+	infos := make([]DiagnoseCheckInfo, len(checks))
+	for i, c := range checks {
+		infos[i] = DiagnoseCheckInfo{
+			ID:          c.ID,
+			Name:        c.Name,
+			Category:    c.Category,
+			Severity:    string(c.Severity),
+			Remediation: c.Remediation,
+			Fixable:     c.Fix != nil,
 		}
 	}
-	output := DiagnoseOutput{
-		Results: results,
-		Summary: DiagnoseSummary{
-			Passed:   passed,
-			Warnings: warnings,
-			Errors:   errors,
-		},
+	switch outputFormat {
+	case "json":
+		return format.FormatJSON(os.Stdout, infos)
+	case "yaml":
+		return format.FormatYAML(os.Stdout, infos)
 	}
+
+	fmt.Printf("%-20s %-12s %-9s %-4s %s\n", "ID", "CATEGORY", "SEVERITY", "FIX", "NAME")
+	for _, info := range infos {
+		fixable := "-"
+		if info.Fixable {
+			fixable = "yes"
+		}
+		fmt.Printf("%-20s %-12s %-9s %-4s %s\n", info.ID, info.Category, info.Severity, fixable, info.Name)
+	}
+	fmt.Println()
+	fmt.Println(styles.Info.Render("Categories: " + strings.Join(registry.Categories(), ", ")))
+	return nil
+}
+
+func outputDiagnoseStructured(outputFormat string, output DiagnoseOutput) error {
 	switch outputFormat {
 	case "json":
 		return format.FormatJSON(os.Stdout, output)
@@ -460,9 +327,6 @@ func outputDiagnoseStructured(outputFormat string, results []DiagnosticResult) e
 func printDiagnosticSummary(results []DiagnosticResult, verbose bool) {
 	// Styles (defined in internal/ui/styles/theme.go)
 	titleStyle := styles.Title
-	okStyle := styles.Success
-	warnStyle := styles.Warning
-	errorStyle := styles.Error
 	infoStyle := styles.Info
 
 	fmt.Println()
@@ -470,45 +334,33 @@ func printDiagnosticSummary(results []DiagnosticResult, verbose bool) {
 	fmt.Println(titleStyle.Render("Summary"))
 	fmt.Println()
 
-	okCount := 0
-	warnCount := 0
-	errorCount := 0
-
-	for _, r := range results {
-		switch r.Status {
-		case "ok":
-			okCount++
-		case "warn":
-			warnCount++
-		case "error":
-			errorCount++
-		}
-	}
-
-	fmt.Printf("  %s %d passed\n", okStyle.Render("✓"), okCount)
-	if warnCount > 0 {
-		fmt.Printf("  %s %d warnings\n", warnStyle.Render("!"), warnCount)
+	summary := diagnose.Summarize(results)
+	fmt.Printf("  %s %d passed\n", diagnosticIcon(diagnose.StatusOK), summary.Passed)
+	if summary.Warnings > 0 {
+		fmt.Printf("  %s %d warnings\n", diagnosticIcon(diagnose.StatusWarn), summary.Warnings)
 	}
-	if errorCount > 0 {
-		fmt.Printf("  %s %d errors\n", errorStyle.Render("✗"), errorCount)
+	if summary.Errors > 0 {
+		fmt.Printf("  %s %d errors\n", diagnosticIcon(diagnose.StatusError), summary.Errors)
 	}
 
 	// Show recommendations for warnings/errors
 	hasRecommendations := false
 	for _, r := range results {
-		if (r.Status == "warn" || r.Status == "error") && r.Details != "" {
-			if !hasRecommendations {
-				fmt.Println()
-				fmt.Println(titleStyle.Render("Recommendations"))
-				hasRecommendations = true
-			}
-			icon := warnStyle.Render("!")
-			if r.Status == "error" {
-				icon = errorStyle.Render("✗")
-			}
-			fmt.Printf("\n  %s %s\n", icon, r.Name)
+		if r.Status == diagnose.StatusOK || (r.Details == "" && r.Remediation == "") {
+			continue
+		}
+		if !hasRecommendations {
+			fmt.Println()
+			fmt.Println(titleStyle.Render("Recommendations"))
+			hasRecommendations = true
+		}
+		fmt.Printf("\n  %s %s\n", diagnosticIcon(r.Status), r.Name)
+		if r.Details != "" {
 			fmt.Printf("    %s\n", infoStyle.Render(r.Details))
 		}
+		if r.Remediation != "" {
+			fmt.Printf("    %s\n", infoStyle.Render("→ "+r.Remediation))
+		}
 	}
 
 	// Verbose output
@@ -516,14 +368,7 @@ func printDiagnosticSummary(results []DiagnosticResult, verbose bool) {
 		fmt.Println()
 		fmt.Println(titleStyle.Render("Detailed Results"))
 		for _, r := range results {
-			icon := okStyle.Render("✓")
-			switch r.Status {
-			case "warn":
-				icon = warnStyle.Render("!")
-			case "error":
-				icon = errorStyle.Render("✗")
-			}
-			fmt.Printf("\n  %s %s: %s\n", icon, r.Name, r.Message)
+			fmt.Printf("\n  %s %s: %s\n", diagnosticIcon(r.Status), r.Name, r.Message)
 			if r.Details != "" {
 				fmt.Printf("    %s\n", infoStyle.Render(r.Details))
 			}
@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bsisduck/octo/internal/diagnose"
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+// DiagnoseFix records a fix offered by diagnose --fix
+type DiagnoseFix struct {
+	CheckID     string `json:"check_id" yaml:"check_id"`
+	Title       string `json:"title" yaml:"title"`
+	Tier        string `json:"tier" yaml:"tier"`
+	Description string `json:"description" yaml:"description"`
+	Applied     bool   `json:"applied" yaml:"applied"`
+	Summary     string `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Error       string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// plannedFix is a pending fix with its dry-run plan.
+type plannedFix struct {
+	diagnose.PendingFix
+	info docker.ConfirmationInfo
+	err  error
+}
+
+// runDiagnoseFixes offers the fixes applicable to output.Results, applies
+// the selected ones and re-runs the checks, recording everything in output.
+// Text mode prompts unless force is set; structured mode applies all fixes
+// (the caller requires --force or --dry-run there).
+func runDiagnoseFixes(ctx context.Context, env *diagnose.Env, checks []diagnose.Check, output *DiagnoseOutput, textMode, force bool) error {
+	pending := diagnose.Fixes(checks, output.Results)
+	if len(pending) == 0 {
+		if textMode {
+			fmt.Println(styles.Info.Render("No automatic fixes available."))
+			fmt.Println()
+		}
+		return nil
+	}
+
+	planned := make([]plannedFix, len(pending))
+	for i, p := range pending {
+		planned[i] = plannedFix{PendingFix: p}
+		planned[i].info, planned[i].err = p.Check.Fix.Plan(ctx, env)
+	}
+	output.Fixes = make([]DiagnoseFix, len(planned))
+	for i, p := range planned {
+		output.Fixes[i] = DiagnoseFix{
+			CheckID:     p.Check.ID,
+			Title:       p.Check.Fix.Title,
+			Tier:        p.info.Tier.String(),
+			Description: p.info.Description,
+		}
+		if p.err != nil {
+			output.Fixes[i].Error = p.err.Error()
+		}
+	}
+
+	if textMode {
+		printFixChecklist(planned)
+	}
+	if IsDryRun() {
+		if textMode {
+			fmt.Println(styles.Warning.Render("DRY RUN - No changes were made"))
+			fmt.Println()
+		}
+		return nil
+	}
+
+	selected := make([]int, 0, len(planned))
+	if force {
+		for i := range planned {
+			selected = append(selected, i)
+		}
+	} else {
+		var err error
+		if selected, err = promptFixSelection(len(planned)); err != nil {
+			return err
+		}
+	}
+	if len(selected) == 0 {
+		if textMode {
+			fmt.Println(styles.Info.Render("No fixes applied."))
+			fmt.Println()
+		}
+		return nil
+	}
+
+	if textMode {
+		fmt.Println()
+		fmt.Println("Applying fixes...")
+		fmt.Println()
+	}
+	applied := 0
+	for _, i := range selected {
+		p := planned[i]
+		if p.err != nil {
+			continue
+		}
+		// Bulk operations get a second confirmation, as in prune and cleanup.
+		if !force && p.info.Tier >= docker.TierBulkDestructive &&
+			!confirmAction(fmt.Sprintf("%s is a %s operation. Continue?", p.Check.Fix.Title, strings.ToLower(p.info.Tier.String()))) {
+			continue
+		}
+		if textMode {
+			fmt.Printf("  %s... ", p.Check.Fix.Title)
+		}
+		summary, err := p.Check.Fix.Apply(ctx, env)
+		if err != nil {
+			output.Fixes[i].Error = err.Error()
+			if textMode {
+				fmt.Println(styles.Error.Render(fmt.Sprintf("error: %v", err)))
+			}
+			continue
+		}
+		applied++
+		output.Fixes[i].Applied = true
+		output.Fixes[i].Summary = summary
+		if textMode {
+			fmt.Println(styles.Success.Render("done (" + summary + ")"))
+		}
+	}
+	if applied == 0 {
+		return nil
+	}
+
+	// Re-run against fresh data so cached info from the first run is not reused.
+	fresh, err := env.Fresh()
+	if err != nil {
+		return err
+	}
+	output.After = diagnose.Run(ctx, fresh, checks, nil)
+	summary := diagnose.Summarize(output.After)
+	output.AfterSummary = &summary
+	if textMode {
+		printFixComparison(output.Results, output.After, output.Fixes)
+	}
+	return nil
+}
+
+func printFixChecklist(planned []plannedFix) {
+	fmt.Println(styles.Title.Render("Available Fixes"))
+	for i, p := range planned {
+		fmt.Println()
+		if p.err != nil {
+			fmt.Printf("  [%d] %s %s\n", i+1, p.Check.Fix.Title, styles.Error.Render("(unavailable: "+p.err.Error()+")"))
+			continue
+		}
+		tierStyle := styles.TierStyle(int(p.info.Tier))
+		fmt.Printf("  [%d] %s %s\n", i+1, p.Check.Fix.Title, tierStyle.Render("("+p.info.Tier.String()+")"))
+		fmt.Printf("      %s\n", p.info.Description)
+		for _, w := range p.info.Warnings {
+			fmt.Printf("      %s\n", styles.Warning.Render("! "+w))
+		}
+		if p.info.UndoInstructions != "" {
+			fmt.Printf("      %s\n", styles.Info.Render("Undo: "+p.info.UndoInstructions))
+		}
+	}
+	fmt.Println()
+}
+
+// promptFixSelection asks which of n fixes to apply.
+func promptFixSelection(n int) ([]int, error) {
+	fmt.Printf("Apply which fixes? [all, none, or numbers e.g. 1,3] (none): ")
+	return parseFixSelection(readLine(os.Stdin), n)
+}
+
+// readLine reads one line a byte at a time. Unlike a buffered reader it
+// leaves the rest of the input, such as piped answers to the confirmation
+// prompts that follow, unread.
+func readLine(r io.Reader) string {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n == 1 {
+			if b[0] == '\n' {
+				break
+			}
+			line = append(line, b[0])
+		}
+		if err != nil {
+			break
+		}
+	}
+	return string(line)
+}
+
+// parseFixSelection parses "all", "none"/"" or a comma/space separated list
+// of 1-based fix numbers into sorted, distinct 0-based indexes.
+func parseFixSelection(input string, n int) ([]int, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	switch input {
+	case "", "none", "n", "no":
+		return nil, nil
+	case "all", "a", "y", "yes":
+		all := make([]int, n)
+		for i := range all {
+			all[i] = i
+		}
+		return all, nil
+	}
+	chosen := make([]bool, n)
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		num, err := strconv.Atoi(field)
+		if err != nil || num < 1 || num > n {
+			return nil, fmt.Errorf("invalid fix selection %q: choose numbers from 1 to %d", field, n)
+		}
+		chosen[num-1] = true
+	}
+	var selected []int
+	for i, ok := range chosen {
+		if ok {
+			selected = append(selected, i)
+		}
+	}
+	return selected, nil
+}
+
+// printFixComparison shows the before/after status of every check that had
+// a fix applied or whose status changed.
+func printFixComparison(before, after []DiagnosticResult, fixes []DiagnoseFix) {
+	fixed := make(map[string]bool)
+	for _, f := range fixes {
+		if f.Applied {
+			fixed[f.CheckID] = true
+		}
+	}
+	afterByID := make(map[string]DiagnosticResult, len(after))
+	for _, r := range after {
+		afterByID[r.ID] = r
+	}
+
+	fmt.Println()
+	fmt.Println(styles.Title.Render("Before / After"))
+	fmt.Println()
+	for _, b := range before {
+		a, ok := afterByID[b.ID]
+		if !ok || (!fixed[b.ID] && a.Status == b.Status) {
+			continue
+		}
+		fmt.Printf("  %-24s %s → %s  %s\n", b.Name,
+			diagnosticStyle(b.Status).Render(strings.ToUpper(string(b.Status))),
+			diagnosticStyle(a.Status).Render(strings.ToUpper(string(a.Status))),
+			styles.Info.Render(a.Message))
+	}
+
+	s := diagnose.Summarize(after)
+	fmt.Println()
+	fmt.Printf("  %s %d passed", diagnosticIcon(diagnose.StatusOK), s.Passed)
+	if s.Warnings > 0 {
+		fmt.Printf("  %s %d warnings", diagnosticIcon(diagnose.StatusWarn), s.Warnings)
+	}
+	if s.Errors > 0 {
+		fmt.Printf("  %s %d errors", diagnosticIcon(diagnose.StatusError), s.Errors)
+	}
+	fmt.Println()
+	fmt.Println()
+}
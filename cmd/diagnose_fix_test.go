@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestReadLineLeavesLaterAnswers(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+	if _, err := w.WriteString("1, 3\ny\n"); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+
+	if got := readLine(r); got != "1, 3" {
+		t.Errorf("selection = %q, want %q", got, "1, 3")
+	}
+	var confirm string
+	_, _ = fmt.Fscanln(r, &confirm)
+	if confirm != "y" {
+		t.Errorf("confirmation = %q, want %q: the selection prompt read ahead", confirm, "y")
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/graph"
+	"github.com/bsisduck/octo/internal/ui/format"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [resource]",
+	Short: "Export container, image, volume and network relations as a graph",
+	Long: `Export how Docker resources relate: the image each container runs, the
+volumes it mounts and the networks it joins. The graph is written as
+Graphviz DOT or a Mermaid flowchart; with --output-format json or yaml the
+nodes and edges are listed instead.
+
+Given a container, image, volume or network (by name or ID prefix), only
+the resources within two steps of it are included: what uses it, and what
+those in turn use.
+
+  octo graph | dot -Tsvg > docker.svg
+  octo graph --format mermaid -o docker.mmd
+  octo graph pgdata          # What would removing this volume affect?`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runGraph,
+}
+
+func init() {
+	graphCmd.Flags().StringP("format", "f", "dot", "Graph format: dot, mermaid")
+	graphCmd.Flags().StringP("output", "o", "", "Write the graph to a file instead of stdout")
+	_ = graphCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"dot", "mermaid"}, cobra.ShellCompDirectiveDefault
+	})
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	graphFormat, _ := cmd.Flags().GetString("format")
+	outputPath, _ := cmd.Flags().GetString("output")
+	outputFormat, _ := cmd.Flags().GetString("output-format")
+
+	if graphFormat != "dot" && graphFormat != "mermaid" {
+		return fmt.Errorf("invalid graph format: %s. Choose: dot, mermaid", graphFormat)
+	}
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("connecting to Docker: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), docker.TimeoutList)
+	defer cancel()
+	g, err := graph.Load(ctx, client)
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		node, err := g.Find(args[0])
+		if err != nil {
+			return err
+		}
+		g = g.Around(node.ID)
+	}
+
+	out := os.Stdout
+	if outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", outputPath, err)
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+
+	switch outputFormat {
+	case "json":
+		return format.FormatJSON(out, g)
+	case "yaml":
+		return format.FormatYAML(out, g)
+	}
+	text := g.DOT()
+	if graphFormat == "mermaid" {
+		text = g.Mermaid()
+	}
+	if _, err := fmt.Fprint(out, text); err != nil {
+		return fmt.Errorf("writing graph: %w", err)
+	}
+	return nil
+}
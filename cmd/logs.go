@@ -2,15 +2,24 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 
 	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/logfile"
+	"github.com/bsisduck/octo/internal/logwatch"
+	"github.com/bsisduck/octo/internal/tui/logs"
 	"github.com/bsisduck/octo/internal/ui/format"
+	"github.com/bsisduck/octo/internal/ui/styles"
 )
 
 // LogOutputEntry is used for JSON/YAML log output
@@ -21,7 +30,7 @@ type LogOutputEntry struct {
 }
 
 var logsCmd = &cobra.Command{
-	Use:   "logs <container-id>",
+	Use:   "logs [container-id]",
 	Short: "View container logs",
 	Long: `View logs from a Docker container.
 
@@ -29,21 +38,139 @@ Examples:
   octo logs my-container
   octo logs my-container --tail 50
   octo logs my-container --follow
-  octo logs my-container --output-format json`,
-	Args: cobra.ExactArgs(1),
+  octo logs my-container --output-format json
+  octo logs my-container --save web.log
+  octo logs my-container -f --save web.ndjson.gz --max-size 50MB --max-files 3
+
+With --save, entries are written to the file instead of stdout. The format
+is chosen from the extension: .ndjson/.jsonl for NDJSON with timestamp and
+stream fields, plain text otherwise, gzip-compressed when ending in .gz.
+
+Alerts (--alert, --alert-level) imply --follow. When following, JSON output
+is written as NDJSON, one entry per line; YAML cannot be followed. Each match prints an alert
+to stderr, rings the terminal bell, and optionally runs --exec (through the
+shell, with the line on stdin and OCTO_ALERT_* variables set) or appends to
+--alert-file. Each rule fires at most once per --alert-cooldown. Actions
+run in the background, one at a time and for at most 30 seconds; an alert
+firing while one runs skips its action.
+
+  octo logs api --alert 'panic|OOM' --exec ./notify.sh
+  octo logs api --alert-level error --alert-file alerts.log
+
+When following, the stream survives daemon restarts and container
+restarts: it reconnects with backoff and resumes from the last line seen,
+without repeating lines. For Compose services it follows the service to
+the new container when it is recreated. Use --no-reconnect to stop when
+the stream ends instead.
+
+With --interactive, logs open in a scrollable viewer with search, export
+and alerts. It keeps --buffer-lines lines in memory; --spill moves older
+lines to a temporary file so long histories stay scrollable.
+
+  octo logs api -i --spill --buffer-lines 20000
+
+With --stats, log streams are sampled for --window and summarized as
+lines/sec, bytes/sec, stderr ratio, level histogram and a per-second
+sparkline. Without a container, all running containers are ranked from
+noisiest to quietest.
+
+  octo logs --stats --window 30s
+  octo logs api --stats --output-format json`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runLogs,
 }
 
 func init() {
 	logsCmd.Flags().IntP("tail", "n", 100, "Number of lines to show from end of logs")
 	logsCmd.Flags().BoolP("follow", "f", false, "Follow log output")
+	logsCmd.Flags().String("save", "", "Write logs to a file (.log, .ndjson, optionally .gz)")
+	logsCmd.Flags().String("max-size", "", "Rotate the --save file when it reaches this size (e.g. 50MB)")
+	logsCmd.Flags().Int("max-files", 5, "Number of rotated --save files to keep")
+	logsCmd.Flags().StringArray("alert", nil, "Alert when a line matches this regex (repeatable)")
+	logsCmd.Flags().String("alert-level", "", "Alert on lines at or above this level (warn, error, fatal, ...)")
+	logsCmd.Flags().String("exec", "", "Command to run when an alert fires")
+	logsCmd.Flags().String("alert-file", "", "File to append fired alerts to")
+	logsCmd.Flags().Duration("alert-cooldown", logwatch.DefaultCooldown, "Minimum time between alerts from the same rule")
+	logsCmd.Flags().Bool("no-bell", false, "Do not ring the terminal bell on alerts")
+	logsCmd.Flags().BoolP("interactive", "i", false, "Open logs in the interactive viewer")
+	logsCmd.Flags().Int("buffer-lines", logs.DefaultCapacity, "Lines the interactive viewer keeps in memory")
+	logsCmd.Flags().Bool("spill", false, "Page older lines to a temporary file in the interactive viewer")
+	logsCmd.Flags().String("spill-dir", "", "Directory for the --spill file (default: system temp dir)")
+	logsCmd.Flags().Bool("no-reconnect", false, "Stop following when the log stream ends instead of reconnecting")
+	logsCmd.Flags().Bool("stats", false, "Show log rate statistics instead of log lines")
+	logsCmd.Flags().Duration("window", 10*time.Second, "Sampling window for --stats")
+}
+
+// alertRulesFromFlags builds the alert rules requested on the command line.
+func alertRulesFromFlags(cmd *cobra.Command) ([]logwatch.Rule, error) {
+	patterns, _ := cmd.Flags().GetStringArray("alert")
+	level, _ := cmd.Flags().GetString("alert-level")
+	execCmd, _ := cmd.Flags().GetString("exec")
+	alertFile, _ := cmd.Flags().GetString("alert-file")
+	cooldown, _ := cmd.Flags().GetDuration("alert-cooldown")
+
+	specs := patterns
+	if level != "" {
+		specs = append(specs, "level:"+level)
+	}
+	var rules []logwatch.Rule
+	for _, spec := range specs {
+		rule, err := logwatch.ParseRule(spec)
+		if err != nil {
+			return nil, err
+		}
+		rule.Exec = execCmd
+		rule.File = alertFile
+		rule.Cooldown = cooldown
+		rules = append(rules, rule)
+	}
+	if len(rules) == 0 && (execCmd != "" || alertFile != "") {
+		return nil, fmt.Errorf("--exec and --alert-file require --alert or --alert-level")
+	}
+	return rules, nil
 }
 
 func runLogs(cmd *cobra.Command, args []string) error {
-	containerID := args[0]
+	statsMode, _ := cmd.Flags().GetBool("stats")
+	if len(args) == 0 && !statsMode {
+		return fmt.Errorf("requires a container (or --stats to rank all running containers)")
+	}
+	containerID := ""
+	if len(args) > 0 {
+		containerID = args[0]
+	}
 	tail, _ := cmd.Flags().GetInt("tail")
 	follow, _ := cmd.Flags().GetBool("follow")
 	outputFormat, _ := cmd.Flags().GetString("output-format")
+	savePath, _ := cmd.Flags().GetString("save")
+
+	var saveOpts logfile.Options
+	if savePath != "" {
+		saveOpts = logfile.OptionsFromPath(savePath)
+		if maxSize, _ := cmd.Flags().GetString("max-size"); maxSize != "" {
+			size, err := humanize.ParseBytes(maxSize)
+			if err != nil {
+				return fmt.Errorf("invalid --max-size %q: %w", maxSize, err)
+			}
+			saveOpts.MaxSize = int64(size)
+		}
+		saveOpts.MaxFiles, _ = cmd.Flags().GetInt("max-files")
+	}
+
+	rules, err := alertRulesFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	var alerts *logwatch.Watcher
+	if len(rules) > 0 {
+		alerts = logwatch.NewWatcher(rules...)
+		follow = true
+	}
+	noBell, _ := cmd.Flags().GetBool("no-bell")
+	interactive, _ := cmd.Flags().GetBool("interactive")
+	if follow && outputFormat == "yaml" && savePath == "" && !statsMode && !interactive {
+		return fmt.Errorf("following logs (--follow, --alert, --alert-level) needs text or json output")
+	}
 
 	client, err := docker.NewClient()
 	if err != nil {
@@ -51,6 +178,18 @@ func runLogs(cmd *cobra.Command, args []string) error {
 	}
 	defer func() { _ = client.Close() }()
 
+	if statsMode {
+		window, _ := cmd.Flags().GetDuration("window")
+		return runLogStats(client, containerID, window, outputFormat)
+	}
+
+	if interactive {
+		return runLogsTUI(cmd, client, containerID, rules)
+	}
+
+	onAlert, waitAlerts := alertHandler(alerts, containerID, !noBell)
+	defer waitAlerts()
+
 	// Fetch initial logs
 	ctx := context.Background()
 	entries, err := client.GetContainerLogs(ctx, containerID, tail)
@@ -58,44 +197,137 @@ func runLogs(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("fetching logs: %w", err)
 	}
 
+	if savePath != "" {
+		lf := newLogFollow(cmd, containerID, entries)
+		return saveLogs(ctx, client, lf, entries, savePath, saveOpts, follow, onAlert)
+	}
+
 	switch outputFormat {
 	case "json":
+		if follow {
+			return followJSON(ctx, client, newLogFollow(cmd, containerID, entries), entries, onAlert)
+		}
 		output := make([]LogOutputEntry, len(entries))
 		for i, e := range entries {
-			output[i] = LogOutputEntry{
-				Timestamp: e.Timestamp.Format("2006-01-02T15:04:05.000Z"),
-				Stream:    e.Stream,
-				Content:   e.Content,
-			}
+			output[i] = logOutputEntry(e)
 		}
 		return format.FormatJSON(os.Stdout, output)
 	case "yaml":
 		output := make([]LogOutputEntry, len(entries))
 		for i, e := range entries {
-			output[i] = LogOutputEntry{
-				Timestamp: e.Timestamp.Format("2006-01-02T15:04:05.000Z"),
-				Stream:    e.Stream,
-				Content:   e.Content,
-			}
+			output[i] = logOutputEntry(e)
 		}
 		return format.FormatYAML(os.Stdout, output)
 	}
 
 	// Text output
 	for _, e := range entries {
-		ts := e.Timestamp.Format("2006-01-02 15:04:05")
-		fmt.Printf("%s  %-6s  %s\n", ts, e.Stream, e.Content)
+		fmt.Println(logfile.FormatLine(e))
 	}
 
 	if !follow {
 		return nil
 	}
 
-	// Follow mode: stream new logs
+	return followLogs(ctx, client, newLogFollow(cmd, containerID, entries), func(e docker.LogEntry) error {
+		fmt.Println(logfile.FormatLine(e))
+		return nil
+	}, onAlert)
+}
+
+// logOutputEntry converts a log entry for JSON/YAML output.
+func logOutputEntry(e docker.LogEntry) LogOutputEntry {
+	return LogOutputEntry{
+		Timestamp: e.Timestamp.Format("2006-01-02T15:04:05.000Z"),
+		Stream:    e.Stream,
+		Content:   e.Content,
+	}
+}
+
+// followJSON writes the entries and then the followed ones as NDJSON, one
+// object per line, since a JSON array cannot be streamed.
+func followJSON(ctx context.Context, client docker.DockerService, lf logFollow, entries []docker.LogEntry, onEntry func(docker.LogEntry) error) error {
+	enc := json.NewEncoder(os.Stdout)
+	emit := func(e docker.LogEntry) error {
+		return enc.Encode(logOutputEntry(e))
+	}
+	for _, e := range entries {
+		if err := emit(e); err != nil {
+			return err
+		}
+	}
+	return followLogs(ctx, client, lf, emit, onEntry)
+}
+
+// runLogsTUI opens the interactive logs viewer.
+func runLogsTUI(cmd *cobra.Command, client docker.DockerService, containerID string, rules []logwatch.Rule) error {
+	if _, err := loadTUIConfig(); err != nil {
+		return err
+	}
+	opts := logs.BufferOptions{}
+	opts.Capacity, _ = cmd.Flags().GetInt("buffer-lines")
+	opts.SpillToDisk, _ = cmd.Flags().GetBool("spill")
+	opts.SpillDir, _ = cmd.Flags().GetString("spill-dir")
+
+	model, err := logs.NewWithOptions(client, containerID, containerID, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, styles.Warn.Render(fmt.Sprintf("%v; keeping logs in memory only", err)))
+	}
+
+	p := tea.NewProgram(model.WithAlerts(rules...), tea.WithAltScreen(), tea.WithMouseCellMotion())
+	final, err := p.Run()
+	if lm, ok := final.(logs.Model); ok {
+		_ = lm.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("running logs viewer: %w", err)
+	}
+	return nil
+}
+
+// logFollow describes which stream octo logs follows and how.
+type logFollow struct {
+	containerID string
+	since       time.Time // newest entry already written; the stream resumes after it
+	reconnect   bool      // survive daemon restarts and container recreation
+}
+
+// newLogFollow builds the follow settings for a container whose initial
+// entries have already been written.
+func newLogFollow(cmd *cobra.Command, containerID string, entries []docker.LogEntry) logFollow {
+	lf := logFollow{containerID: containerID}
+	noReconnect, _ := cmd.Flags().GetBool("no-reconnect")
+	lf.reconnect = !noReconnect
+	for _, e := range entries {
+		if e.Timestamp.After(lf.since) {
+			lf.since = e.Timestamp
+		}
+	}
+	return lf
+}
+
+// followLogs streams new entries to emit until the process is interrupted
+// or, without reconnect, until the stream ends. onEntry, when non-nil, sees
+// every streamed entry.
+func followLogs(ctx context.Context, client docker.DockerService, lf logFollow, emit, onEntry func(docker.LogEntry) error) error {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
 
-	logCh, errCh, cancel := client.StreamContainerLogs(ctx, containerID)
+	var (
+		logCh   <-chan docker.LogEntry
+		errCh   <-chan error
+		eventCh <-chan docker.FollowEvent
+		cancel  func()
+	)
+	if lf.reconnect {
+		logCh, eventCh, cancel = docker.FollowContainerLogs(ctx, client, lf.containerID, docker.FollowOptions{
+			Since:        lf.since,
+			TrackService: true,
+		})
+	} else {
+		logCh, errCh, cancel = client.StreamContainerLogs(ctx, lf.containerID)
+	}
 	defer cancel()
 
 	for {
@@ -104,15 +336,102 @@ func runLogs(cmd *cobra.Command, args []string) error {
 			if !ok {
 				return nil
 			}
-			ts := entry.Timestamp.Format("2006-01-02 15:04:05")
-			fmt.Printf("%s  %-6s  %s\n", ts, entry.Stream, entry.Content)
+			if err := emit(entry); err != nil {
+				return err
+			}
+			if onEntry != nil {
+				if err := onEntry(entry); err != nil {
+					return err
+				}
+			}
 		case err := <-errCh:
 			if err != nil {
 				return fmt.Errorf("log stream error: %w", err)
 			}
 			return nil
+		case ev := <-eventCh:
+			style := styles.Info
+			if ev.Kind == docker.FollowDisconnected {
+				style = styles.Warn
+			}
+			fmt.Fprintln(os.Stderr, style.Render(ev.String()))
 		case <-sigCh:
 			return nil
 		}
 	}
 }
+
+// alertHandler returns a per-entry hook that reports fired alerts on stderr,
+// rings the bell, and runs the rules' actions in the background so a slow
+// --exec does not hold up the stream. One action runs at a time; alerts
+// firing meanwhile are reported but their actions skipped. Action failures
+// are reported but do not stop the stream. The returned func waits for the
+// running action. The hook is nil when no rules are registered.
+func alertHandler(alerts *logwatch.Watcher, container string, bell bool) (func(docker.LogEntry) error, func()) {
+	if alerts == nil {
+		return nil, func() {}
+	}
+	busy := make(chan struct{}, 1)
+	var wg sync.WaitGroup
+	return func(e docker.LogEntry) error {
+		for _, a := range alerts.Check(e) {
+			if bell {
+				fmt.Fprint(os.Stderr, "\a")
+			}
+			fmt.Fprintln(os.Stderr, styles.Warn.Render("ALERT "+a.String()))
+			if a.Rule.Exec == "" && a.Rule.File == "" {
+				continue
+			}
+			select {
+			case busy <- struct{}{}:
+			default:
+				fmt.Fprintln(os.Stderr, styles.Warn.Render(fmt.Sprintf("alert %s: previous action still running; skipped", a.Rule.Name)))
+				continue
+			}
+			wg.Add(1)
+			go func(a logwatch.Alert) {
+				defer wg.Done()
+				defer func() { <-busy }()
+				ctx, cancel := context.WithTimeout(context.Background(), logwatch.DispatchTimeout)
+				defer cancel()
+				if err := logwatch.Dispatch(ctx, a, container); err != nil {
+					fmt.Fprintln(os.Stderr, styles.Error.Render(err.Error()))
+				}
+			}(a)
+		}
+		return nil
+	}, wg.Wait
+}
+
+// saveLogs writes the initial entries to path and, when following, keeps
+// appending streamed entries (rotating by size) until interrupted.
+func saveLogs(ctx context.Context, client docker.DockerService, lf logFollow, entries []docker.LogEntry, path string, opts logfile.Options, follow bool, onEntry func(docker.LogEntry) error) error {
+	w, err := logfile.Create(path, opts)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+	written := 0
+	defer func() {
+		_ = w.Close()
+		fmt.Fprintf(os.Stderr, "Saved %d lines to %s\n", written, path)
+	}()
+
+	for _, e := range entries {
+		if err := w.Write(e); err != nil {
+			return err
+		}
+		written++
+	}
+
+	if !follow {
+		return nil
+	}
+
+	return followLogs(ctx, client, lf, func(e docker.LogEntry) error {
+		if err := w.Write(e); err != nil {
+			return err
+		}
+		written++
+		return nil
+	}, onEntry)
+}
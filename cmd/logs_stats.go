@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/logwatch"
+	"github.com/bsisduck/octo/internal/ui/format"
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+// LogStatsOutput is used for JSON/YAML output of octo logs --stats
+type LogStatsOutput struct {
+	Container   string         `json:"container" yaml:"container"`
+	Lines       int            `json:"lines" yaml:"lines"`
+	Bytes       int            `json:"bytes" yaml:"bytes"`
+	LinesPerSec float64        `json:"lines_per_sec" yaml:"lines_per_sec"`
+	BytesPerSec float64        `json:"bytes_per_sec" yaml:"bytes_per_sec"`
+	StderrRatio float64        `json:"stderr_ratio" yaml:"stderr_ratio"`
+	Levels      map[string]int `json:"levels" yaml:"levels"`
+	Rate        []int          `json:"rate_per_sec" yaml:"rate_per_sec"`
+}
+
+// logStatsTarget is a container sampled by runLogStats.
+type logStatsTarget struct {
+	id   string
+	name string
+}
+
+// runLogStats samples the log streams of the given container, or of every
+// running container when containerID is empty, for the window duration and
+// prints them ranked from noisiest to quietest.
+func runLogStats(client docker.DockerService, containerID string, window time.Duration, outputFormat string) error {
+	ctx := context.Background()
+
+	var targets []logStatsTarget
+	if containerID != "" {
+		targets = append(targets, logStatsTarget{id: containerID, name: containerID})
+	} else {
+		listCtx, cancel := context.WithTimeout(ctx, docker.TimeoutList)
+		containers, err := client.ListContainers(listCtx, false)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("listing containers: %w", err)
+		}
+		for _, c := range containers {
+			if c.State == "running" {
+				targets = append(targets, logStatsTarget{id: c.ID, name: c.Name})
+			}
+		}
+	}
+	structured := outputFormat == "json" || outputFormat == "yaml"
+	if len(targets) == 0 && !structured {
+		fmt.Println("No running containers")
+		return nil
+	}
+
+	if !structured {
+		fmt.Fprintf(os.Stderr, "Sampling logs from %d container(s) for %s...\n", len(targets), window)
+	}
+	stats := sampleLogStats(ctx, client, targets, window)
+
+	type ranked struct {
+		name string
+		snap logwatch.Snapshot
+	}
+	results := make([]ranked, len(targets))
+	for i, t := range targets {
+		results[i] = ranked{name: t.name, snap: stats[i].Snapshot()}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].snap.LinesPerSec != results[j].snap.LinesPerSec {
+			return results[i].snap.LinesPerSec > results[j].snap.LinesPerSec
+		}
+		return results[i].snap.BytesPerSec > results[j].snap.BytesPerSec
+	})
+
+	if structured {
+		output := make([]LogStatsOutput, len(results))
+		for i, r := range results {
+			levels := make(map[string]int, len(r.snap.Levels))
+			for l, c := range r.snap.Levels {
+				levels[l.String()] = c
+			}
+			output[i] = LogStatsOutput{
+				Container:   r.name,
+				Lines:       r.snap.Lines,
+				Bytes:       r.snap.Bytes,
+				LinesPerSec: r.snap.LinesPerSec,
+				BytesPerSec: r.snap.BytesPerSec,
+				StderrRatio: r.snap.StderrRatio,
+				Levels:      levels,
+				Rate:        r.snap.Rate,
+			}
+		}
+		if outputFormat == "json" {
+			return format.FormatJSON(os.Stdout, output)
+		}
+		return format.FormatYAML(os.Stdout, output)
+	}
+
+	fmt.Printf("%-30s %10s %10s %7s  %s\n", "CONTAINER", "LINES/S", "BYTES/S", "STDERR", "ACTIVITY")
+	for _, r := range results {
+		fmt.Printf("%-30.30s %10.1f %10s %6.0f%%  %s  %s\n",
+			r.name, r.snap.LinesPerSec, humanize.Bytes(uint64(r.snap.BytesPerSec)), r.snap.StderrRatio*100,
+			styles.StatValue.Render(format.Sparkline(r.snap.Rate)),
+			styles.Help.Render(r.snap.LevelSummary()))
+	}
+	return nil
+}
+
+// sampleLogStats streams every target concurrently for the window (or until
+// interrupted) and returns one accumulator per target, in target order.
+func sampleLogStats(ctx context.Context, client docker.DockerService, targets []logStatsTarget, window time.Duration) []*logwatch.Stats {
+	ctx, cancel := context.WithTimeout(ctx, window)
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		select {
+		case <-sigCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	stats := make([]*logwatch.Stats, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		stats[i] = logwatch.NewStats(window)
+		logCh, errCh, stop := client.StreamContainerLogs(ctx, t.id)
+		wg.Add(1)
+		go func(s *logwatch.Stats) {
+			defer wg.Done()
+			defer stop()
+			for {
+				select {
+				case entry, ok := <-logCh:
+					if !ok {
+						return
+					}
+					s.Add(entry)
+				case err, ok := <-errCh:
+					if ok && err != nil {
+						return
+					}
+					errCh = nil // closed; drain logCh until it closes too
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(stats[i])
+	}
+	wg.Wait()
+	return stats
+}
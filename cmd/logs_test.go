@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/logwatch"
+)
+
+func TestAlertHandlerRunsActionsInBackground(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "ran")
+	alerts := logwatch.NewWatcher(logwatch.Rule{
+		Name:     "boom",
+		Pattern:  regexp.MustCompile("boom"),
+		Exec:     "sleep 0.5; echo ran >> " + out,
+		Cooldown: time.Nanosecond,
+	})
+	onAlert, wait := alertHandler(alerts, "web", false)
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		time.Sleep(time.Millisecond) // past the cooldown
+		if err := onAlert(docker.LogEntry{Timestamp: time.Now(), Stream: "stdout", Content: "boom"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(start); d > 250*time.Millisecond {
+		t.Errorf("alert hook blocked for %s", d)
+	}
+
+	wait()
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "ran\n" {
+		t.Errorf("expected one action run, the second skipped while busy; got %q", data)
+	}
+}
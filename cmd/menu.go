@@ -1,11 +1,21 @@
 package cmd
 
 import (
+	"fmt"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/bsisduck/octo/internal/config"
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/hostdisk"
+	"github.com/bsisduck/octo/internal/tui/app"
 	"github.com/bsisduck/octo/internal/tui/menu"
 )
 
+// appCacheTTL is how long tabs of the interactive app share list results.
+const appCacheTTL = 2 * time.Second
+
 // NewInteractiveMenu creates a new interactive menu
 func NewInteractiveMenu() *InteractiveMenu {
 	return &InteractiveMenu{}
@@ -16,9 +26,43 @@ type InteractiveMenu struct {
 	program *tea.Program
 }
 
-// Run starts the interactive menu and returns the chosen action.
-// Returns an empty string if the user quit without selecting.
+// Run starts the interactive app, with the menu as landing page, and
+// returns the command chosen there that runs outside the app (cleanup,
+// prune, diagnose, version). Returns an empty string if the user quit.
+// Without a Docker connection only the menu is shown.
 func (m *InteractiveMenu) Run() (string, error) {
+	cfg, err := loadTUIConfig()
+	if err != nil {
+		return "", err
+	}
+	client, err := docker.NewClient()
+	if err != nil {
+		return m.runMenu()
+	}
+	defer func() { _ = client.Close() }()
+
+	diskOpts := hostdisk.OptionsFrom(cfg.Disk)
+	model := app.New(docker.NewCachedService(client, appCacheTTL), app.Options{
+		HostDisk: &diskOpts,
+		Analyze:  cfg.Analyze,
+		PersistAnalyze: func(view config.AnalyzeConfig) error {
+			return config.SaveAnalyze(configPath, view)
+		},
+	})
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	m.program = p
+	finalModel, err := p.Run()
+	if err != nil {
+		return "", fmt.Errorf("running app: %w", err)
+	}
+	if mdl, ok := finalModel.(app.Model); ok {
+		return mdl.ChosenAction(), nil
+	}
+	return "", nil
+}
+
+// runMenu shows the standalone menu, which reports the connection error.
+func (m *InteractiveMenu) runMenu() (string, error) {
 	p := tea.NewProgram(menu.New(), tea.WithAltScreen(), tea.WithMouseCellMotion())
 	m.program = p
 	finalModel, err := p.Run()
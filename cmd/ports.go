@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/ports"
+	"github.com/bsisduck/octo/internal/ui/format"
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+var portsCmd = &cobra.Command{
+	Use:   "ports",
+	Short: "Map published host ports to containers and flag conflicts",
+	Long: `List every host port published by a container with its bind address,
+owning container and Compose service, and flag conflicts:
+- Two containers publishing the same port and address
+- A host process other than Docker bound to a published port
+- A stopped container whose port is taken (it cannot start)
+
+Host sockets are read from /proc/net when the daemon runs on this Linux
+host. Identifying the processes of other users requires root.
+
+  octo ports
+  octo ports --all          # Include ports claimed by stopped containers
+  octo ports --conflicts    # Only ports with conflicts`,
+	RunE: runPorts,
+}
+
+func init() {
+	portsCmd.Flags().BoolP("all", "a", false, "Include ports configured on stopped containers")
+	portsCmd.Flags().Bool("conflicts", false, "Only show ports with conflicts")
+}
+
+// PortsOutput holds structured port data for JSON/YAML output
+type PortsOutput struct {
+	Ports            []ports.Entry `json:"ports" yaml:"ports"`
+	Published        int           `json:"published" yaml:"published"`
+	Conflicts        int           `json:"conflicts" yaml:"conflicts"`
+	ListenersChecked bool          `json:"host_listeners_checked" yaml:"host_listeners_checked"`
+}
+
+func runPorts(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	conflictsOnly, _ := cmd.Flags().GetBool("conflicts")
+	outputFormat, _ := cmd.Flags().GetString("output-format")
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("connecting to Docker: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	ctx := context.Background()
+	listCtx, cancel := context.WithTimeout(ctx, docker.TimeoutList)
+	containers, err := client.ListContainers(listCtx, all)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("listing containers: %w", err)
+	}
+	if all {
+		if containers, err = ports.WithConfigured(ctx, client, containers); err != nil {
+			return err
+		}
+	}
+
+	listeners, checked := ports.LocalListeners()
+	entries := ports.Map(containers, listeners)
+	output := PortsOutput{
+		Ports:            entries,
+		Published:        len(entries),
+		Conflicts:        len(ports.Conflicted(entries)),
+		ListenersChecked: checked,
+	}
+	if conflictsOnly {
+		output.Ports = ports.Conflicted(entries)
+	}
+
+	switch outputFormat {
+	case "json":
+		return format.FormatJSON(os.Stdout, output)
+	case "yaml":
+		return format.FormatYAML(os.Stdout, output)
+	}
+	printPorts(output)
+	return nil
+}
+
+func printPorts(output PortsOutput) {
+	fmt.Println()
+	fmt.Println(styles.Title.Render("🐙 Octo Port Map"))
+	fmt.Println(strings.Repeat("─", 50))
+	fmt.Println()
+
+	if len(output.Ports) == 0 {
+		fmt.Println(styles.Info.Render("No published ports"))
+	} else {
+		fmt.Printf("%-22s %-10s %-30s %s\n", "ADDRESS", "PORT", "CONTAINER", "TARGET")
+		for _, e := range output.Ports {
+			owner := e.Owner()
+			if !e.Running {
+				owner += " (stopped)"
+			}
+			line := fmt.Sprintf("%-22s %-10s %-30s %d/%s",
+				e.Address(), fmt.Sprintf("%d/%s", e.HostPort, e.Protocol), owner, e.ContainerPort, e.Protocol)
+			if len(e.Conflicts) > 0 {
+				fmt.Println(styles.Error.Render(line))
+				for _, c := range e.Conflicts {
+					fmt.Printf("  %s\n", styles.Warning.Render("⚠ "+c))
+				}
+			} else {
+				fmt.Println(line)
+			}
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(strings.Repeat("─", 50))
+	if output.Conflicts > 0 {
+		fmt.Println(styles.Error.Render(fmt.Sprintf("✗ Conflicts on %d of %d published ports", output.Conflicts, output.Published)))
+	} else {
+		fmt.Println(styles.Success.Render("✓ No port conflicts"))
+	}
+	if !output.ListenersChecked {
+		fmt.Println(styles.Help.Render("Host listeners not checked (daemon is not on this Linux host)"))
+	}
+	fmt.Println()
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/rightsize"
+	"github.com/bsisduck/octo/internal/ui/format"
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+var rightsizeCmd = &cobra.Command{
+	Use:   "rightsize",
+	Short: "Compare container CPU/memory use with their limits and suggest values",
+	Long: `Sample the stats of running containers over a window and compare the
+observed CPU and memory peaks with each container's limits. Flags:
+- Containers without CPU or memory limits
+- Limits far above the observed peak
+- Memory peaks near the limit (OOM risk)
+
+Suggested limits (peak plus 50% headroom) are printed per Compose service as
+a compose override, and as docker update commands for other containers.
+Sample over a representative load: peaks outside the window are missed.
+
+  octo rightsize                       # Sample for 1 minute
+  octo rightsize --window 10m --interval 10s
+  octo rightsize --output-format json`,
+	RunE: runRightsize,
+}
+
+func init() {
+	rightsizeCmd.Flags().Duration("window", time.Minute, "How long to sample stats")
+	rightsizeCmd.Flags().Duration("interval", 5*time.Second, "Time between samples")
+}
+
+// RightsizeOutput holds structured right-sizing data for JSON/YAML output
+type RightsizeOutput struct {
+	Window   string             `json:"window" yaml:"window"`
+	Interval string             `json:"interval" yaml:"interval"`
+	Services []rightsize.Report `json:"services" yaml:"services"`
+}
+
+func runRightsize(cmd *cobra.Command, args []string) error {
+	window, _ := cmd.Flags().GetDuration("window")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	outputFormat, _ := cmd.Flags().GetString("output-format")
+	if window <= 0 || interval <= 0 {
+		return fmt.Errorf("--window and --interval must be positive")
+	}
+	if interval > window {
+		interval = window
+	}
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("connecting to Docker: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		select {
+		case <-sigCh:
+			cancel() // report what was sampled so far
+		case <-ctx.Done():
+		}
+	}()
+
+	targets, err := rightsize.Targets(ctx, client)
+	if err != nil {
+		return err
+	}
+	structured := outputFormat == "json" || outputFormat == "yaml"
+	if len(targets) == 0 && !structured {
+		fmt.Println("No running containers")
+		return nil
+	}
+	if !structured {
+		fmt.Fprintf(os.Stderr, "Sampling %d container(s) every %s for %s (Ctrl+C to stop early)...\n", len(targets), interval, window)
+	}
+	observed := rightsize.Sample(ctx, client, targets, window, interval)
+	output := RightsizeOutput{
+		Window:   window.String(),
+		Interval: interval.String(),
+		Services: rightsize.Analyze(targets, observed),
+	}
+
+	switch outputFormat {
+	case "json":
+		return format.FormatJSON(os.Stdout, output)
+	case "yaml":
+		return format.FormatYAML(os.Stdout, output)
+	}
+	printRightsize(output)
+	return nil
+}
+
+func printRightsize(output RightsizeOutput) {
+	fmt.Println()
+	fmt.Println(styles.Title.Render("🐙 Octo Right-Sizing"))
+	fmt.Println(strings.Repeat("─", 50))
+	fmt.Println()
+
+	fmt.Printf("%-30s %-18s %-22s %s\n", "SERVICE", "CPU PEAK/LIMIT", "MEMORY PEAK/LIMIT", "SUGGESTED")
+	flagged := 0
+	for _, r := range output.Services {
+		name := r.Name
+		if len(r.Containers) > 1 {
+			name += fmt.Sprintf(" (×%d)", len(r.Containers))
+		}
+		cpuPeak := rightsize.FormatCPUs(r.Observed.CPUPeak)
+		if r.Observed.CPUSamples == 0 {
+			cpuPeak = "?"
+		}
+		cpu := fmt.Sprintf("%s / %s", cpuPeak, limitOrDash(r.Limits.CPUs > 0, rightsize.FormatCPUs(r.Limits.CPUs)))
+		mem := fmt.Sprintf("%s / %s", humanize.IBytes(r.Observed.MemoryPeak), limitOrDash(r.Limits.Memory > 0, humanize.IBytes(r.Limits.Memory)))
+		suggested := "-"
+		switch {
+		case r.Suggested == nil:
+		case r.Suggested.CPUs > 0:
+			suggested = fmt.Sprintf("cpus %s, memory %s", rightsize.FormatCPUs(r.Suggested.CPUs), rightsize.FormatMemory(r.Suggested.Memory))
+		default:
+			suggested = "memory " + rightsize.FormatMemory(r.Suggested.Memory)
+		}
+		line := fmt.Sprintf("%-30.30s %-18s %-22s %s", name, cpu, mem, suggested)
+		if len(r.Findings) == 0 {
+			fmt.Println(line)
+			printCPUNotMeasured(r)
+			continue
+		}
+		flagged++
+		fmt.Println(styles.Warning.Render(line))
+		for _, f := range r.Findings {
+			style := styles.Warning
+			if f.Kind == rightsize.FindingNearLimit {
+				style = styles.Error
+			}
+			fmt.Printf("  %s\n", style.Render("⚠ "+f.Message))
+		}
+		printCPUNotMeasured(r)
+	}
+
+	if snippet := rightsize.ComposeSnippet(output.Services); snippet != "" {
+		fmt.Println()
+		fmt.Println(styles.Section.Render("Compose overrides"))
+		fmt.Print(snippet)
+	}
+	var updates []string
+	for _, r := range output.Services {
+		if r.Service == "" && r.Suggested != nil {
+			updates = append(updates, r.UpdateCommand())
+		}
+	}
+	if len(updates) > 0 {
+		fmt.Println()
+		fmt.Println(styles.Section.Render("Other containers"))
+		for _, u := range updates {
+			fmt.Println("  " + u)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(strings.Repeat("─", 50))
+	if flagged > 0 {
+		fmt.Println(styles.Warning.Render(fmt.Sprintf("⚠ %d of %d services need attention (sampled %s)", flagged, len(output.Services), output.Window)))
+	} else {
+		fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Limits fit the observed usage (sampled %s)", output.Window)))
+	}
+	fmt.Println()
+}
+
+// printCPUNotMeasured notes a service whose CPU use could not be computed:
+// without raw counters it takes two samples.
+func printCPUNotMeasured(r rightsize.Report) {
+	if r.Observed.Samples > 0 && r.Observed.CPUSamples == 0 {
+		fmt.Printf("  %s\n", styles.Info.Render("CPU not measured; sample over a longer window"))
+	}
+}
+
+// limitOrDash renders a limit, or "-" when there is none.
+func limitOrDash(set bool, s string) string {
+	if !set {
+		return "-"
+	}
+	return s
+}
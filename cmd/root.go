@@ -7,6 +7,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/bsisduck/octo/internal/config"
+	"github.com/bsisduck/octo/internal/tui/common"
 	"github.com/bsisduck/octo/internal/ui/styles"
 )
 
@@ -17,9 +19,11 @@ var (
 	GitCommit = ""
 
 	// Global flags
-	debug   bool
-	dryRun  bool
-	noColor bool
+	debug      bool
+	dryRun     bool
+	noColor    bool
+	configPath string
+	themeName  string
 )
 
 const (
@@ -43,7 +47,8 @@ var rootCmd = &cobra.Command{
 Octo helps you manage Docker containers, images, volumes, and networks
 with an intuitive interface and powerful cleanup capabilities.
 
-Run 'octo' without arguments to launch the interactive menu.`, octoLogo, octoTagline),
+Run 'octo' without arguments to launch the interactive app: tabs for status,
+containers, images, volumes, networks, logs and events.`, octoLogo, octoTagline),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Launch interactive menu when no subcommand is provided
 		return runInteractiveMenu()
@@ -58,8 +63,14 @@ Run 'octo' without arguments to launch the interactive menu.`, octoLogo, octoTag
 			return fmt.Errorf("invalid output format: %s. Choose: text, json, yaml", outputFormat)
 		}
 
-		if noColor || os.Getenv("NO_COLOR") != "" {
+		if NoColor() {
 			styles.DisableColors()
+			return nil
+		}
+		if styledOutput(cmd, outputFormat) {
+			if err := applyTheme(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v; using the default theme\n", err)
+			}
 		}
 		return nil
 	},
@@ -78,11 +89,16 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Preview changes without executing")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
 	rootCmd.PersistentFlags().String("output-format", "text", "Output format: text, json, yaml")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Config file (default: $OCTO_CONFIG or ~/.octo/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&themeName, "theme", "", "Color theme: auto, dark, light, high-contrast or a theme file (default: from config, else auto)")
 
 	// Register completion for output-format flag
 	_ = rootCmd.RegisterFlagCompletionFunc("output-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"text", "json", "yaml"}, cobra.ShellCompDirectiveDefault
 	})
+	_ = rootCmd.RegisterFlagCompletionFunc("theme", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return styles.ThemeNames(), cobra.ShellCompDirectiveDefault
+	})
 
 	// Add subcommands
 	rootCmd.AddCommand(statusCmd)
@@ -90,13 +106,17 @@ func init() {
 	rootCmd.AddCommand(cleanupCmd)
 	rootCmd.AddCommand(pruneCmd)
 	rootCmd.AddCommand(diagnoseCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(portsCmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(rightsizeCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(completionCmd)
 	rootCmd.AddCommand(logsCmd)
 }
 
-// runInteractiveMenu launches the TUI-based interactive menu
-// and dispatches the selected command after the TUI exits.
+// runInteractiveMenu launches the tabbed interactive app and dispatches
+// the command chosen on its landing page after the TUI exits.
 func runInteractiveMenu() error {
 	menu := NewInteractiveMenu()
 	action, err := menu.Run()
@@ -141,3 +161,51 @@ func IsDryRun() bool {
 func NoColor() bool {
 	return noColor || os.Getenv("NO_COLOR") != ""
 }
+
+// loadTUIConfig reads the config file and applies its keybindings to the
+// TUI views.
+func loadTUIConfig() (*config.Config, error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := common.NewKeyMap(cfg.Keys.Preset, cfg.Keys.Bindings)
+	if err != nil {
+		return nil, fmt.Errorf("config keys: %w", err)
+	}
+	common.SetKeyMap(keys)
+	return cfg, nil
+}
+
+// styledOutput reports whether cmd renders styled text. Themes are only
+// resolved for those: resolving "auto" queries the terminal, and a broken
+// theme should not get in the way of machine-readable output.
+func styledOutput(cmd *cobra.Command, outputFormat string) bool {
+	switch cmd.Name() {
+	case "version", "completion", "graph", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return false
+	}
+	return outputFormat == "text"
+}
+
+// applyTheme applies the theme named by --theme, else the config file's.
+// Colors the config sets replace those of either.
+func applyTheme() error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	name := themeName
+	if name == "" {
+		name = cfg.Theme.Name
+	}
+	theme, err := styles.ResolveTheme(name)
+	if err != nil {
+		return err
+	}
+	if theme, err = theme.WithColors(cfg.Theme.Colors); err != nil {
+		return fmt.Errorf("config theme: %w", err)
+	}
+	styles.Apply(theme)
+	return nil
+}
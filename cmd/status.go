@@ -4,14 +4,17 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 
 	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/hostdisk"
 	"github.com/bsisduck/octo/internal/tui/status"
 	"github.com/bsisduck/octo/internal/ui/format"
+	"github.com/bsisduck/octo/internal/ui/styles"
 )
 
 // StatusOutput holds structured status data for JSON/YAML output
@@ -23,6 +26,7 @@ type StatusOutput struct {
 	Images        ImageStatus     `json:"images" yaml:"images"`
 	Volumes       VolumeStatus    `json:"volumes" yaml:"volumes"`
 	DiskUsage     DiskStatus      `json:"disk_usage" yaml:"disk_usage"`
+	HostDisk      *HostDiskStatus `json:"host_disk,omitempty" yaml:"host_disk,omitempty"`
 }
 
 type ContainerStatus struct {
@@ -49,6 +53,21 @@ type DiskStatus struct {
 	BuildCache  int64 `json:"build_cache_bytes" yaml:"build_cache_bytes"`
 }
 
+// HostDiskStatus describes the host filesystem backing the Docker data
+// root. It is only reported when the daemon runs on this host.
+type HostDiskStatus struct {
+	Path              string             `json:"path" yaml:"path"`
+	Total             uint64             `json:"total_bytes" yaml:"total_bytes"`
+	Avail             uint64             `json:"available_bytes" yaml:"available_bytes"`
+	UsedPercent       float64            `json:"used_percent" yaml:"used_percent"`
+	SpaceLevel        hostdisk.Level     `json:"space_level" yaml:"space_level"`
+	Inodes            uint64             `json:"inodes" yaml:"inodes"`
+	InodesFree        uint64             `json:"inodes_free" yaml:"inodes_free"`
+	InodesUsedPercent float64            `json:"inodes_used_percent" yaml:"inodes_used_percent"`
+	InodeLevel        hostdisk.Level     `json:"inode_level" yaml:"inode_level"`
+	Forecast          *hostdisk.Forecast `json:"forecast,omitempty" yaml:"forecast,omitempty"`
+}
+
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show Docker system status and resource usage",
@@ -57,7 +76,9 @@ var statusCmd = &cobra.Command{
 - Image count and total size
 - Volume usage
 - Network status
-- System resource consumption`,
+- System resource consumption
+- Free space and inodes on the filesystem holding the Docker data root
+  (local daemons), with a days-until-full estimate from growth history`,
 	RunE: runStatus,
 }
 
@@ -68,6 +89,12 @@ func init() {
 func runStatus(cmd *cobra.Command, args []string) error {
 	watch, _ := cmd.Flags().GetBool("watch")
 
+	cfg, err := loadTUIConfig()
+	if err != nil {
+		return err
+	}
+	diskOpts := hostdisk.OptionsFrom(cfg.Disk)
+
 	client, err := docker.NewClient()
 	if err != nil {
 		return fmt.Errorf("connecting to Docker: %w", err)
@@ -75,7 +102,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	defer func() { _ = client.Close() }()
 
 	if watch {
-		model := status.New(client, true)
+		model := status.New(client, true).WithHostDisk(diskOpts)
 		p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 		if _, runErr := p.Run(); runErr != nil {
 			return fmt.Errorf("running status: %w", runErr)
@@ -97,61 +124,44 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	volumes, _ := client.ListVolumes(ctx)
 	unusedVolumes, _ := client.GetUnusedVolumes(ctx)
 
+	output := StatusOutput{
+		ServerVersion: info.ServerVersion,
+		OS:            info.OperatingSystem,
+		Arch:          info.Architecture,
+		Containers: ContainerStatus{
+			Running: info.ContainersRunning,
+			Paused:  info.ContainersPaused,
+			Stopped: info.ContainersStopped,
+			Total:   info.Containers,
+		},
+		Images: ImageStatus{
+			Total: info.Images,
+			Size:  diskUsage.Images,
+		},
+		Volumes: VolumeStatus{
+			Total:  len(volumes),
+			Unused: len(unusedVolumes),
+			Size:   diskUsage.Volumes,
+		},
+		DiskUsage: DiskStatus{
+			Total:       diskUsage.Total,
+			Reclaimable: diskUsage.TotalReclaimable,
+			BuildCache:  diskUsage.BuildCache,
+		},
+	}
+	if root, ok := hostdisk.DataRoot(info); ok {
+		if report, err := hostdisk.Measure(root, diskOpts, time.Now()); err == nil {
+			output.HostDisk = newHostDiskStatus(report)
+		}
+	}
+
 	// Check output format
 	outputFormat, _ := cmd.Flags().GetString("output-format")
 	switch outputFormat {
 	case "json":
-		return format.FormatJSON(os.Stdout, StatusOutput{
-			ServerVersion: info.ServerVersion,
-			OS:            info.OperatingSystem,
-			Arch:          info.Architecture,
-			Containers: ContainerStatus{
-				Running: info.ContainersRunning,
-				Paused:  info.ContainersPaused,
-				Stopped: info.ContainersStopped,
-				Total:   info.Containers,
-			},
-			Images: ImageStatus{
-				Total: info.Images,
-				Size:  diskUsage.Images,
-			},
-			Volumes: VolumeStatus{
-				Total:  len(volumes),
-				Unused: len(unusedVolumes),
-				Size:   diskUsage.Volumes,
-			},
-			DiskUsage: DiskStatus{
-				Total:       diskUsage.Total,
-				Reclaimable: diskUsage.TotalReclaimable,
-				BuildCache:  diskUsage.BuildCache,
-			},
-		})
+		return format.FormatJSON(os.Stdout, output)
 	case "yaml":
-		return format.FormatYAML(os.Stdout, StatusOutput{
-			ServerVersion: info.ServerVersion,
-			OS:            info.OperatingSystem,
-			Arch:          info.Architecture,
-			Containers: ContainerStatus{
-				Running: info.ContainersRunning,
-				Paused:  info.ContainersPaused,
-				Stopped: info.ContainersStopped,
-				Total:   info.Containers,
-			},
-			Images: ImageStatus{
-				Total: info.Images,
-				Size:  diskUsage.Images,
-			},
-			Volumes: VolumeStatus{
-				Total:  len(volumes),
-				Unused: len(unusedVolumes),
-				Size:   diskUsage.Volumes,
-			},
-			DiskUsage: DiskStatus{
-				Total:       diskUsage.Total,
-				Reclaimable: diskUsage.TotalReclaimable,
-				BuildCache:  diskUsage.BuildCache,
-			},
-		})
+		return format.FormatYAML(os.Stdout, output)
 	}
 
 	// Text output (default)
@@ -181,5 +191,44 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Reclaimable: %s\n", humanize.Bytes(uint64(diskUsage.TotalReclaimable)))
 	fmt.Printf("  Build Cache: %s\n", humanize.Bytes(uint64(diskUsage.BuildCache)))
 	fmt.Println()
+	if hd := output.HostDisk; hd != nil {
+		fmt.Printf("Host Disk (%s)\n", hd.Path)
+		fmt.Printf("  Free: %s of %s (%.0f%% used)%s\n",
+			humanize.Bytes(hd.Avail), humanize.Bytes(hd.Total), hd.UsedPercent, levelSuffix(hd.SpaceLevel))
+		if hd.Inodes > 0 {
+			fmt.Printf("  Inodes Free: %s (%.0f%% used)%s\n",
+				humanize.Comma(int64(hd.InodesFree)), hd.InodesUsedPercent, levelSuffix(hd.InodeLevel))
+		}
+		if f := hd.Forecast; f != nil {
+			fmt.Printf("  Full In: ~%.0f days (growing %s/day)\n", f.DaysLeft, humanize.Bytes(uint64(f.GrowthPerDay)))
+		}
+		fmt.Println()
+	}
 	return nil
 }
+
+func newHostDiskStatus(r hostdisk.Report) *HostDiskStatus {
+	return &HostDiskStatus{
+		Path:              r.Path,
+		Total:             r.Total,
+		Avail:             r.Avail,
+		UsedPercent:       r.UsedPercent(),
+		SpaceLevel:        r.SpaceLevel,
+		Inodes:            r.Inodes,
+		InodesFree:        r.InodesFree,
+		InodesUsedPercent: r.InodesUsedPercent(),
+		InodeLevel:        r.InodeLevel,
+		Forecast:          r.Forecast,
+	}
+}
+
+// levelSuffix flags a host disk measurement past its threshold.
+func levelSuffix(l hostdisk.Level) string {
+	switch l {
+	case hostdisk.LevelError:
+		return " " + styles.Error.Render("[ERROR]")
+	case hostdisk.LevelWarn:
+		return " " + styles.Warning.Render("[WARN]")
+	}
+	return ""
+}
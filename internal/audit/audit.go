@@ -0,0 +1,151 @@
+// Package audit inspects container configuration for risky security
+// settings and reports severity-ranked findings.
+package audit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// Severity ranks findings. Higher is worse.
+type Severity int
+
+const (
+	SeverityLow Severity = iota + 1
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = map[Severity]string{
+	SeverityLow:      "low",
+	SeverityMedium:   "medium",
+	SeverityHigh:     "high",
+	SeverityCritical: "critical",
+}
+
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// MarshalText encodes the severity by name in JSON and YAML output.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ParseSeverity parses a severity name (case-insensitive).
+func ParseSeverity(name string) (Severity, error) {
+	for s, n := range severityNames {
+		if strings.EqualFold(name, n) {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q (choose: low, medium, high, critical)", name)
+}
+
+// Rule is one risky setting the audit looks for.
+type Rule struct {
+	ID          string
+	Name        string
+	Severity    Severity
+	Description string
+	Remediation string
+	// Check returns one message per occurrence of the risky setting.
+	Check func(c types.ContainerJSON) []string
+}
+
+// Finding is a rule violation in one container.
+type Finding struct {
+	RuleID      string   `json:"rule_id" yaml:"rule_id"`
+	Rule        string   `json:"rule" yaml:"rule"`
+	Severity    Severity `json:"severity" yaml:"severity"`
+	ContainerID string   `json:"container_id" yaml:"container_id"`
+	Container   string   `json:"container" yaml:"container"`
+	Message     string   `json:"message" yaml:"message"`
+	Remediation string   `json:"remediation" yaml:"remediation"`
+	ComposeFile string   `json:"compose_file,omitempty" yaml:"compose_file,omitempty"` // defining the container, if any
+}
+
+// Run applies rules to the containers and returns findings at or above
+// minSeverity, worst first, then by container name and rule order.
+func Run(containers []types.ContainerJSON, rules []Rule, minSeverity Severity) []Finding {
+	order := make(map[string]int, len(rules))
+	for i, r := range rules {
+		order[r.ID] = i
+	}
+
+	findings := []Finding{}
+	for _, c := range containers {
+		if c.ContainerJSONBase == nil {
+			continue
+		}
+		name := strings.TrimPrefix(c.Name, "/")
+		composeFile := ""
+		if c.Config != nil {
+			composeFile, _, _ = strings.Cut(c.Config.Labels[docker.ComposeConfigFilesLabel], ",")
+		}
+		for _, r := range rules {
+			if r.Severity < minSeverity {
+				continue
+			}
+			for _, msg := range r.Check(c) {
+				findings = append(findings, Finding{
+					RuleID:      r.ID,
+					Rule:        r.Name,
+					Severity:    r.Severity,
+					ContainerID: c.ID,
+					Container:   name,
+					Message:     msg,
+					Remediation: r.Remediation,
+					ComposeFile: composeFile,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if a.Container != b.Container {
+			return a.Container < b.Container
+		}
+		return order[a.RuleID] < order[b.RuleID]
+	})
+	return findings
+}
+
+// Summary counts findings by severity.
+type Summary struct {
+	Critical int `json:"critical" yaml:"critical"`
+	High     int `json:"high" yaml:"high"`
+	Medium   int `json:"medium" yaml:"medium"`
+	Low      int `json:"low" yaml:"low"`
+}
+
+// Summarize counts findings by severity.
+func Summarize(findings []Finding) Summary {
+	var s Summary
+	for _, f := range findings {
+		switch f.Severity {
+		case SeverityCritical:
+			s.Critical++
+		case SeverityHigh:
+			s.High++
+		case SeverityMedium:
+			s.Medium++
+		case SeverityLow:
+			s.Low++
+		}
+	}
+	return s
+}
@@ -0,0 +1,171 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// hardened returns a container that passes every rule.
+func hardened(name string) types.ContainerJSON {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:   name + "-id",
+			Name: "/" + name,
+			HostConfig: &container.HostConfig{
+				NetworkMode:    "bridge",
+				ReadonlyRootfs: true,
+				Resources:      container.Resources{Memory: 256 << 20},
+			},
+		},
+		Config: &container.Config{User: "1000:1000"},
+		NetworkSettings: &types.NetworkSettings{NetworkSettingsBase: types.NetworkSettingsBase{
+			Ports: nat.PortMap{"80/tcp": {{HostIP: "127.0.0.1", HostPort: "8080"}}},
+		}},
+	}
+}
+
+func ruleIDs(findings []Finding) []string {
+	ids := make([]string, len(findings))
+	for i, f := range findings {
+		ids[i] = f.Container + "/" + f.RuleID
+	}
+	return ids
+}
+
+func TestRunHardenedContainer(t *testing.T) {
+	findings := Run([]types.ContainerJSON{hardened("safe")}, Rules(), SeverityLow)
+	assert.Empty(t, findings)
+}
+
+func TestRunFindsRiskySettings(t *testing.T) {
+	risky := hardened("risky")
+	risky.HostConfig.Privileged = true
+	risky.HostConfig.NetworkMode = "host"
+	risky.HostConfig.PidMode = "host"
+	risky.HostConfig.CapAdd = []string{"NET_ADMIN", "SYS_TIME"}
+	risky.HostConfig.ReadonlyRootfs = false
+	risky.HostConfig.Memory = 0
+	risky.Config.User = ""
+	risky.Mounts = []types.MountPoint{{Source: "/var/run/docker.sock", Destination: "/var/run/docker.sock", RW: true}}
+	risky.NetworkSettings.Ports = nat.PortMap{
+		"80/tcp":  {{HostIP: "0.0.0.0", HostPort: "80"}, {HostIP: "::", HostPort: "80"}},
+		"443/tcp": {{HostIP: "127.0.0.1", HostPort: "443"}},
+	}
+
+	root := hardened("alpha")
+	root.Config.User = "0:0"
+
+	findings := Run([]types.ContainerJSON{root, risky}, Rules(), SeverityLow)
+
+	assert.Equal(t, []string{
+		"risky/privileged",
+		"risky/docker-socket",
+		"risky/host-network",
+		"risky/host-pid",
+		"alpha/root-user",
+		"risky/cap-add",
+		"risky/root-user",
+		"risky/public-ports",
+		"risky/writable-rootfs",
+		"risky/no-memory-limit",
+	}, ruleIDs(findings))
+
+	byRule := make(map[string]Finding)
+	for _, f := range findings {
+		byRule[f.Container+"/"+f.RuleID] = f
+	}
+	assert.Equal(t, "adds capabilities: NET_ADMIN, SYS_TIME", byRule["risky/cap-add"].Message)
+	assert.Equal(t, "publishes 0.0.0.0:80->80/tcp, [::]:80->80/tcp", byRule["risky/public-ports"].Message)
+	assert.Equal(t, "mounts /var/run/docker.sock at /var/run/docker.sock (read-write)", byRule["risky/docker-socket"].Message)
+
+	assert.Equal(t, Summary{Critical: 2, High: 2, Medium: 4, Low: 2}, Summarize(findings))
+}
+
+func TestRunMinSeverity(t *testing.T) {
+	c := hardened("app")
+	c.HostConfig.Privileged = true
+	c.HostConfig.Memory = 0
+
+	findings := Run([]types.ContainerJSON{c}, Rules(), SeverityHigh)
+	assert.Equal(t, []string{"app/privileged"}, ruleIDs(findings))
+}
+
+func TestParseSeverity(t *testing.T) {
+	s, err := ParseSeverity("HIGH")
+	require.NoError(t, err)
+	assert.Equal(t, SeverityHigh, s)
+
+	_, err = ParseSeverity("severe")
+	assert.Error(t, err)
+
+	data, err := json.Marshal(SeverityCritical)
+	require.NoError(t, err)
+	assert.Equal(t, `"critical"`, string(data))
+}
+
+func TestWriteSARIF(t *testing.T) {
+	c := hardened("web")
+	c.HostConfig.Privileged = true
+	c.HostConfig.Memory = 0
+	rules := Rules()
+	findings := Run([]types.ContainerJSON{c}, rules, SeverityLow)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSARIF(&buf, findings, rules, "1.2.3"))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, "octo", run.Tool.Driver.Name)
+	assert.Equal(t, "1.2.3", run.Tool.Driver.Version)
+	assert.Len(t, run.Tool.Driver.Rules, len(rules))
+
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, "privileged", run.Results[0].RuleID)
+	assert.Equal(t, "error", run.Results[0].Level)
+	assert.Equal(t, "privileged", run.Tool.Driver.Rules[run.Results[0].RuleIndex].ID)
+	assert.Equal(t, "web: runs in privileged mode", run.Results[0].Message.Text)
+	assert.Equal(t, "web", run.Results[0].Locations[0].LogicalLocations[0].Name)
+	assert.Equal(t, "container/web", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "note", run.Results[1].Level)
+}
+
+func TestWriteSARIFLocatesComposeFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	c := hardened("shop-web-1")
+	c.HostConfig.Privileged = true
+	c.Config.Labels = map[string]string{
+		docker.ComposeConfigFilesLabel: filepath.Join(dir, "deploy", "compose.yaml") + "," + filepath.Join(dir, "compose.override.yaml"),
+	}
+	other := hardened("elsewhere")
+	other.HostConfig.Privileged = true
+	other.Config.Labels = map[string]string{docker.ComposeConfigFilesLabel: "/srv/shop/compose.yaml"}
+	rules := Rules()
+	findings := Run([]types.ContainerJSON{c, other}, rules, SeverityCritical)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSARIF(&buf, findings, rules, "1.2.3"))
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	results := log.Runs[0].Results
+	require.Len(t, results, 2)
+	uris := []string{
+		results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI,
+		results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI,
+	}
+	assert.Equal(t, []string{"file:///srv/shop/compose.yaml", "deploy/compose.yaml"}, uris)
+	assert.Equal(t, 1, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+}
@@ -0,0 +1,188 @@
+package audit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// dockerSockets are the host paths of the Docker API socket.
+var dockerSockets = []string{"/var/run/docker.sock", "/run/docker.sock"}
+
+// Rules returns the built-in audit rules, most severe first.
+func Rules() []Rule {
+	return []Rule{
+		{
+			ID:          "privileged",
+			Name:        "Privileged container",
+			Severity:    SeverityCritical,
+			Description: "Privileged containers have full access to host devices and kernel capabilities.",
+			Remediation: "Drop --privileged and grant only the capabilities or devices the container needs",
+			Check:       checkPrivileged,
+		},
+		{
+			ID:          "docker-socket",
+			Name:        "Docker socket mounted",
+			Severity:    SeverityCritical,
+			Description: "Access to the Docker socket is equivalent to root on the host.",
+			Remediation: "Remove the socket mount or put a filtering proxy in front of the Docker API",
+			Check:       checkDockerSocket,
+		},
+		{
+			ID:          "host-network",
+			Name:        "Host network namespace",
+			Severity:    SeverityHigh,
+			Description: "The container shares the host's network stack and can bind or sniff any host interface.",
+			Remediation: "Use a bridge or user-defined network and publish only the ports needed",
+			Check:       checkHostNetwork,
+		},
+		{
+			ID:          "host-pid",
+			Name:        "Host PID namespace",
+			Severity:    SeverityHigh,
+			Description: "The container can see and signal every process on the host.",
+			Remediation: "Remove --pid=host",
+			Check:       checkHostPID,
+		},
+		{
+			ID:          "cap-add",
+			Name:        "Added capabilities",
+			Severity:    SeverityMedium,
+			Description: "Capabilities beyond Docker's default set widen what a compromised process can do.",
+			Remediation: "Remove --cap-add entries the workload does not strictly need",
+			Check:       checkCapAdd,
+		},
+		{
+			ID:          "root-user",
+			Name:        "Running as root",
+			Severity:    SeverityMedium,
+			Description: "The main process runs as UID 0 inside the container.",
+			Remediation: "Set USER in the image or run with --user to a non-root UID",
+			Check:       checkRootUser,
+		},
+		{
+			ID:          "public-ports",
+			Name:        "Ports bound to all interfaces",
+			Severity:    SeverityMedium,
+			Description: "Published ports listen on every host interface, including public ones.",
+			Remediation: "Bind published ports to a specific address such as 127.0.0.1:PORT:PORT",
+			Check:       checkPublicPorts,
+		},
+		{
+			ID:          "writable-rootfs",
+			Name:        "Writable root filesystem",
+			Severity:    SeverityLow,
+			Description: "The container can modify its own filesystem, which helps an attacker persist.",
+			Remediation: "Run with --read-only and mount writable volumes or tmpfs where needed",
+			Check:       checkWritableRootfs,
+		},
+		{
+			ID:          "no-memory-limit",
+			Name:        "No memory limit",
+			Severity:    SeverityLow,
+			Description: "Without a limit a runaway container can exhaust host memory.",
+			Remediation: "Set a memory limit with --memory or deploy.resources.limits.memory",
+			Check:       checkMemoryLimit,
+		},
+	}
+}
+
+func checkPrivileged(c types.ContainerJSON) []string {
+	if c.HostConfig != nil && c.HostConfig.Privileged {
+		return []string{"runs in privileged mode"}
+	}
+	return nil
+}
+
+func checkDockerSocket(c types.ContainerJSON) []string {
+	var msgs []string
+	for _, m := range c.Mounts {
+		for _, sock := range dockerSockets {
+			if m.Source == sock {
+				mode := "read-write"
+				if !m.RW {
+					mode = "read-only"
+				}
+				msgs = append(msgs, fmt.Sprintf("mounts %s at %s (%s)", m.Source, m.Destination, mode))
+			}
+		}
+	}
+	return msgs
+}
+
+func checkHostNetwork(c types.ContainerJSON) []string {
+	if c.HostConfig != nil && c.HostConfig.NetworkMode.IsHost() {
+		return []string{"uses the host network namespace"}
+	}
+	return nil
+}
+
+func checkHostPID(c types.ContainerJSON) []string {
+	if c.HostConfig != nil && c.HostConfig.PidMode.IsHost() {
+		return []string{"uses the host PID namespace"}
+	}
+	return nil
+}
+
+func checkCapAdd(c types.ContainerJSON) []string {
+	if c.HostConfig == nil || len(c.HostConfig.CapAdd) == 0 {
+		return nil
+	}
+	return []string{"adds capabilities: " + strings.Join(c.HostConfig.CapAdd, ", ")}
+}
+
+func checkRootUser(c types.ContainerJSON) []string {
+	if c.Config == nil {
+		return nil
+	}
+	user, _, _ := strings.Cut(c.Config.User, ":")
+	if user == "" || user == "root" || user == "0" {
+		return []string{"runs as root"}
+	}
+	return nil
+}
+
+func checkPublicPorts(c types.ContainerJSON) []string {
+	if c.NetworkSettings == nil {
+		return nil
+	}
+	var ports []string
+	for port, bindings := range c.NetworkSettings.Ports {
+		for _, b := range bindings {
+			if b.HostIP == "" || b.HostIP == "0.0.0.0" || b.HostIP == "::" {
+				ports = append(ports, fmt.Sprintf("%s:%s->%s", hostIP(b.HostIP), b.HostPort, port))
+			}
+		}
+	}
+	if len(ports) == 0 {
+		return nil
+	}
+	sort.Strings(ports)
+	return []string{"publishes " + strings.Join(ports, ", ")}
+}
+
+func hostIP(ip string) string {
+	switch ip {
+	case "":
+		return "0.0.0.0"
+	case "::":
+		return "[::]"
+	}
+	return ip
+}
+
+func checkWritableRootfs(c types.ContainerJSON) []string {
+	if c.HostConfig != nil && !c.HostConfig.ReadonlyRootfs {
+		return []string{"root filesystem is writable"}
+	}
+	return nil
+}
+
+func checkMemoryLimit(c types.ContainerJSON) []string {
+	if c.HostConfig != nil && c.HostConfig.Memory == 0 {
+		return []string{"has no memory limit"}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolURI      = "https://github.com/bsisduck/octo"
+)
+
+// SARIF 2.1.0 subset: one run with the rule catalogue and one result per
+// finding, located by container name and, as code scanning requires a file,
+// by the container's Compose file (or a container/<name> URI outside
+// Compose).
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      sarifMessage       `json:"fullDescription"`
+	Help                 sarifMessage       `json:"help"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           sarifProperties    `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifProperties struct {
+	SecuritySeverity string   `json:"security-severity"`
+	Tags             []string `json:"tags"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// sarifLevel maps a severity to a SARIF result level.
+func sarifLevel(s Severity) string {
+	switch s {
+	case SeverityCritical, SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	}
+	return "note"
+}
+
+// securitySeverity maps a severity to the CVSS-like score that code
+// scanning dashboards use for ranking.
+func securitySeverity(s Severity) string {
+	switch s {
+	case SeverityCritical:
+		return "9.5"
+	case SeverityHigh:
+		return "8.0"
+	case SeverityMedium:
+		return "5.5"
+	}
+	return "2.0"
+}
+
+// artifactURI returns the file a finding is reported against: its Compose
+// file, relative to base when under it (a repository checkout in CI), else
+// a stable container/<name> URI.
+func artifactURI(f Finding, base string) string {
+	if f.ComposeFile == "" {
+		return "container/" + f.Container
+	}
+	if base != "" {
+		if rel, err := filepath.Rel(base, f.ComposeFile); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return "file://" + filepath.ToSlash(f.ComposeFile)
+}
+
+// WriteSARIF writes the findings as a SARIF 2.1.0 log. rules is the rule
+// catalogue the findings were produced from.
+func WriteSARIF(w io.Writer, findings []Finding, rules []Rule, toolVersion string) error {
+	index := make(map[string]int, len(rules))
+	sRules := make([]sarifRule, len(rules))
+	for i, r := range rules {
+		index[r.ID] = i
+		sRules[i] = sarifRule{
+			ID:                   r.ID,
+			Name:                 r.Name,
+			ShortDescription:     sarifMessage{Text: r.Name},
+			FullDescription:      sarifMessage{Text: r.Description},
+			Help:                 sarifMessage{Text: r.Remediation},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(r.Severity)},
+			Properties: sarifProperties{
+				SecuritySeverity: securitySeverity(r.Severity),
+				Tags:             []string{"security", "docker"},
+			},
+		}
+	}
+
+	base, _ := os.Getwd()
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		results = append(results, sarifResult{
+			RuleID:    f.RuleID,
+			RuleIndex: index[f.RuleID],
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: f.Container + ": " + f.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: artifactURI(f, base)},
+					Region:           sarifRegion{StartLine: 1},
+				},
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               f.Container,
+					FullyQualifiedName: "container/" + f.Container,
+					Kind:               "resource",
+				}},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "octo",
+				Version:        toolVersion,
+				InformationURI: toolURI,
+				Rules:          sRules,
+			}},
+			Results: results,
+		}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
@@ -0,0 +1,222 @@
+// Package config loads user settings from ~/.octo/config.yaml.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPath names the environment variable that overrides the config location.
+const EnvPath = "OCTO_CONFIG"
+
+// Config is the root of the configuration file. Every section is optional.
+type Config struct {
+	Analyze  AnalyzeConfig  `yaml:"analyze"`
+	Diagnose DiagnoseConfig `yaml:"diagnose"`
+	Disk     DiskConfig     `yaml:"disk"`
+	Keys     KeysConfig     `yaml:"keys"`
+	Theme    ThemeConfig    `yaml:"theme"`
+}
+
+// AnalyzeConfig holds the view settings of the analyze TUI. The TUI saves
+// it when the sort order or the columns change.
+type AnalyzeConfig struct {
+	Sort        string `yaml:"sort"` // name, size, created, cpu, memory or status
+	SortReverse bool   `yaml:"sort_reverse"`
+	// Columns lists the optional columns shown: id, created, ports, image
+	// and service. Nil keeps the default.
+	Columns []string `yaml:"columns"`
+}
+
+// KeysConfig configures the keybindings of the TUI.
+type KeysConfig struct {
+	Preset string `yaml:"preset"` // default, vim or emacs
+	// Bindings replaces the keys of single actions, e.g. stop: [T].
+	Bindings map[string][]string `yaml:"bindings"`
+}
+
+// ThemeConfig selects the color theme of the output and the TUI.
+type ThemeConfig struct {
+	// Name is auto (default), dark, light, high-contrast or the path of a
+	// theme file.
+	Name string `yaml:"name"`
+	// Colors replaces palette colors of the theme, e.g. primary: "#5f87ff".
+	Colors map[string]string `yaml:"colors"`
+}
+
+// DiskConfig configures the host disk checks of diagnose and status on the
+// filesystem backing Docker's data root. Zero values keep the defaults.
+type DiskConfig struct {
+	WarnPercent       float64 `yaml:"warn_percent"`  // used space, default 85
+	ErrorPercent      float64 `yaml:"error_percent"` // default 95
+	InodeWarnPercent  float64 `yaml:"inode_warn_percent"`
+	InodeErrorPercent float64 `yaml:"inode_error_percent"`
+	WarnDays          float64 `yaml:"warn_days"` // forecast full within, default 7
+	History           *bool   `yaml:"history"`   // record growth history, default true
+	HistoryFile       string  `yaml:"history_file"`
+}
+
+// DiagnoseConfig configures octo diagnose.
+type DiagnoseConfig struct {
+	Checks []CustomCheck `yaml:"checks"`
+	// DaemonConfig overrides the daemon.json path linted by the config checks.
+	DaemonConfig string `yaml:"daemon_config"`
+}
+
+// CustomCheck is a team-defined diagnostic. It either runs Command through
+// the shell (passing on exit status 0) or compares a system.Info field,
+// addressed by a dotted path such as "Swarm.LocalNodeState", against the
+// expectations that are set.
+type CustomCheck struct {
+	ID          string `yaml:"id"`
+	Name        string `yaml:"name"`
+	Category    string `yaml:"category"`
+	Severity    string `yaml:"severity"` // warn (default) or error
+	Remediation string `yaml:"remediation"`
+	Fix         string `yaml:"fix"` // shell command run by diagnose --fix
+
+	Command string `yaml:"command"`
+
+	Field     string   `yaml:"field"`
+	Equals    *string  `yaml:"equals"`
+	NotEquals *string  `yaml:"not_equals"`
+	Contains  *string  `yaml:"contains"`
+	Matches   *string  `yaml:"matches"`
+	Min       *float64 `yaml:"min"`
+	Max       *float64 `yaml:"max"`
+}
+
+// DefaultPath returns $OCTO_CONFIG if set, otherwise ~/.octo/config.yaml.
+func DefaultPath() string {
+	if p := os.Getenv(EnvPath); p != "" {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".octo", "config.yaml")
+	}
+	return filepath.Join(home, ".octo", "config.yaml")
+}
+
+// Load reads the config file at path, or DefaultPath when path is empty.
+// A missing file yields an empty config.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		path = DefaultPath()
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// SaveAnalyze writes the analyze section to the config file at path, or
+// DefaultPath when path is empty, creating the file if needed. The rest of
+// the file, comments included, is kept as is.
+func SaveAnalyze(path string, analyze AnalyzeConfig) error {
+	return saveSection(path, "analyze", analyze)
+}
+
+// saveSection replaces one top-level key of the config file. Only the lines
+// of that key are rewritten, in the file's indentation; the rest of the file
+// is kept byte for byte.
+func saveSection(path, key string, value any) error {
+	if path == "" {
+		path = DefaultPath()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing config %s: %w", path, err)
+	}
+
+	section, err := encodeSection(key, value, detectIndent(data))
+	if err != nil {
+		return fmt.Errorf("encoding %s config: %w", key, err)
+	}
+
+	// Without the key, the section is appended.
+	lines := strings.SplitAfter(string(data), "\n")
+	start, end := len(lines), len(lines)
+	if len(doc.Content) > 0 {
+		root := doc.Content[0]
+		if root.Kind != yaml.MappingNode {
+			return fmt.Errorf("config %s: top level is not a mapping", path)
+		}
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if root.Content[i].Value != key {
+				continue
+			}
+			start = root.Content[i].Line - 1
+			if i+2 < len(root.Content) {
+				end = root.Content[i+2].Line - 1
+			}
+			// Blank lines and top-level comments before the next key
+			// belong to it.
+			for end > start+1 && isSectionGap(lines[end-1]) {
+				end--
+			}
+			break
+		}
+	}
+	head := strings.Join(lines[:start], "")
+	if head != "" && !strings.HasSuffix(head, "\n") {
+		head += "\n"
+	}
+	out := head + section + strings.Join(lines[end:], "")
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(out), 0o644); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return nil
+}
+
+// encodeSection renders key: value as YAML indented by indent spaces.
+func encodeSection(key string, value any, indent int) (string, error) {
+	var b strings.Builder
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(indent)
+	if err := enc.Encode(map[string]any{key: value}); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// detectIndent returns the indentation of the first nested line of a YAML
+// file, or 2 when there is none.
+func detectIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if n := len(line) - len(trimmed); n > 0 && trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return max(n, 2)
+		}
+	}
+	return 2
+}
+
+// isSectionGap reports whether a line is blank or a top-level comment.
+func isSectionGap(line string) bool {
+	return strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#")
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Diagnose.Checks)
+}
+
+func TestLoadDiagnoseChecks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `diagnose:
+  checks:
+    - id: mirror
+      command: curl -sf https://mirror/v2/
+    - id: cgroup
+      field: CgroupVersion
+      equals: "2"
+      min: 1
+      severity: error
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, cfg.Diagnose.Checks, 2)
+	assert.Equal(t, "curl -sf https://mirror/v2/", cfg.Diagnose.Checks[0].Command)
+	c := cfg.Diagnose.Checks[1]
+	require.NotNil(t, c.Equals)
+	assert.Equal(t, "2", *c.Equals)
+	require.NotNil(t, c.Min)
+	assert.Equal(t, 1.0, *c.Min)
+	assert.Nil(t, c.Max)
+}
+
+func TestLoadDiskConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `disk:
+  warn_percent: 80
+  inode_error_percent: 98
+  history: false
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 80.0, cfg.Disk.WarnPercent)
+	assert.Equal(t, 98.0, cfg.Disk.InodeErrorPercent)
+	assert.Zero(t, cfg.Disk.ErrorPercent)
+	require.NotNil(t, cfg.Disk.History)
+	assert.False(t, *cfg.Disk.History)
+}
+
+func TestLoadKeysConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `keys:
+  preset: emacs
+  bindings:
+    stop: [T]
+    mark: [space, m]
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "emacs", cfg.Keys.Preset)
+	assert.Equal(t, []string{"T"}, cfg.Keys.Bindings["stop"])
+	assert.Equal(t, []string{"space", "m"}, cfg.Keys.Bindings["mark"])
+}
+
+func TestLoadThemeConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `theme:
+  name: light
+  colors:
+    primary: "#268bd2"
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "light", cfg.Theme.Name)
+	assert.Equal(t, map[string]string{"primary": "#268bd2"}, cfg.Theme.Colors)
+}
+
+func TestSaveAnalyze(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "octo", "config.yaml")
+	require.NoError(t, SaveAnalyze(path, AnalyzeConfig{Sort: "size", Columns: []string{"id"}}))
+
+	data := `# team settings
+disk:
+  warn_percent: 80 # earlier alerts
+analyze:
+  sort: name
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	require.NoError(t, SaveAnalyze(path, AnalyzeConfig{Sort: "cpu", SortReverse: true, Columns: []string{}}))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "cpu", cfg.Analyze.Sort)
+	assert.True(t, cfg.Analyze.SortReverse)
+	assert.NotNil(t, cfg.Analyze.Columns, "an empty list hides every optional column")
+	assert.Empty(t, cfg.Analyze.Columns)
+	assert.Equal(t, 80.0, cfg.Disk.WarnPercent)
+
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(saved), "# team settings")
+	assert.Contains(t, string(saved), "# earlier alerts")
+}
+
+func TestSaveAnalyzeKeepsOtherSections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	head := `# team settings
+disk:
+  warn_percent: 80 # earlier alerts
+  history: "~/.octo/disk.json"
+
+`
+	tail := `
+# custom checks
+diagnose:
+  checks:
+    - id: mirror
+      command: curl -sf https://mirror.internal/v2/
+
+theme:
+  name: light
+`
+	require.NoError(t, os.WriteFile(path, []byte(head+"analyze:\n  sort: name\n  columns: [id, size]\n"+tail), 0o644))
+	require.NoError(t, SaveAnalyze(path, AnalyzeConfig{Sort: "cpu", Columns: []string{"id"}}))
+
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, head+"analyze:\n  sort: cpu\n  sort_reverse: false\n  columns:\n    - id\n"+tail, string(saved))
+
+	// The file's indentation is kept for the rewritten section.
+	require.NoError(t, os.WriteFile(path, []byte("disk:\n    warn_percent: 80\n"), 0o644))
+	require.NoError(t, SaveAnalyze(path, AnalyzeConfig{Sort: "size"}))
+	saved, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "disk:\n    warn_percent: 80\nanalyze:\n    sort: size\n    sort_reverse: false\n    columns: []\n", string(saved))
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("diagnose: [oops"), 0o644))
+	_, err := Load(path)
+	assert.Error(t, err)
+}
+
+func TestDefaultPathEnv(t *testing.T) {
+	t.Setenv(EnvPath, "/tmp/custom.yaml")
+	assert.Equal(t, "/tmp/custom.yaml", DefaultPath())
+}
@@ -0,0 +1,289 @@
+package diagnose
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// Check categories used by the built-in checks.
+const (
+	CategoryDaemon      = "daemon"
+	CategoryStorage     = "storage"
+	CategoryDisk        = "disk"
+	CategoryResources   = "resources"
+	CategoryPerformance = "performance"
+)
+
+// connectionCheck verifies the daemon is reachable. When the connection
+// fails, Run reports it on the check's behalf.
+var connectionCheck = Check{
+	ID:          "connection",
+	Name:        "Docker Connection",
+	Category:    CategoryDaemon,
+	Severity:    StatusError,
+	Remediation: "Make sure the Docker daemon is running and DOCKER_HOST points at it",
+	Run: func(context.Context, *Env) Result {
+		return OK("OK", "Connected to Docker daemon")
+	},
+}
+
+func connectionFailure(err error) Result {
+	r := Error("FAILED", "Cannot connect to Docker daemon", err.Error())
+	r.ID, r.Name, r.Category = connectionCheck.ID, connectionCheck.Name, connectionCheck.Category
+	r.Remediation = connectionCheck.Remediation
+	return r
+}
+
+// Builtin returns the standard checks in the order they run.
+func Builtin() []Check {
+	checks := daemonChecks()
+	checks = append(checks, daemonConfigChecks()...)
+	return append(checks, containerChecks()...)
+}
+
+// daemonChecks returns the checks on the daemon and its resources.
+func daemonChecks() []Check {
+	return []Check{
+		connectionCheck,
+		{
+			ID:       "version",
+			Name:     "Docker Version",
+			Category: CategoryDaemon,
+			Severity: StatusError,
+			Run:      checkVersion,
+		},
+		{
+			ID:          "daemon-mode",
+			Name:        "Daemon Mode",
+			Category:    CategoryDaemon,
+			Severity:    StatusWarn,
+			Remediation: "Some operations may behave differently in Swarm mode",
+			Run:         checkDaemonMode,
+		},
+		{
+			ID:          "storage-driver",
+			Name:        "Storage Driver",
+			Category:    CategoryStorage,
+			Severity:    StatusWarn,
+			Remediation: "Consider using overlay2 for better performance",
+			Run:         checkStorageDriver,
+		},
+		{
+			ID:          "disk-usage",
+			Name:        "Disk Usage",
+			Category:    CategoryDisk,
+			Severity:    StatusError,
+			Remediation: "Consider running 'octo cleanup' or 'octo prune'",
+			Run:         checkDiskUsage,
+			Fix:         pruneBuildCacheFix,
+		},
+		{
+			ID:          "host-disk",
+			Name:        "Host Disk Space",
+			Category:    CategoryDisk,
+			Severity:    StatusError,
+			Remediation: "Free space on the filesystem holding the Docker data root ('octo cleanup', 'octo prune') or grow it",
+			Run:         checkHostDisk,
+		},
+		{
+			ID:          "host-inodes",
+			Name:        "Host Inodes",
+			Category:    CategoryDisk,
+			Severity:    StatusError,
+			Remediation: "Remove unused images and build cache; many small layers exhaust inodes before space",
+			Run:         checkHostInodes,
+		},
+		{
+			ID:          "containers",
+			Name:        "Containers",
+			Category:    CategoryResources,
+			Severity:    StatusWarn,
+			Remediation: "Consider removing unused stopped containers with 'octo cleanup --containers'",
+			Run:         checkContainers,
+			Fix:         pruneContainersFix,
+		},
+		{
+			ID:          "dangling-images",
+			Name:        "Dangling Images",
+			Category:    CategoryDisk,
+			Severity:    StatusWarn,
+			Remediation: "Run 'octo cleanup --images' to remove dangling images",
+			Run:         checkDanglingImages,
+			Fix:         pruneImagesFix,
+		},
+		{
+			ID:          "unused-volumes",
+			Name:        "Unused Volumes",
+			Category:    CategoryDisk,
+			Severity:    StatusWarn,
+			Remediation: "Run 'octo cleanup --volumes' to remove unused volumes",
+			Run:         checkUnusedVolumes,
+			Fix:         pruneVolumesFix,
+		},
+		{
+			ID:          "api-response",
+			Name:        "API Response",
+			Category:    CategoryPerformance,
+			Severity:    StatusError,
+			Remediation: "The Docker daemon may be overloaded or unresponsive",
+			Run:         checkAPIResponse,
+		},
+		{
+			ID:          "memory",
+			Name:        "Memory",
+			Category:    CategoryResources,
+			Severity:    StatusWarn,
+			Remediation: "Consider increasing Docker Desktop memory allocation",
+			Run:         checkMemory,
+		},
+	}
+}
+
+func checkVersion(ctx context.Context, env *Env) Result {
+	info, err := env.Info(ctx)
+	if err != nil {
+		return Error("FAILED", "Cannot get Docker version", err.Error())
+	}
+	r := OK(info.ServerVersion, fmt.Sprintf("Docker %s", info.ServerVersion))
+	r.Details = fmt.Sprintf("OS: %s, Arch: %s", info.OperatingSystem, info.Architecture)
+	return r
+}
+
+func checkDaemonMode(ctx context.Context, env *Env) Result {
+	info, err := env.Info(ctx)
+	if err != nil {
+		return Error("FAILED", "Cannot get daemon info", err.Error())
+	}
+	if info.Swarm.LocalNodeState == "active" {
+		return Warn("SWARM MODE", "Docker is running in Swarm mode", "")
+	}
+	return OK("STANDALONE", "Docker is running in standalone mode")
+}
+
+func checkStorageDriver(ctx context.Context, env *Env) Result {
+	info, err := env.Info(ctx)
+	if err != nil {
+		return Error("FAILED", "Cannot get daemon info", err.Error())
+	}
+	recommendedDrivers := map[string]bool{"overlay2": true, "btrfs": true}
+	if recommendedDrivers[info.Driver] {
+		return OK(info.Driver, fmt.Sprintf("Using %s (recommended)", info.Driver))
+	}
+	return Warn(info.Driver, fmt.Sprintf("Using %s", info.Driver), "")
+}
+
+func checkDiskUsage(ctx context.Context, env *Env) Result {
+	client, _ := env.Docker()
+	ctx, cancel := context.WithTimeout(ctx, docker.TimeoutDiskUsage)
+	defer cancel()
+	diskUsage, err := client.GetDiskUsage(ctx)
+	if err != nil {
+		return Error("FAILED", "Cannot get disk usage", err.Error())
+	}
+	var reclaimablePercent float64
+	if diskUsage.Total > 0 {
+		reclaimablePercent = float64(diskUsage.TotalReclaimable) / float64(diskUsage.Total) * 100
+	}
+	total, reclaimable := humanize.Bytes(uint64(diskUsage.Total)), humanize.Bytes(uint64(diskUsage.TotalReclaimable))
+	if reclaimablePercent > 50 {
+		return Warn(fmt.Sprintf("%.0f%% reclaimable", reclaimablePercent),
+			fmt.Sprintf("%s used, %s reclaimable (%.0f%%)", total, reclaimable, reclaimablePercent), "")
+	}
+	return OK(total, fmt.Sprintf("%s used, %s reclaimable", total, reclaimable))
+}
+
+func checkContainers(ctx context.Context, env *Env) Result {
+	containers, err := env.Containers(ctx)
+	if err != nil {
+		return Error("FAILED", "Cannot list containers", err.Error())
+	}
+	running, stopped := 0, 0
+	for _, c := range containers {
+		if c.State == "running" {
+			running++
+		} else {
+			stopped++
+		}
+	}
+	message := fmt.Sprintf("%d running, %d stopped", running, stopped)
+	if stopped > 10 {
+		return Warn(fmt.Sprintf("%d stopped", stopped), message, "")
+	}
+	return OK(fmt.Sprintf("%d running", running), message)
+}
+
+func checkDanglingImages(ctx context.Context, env *Env) Result {
+	client, _ := env.Docker()
+	ctx, cancel := context.WithTimeout(ctx, docker.TimeoutList)
+	defer cancel()
+	danglingImages, err := client.GetDanglingImages(ctx)
+	if err != nil {
+		return Error("FAILED", "Cannot list dangling images", err.Error())
+	}
+	if len(danglingImages) > 5 {
+		var totalSize int64
+		for _, img := range danglingImages {
+			totalSize += img.Size
+		}
+		size := humanize.Bytes(uint64(totalSize))
+		return Warn(fmt.Sprintf("%d (%s)", len(danglingImages), size),
+			fmt.Sprintf("%d dangling images (%s)", len(danglingImages), size), "")
+	}
+	return OK(fmt.Sprintf("%d", len(danglingImages)), fmt.Sprintf("%d dangling images", len(danglingImages)))
+}
+
+func checkUnusedVolumes(ctx context.Context, env *Env) Result {
+	client, _ := env.Docker()
+	ctx, cancel := context.WithTimeout(ctx, docker.TimeoutList)
+	defer cancel()
+	unusedVolumes, err := client.GetUnusedVolumes(ctx)
+	if err != nil {
+		return Error("FAILED", "Cannot list unused volumes", err.Error())
+	}
+	if len(unusedVolumes) > 5 {
+		return Warn(fmt.Sprintf("%d", len(unusedVolumes)), fmt.Sprintf("%d unused volumes", len(unusedVolumes)), "")
+	}
+	return OK(fmt.Sprintf("%d", len(unusedVolumes)), fmt.Sprintf("%d unused volumes", len(unusedVolumes)))
+}
+
+func checkAPIResponse(ctx context.Context, env *Env) Result {
+	client, _ := env.Docker()
+	start := time.Now()
+	pingCtx, cancel := context.WithTimeout(ctx, docker.TimeoutPing)
+	err := client.Ping(pingCtx)
+	cancel()
+	elapsed := time.Since(start)
+	if err != nil {
+		return Error("TIMEOUT", "Docker API timed out", err.Error())
+	}
+	message := fmt.Sprintf("API response time: %dms", elapsed.Milliseconds())
+	if elapsed > 500*time.Millisecond {
+		return Warn(fmt.Sprintf("%dms", elapsed.Milliseconds()), message, "Docker daemon may be under heavy load")
+	}
+	return OK(fmt.Sprintf("%dms", elapsed.Milliseconds()), message)
+}
+
+func checkMemory(ctx context.Context, env *Env) Result {
+	if runtime.GOOS != "darwin" && runtime.GOOS != "windows" {
+		return OK("Native", "Running on native Linux (no VM overhead)")
+	}
+	info, err := env.Info(ctx)
+	if err != nil {
+		return Error("FAILED", "Cannot get daemon info", err.Error())
+	}
+	if info.MemTotal <= 0 {
+		return OK("N/A", "Memory allocation not reported by Docker")
+	}
+	memGB := float64(info.MemTotal) / (1024 * 1024 * 1024)
+	message := fmt.Sprintf("%.1fGB allocated to Docker", memGB)
+	if memGB < 2 {
+		return Warn(fmt.Sprintf("%.1fGB allocated", memGB), message, "")
+	}
+	return OK(fmt.Sprintf("%.1fGB", memGB), message)
+}
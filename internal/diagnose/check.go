@@ -0,0 +1,260 @@
+// Package diagnose implements the checks run by octo diagnose as a registry
+// of named, categorized checks that produce uniform results.
+package diagnose
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	StatusOK    Status = "ok"
+	StatusWarn  Status = "warn"
+	StatusError Status = "error"
+)
+
+// rank orders statuses from best to worst.
+func (s Status) rank() int {
+	switch s {
+	case StatusWarn:
+		return 1
+	case StatusError:
+		return 2
+	}
+	return 0
+}
+
+// AtLeast reports whether s is as bad as or worse than threshold.
+func (s Status) AtLeast(threshold Status) bool {
+	return s.rank() >= threshold.rank()
+}
+
+// ParseThreshold parses a --fail-on value: warn or error.
+func ParseThreshold(name string) (Status, error) {
+	switch strings.ToLower(name) {
+	case "warn", "warning":
+		return StatusWarn, nil
+	case "error":
+		return StatusError, nil
+	}
+	return "", fmt.Errorf("invalid threshold %q (choose: warn, error)", name)
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	ID          string `json:"id" yaml:"id"`
+	Name        string `json:"name" yaml:"name"`
+	Category    string `json:"category" yaml:"category"`
+	Status      Status `json:"status" yaml:"status"`
+	Message     string `json:"message" yaml:"message"`
+	Details     string `json:"details,omitempty" yaml:"details,omitempty"`
+	Remediation string `json:"remediation,omitempty" yaml:"remediation,omitempty"`
+
+	// Short is the value shown on the progress line ("OK", "overlay2", ...).
+	Short string `json:"-" yaml:"-"`
+	// Duration is how long the check took to run.
+	Duration time.Duration `json:"-" yaml:"-"`
+}
+
+// OK builds a passing result.
+func OK(short, message string) Result {
+	return Result{Status: StatusOK, Short: short, Message: message}
+}
+
+// Warn builds a warning result.
+func Warn(short, message, details string) Result {
+	return Result{Status: StatusWarn, Short: short, Message: message, Details: details}
+}
+
+// Error builds an error result.
+func Error(short, message, details string) Result {
+	return Result{Status: StatusError, Short: short, Message: message, Details: details}
+}
+
+// Check is a named diagnostic.
+type Check struct {
+	ID          string
+	Name        string
+	Category    string
+	Severity    Status // worst status the check reports
+	Remediation string // how to fix a failure; attached to non-ok results
+	Local       bool   // runs without a Docker connection
+	Run         func(ctx context.Context, env *Env) Result
+	Fix         *Fix // optional remediation applied by diagnose --fix
+}
+
+// Registry is an ordered set of checks.
+type Registry struct {
+	checks []Check
+	byID   map[string]int
+}
+
+// NewRegistry creates a registry with the given checks.
+func NewRegistry(checks ...Check) *Registry {
+	r := &Registry{byID: make(map[string]int)}
+	for _, c := range checks {
+		_ = r.Register(c)
+	}
+	return r
+}
+
+// Register adds a check. IDs must be unique.
+func (r *Registry) Register(c Check) error {
+	if c.ID == "" || c.Run == nil {
+		return fmt.Errorf("check %q: id and run function are required", c.Name)
+	}
+	if _, dup := r.byID[c.ID]; dup {
+		return fmt.Errorf("check %q is already registered", c.ID)
+	}
+	if c.Name == "" {
+		c.Name = c.ID
+	}
+	if c.Severity == "" {
+		c.Severity = StatusWarn
+	}
+	r.byID[c.ID] = len(r.checks)
+	r.checks = append(r.checks, c)
+	return nil
+}
+
+// Checks returns the registered checks in registration order.
+func (r *Registry) Checks() []Check {
+	return append([]Check(nil), r.checks...)
+}
+
+// Categories returns the distinct categories, sorted.
+func (r *Registry) Categories() []string {
+	seen := make(map[string]bool)
+	var cats []string
+	for _, c := range r.checks {
+		if !seen[c.Category] {
+			seen[c.Category] = true
+			cats = append(cats, c.Category)
+		}
+	}
+	sort.Strings(cats)
+	return cats
+}
+
+// Select returns the checks matching include (all when empty) minus those
+// matching exclude, in registration order. Names match check IDs or
+// categories; unknown names are an error.
+func (r *Registry) Select(include, exclude []string) ([]Check, error) {
+	inc, err := r.resolve(include)
+	if err != nil {
+		return nil, err
+	}
+	exc, err := r.resolve(exclude)
+	if err != nil {
+		return nil, err
+	}
+	var selected []Check
+	for _, c := range r.checks {
+		if (len(include) == 0 || inc[c.ID]) && !exc[c.ID] {
+			selected = append(selected, c)
+		}
+	}
+	return selected, nil
+}
+
+// resolve expands check IDs and category names into a set of check IDs.
+func (r *Registry) resolve(names []string) (map[string]bool, error) {
+	ids := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		matched := false
+		for _, c := range r.checks {
+			if c.ID == name || c.Category == name {
+				ids[c.ID] = true
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("unknown check or category %q (see --list)", name)
+		}
+	}
+	return ids, nil
+}
+
+// Run executes checks in order. Checks needing Docker are skipped when the
+// connection fails; the failure is reported once as the "connection"
+// result. progress, when non-nil, is called before each check runs and
+// with each result.
+func Run(ctx context.Context, env *Env, checks []Check, progress func(c Check, r *Result)) []Result {
+	results := []Result{}
+	connReported := false
+	for _, c := range checks {
+		if !c.Local {
+			if _, err := env.Docker(); err != nil {
+				if !connReported {
+					connReported = true
+					r := connectionFailure(err)
+					if progress != nil {
+						progress(connectionCheck, nil)
+						progress(connectionCheck, &r)
+					}
+					results = append(results, r)
+				}
+				continue
+			}
+		}
+		if progress != nil {
+			progress(c, nil)
+		}
+		start := time.Now()
+		r := c.Run(ctx, env)
+		r.Duration = time.Since(start)
+		r.ID, r.Name, r.Category = c.ID, c.Name, c.Category
+		if r.Status != StatusOK && r.Remediation == "" {
+			r.Remediation = c.Remediation
+		}
+		if progress != nil {
+			progress(c, &r)
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// Summary counts results by status.
+type Summary struct {
+	Passed   int `json:"passed" yaml:"passed"`
+	Warnings int `json:"warnings" yaml:"warnings"`
+	Errors   int `json:"errors" yaml:"errors"`
+}
+
+// Failed returns the results at or above threshold.
+func Failed(results []Result, threshold Status) []Result {
+	var failed []Result
+	for _, r := range results {
+		if r.Status != StatusOK && r.Status.AtLeast(threshold) {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// Summarize counts results by status.
+func Summarize(results []Result) Summary {
+	var s Summary
+	for _, r := range results {
+		switch r.Status {
+		case StatusOK:
+			s.Passed++
+		case StatusWarn:
+			s.Warnings++
+		case StatusError:
+			s.Errors++
+		}
+	}
+	return s
+}
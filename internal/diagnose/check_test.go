@@ -0,0 +1,161 @@
+package diagnose
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+func okCheck(id, category string) Check {
+	return Check{
+		ID:       id,
+		Category: category,
+		Run:      func(context.Context, *Env) Result { return OK("OK", id) },
+	}
+}
+
+func ids(checks []Check) []string {
+	out := make([]string, len(checks))
+	for i, c := range checks {
+		out[i] = c.ID
+	}
+	return out
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := NewRegistry(okCheck("a", "x"))
+
+	assert.Error(t, r.Register(okCheck("a", "y")), "duplicate id")
+	assert.Error(t, r.Register(Check{ID: "b"}), "missing run function")
+	require.NoError(t, r.Register(okCheck("b", "y")))
+
+	checks := r.Checks()
+	assert.Equal(t, []string{"a", "b"}, ids(checks))
+	assert.Equal(t, "b", checks[1].Name, "name defaults to id")
+	assert.Equal(t, StatusWarn, checks[1].Severity, "severity defaults to warn")
+	assert.Equal(t, []string{"x", "y"}, r.Categories())
+}
+
+func TestRegistrySelect(t *testing.T) {
+	r := NewRegistry(okCheck("a", "disk"), okCheck("b", "storage"), okCheck("c", "disk"), okCheck("d", "daemon"))
+
+	tests := []struct {
+		name             string
+		include, exclude []string
+		want             []string
+	}{
+		{"all", nil, nil, []string{"a", "b", "c", "d"}},
+		{"by id", []string{"d", "a"}, nil, []string{"a", "d"}},
+		{"by category", []string{"storage", "disk"}, nil, []string{"a", "b", "c"}},
+		{"skip category", nil, []string{"disk"}, []string{"b", "d"}},
+		{"include and skip", []string{"disk"}, []string{"c"}, []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Select(tt.include, tt.exclude)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ids(got))
+		})
+	}
+
+	_, err := r.Select([]string{"nope"}, nil)
+	assert.ErrorContains(t, err, `unknown check or category "nope"`)
+	_, err = r.Select(nil, []string{"nope"})
+	assert.Error(t, err)
+}
+
+func TestRunFillsResultFields(t *testing.T) {
+	env := NewEnvWithClient(&docker.MockDockerService{})
+	checks := []Check{
+		{
+			ID: "w", Name: "Warns", Category: "cat", Remediation: "fix it",
+			Run: func(context.Context, *Env) Result { return Warn("W", "warned", "") },
+		},
+		{
+			ID: "o", Name: "Passes", Category: "cat", Remediation: "unused",
+			Run: func(context.Context, *Env) Result { return OK("OK", "fine") },
+		},
+	}
+
+	var progress []string
+	results := Run(context.Background(), env, checks, func(c Check, r *Result) {
+		if r == nil {
+			progress = append(progress, "start:"+c.ID)
+		} else {
+			progress = append(progress, "done:"+r.ID)
+		}
+	})
+
+	require.Len(t, results, 2)
+	results[0].Duration = 0
+	assert.Equal(t, Result{ID: "w", Name: "Warns", Category: "cat", Status: StatusWarn, Message: "warned", Remediation: "fix it", Short: "W"}, results[0])
+	assert.Empty(t, results[1].Remediation, "remediation is only attached to failures")
+	assert.Equal(t, []string{"start:w", "done:w", "start:o", "done:o"}, progress)
+	assert.Equal(t, Summary{Passed: 1, Warnings: 1}, Summarize(results))
+}
+
+func TestRunConnectionFailure(t *testing.T) {
+	env := NewEnv(func() (docker.DockerService, error) { return nil, errors.New("no daemon") })
+	local := okCheck("local", "custom")
+	local.Local = true
+
+	results := Run(context.Background(), env, []Check{okCheck("a", "x"), local, okCheck("b", "x")}, nil)
+
+	require.Len(t, results, 2)
+	assert.Equal(t, "connection", results[0].ID)
+	assert.Equal(t, StatusError, results[0].Status)
+	assert.Equal(t, "no daemon", results[0].Details)
+	assert.Equal(t, "local", results[1].ID, "local checks still run")
+}
+
+func TestBuiltinChecks(t *testing.T) {
+	mock := &docker.MockDockerService{
+		GetServerInfoFn: func(context.Context) (system.Info, error) {
+			return system.Info{ServerVersion: "27.0.1", Driver: "vfs"}, nil
+		},
+		ListContainersFn: func(context.Context, bool) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{{State: "running"}, {State: "exited"}}, nil
+		},
+		GetDiskUsageFn: func(context.Context) (*docker.DiskUsageInfo, error) {
+			return &docker.DiskUsageInfo{Total: 100, TotalReclaimable: 10}, nil
+		},
+	}
+	env := NewEnvWithClient(mock)
+
+	results := Run(context.Background(), env, Builtin(), nil)
+	byID := make(map[string]Result)
+	for _, r := range results {
+		byID[r.ID] = r
+	}
+
+	assert.Len(t, results, len(Builtin()))
+	assert.Equal(t, StatusOK, byID["version"].Status)
+	assert.Equal(t, "Docker 27.0.1", byID["version"].Message)
+	assert.Equal(t, StatusWarn, byID["storage-driver"].Status)
+	assert.Equal(t, "Consider using overlay2 for better performance", byID["storage-driver"].Remediation)
+	assert.Equal(t, "1 running, 1 stopped", byID["containers"].Message)
+	assert.Equal(t, StatusOK, byID["disk-usage"].Status)
+}
+
+func TestThresholds(t *testing.T) {
+	warn, err := ParseThreshold("WARN")
+	require.NoError(t, err)
+	assert.Equal(t, StatusWarn, warn)
+	_, err = ParseThreshold("fatal")
+	assert.Error(t, err)
+
+	assert.True(t, StatusError.AtLeast(StatusWarn))
+	assert.False(t, StatusWarn.AtLeast(StatusError))
+
+	results := []Result{{ID: "a", Status: StatusOK}, {ID: "b", Status: StatusWarn}, {ID: "c", Status: StatusError}}
+	assert.Len(t, Failed(results, StatusWarn), 2)
+	failed := Failed(results, StatusError)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "c", failed[0].ID)
+}
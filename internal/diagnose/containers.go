@@ -0,0 +1,197 @@
+package diagnose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// CategoryContainers groups checks on individual containers.
+const CategoryContainers = "containers"
+
+// Thresholds for the container health checks.
+const (
+	RestartCountThreshold = 5              // restarts before a container counts as looping
+	RecentOOMWindow       = 24 * time.Hour // how far back an OOM kill is reported
+	maxProbeOutput        = 120            // characters of healthcheck output shown
+)
+
+// containerChecks returns the checks on container state, in the order they
+// run. They are part of Builtin.
+func containerChecks() []Check {
+	return []Check{
+		{
+			ID:          "restart-loops",
+			Name:        "Restart Loops",
+			Category:    CategoryContainers,
+			Severity:    StatusWarn,
+			Remediation: "Inspect the logs of the listed containers with 'octo logs <container>'",
+			Run:         checkRestartLoops,
+		},
+		{
+			ID:          "restarting",
+			Name:        "Restarting Containers",
+			Category:    CategoryContainers,
+			Severity:    StatusError,
+			Remediation: "The containers fail on start; check their logs and configuration",
+			Run:         checkRestarting,
+		},
+		{
+			ID:          "oom-killed",
+			Name:        "OOM Kills",
+			Category:    CategoryContainers,
+			Severity:    StatusError,
+			Remediation: "Raise the memory limit of the listed containers or reduce their memory use",
+			Run:         checkOOMKilled,
+		},
+		{
+			ID:          "exit-codes",
+			Name:        "Exit Codes",
+			Category:    CategoryContainers,
+			Severity:    StatusWarn,
+			Remediation: "Check the logs of the listed containers for the cause of the failure",
+			Run:         checkExitCodes,
+		},
+		{
+			ID:          "healthchecks",
+			Name:        "Healthchecks",
+			Category:    CategoryContainers,
+			Severity:    StatusError,
+			Remediation: "Fix the failing service or its HEALTHCHECK command",
+			Run:         checkHealthchecks,
+			Fix:         restartUnhealthyFix,
+		},
+		logsCheck,
+	}
+}
+
+// containerName returns the container's name without the leading slash.
+func containerName(c types.ContainerJSON) string {
+	return strings.TrimPrefix(c.Name, "/")
+}
+
+// flagContainers runs match on each inspected container and reports those
+// for which it returns a description. ok and bad build the summary message
+// from the number of flagged containers.
+func flagContainers(ctx context.Context, env *Env, severity Status, match func(types.ContainerJSON) (string, bool), ok string, bad func(n int) string) Result {
+	containers, err := env.InspectedContainers(ctx)
+	if err != nil {
+		return Error("FAILED", "Cannot inspect containers", err.Error())
+	}
+	var flagged []string
+	for _, c := range containers {
+		if c.ContainerJSONBase == nil || c.State == nil {
+			continue
+		}
+		if desc, hit := match(c); hit {
+			flagged = append(flagged, desc)
+		}
+	}
+	if len(flagged) == 0 {
+		return OK("OK", ok)
+	}
+	return Result{
+		Status:  severity,
+		Short:   fmt.Sprintf("%d", len(flagged)),
+		Message: bad(len(flagged)),
+		Details: strings.Join(flagged, "; "),
+	}
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+func checkRestartLoops(ctx context.Context, env *Env) Result {
+	return flagContainers(ctx, env, StatusWarn,
+		func(c types.ContainerJSON) (string, bool) {
+			return fmt.Sprintf("%s (%d restarts)", containerName(c), c.RestartCount), c.RestartCount >= RestartCountThreshold
+		},
+		"No containers restarting repeatedly",
+		func(n int) string {
+			return fmt.Sprintf("%s restarted %d+ times", plural(n, "container"), RestartCountThreshold)
+		})
+}
+
+func checkRestarting(ctx context.Context, env *Env) Result {
+	return flagContainers(ctx, env, StatusError,
+		func(c types.ContainerJSON) (string, bool) {
+			return fmt.Sprintf("%s (exit code %d)", containerName(c), c.State.ExitCode), c.State.Restarting
+		},
+		"No containers stuck restarting",
+		func(n int) string { return fmt.Sprintf("%s stuck in restarting state", plural(n, "container")) })
+}
+
+func checkOOMKilled(ctx context.Context, env *Env) Result {
+	now := time.Now()
+	return flagContainers(ctx, env, StatusError,
+		func(c types.ContainerJSON) (string, bool) {
+			if !c.State.OOMKilled {
+				return "", false
+			}
+			finished, err := time.Parse(time.RFC3339Nano, c.State.FinishedAt)
+			if err == nil && !finished.IsZero() && now.Sub(finished) > RecentOOMWindow {
+				return "", false
+			}
+			desc := containerName(c)
+			if err == nil && !finished.IsZero() {
+				desc += fmt.Sprintf(" (%s ago)", now.Sub(finished).Round(time.Minute))
+			}
+			return desc, true
+		},
+		"No recent OOM kills",
+		func(n int) string { return fmt.Sprintf("%s OOM-killed in the last 24h", plural(n, "container")) })
+}
+
+func checkExitCodes(ctx context.Context, env *Env) Result {
+	return flagContainers(ctx, env, StatusWarn,
+		func(c types.ContainerJSON) (string, bool) {
+			// OOM kills and restart loops are reported by their own checks;
+			// docker stop and Compose shutdowns end in SIGTERM or SIGKILL.
+			failed := c.State.Status == "exited" && c.State.ExitCode != 0 && !c.State.OOMKilled && !stoppedBySignal(c.State.ExitCode)
+			return fmt.Sprintf("%s (exit code %d)", containerName(c), c.State.ExitCode), failed
+		},
+		"No containers exited with an error",
+		func(n int) string { return fmt.Sprintf("%s exited with a non-zero code", plural(n, "container")) })
+}
+
+// stoppedBySignal reports whether an exit code is that of a process ended
+// by SIGINT, SIGKILL or SIGTERM (128 + the signal number), as a stopped
+// container is.
+func stoppedBySignal(code int) bool {
+	return code == 130 || code == 137 || code == 143
+}
+
+func checkHealthchecks(ctx context.Context, env *Env) Result {
+	return flagContainers(ctx, env, StatusError,
+		func(c types.ContainerJSON) (string, bool) {
+			health := c.State.Health
+			if health == nil || health.Status != types.Unhealthy {
+				return "", false
+			}
+			desc := fmt.Sprintf("%s (%d failing probes)", containerName(c), health.FailingStreak)
+			if n := len(health.Log); n > 0 && health.Log[n-1] != nil {
+				if out := probeOutput(health.Log[n-1].Output); out != "" {
+					desc += ": " + out
+				}
+			}
+			return desc, true
+		},
+		"No unhealthy containers",
+		func(n int) string { return fmt.Sprintf("%s unhealthy", plural(n, "container")) })
+}
+
+// probeOutput collapses healthcheck output onto one line and truncates it.
+func probeOutput(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > maxProbeOutput {
+		s = string(r[:maxProbeOutput-1]) + "…"
+	}
+	return s
+}
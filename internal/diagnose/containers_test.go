@@ -0,0 +1,109 @@
+package diagnose
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+func inspected(name string, state types.ContainerState, restarts int) types.ContainerJSON {
+	return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{
+		ID:           name,
+		Name:         "/" + name,
+		State:        &state,
+		RestartCount: restarts,
+	}}
+}
+
+func containerEnv(containers ...types.ContainerJSON) *Env {
+	byID := make(map[string]types.ContainerJSON)
+	var list []docker.ContainerInfo
+	for _, c := range containers {
+		byID[c.ID] = c
+		list = append(list, docker.ContainerInfo{ID: c.ID, Name: containerName(c)})
+	}
+	list = append(list, docker.ContainerInfo{ID: "gone"})
+	return NewEnvWithClient(&docker.MockDockerService{
+		ListContainersFn: func(context.Context, bool) ([]docker.ContainerInfo, error) { return list, nil },
+		InspectContainerFn: func(_ context.Context, id string) (types.ContainerJSON, error) {
+			c, ok := byID[id]
+			if !ok {
+				return types.ContainerJSON{}, errdefs.NotFound(errors.New("no such container"))
+			}
+			return c, nil
+		},
+	})
+}
+
+func TestContainerChecksHealthy(t *testing.T) {
+	env := containerEnv(
+		inspected("web", types.ContainerState{Status: "running", Running: true, Health: &types.Health{Status: types.Healthy}}, 1),
+		inspected("job", types.ContainerState{Status: "exited", ExitCode: 0}, 0),
+	)
+	for _, c := range containerChecks() {
+		r := c.Run(context.Background(), env)
+		assert.Equal(t, StatusOK, r.Status, c.ID+": "+r.Details)
+	}
+}
+
+func TestContainerChecksFlagProblems(t *testing.T) {
+	recent := time.Now().Add(-time.Hour).Format(time.RFC3339Nano)
+	old := time.Now().Add(-72 * time.Hour).Format(time.RFC3339Nano)
+	env := containerEnv(
+		inspected("loop", types.ContainerState{Status: "restarting", Restarting: true, ExitCode: 1}, 12),
+		inspected("oom", types.ContainerState{Status: "exited", OOMKilled: true, ExitCode: 137, FinishedAt: recent}, 0),
+		inspected("old-oom", types.ContainerState{Status: "exited", OOMKilled: true, ExitCode: 137, FinishedAt: old}, 0),
+		inspected("crash", types.ContainerState{Status: "exited", ExitCode: 2}, 0),
+		inspected("stopped", types.ContainerState{Status: "exited", ExitCode: 143}, 0),
+		inspected("killed", types.ContainerState{Status: "exited", ExitCode: 137}, 0),
+		inspected("sick", types.ContainerState{Status: "running", Running: true, Health: &types.Health{
+			Status:        types.Unhealthy,
+			FailingStreak: 3,
+			Log: []*types.HealthcheckResult{
+				{ExitCode: 1, Output: "old output"},
+				{ExitCode: 1, Output: "curl: (7) Failed to connect\n to localhost port 80\n"},
+			},
+		}}, 0),
+	)
+
+	results := Run(context.Background(), env, containerChecks(), nil)
+	byID := make(map[string]Result)
+	for _, r := range results {
+		byID[r.ID] = r
+	}
+
+	assert.Equal(t, StatusWarn, byID["restart-loops"].Status)
+	assert.Equal(t, "loop (12 restarts)", byID["restart-loops"].Details)
+
+	assert.Equal(t, StatusError, byID["restarting"].Status)
+	assert.Equal(t, "loop (exit code 1)", byID["restarting"].Details)
+
+	assert.Equal(t, StatusError, byID["oom-killed"].Status)
+	assert.Contains(t, byID["oom-killed"].Details, "oom (")
+	assert.NotContains(t, byID["oom-killed"].Details, "old-oom")
+
+	assert.Equal(t, StatusWarn, byID["exit-codes"].Status)
+	assert.Equal(t, "crash (exit code 2)", byID["exit-codes"].Details)
+
+	require.Equal(t, StatusError, byID["healthchecks"].Status)
+	assert.Equal(t, "sick (3 failing probes): curl: (7) Failed to connect to localhost port 80", byID["healthchecks"].Details)
+	assert.Equal(t, "1 container unhealthy", byID["healthchecks"].Message)
+	assert.NotEmpty(t, byID["healthchecks"].Remediation)
+}
+
+func TestProbeOutputTruncates(t *testing.T) {
+	long := make([]byte, 300)
+	for i := range long {
+		long[i] = 'x'
+	}
+	out := probeOutput(string(long))
+	assert.Len(t, []rune(out), maxProbeOutput)
+}
@@ -0,0 +1,209 @@
+package diagnose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/bsisduck/octo/internal/config"
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// CategoryCustom is the default category of checks defined in the config file.
+const CategoryCustom = "custom"
+
+// CustomChecks converts config-defined checks into registry checks. Each
+// must set either a command or a field expectation.
+func CustomChecks(defs []config.CustomCheck) ([]Check, error) {
+	checks := make([]Check, 0, len(defs))
+	for _, d := range defs {
+		c, err := customCheck(d)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
+
+func customCheck(d config.CustomCheck) (Check, error) {
+	if d.ID == "" {
+		return Check{}, fmt.Errorf("custom check %q: id is required", d.Name)
+	}
+	severity := StatusWarn
+	switch strings.ToLower(d.Severity) {
+	case "", "warn", "warning":
+	case "error":
+		severity = StatusError
+	default:
+		return Check{}, fmt.Errorf("custom check %q: severity must be warn or error", d.ID)
+	}
+	category := d.Category
+	if category == "" {
+		category = CategoryCustom
+	}
+	c := Check{
+		ID:          d.ID,
+		Name:        d.Name,
+		Category:    category,
+		Severity:    severity,
+		Remediation: d.Remediation,
+	}
+	if d.Fix != "" {
+		c.Fix = commandFix(d.Fix)
+	}
+
+	switch {
+	case d.Command != "" && d.Field != "":
+		return Check{}, fmt.Errorf("custom check %q: set either command or field, not both", d.ID)
+	case d.Command != "":
+		c.Local = true
+		c.Run = commandCheck(d.Command, severity)
+	case d.Field != "":
+		run, err := fieldCheck(d, severity)
+		if err != nil {
+			return Check{}, fmt.Errorf("custom check %q: %w", d.ID, err)
+		}
+		c.Run = run
+	default:
+		return Check{}, fmt.Errorf("custom check %q: command or field is required", d.ID)
+	}
+	return c, nil
+}
+
+// commandCheck passes when the shell command exits 0; its trimmed output
+// becomes the result details.
+func commandCheck(command string, severity Status) func(context.Context, *Env) Result {
+	return func(ctx context.Context, _ *Env) Result {
+		output, err := runShell(ctx, command)
+		if err != nil {
+			return Result{Status: severity, Short: "FAILED", Message: fmt.Sprintf("`%s` failed: %v", command, err), Details: output}
+		}
+		r := OK("OK", fmt.Sprintf("`%s` succeeded", command))
+		r.Details = output
+		return r
+	}
+}
+
+// runShell runs command through the platform shell and returns its trimmed
+// combined output.
+func runShell(ctx context.Context, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, docker.TimeoutCommand)
+	defer cancel()
+	name, args := "sh", []string{"-c", command}
+	if runtime.GOOS == "windows" {
+		name, args = "cmd", []string{"/C", command}
+	}
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+// fieldCheck compares a system.Info field against the configured
+// expectations; every expectation that is set must hold.
+func fieldCheck(d config.CustomCheck, severity Status) (func(context.Context, *Env) Result, error) {
+	var re *regexp.Regexp
+	if d.Matches != nil {
+		var err error
+		if re, err = regexp.Compile(*d.Matches); err != nil {
+			return nil, fmt.Errorf("invalid matches pattern: %w", err)
+		}
+	}
+	if d.Equals == nil && d.NotEquals == nil && d.Contains == nil && re == nil && d.Min == nil && d.Max == nil {
+		return nil, fmt.Errorf("field %q needs an expectation (equals, not_equals, contains, matches, min, max)", d.Field)
+	}
+
+	return func(ctx context.Context, env *Env) Result {
+		info, err := env.Info(ctx)
+		if err != nil {
+			return Error("FAILED", "Cannot get daemon info", err.Error())
+		}
+		value, ok, err := lookupField(info, d.Field)
+		if err != nil {
+			return Error("FAILED", fmt.Sprintf("Cannot read %s", d.Field), err.Error())
+		}
+		if !ok {
+			return Result{Status: severity, Short: "MISSING", Message: fmt.Sprintf("%s is not reported by the daemon", d.Field)}
+		}
+
+		var failures []string
+		if d.Equals != nil && value != *d.Equals {
+			failures = append(failures, fmt.Sprintf("expected %q", *d.Equals))
+		}
+		if d.NotEquals != nil && value == *d.NotEquals {
+			failures = append(failures, fmt.Sprintf("expected anything but %q", *d.NotEquals))
+		}
+		if d.Contains != nil && !strings.Contains(value, *d.Contains) {
+			failures = append(failures, fmt.Sprintf("expected to contain %q", *d.Contains))
+		}
+		if re != nil && !re.MatchString(value) {
+			failures = append(failures, fmt.Sprintf("expected to match %q", re.String()))
+		}
+		if d.Min != nil || d.Max != nil {
+			n, err := strconv.ParseFloat(value, 64)
+			switch {
+			case err != nil:
+				failures = append(failures, "expected a number")
+			case d.Min != nil && n < *d.Min:
+				failures = append(failures, fmt.Sprintf("expected at least %g", *d.Min))
+			case d.Max != nil && n > *d.Max:
+				failures = append(failures, fmt.Sprintf("expected at most %g", *d.Max))
+			}
+		}
+
+		message := fmt.Sprintf("%s = %s", d.Field, value)
+		if len(failures) > 0 {
+			return Result{Status: severity, Short: value, Message: message, Details: strings.Join(failures, "; ")}
+		}
+		return OK(value, message)
+	}, nil
+}
+
+// lookupField resolves a dotted path (case-insensitive keys) in the JSON
+// form of v and returns the value as a string.
+func lookupField(v any, path string) (string, bool, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", false, err
+	}
+	var cur any
+	if err := json.Unmarshal(data, &cur); err != nil {
+		return "", false, err
+	}
+	for _, part := range strings.Split(path, ".") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return "", false, nil
+		}
+		next, found := obj[part]
+		if !found {
+			for k, val := range obj {
+				if strings.EqualFold(k, part) {
+					next, found = val, true
+					break
+				}
+			}
+		}
+		if !found {
+			return "", false, nil
+		}
+		cur = next
+	}
+	switch val := cur.(type) {
+	case nil:
+		return "", true, nil
+	case string:
+		return val, true, nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), true, nil
+	case bool:
+		return strconv.FormatBool(val), true, nil
+	default:
+		out, _ := json.Marshal(val)
+		return string(out), true, nil
+	}
+}
@@ -0,0 +1,92 @@
+package diagnose
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/docker/docker/api/types/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/config"
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+func strPtr(s string) *string     { return &s }
+func floatPtr(f float64) *float64 { return &f }
+
+func TestCustomChecksValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		def  config.CustomCheck
+	}{
+		{"missing id", config.CustomCheck{Command: "true"}},
+		{"no command or field", config.CustomCheck{ID: "x"}},
+		{"both command and field", config.CustomCheck{ID: "x", Command: "true", Field: "Driver", Equals: strPtr("a")}},
+		{"field without expectation", config.CustomCheck{ID: "x", Field: "Driver"}},
+		{"bad pattern", config.CustomCheck{ID: "x", Field: "Driver", Matches: strPtr("(")}},
+		{"bad severity", config.CustomCheck{ID: "x", Command: "true", Severity: "fatal"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CustomChecks([]config.CustomCheck{tt.def})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCustomCommandCheck(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	checks, err := CustomChecks([]config.CustomCheck{
+		{ID: "pass", Command: "echo hello"},
+		{ID: "fail", Command: "echo broken; exit 3", Severity: "error"},
+	})
+	require.NoError(t, err)
+	require.Len(t, checks, 2)
+	assert.True(t, checks[0].Local)
+	assert.Equal(t, CategoryCustom, checks[0].Category)
+
+	env := NewEnvWithClient(&docker.MockDockerService{})
+	pass := checks[0].Run(context.Background(), env)
+	assert.Equal(t, StatusOK, pass.Status)
+	assert.Equal(t, "hello", pass.Details)
+
+	fail := checks[1].Run(context.Background(), env)
+	assert.Equal(t, StatusError, fail.Status)
+	assert.Equal(t, "broken", fail.Details)
+}
+
+func TestCustomFieldCheck(t *testing.T) {
+	info := system.Info{Driver: "overlay2", NCPU: 4, CgroupVersion: "2"}
+	info.Swarm.LocalNodeState = "inactive"
+	env := NewEnvWithClient(&docker.MockDockerService{
+		GetServerInfoFn: func(context.Context) (system.Info, error) { return info, nil },
+	})
+
+	tests := []struct {
+		name string
+		def  config.CustomCheck
+		want Status
+	}{
+		{"equals", config.CustomCheck{Field: "Driver", Equals: strPtr("overlay2")}, StatusOK},
+		{"equals case-insensitive path", config.CustomCheck{Field: "driver", Equals: strPtr("btrfs")}, StatusWarn},
+		{"not equals", config.CustomCheck{Field: "CgroupVersion", NotEquals: strPtr("1")}, StatusOK},
+		{"nested", config.CustomCheck{Field: "Swarm.LocalNodeState", Contains: strPtr("active")}, StatusOK},
+		{"matches", config.CustomCheck{Field: "Driver", Matches: strPtr("^overlay")}, StatusOK},
+		{"min", config.CustomCheck{Field: "NCPU", Min: floatPtr(8)}, StatusWarn},
+		{"max", config.CustomCheck{Field: "NCPU", Max: floatPtr(8)}, StatusOK},
+		{"missing field", config.CustomCheck{Field: "NoSuchField", Equals: strPtr("x"), Severity: "error"}, StatusError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.def.ID = "check"
+			checks, err := CustomChecks([]config.CustomCheck{tt.def})
+			require.NoError(t, err)
+			r := checks[0].Run(context.Background(), env)
+			assert.Equal(t, tt.want, r.Status, r.Message+" "+r.Details)
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package diagnose
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/system"
+)
+
+// CategoryConfig groups the daemon configuration lint checks.
+const CategoryConfig = "config"
+
+// minNofile is the lowest default open-files soft limit not flagged.
+const minNofile = 1024
+
+// DaemonConfig is the subset of daemon.json the lint checks read.
+type DaemonConfig struct {
+	Path           string                  `json:"-"`
+	LogOpts        map[string]string       `json:"log-opts"`
+	LiveRestore    *bool                   `json:"live-restore"`
+	DefaultUlimits map[string]DaemonUlimit `json:"default-ulimits"`
+}
+
+// DaemonUlimit is an entry of daemon.json's default-ulimits.
+type DaemonUlimit struct {
+	Name string `json:"Name"`
+	Soft int64  `json:"Soft"`
+	Hard int64  `json:"Hard"`
+}
+
+// DefaultDaemonConfigPath returns where dockerd reads daemon.json on this
+// platform (Docker Desktop keeps it under ~/.docker on macOS).
+func DefaultDaemonConfigPath() string {
+	switch runtime.GOOS {
+	case "windows":
+		return filepath.Join(os.Getenv("ProgramData"), "docker", "config", "daemon.json")
+	case "darwin":
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, ".docker", "daemon.json")
+		}
+	}
+	return "/etc/docker/daemon.json"
+}
+
+// LoadDaemonConfig reads and parses a daemon.json file. A missing file
+// returns an error satisfying errors.Is(err, os.ErrNotExist).
+func LoadDaemonConfig(path string) (*DaemonConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &DaemonConfig{Path: path}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// daemonConfigChecks returns the daemon configuration lint checks. They are
+// part of Builtin.
+func daemonConfigChecks() []Check {
+	return []Check{
+		{
+			ID:          "daemon-json",
+			Name:        "daemon.json",
+			Category:    CategoryConfig,
+			Severity:    StatusError,
+			Remediation: "Fix the syntax of daemon.json; dockerd refuses to start with an invalid file",
+			Local:       true,
+			Run:         checkDaemonJSON,
+		},
+		{
+			ID:          "log-rotation",
+			Name:        "Log Rotation",
+			Category:    CategoryConfig,
+			Severity:    StatusWarn,
+			Remediation: `Set "log-opts": {"max-size": "10m", "max-file": "3"} in daemon.json or switch to the "local" log driver`,
+			Run:         checkLogRotation,
+		},
+		{
+			ID:          "live-restore",
+			Name:        "Live Restore",
+			Category:    CategoryConfig,
+			Severity:    StatusWarn,
+			Remediation: `Set "live-restore": true in daemon.json so containers keep running across daemon restarts`,
+			Run:         checkLiveRestore,
+		},
+		{
+			ID:          "insecure-registries",
+			Name:        "Insecure Registries",
+			Category:    CategoryConfig,
+			Severity:    StatusWarn,
+			Remediation: "Serve the registries over TLS and remove them from insecure-registries",
+			Run:         checkInsecureRegistries,
+		},
+		{
+			ID:          "registry-mirrors",
+			Name:        "Registry Mirrors",
+			Category:    CategoryConfig,
+			Severity:    StatusWarn,
+			Remediation: "Use https:// URLs for registry mirrors",
+			Run:         checkRegistryMirrors,
+		},
+		{
+			ID:          "userns",
+			Name:        "User Namespaces",
+			Category:    CategoryConfig,
+			Severity:    StatusWarn,
+			Remediation: `Set "userns-remap": "default" in daemon.json or run Docker rootless`,
+			Run:         checkUserns,
+		},
+		{
+			ID:          "cgroups",
+			Name:        "Cgroups",
+			Category:    CategoryConfig,
+			Severity:    StatusWarn,
+			Remediation: "Use cgroup v2 with the systemd cgroup driver",
+			Run:         checkCgroups,
+		},
+		{
+			ID:          "default-ulimits",
+			Name:        "Default Ulimits",
+			Category:    CategoryConfig,
+			Severity:    StatusWarn,
+			Remediation: fmt.Sprintf("Raise the nofile soft limit in default-ulimits to at least %d", minNofile),
+			Local:       true,
+			Run:         checkDefaultUlimits,
+		},
+	}
+}
+
+// daemonConfigNote explains why daemon.json could not be used.
+func daemonConfigNote(env *Env, err error) string {
+	if errors.Is(err, ErrDaemonNotLocal) {
+		return err.Error()
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return env.DaemonConfigPath() + " not present"
+	}
+	return env.DaemonConfigPath() + " not readable"
+}
+
+func checkDaemonJSON(_ context.Context, env *Env) Result {
+	_, err := env.DaemonConfig()
+	switch {
+	case err == nil:
+		return OK("OK", fmt.Sprintf("%s is valid", env.DaemonConfigPath()))
+	case errors.Is(err, ErrDaemonNotLocal):
+		return OK("SKIPPED", "Daemon is not on this host; set diagnose.daemon_config to lint a copy of its daemon.json")
+	case errors.Is(err, os.ErrNotExist):
+		return OK("DEFAULTS", fmt.Sprintf("No %s; daemon uses defaults", env.DaemonConfigPath()))
+	case errors.Is(err, os.ErrPermission):
+		return OK("SKIPPED", fmt.Sprintf("%s not readable; file checks skipped", env.DaemonConfigPath()))
+	}
+	return Error("INVALID", "daemon.json cannot be parsed", err.Error())
+}
+
+func checkLogRotation(ctx context.Context, env *Env) Result {
+	info, err := env.Info(ctx)
+	if err != nil {
+		return Error("FAILED", "Cannot get daemon info", err.Error())
+	}
+	if info.LoggingDriver != "json-file" {
+		return OK(info.LoggingDriver, fmt.Sprintf("Logging driver %s", info.LoggingDriver))
+	}
+	cfg, err := env.DaemonConfig()
+	if errors.Is(err, ErrDaemonNotLocal) {
+		return OK("SKIPPED", "json-file logging; rotation not verifiable ("+daemonConfigNote(env, err)+")")
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return OK("json-file", "json-file logging; rotation not verifiable ("+daemonConfigNote(env, err)+")")
+	}
+	if err == nil && cfg.LogOpts["max-size"] != "" {
+		msg := fmt.Sprintf("json-file logging rotated at %s", cfg.LogOpts["max-size"])
+		if n := cfg.LogOpts["max-file"]; n != "" {
+			msg += fmt.Sprintf(", %s files kept", n)
+		}
+		return OK("ROTATED", msg)
+	}
+	details := "no max-size in log-opts"
+	if err != nil {
+		details = daemonConfigNote(env, err)
+	}
+	return Warn("NO MAX-SIZE", "json-file logging without max-size; container logs grow without limit", details)
+}
+
+func checkLiveRestore(ctx context.Context, env *Env) Result {
+	info, err := env.Info(ctx)
+	if err != nil {
+		return Error("FAILED", "Cannot get daemon info", err.Error())
+	}
+	if info.LiveRestoreEnabled {
+		return OK("ENABLED", "Live restore is enabled")
+	}
+	if info.Swarm.LocalNodeState == "active" {
+		return OK("N/A", "Live restore is not supported in Swarm mode")
+	}
+	details := ""
+	if cfg, err := env.DaemonConfig(); err == nil && cfg.LiveRestore != nil && *cfg.LiveRestore {
+		details = "daemon.json enables live-restore but the daemon has not applied it; reload dockerd"
+	}
+	return Warn("DISABLED", "Live restore is disabled; restarting the daemon stops all containers", details)
+}
+
+func checkInsecureRegistries(ctx context.Context, env *Env) Result {
+	info, err := env.Info(ctx)
+	if err != nil {
+		return Error("FAILED", "Cannot get daemon info", err.Error())
+	}
+	insecure := insecureRegistries(info)
+	if len(insecure) == 0 {
+		return OK("NONE", "No insecure registries configured")
+	}
+	return Warn(fmt.Sprintf("%d", len(insecure)),
+		fmt.Sprintf("%d insecure registry entries (no TLS verification)", len(insecure)),
+		strings.Join(insecure, ", "))
+}
+
+// insecureRegistries lists registries and CIDRs the daemon trusts without
+// TLS, ignoring the loopback range that is always insecure.
+func insecureRegistries(info system.Info) []string {
+	if info.RegistryConfig == nil {
+		return nil
+	}
+	var insecure []string
+	for _, cidr := range info.RegistryConfig.InsecureRegistryCIDRs {
+		if cidr == nil {
+			continue
+		}
+		ipnet := net.IPNet(*cidr)
+		if ipnet.IP.IsLoopback() {
+			continue
+		}
+		insecure = append(insecure, ipnet.String())
+	}
+	for name, idx := range info.RegistryConfig.IndexConfigs {
+		if idx != nil && !idx.Secure {
+			insecure = append(insecure, name)
+		}
+	}
+	sort.Strings(insecure)
+	return insecure
+}
+
+func checkRegistryMirrors(ctx context.Context, env *Env) Result {
+	info, err := env.Info(ctx)
+	if err != nil {
+		return Error("FAILED", "Cannot get daemon info", err.Error())
+	}
+	if info.RegistryConfig == nil || len(info.RegistryConfig.Mirrors) == 0 {
+		return OK("NONE", "No registry mirrors configured")
+	}
+	mirrors := info.RegistryConfig.Mirrors
+	var plain []string
+	for _, m := range mirrors {
+		if strings.HasPrefix(strings.ToLower(m), "http://") {
+			plain = append(plain, m)
+		}
+	}
+	if len(plain) > 0 {
+		return Warn(fmt.Sprintf("%d HTTP", len(plain)),
+			fmt.Sprintf("%s use plain HTTP", plural(len(plain), "registry mirror")),
+			strings.Join(plain, ", "))
+	}
+	return OK(fmt.Sprintf("%d", len(mirrors)), fmt.Sprintf("Mirrors: %s", strings.Join(mirrors, ", ")))
+}
+
+func checkUserns(ctx context.Context, env *Env) Result {
+	info, err := env.Info(ctx)
+	if err != nil {
+		return Error("FAILED", "Cannot get daemon info", err.Error())
+	}
+	opts, _ := system.DecodeSecurityOptions(info.SecurityOptions)
+	for _, o := range opts {
+		switch o.Name {
+		case "userns":
+			return OK("REMAPPED", "User namespace remapping is enabled")
+		case "rootless":
+			return OK("ROOTLESS", "Docker runs rootless")
+		}
+	}
+	return Warn("DISABLED", "Container root is host root (no userns-remap, not rootless)", "")
+}
+
+func checkCgroups(ctx context.Context, env *Env) Result {
+	info, err := env.Info(ctx)
+	if err != nil {
+		return Error("FAILED", "Cannot get daemon info", err.Error())
+	}
+	if info.CgroupDriver == "" && info.CgroupVersion == "" {
+		return OK("N/A", "Cgroups not reported by the daemon")
+	}
+	desc := fmt.Sprintf("cgroup v%s, %s driver", info.CgroupVersion, info.CgroupDriver)
+	switch {
+	case info.CgroupVersion == "1":
+		return Warn("v1", desc, "cgroup v1 is deprecated and lacks unified memory and I/O accounting")
+	case info.CgroupVersion == "2" && info.CgroupDriver == "cgroupfs":
+		return Warn("cgroupfs", desc, "with cgroupfs, systemd and dockerd manage cgroups independently, which can destabilize the host under resource pressure")
+	}
+	return OK("v"+info.CgroupVersion, desc)
+}
+
+func checkDefaultUlimits(_ context.Context, env *Env) Result {
+	cfg, err := env.DaemonConfig()
+	if errors.Is(err, ErrDaemonNotLocal) {
+		return OK("SKIPPED", "Daemon default ulimits not checked ("+daemonConfigNote(env, err)+")")
+	}
+	if err != nil {
+		return OK("DEFAULTS", "Daemon default ulimits ("+daemonConfigNote(env, err)+")")
+	}
+	nofile, set := cfg.DefaultUlimits["nofile"]
+	if !set {
+		return OK("DEFAULTS", "No default-ulimits in daemon.json")
+	}
+	if nofile.Soft > 0 && nofile.Soft < minNofile {
+		return Warn(fmt.Sprintf("nofile %d", nofile.Soft),
+			fmt.Sprintf("Default nofile soft limit %d is low", nofile.Soft),
+			fmt.Sprintf("soft %d, hard %d", nofile.Soft, nofile.Hard))
+	}
+	return OK(fmt.Sprintf("nofile %d", nofile.Soft), fmt.Sprintf("Default nofile limit soft %d, hard %d", nofile.Soft, nofile.Hard))
+}
@@ -0,0 +1,174 @@
+package diagnose
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// configEnv returns an env reporting info, with daemon.json containing
+// daemonJSON (no file when empty).
+func configEnv(t *testing.T, info system.Info, daemonJSON string) *Env {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "daemon.json")
+	if daemonJSON != "" {
+		require.NoError(t, os.WriteFile(path, []byte(daemonJSON), 0o644))
+	}
+	env := NewEnvWithClient(&docker.MockDockerService{
+		GetServerInfoFn: func(context.Context) (system.Info, error) { return info, nil },
+	})
+	env.SetDaemonConfigPath(path)
+	return env
+}
+
+func runConfigCheck(t *testing.T, id string, env *Env) Result {
+	t.Helper()
+	for _, c := range daemonConfigChecks() {
+		if c.ID == id {
+			return c.Run(context.Background(), env)
+		}
+	}
+	t.Fatalf("no check %q", id)
+	return Result{}
+}
+
+func TestDaemonJSONCheck(t *testing.T) {
+	assert.Equal(t, StatusOK, runConfigCheck(t, "daemon-json", configEnv(t, system.Info{}, "")).Status)
+	assert.Equal(t, StatusOK, runConfigCheck(t, "daemon-json", configEnv(t, system.Info{}, `{"live-restore": true}`)).Status)
+
+	r := runConfigCheck(t, "daemon-json", configEnv(t, system.Info{}, `{"live-restore": true,}`))
+	assert.Equal(t, StatusError, r.Status)
+	assert.Contains(t, r.Details, "daemon.json")
+}
+
+func TestLogRotationCheck(t *testing.T) {
+	jsonFile := system.Info{LoggingDriver: "json-file"}
+
+	tests := []struct {
+		name       string
+		info       system.Info
+		daemonJSON string
+		want       Status
+	}{
+		{"local driver", system.Info{LoggingDriver: "local"}, "", StatusOK},
+		{"json-file without daemon.json", jsonFile, "", StatusWarn},
+		{"json-file without max-size", jsonFile, `{"log-opts": {"max-file": "3"}}`, StatusWarn},
+		{"json-file with max-size", jsonFile, `{"log-opts": {"max-size": "10m", "max-file": "3"}}`, StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := runConfigCheck(t, "log-rotation", configEnv(t, tt.info, tt.daemonJSON))
+			assert.Equal(t, tt.want, r.Status, r.Message)
+		})
+	}
+}
+
+func TestLiveRestoreCheck(t *testing.T) {
+	assert.Equal(t, StatusOK, runConfigCheck(t, "live-restore", configEnv(t, system.Info{LiveRestoreEnabled: true}, "")).Status)
+
+	r := runConfigCheck(t, "live-restore", configEnv(t, system.Info{}, ""))
+	assert.Equal(t, StatusWarn, r.Status)
+	assert.Empty(t, r.Details)
+
+	r = runConfigCheck(t, "live-restore", configEnv(t, system.Info{}, `{"live-restore": true}`))
+	assert.Equal(t, StatusWarn, r.Status)
+	assert.Contains(t, r.Details, "not applied")
+
+	swarm := system.Info{}
+	swarm.Swarm.LocalNodeState = "active"
+	assert.Equal(t, StatusOK, runConfigCheck(t, "live-restore", configEnv(t, swarm, "")).Status)
+}
+
+func TestRegistryChecks(t *testing.T) {
+	_, loopback, _ := net.ParseCIDR("127.0.0.0/8")
+	_, lan, _ := net.ParseCIDR("10.0.0.0/8")
+	info := system.Info{RegistryConfig: &registry.ServiceConfig{
+		InsecureRegistryCIDRs: []*registry.NetIPNet{(*registry.NetIPNet)(loopback), (*registry.NetIPNet)(lan)},
+		IndexConfigs: map[string]*registry.IndexInfo{
+			"docker.io":           {Name: "docker.io", Secure: true, Official: true},
+			"registry.local:5000": {Name: "registry.local:5000", Secure: false},
+		},
+		Mirrors: []string{"https://mirror.example.com/", "http://cache.lan:5000/"},
+	}}
+	env := configEnv(t, info, "")
+
+	r := runConfigCheck(t, "insecure-registries", env)
+	assert.Equal(t, StatusWarn, r.Status)
+	assert.Equal(t, "10.0.0.0/8, registry.local:5000", r.Details)
+
+	r = runConfigCheck(t, "registry-mirrors", env)
+	assert.Equal(t, StatusWarn, r.Status)
+	assert.Equal(t, "http://cache.lan:5000/", r.Details)
+
+	clean := configEnv(t, system.Info{RegistryConfig: &registry.ServiceConfig{
+		InsecureRegistryCIDRs: []*registry.NetIPNet{(*registry.NetIPNet)(loopback)},
+	}}, "")
+	assert.Equal(t, StatusOK, runConfigCheck(t, "insecure-registries", clean).Status)
+	assert.Equal(t, StatusOK, runConfigCheck(t, "registry-mirrors", clean).Status)
+}
+
+func TestUsernsAndCgroupChecks(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		info system.Info
+		want Status
+	}{
+		{"userns off", "userns", system.Info{SecurityOptions: []string{"name=seccomp,profile=builtin"}}, StatusWarn},
+		{"userns remap", "userns", system.Info{SecurityOptions: []string{"name=userns"}}, StatusOK},
+		{"rootless", "userns", system.Info{SecurityOptions: []string{"name=rootless"}}, StatusOK},
+		{"cgroup v1", "cgroups", system.Info{CgroupVersion: "1", CgroupDriver: "cgroupfs"}, StatusWarn},
+		{"cgroup v2 cgroupfs", "cgroups", system.Info{CgroupVersion: "2", CgroupDriver: "cgroupfs"}, StatusWarn},
+		{"cgroup v2 systemd", "cgroups", system.Info{CgroupVersion: "2", CgroupDriver: "systemd"}, StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := runConfigCheck(t, tt.id, configEnv(t, tt.info, ""))
+			assert.Equal(t, tt.want, r.Status, r.Message)
+		})
+	}
+}
+
+func TestDefaultUlimitsCheck(t *testing.T) {
+	assert.Equal(t, StatusOK, runConfigCheck(t, "default-ulimits", configEnv(t, system.Info{}, "")).Status)
+
+	low := `{"default-ulimits": {"nofile": {"Name": "nofile", "Soft": 256, "Hard": 4096}}}`
+	r := runConfigCheck(t, "default-ulimits", configEnv(t, system.Info{}, low))
+	assert.Equal(t, StatusWarn, r.Status)
+	assert.Equal(t, "soft 256, hard 4096", r.Details)
+
+	ok := `{"default-ulimits": {"nofile": {"Name": "nofile", "Soft": 65536, "Hard": 65536}}}`
+	assert.Equal(t, StatusOK, runConfigCheck(t, "default-ulimits", configEnv(t, system.Info{}, ok)).Status)
+}
+
+func TestDaemonConfigChecksSkipRemoteDaemon(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "tcp://build-host:2376")
+	env := NewEnvWithClient(&docker.MockDockerService{
+		GetServerInfoFn: func(context.Context) (system.Info, error) {
+			return system.Info{LoggingDriver: "json-file"}, nil
+		},
+	})
+
+	_, err := env.DaemonConfig()
+	assert.ErrorIs(t, err, ErrDaemonNotLocal)
+	for _, id := range []string{"daemon-json", "log-rotation", "default-ulimits"} {
+		r := runConfigCheck(t, id, env)
+		assert.Equal(t, StatusOK, r.Status, id)
+		assert.Equal(t, "SKIPPED", r.Short, id)
+		assert.Contains(t, r.Message, "not on this host", id)
+	}
+	assert.Empty(t, runConfigCheck(t, "live-restore", env).Details)
+
+	// An explicit path lints that file even for a remote daemon.
+	r := runConfigCheck(t, "log-rotation", configEnv(t, system.Info{LoggingDriver: "json-file"}, `{"log-opts": {"max-size": "10m"}}`))
+	assert.Equal(t, "ROTATED", r.Short)
+}
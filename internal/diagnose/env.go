@@ -0,0 +1,219 @@
+package diagnose
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/errdefs"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/hostdisk"
+)
+
+// Env gives checks lazy, cached access to the Docker daemon so that checks
+// sharing data (server info, container list) fetch it once.
+type Env struct {
+	connect func() (docker.DockerService, error)
+	owned   bool // client was opened by the env and is closed by Close
+
+	connOnce sync.Once
+	client   docker.DockerService
+	connErr  error
+
+	infoOnce sync.Once
+	info     system.Info
+	infoErr  error
+
+	containersOnce sync.Once
+	containers     []docker.ContainerInfo
+	containersErr  error
+
+	daemonConfigPath string // empty means DefaultDaemonConfigPath on a local daemon
+	daemonOnce       sync.Once
+	daemonConfig     *DaemonConfig
+	daemonConfigErr  error
+
+	inspectOnce sync.Once
+	inspected   []types.ContainerJSON
+	inspectErr  error
+
+	hostDiskOpts *hostdisk.Options // nil means hostdisk.DefaultOptions
+	hostDiskOnce sync.Once
+	hostDisk     *hostdisk.Report
+	hostDiskErr  error
+}
+
+// ErrNotLocal is returned by HostDisk when the daemon's data root is not on
+// this host.
+var ErrNotLocal = errors.New("docker data root is not on this host")
+
+// ErrDaemonNotLocal is returned by DaemonConfig when the daemon runs on
+// another machine or in a VM and no daemon.json path was set.
+var ErrDaemonNotLocal = errors.New("daemon is not on this host")
+
+// NewEnv creates an environment that connects on first use.
+func NewEnv(connect func() (docker.DockerService, error)) *Env {
+	return &Env{connect: connect, owned: true}
+}
+
+// NewEnvWithClient creates an environment around an existing client, which
+// the caller keeps ownership of.
+func NewEnvWithClient(client docker.DockerService) *Env {
+	return &Env{connect: func() (docker.DockerService, error) { return client, nil }}
+}
+
+// Fresh returns an env sharing the client and settings of e but none of its
+// cached data, for re-running checks after changes.
+func (e *Env) Fresh() (*Env, error) {
+	client, err := e.Docker()
+	if err != nil {
+		return nil, err
+	}
+	fresh := NewEnvWithClient(client)
+	fresh.daemonConfigPath = e.daemonConfigPath
+	fresh.hostDiskOpts = e.hostDiskOpts
+	return fresh, nil
+}
+
+// Docker returns the Docker client, connecting on first call.
+func (e *Env) Docker() (docker.DockerService, error) {
+	e.connOnce.Do(func() {
+		e.client, e.connErr = e.connect()
+	})
+	return e.client, e.connErr
+}
+
+// Info returns the daemon's system info.
+func (e *Env) Info(ctx context.Context) (system.Info, error) {
+	e.infoOnce.Do(func() {
+		client, err := e.Docker()
+		if err != nil {
+			e.infoErr = err
+			return
+		}
+		ctx, cancel := context.WithTimeout(ctx, docker.TimeoutList)
+		defer cancel()
+		e.info, e.infoErr = client.GetServerInfo(ctx)
+	})
+	return e.info, e.infoErr
+}
+
+// Containers returns all containers, running and stopped.
+func (e *Env) Containers(ctx context.Context) ([]docker.ContainerInfo, error) {
+	e.containersOnce.Do(func() {
+		client, err := e.Docker()
+		if err != nil {
+			e.containersErr = err
+			return
+		}
+		ctx, cancel := context.WithTimeout(ctx, docker.TimeoutList)
+		defer cancel()
+		e.containers, e.containersErr = client.ListContainers(ctx, true)
+	})
+	return e.containers, e.containersErr
+}
+
+// InspectedContainers returns the inspect data of all containers. Containers
+// removed between listing and inspecting are skipped.
+func (e *Env) InspectedContainers(ctx context.Context) ([]types.ContainerJSON, error) {
+	e.inspectOnce.Do(func() {
+		containers, err := e.Containers(ctx)
+		if err != nil {
+			e.inspectErr = err
+			return
+		}
+		client, _ := e.Docker()
+		for _, c := range containers {
+			ictx, cancel := context.WithTimeout(ctx, docker.TimeoutList)
+			details, err := client.InspectContainer(ictx, c.ID)
+			cancel()
+			if err != nil {
+				if errdefs.IsNotFound(err) {
+					continue
+				}
+				e.inspectErr = err
+				return
+			}
+			e.inspected = append(e.inspected, details)
+		}
+	})
+	return e.inspected, e.inspectErr
+}
+
+// SetDaemonConfigPath overrides where DaemonConfig reads daemon.json.
+func (e *Env) SetDaemonConfigPath(path string) {
+	e.daemonConfigPath = path
+}
+
+// DaemonConfigPath returns the daemon.json path the env reads.
+func (e *Env) DaemonConfigPath() string {
+	if e.daemonConfigPath != "" {
+		return e.daemonConfigPath
+	}
+	return DefaultDaemonConfigPath()
+}
+
+// DaemonConfig returns the parsed daemon.json. The default path is only read
+// when the daemon runs on this host; otherwise it returns ErrDaemonNotLocal
+// unless a path was set with SetDaemonConfigPath.
+func (e *Env) DaemonConfig() (*DaemonConfig, error) {
+	e.daemonOnce.Do(func() {
+		if e.daemonConfigPath == "" && !docker.IsLocalDaemon() {
+			e.daemonConfigErr = ErrDaemonNotLocal
+			return
+		}
+		e.daemonConfig, e.daemonConfigErr = LoadDaemonConfig(e.DaemonConfigPath())
+	})
+	return e.daemonConfig, e.daemonConfigErr
+}
+
+// SetHostDiskOptions overrides the thresholds and history file HostDisk
+// uses.
+func (e *Env) SetHostDiskOptions(opts hostdisk.Options) {
+	e.hostDiskOpts = &opts
+}
+
+// HostDiskOptions returns the options HostDisk uses.
+func (e *Env) HostDiskOptions() hostdisk.Options {
+	if e.hostDiskOpts != nil {
+		return *e.hostDiskOpts
+	}
+	return hostdisk.DefaultOptions()
+}
+
+// HostDisk measures the filesystem backing the daemon's data root, recording
+// the sample in the growth history. It returns ErrNotLocal for daemons on
+// another machine or in a VM.
+func (e *Env) HostDisk(ctx context.Context) (*hostdisk.Report, error) {
+	e.hostDiskOnce.Do(func() {
+		info, err := e.Info(ctx)
+		if err != nil {
+			e.hostDiskErr = err
+			return
+		}
+		root, ok := hostdisk.DataRoot(info)
+		if !ok {
+			e.hostDiskErr = ErrNotLocal
+			return
+		}
+		report, err := hostdisk.Measure(root, e.HostDiskOptions(), time.Now())
+		if err != nil {
+			e.hostDiskErr = err
+			return
+		}
+		e.hostDisk = &report
+	})
+	return e.hostDisk, e.hostDiskErr
+}
+
+// Close closes the Docker client if the env opened it.
+func (e *Env) Close() error {
+	if e.owned && e.client != nil {
+		return e.client.Close()
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package diagnose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/dustin/go-humanize"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// Fix is a remediation a check can apply when it does not pass.
+type Fix struct {
+	Title string
+	// Plan describes what Apply would change without changing anything.
+	Plan func(ctx context.Context, env *Env) (docker.ConfirmationInfo, error)
+	// Apply performs the fix and returns a one-line summary.
+	Apply func(ctx context.Context, env *Env) (string, error)
+}
+
+// PendingFix pairs a failed result with the fix of its check.
+type PendingFix struct {
+	Check  Check
+	Result Result
+}
+
+// Fixes returns the fixes applicable to the results: those of checks that
+// did not pass and carry a fix, in result order.
+func Fixes(checks []Check, results []Result) []PendingFix {
+	byID := make(map[string]Check, len(checks))
+	for _, c := range checks {
+		byID[c.ID] = c
+	}
+	var fixes []PendingFix
+	for _, r := range results {
+		if c, ok := byID[r.ID]; ok && c.Fix != nil && r.Status != StatusOK {
+			fixes = append(fixes, PendingFix{Check: c, Result: r})
+		}
+	}
+	return fixes
+}
+
+// pruneContainersFix removes stopped containers.
+var pruneContainersFix = &Fix{
+	Title: "Remove stopped containers",
+	Plan: func(ctx context.Context, env *Env) (docker.ConfirmationInfo, error) {
+		client, _ := env.Docker()
+		ctx, cancel := context.WithTimeout(ctx, docker.TimeoutList)
+		defer cancel()
+		return client.PruneContainersDryRun(ctx)
+	},
+	Apply: func(ctx context.Context, env *Env) (string, error) {
+		client, _ := env.Docker()
+		ctx, cancel := context.WithTimeout(ctx, docker.TimeoutPrune)
+		defer cancel()
+		reclaimed, err := client.PruneContainers(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Reclaimed %s", humanize.Bytes(reclaimed)), nil
+	},
+}
+
+// pruneImagesFix removes dangling images.
+var pruneImagesFix = &Fix{
+	Title: "Remove dangling images",
+	Plan: func(ctx context.Context, env *Env) (docker.ConfirmationInfo, error) {
+		client, _ := env.Docker()
+		ctx, cancel := context.WithTimeout(ctx, docker.TimeoutList)
+		defer cancel()
+		return client.PruneImagesDryRun(ctx, false)
+	},
+	Apply: func(ctx context.Context, env *Env) (string, error) {
+		client, _ := env.Docker()
+		ctx, cancel := context.WithTimeout(ctx, docker.TimeoutPrune)
+		defer cancel()
+		reclaimed, err := client.PruneImages(ctx, false)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Reclaimed %s", humanize.Bytes(reclaimed)), nil
+	},
+}
+
+// pruneVolumesFix removes unused anonymous volumes.
+var pruneVolumesFix = &Fix{
+	Title: "Remove unused volumes",
+	Plan: func(ctx context.Context, env *Env) (docker.ConfirmationInfo, error) {
+		client, _ := env.Docker()
+		ctx, cancel := context.WithTimeout(ctx, docker.TimeoutList)
+		defer cancel()
+		return client.PruneVolumesDryRun(ctx)
+	},
+	Apply: func(ctx context.Context, env *Env) (string, error) {
+		client, _ := env.Docker()
+		ctx, cancel := context.WithTimeout(ctx, docker.TimeoutPrune)
+		defer cancel()
+		reclaimed, err := client.PruneVolumes(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Reclaimed %s", humanize.Bytes(reclaimed)), nil
+	},
+}
+
+// pruneBuildCacheFix removes the build cache, usually the largest
+// reclaimable item when disk usage is flagged.
+var pruneBuildCacheFix = &Fix{
+	Title: "Remove build cache",
+	Plan: func(ctx context.Context, env *Env) (docker.ConfirmationInfo, error) {
+		client, _ := env.Docker()
+		ctx, cancel := context.WithTimeout(ctx, docker.TimeoutDiskUsage)
+		defer cancel()
+		usage, err := client.GetDiskUsage(ctx)
+		if err != nil {
+			return docker.ConfirmationInfo{}, err
+		}
+		size := humanize.Bytes(uint64(usage.BuildCache))
+		return docker.ConfirmationInfo{
+			Tier:             docker.TierModerate,
+			Title:            "Prune Build Cache?",
+			Description:      fmt.Sprintf("Remove unused build cache, freeing up to %s", size),
+			Resources:        []string{fmt.Sprintf("build cache: %s", size)},
+			Reversible:       true,
+			UndoInstructions: "Cache is rebuilt on the next build",
+		}, nil
+	},
+	Apply: func(ctx context.Context, env *Env) (string, error) {
+		client, _ := env.Docker()
+		ctx, cancel := context.WithTimeout(ctx, docker.TimeoutPrune)
+		defer cancel()
+		reclaimed, err := client.PruneBuildCache(ctx, false)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Reclaimed %s", humanize.Bytes(reclaimed)), nil
+	},
+}
+
+// unhealthyContainers returns the running containers whose healthcheck fails.
+func unhealthyContainers(ctx context.Context, env *Env) ([]types.ContainerJSON, error) {
+	containers, err := env.InspectedContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var unhealthy []types.ContainerJSON
+	for _, c := range containers {
+		if c.ContainerJSONBase != nil && c.State != nil && c.State.Health != nil && c.State.Health.Status == types.Unhealthy {
+			unhealthy = append(unhealthy, c)
+		}
+	}
+	return unhealthy, nil
+}
+
+// restartUnhealthyFix restarts containers with a failing healthcheck.
+var restartUnhealthyFix = &Fix{
+	Title: "Restart unhealthy containers",
+	Plan: func(ctx context.Context, env *Env) (docker.ConfirmationInfo, error) {
+		unhealthy, err := unhealthyContainers(ctx, env)
+		if err != nil {
+			return docker.ConfirmationInfo{}, err
+		}
+		resources := make([]string, len(unhealthy))
+		for i, c := range unhealthy {
+			resources[i] = "container: " + containerName(c)
+		}
+		return docker.ConfirmationInfo{
+			Tier:             docker.TierLowRisk,
+			Title:            "Restart Unhealthy Containers?",
+			Description:      fmt.Sprintf("Restart %s", plural(len(unhealthy), "unhealthy container")),
+			Resources:        resources,
+			Reversible:       true,
+			UndoInstructions: "Containers keep their configuration and data",
+			Warnings:         []string{"Services are briefly unavailable while restarting"},
+		}, nil
+	},
+	Apply: func(ctx context.Context, env *Env) (string, error) {
+		unhealthy, err := unhealthyContainers(ctx, env)
+		if err != nil {
+			return "", err
+		}
+		client, _ := env.Docker()
+		var failed []string
+		for _, c := range unhealthy {
+			rctx, cancel := context.WithTimeout(ctx, docker.TimeoutAction)
+			if err := client.RestartContainer(rctx, c.ID); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", containerName(c), err))
+			}
+			cancel()
+		}
+		if len(failed) > 0 {
+			return "", fmt.Errorf("restart failed: %s", strings.Join(failed, "; "))
+		}
+		return fmt.Sprintf("Restarted %s", plural(len(unhealthy), "container")), nil
+	},
+}
+
+// commandFix runs a config-defined fix command through the shell.
+func commandFix(command string) *Fix {
+	return &Fix{
+		Title: fmt.Sprintf("Run `%s`", command),
+		Plan: func(context.Context, *Env) (docker.ConfirmationInfo, error) {
+			return docker.ConfirmationInfo{
+				Tier:        docker.TierModerate,
+				Title:       "Run Fix Command?",
+				Description: fmt.Sprintf("Run `%s`", command),
+				Resources:   []string{"command: " + command},
+				Warnings:    []string{"Custom command from the config file"},
+			}, nil
+		},
+		Apply: func(ctx context.Context, _ *Env) (string, error) {
+			output, err := runShell(ctx, command)
+			if err != nil {
+				if output != "" {
+					return "", fmt.Errorf("%w: %s", err, output)
+				}
+				return "", err
+			}
+			if output == "" {
+				output = "Command succeeded"
+			}
+			return output, nil
+		},
+	}
+}
@@ -0,0 +1,129 @@
+package diagnose
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/config"
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+func TestFixesOnlyForFailedFixableChecks(t *testing.T) {
+	fixable := okCheck("fixable", "x")
+	fixable.Fix = &Fix{Title: "fix it"}
+	passing := okCheck("passing", "x")
+	passing.Fix = &Fix{Title: "not needed"}
+	plain := okCheck("plain", "x")
+
+	results := []Result{
+		{ID: "plain", Status: StatusError},
+		{ID: "passing", Status: StatusOK},
+		{ID: "fixable", Status: StatusWarn},
+	}
+	fixes := Fixes([]Check{fixable, passing, plain}, results)
+
+	require.Len(t, fixes, 1)
+	assert.Equal(t, "fixable", fixes[0].Check.ID)
+	assert.Equal(t, StatusWarn, fixes[0].Result.Status)
+}
+
+func TestBuiltinFixesAttached(t *testing.T) {
+	withFix := make(map[string]bool)
+	for _, c := range Builtin() {
+		withFix[c.ID] = c.Fix != nil
+	}
+	for _, id := range []string{"disk-usage", "containers", "dangling-images", "unused-volumes", "healthchecks"} {
+		assert.True(t, withFix[id], id)
+	}
+	assert.False(t, withFix["restarting"])
+}
+
+func TestPruneImagesFix(t *testing.T) {
+	var pruned bool
+	env := NewEnvWithClient(&docker.MockDockerService{
+		PruneImagesFn: func(_ context.Context, all bool) (uint64, error) {
+			assert.False(t, all, "only dangling images")
+			pruned = true
+			return 2_000_000, nil
+		},
+		PruneImagesDryRunFn: func(context.Context, bool) (docker.ConfirmationInfo, error) {
+			return docker.ConfirmationInfo{Tier: docker.TierBulkDestructive}, nil
+		},
+	})
+
+	info, err := pruneImagesFix.Plan(context.Background(), env)
+	require.NoError(t, err)
+	assert.Equal(t, docker.TierBulkDestructive, info.Tier)
+	assert.False(t, pruned, "plan must not change anything")
+
+	summary, err := pruneImagesFix.Apply(context.Background(), env)
+	require.NoError(t, err)
+	assert.True(t, pruned)
+	assert.Equal(t, "Reclaimed 2.0 MB", summary)
+}
+
+func TestRestartUnhealthyFix(t *testing.T) {
+	env := containerEnv(
+		inspected("ok", types.ContainerState{Status: "running", Health: &types.Health{Status: types.Healthy}}, 0),
+		inspected("sick", types.ContainerState{Status: "running", Health: &types.Health{Status: types.Unhealthy}}, 0),
+	)
+	var restarted []string
+	mock := mustMock(t, env)
+	mock.RestartContainerFn = func(_ context.Context, id string) error {
+		restarted = append(restarted, id)
+		return nil
+	}
+
+	info, err := restartUnhealthyFix.Plan(context.Background(), env)
+	require.NoError(t, err)
+	assert.Equal(t, docker.TierLowRisk, info.Tier)
+	assert.Equal(t, []string{"container: sick"}, info.Resources)
+	assert.Empty(t, restarted)
+
+	summary, err := restartUnhealthyFix.Apply(context.Background(), env)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sick"}, restarted)
+	assert.Equal(t, "Restarted 1 container", summary)
+
+	mock.RestartContainerFn = func(context.Context, string) error { return errors.New("boom") }
+	_, err = restartUnhealthyFix.Apply(context.Background(), env)
+	assert.ErrorContains(t, err, "sick: boom")
+}
+
+func TestCustomCheckFixCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	checks, err := CustomChecks([]config.CustomCheck{
+		{ID: "with-fix", Command: "false", Fix: "echo repaired"},
+		{ID: "failing-fix", Command: "false", Fix: "echo nope; exit 1"},
+		{ID: "no-fix", Command: "false"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, checks[0].Fix)
+	assert.Nil(t, checks[2].Fix)
+
+	env := NewEnvWithClient(&docker.MockDockerService{})
+	summary, err := checks[0].Fix.Apply(context.Background(), env)
+	require.NoError(t, err)
+	assert.Equal(t, "repaired", summary)
+
+	_, err = checks[1].Fix.Apply(context.Background(), env)
+	assert.ErrorContains(t, err, "nope")
+}
+
+// mustMock returns the mock behind an env built by containerEnv.
+func mustMock(t *testing.T, env *Env) *docker.MockDockerService {
+	t.Helper()
+	client, err := env.Docker()
+	require.NoError(t, err)
+	mock, ok := client.(*docker.MockDockerService)
+	require.True(t, ok)
+	return mock
+}
@@ -0,0 +1,78 @@
+package diagnose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/bsisduck/octo/internal/hostdisk"
+)
+
+// hostDiskReport returns the data root measurement, or a result to report
+// in its place when there is none.
+func hostDiskReport(ctx context.Context, env *Env) (*hostdisk.Report, *Result) {
+	report, err := env.HostDisk(ctx)
+	switch {
+	case err == nil:
+		return report, nil
+	case errors.Is(err, ErrNotLocal):
+		r := OK("N/A", "Docker data root is not on this host (remote daemon or VM)")
+		return nil, &r
+	}
+	r := Error("FAILED", "Cannot measure the Docker data root filesystem", err.Error())
+	return nil, &r
+}
+
+func levelResult(level hostdisk.Level, short, message, details string) Result {
+	switch level {
+	case hostdisk.LevelError:
+		return Error(short, message, details)
+	case hostdisk.LevelWarn:
+		return Warn(short, message, details)
+	}
+	r := OK(short, message)
+	r.Details = details
+	return r
+}
+
+func checkHostDisk(ctx context.Context, env *Env) Result {
+	report, r := hostDiskReport(ctx, env)
+	if r != nil {
+		return *r
+	}
+	pct := report.UsedPercent()
+	message := fmt.Sprintf("%s free of %s on %s (%.0f%% used)",
+		humanize.Bytes(report.Avail), humanize.Bytes(report.Total), report.Path, pct)
+	details := ""
+	if f := report.Forecast; f != nil {
+		details = fmt.Sprintf("Growing %s/day; full in about %s", humanize.Bytes(uint64(f.GrowthPerDay)), days(f.DaysLeft))
+	}
+	return levelResult(report.SpaceLevel, fmt.Sprintf("%.0f%% used", pct), message, details)
+}
+
+func checkHostInodes(ctx context.Context, env *Env) Result {
+	report, r := hostDiskReport(ctx, env)
+	if r != nil {
+		return *r
+	}
+	if report.Inodes == 0 {
+		return OK("N/A", fmt.Sprintf("Filesystem of %s does not report inodes", report.Path))
+	}
+	pct := report.InodesUsedPercent()
+	message := fmt.Sprintf("%s of %s inodes free on %s (%.0f%% used)",
+		humanize.Comma(int64(report.InodesFree)), humanize.Comma(int64(report.Inodes)), report.Path, pct)
+	return levelResult(report.InodeLevel, fmt.Sprintf("%.0f%% used", pct), message, "")
+}
+
+// days renders a forecast horizon.
+func days(d float64) string {
+	switch {
+	case d < 1:
+		return "less than a day"
+	case d < 1.5:
+		return "1 day"
+	}
+	return fmt.Sprintf("%.0f days", d)
+}
@@ -0,0 +1,60 @@
+package diagnose
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/docker/docker/api/types/system"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/hostdisk"
+)
+
+func hostDiskEnv(root string, opts hostdisk.Options) *Env {
+	env := NewEnvWithClient(&docker.MockDockerService{
+		GetServerInfoFn: func(context.Context) (system.Info, error) {
+			return system.Info{DockerRootDir: root}, nil
+		},
+	})
+	env.SetHostDiskOptions(opts)
+	return env
+}
+
+func TestHostDiskChecks(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("data root is only measured for local Linux daemons")
+	}
+	t.Setenv("DOCKER_HOST", "")
+	root := t.TempDir()
+
+	relaxed := hostdisk.Options{WarnPercent: 101, ErrorPercent: 101, InodeWarnPercent: 101, InodeErrorPercent: 101}
+	r := checkHostDisk(context.Background(), hostDiskEnv(root, relaxed))
+	assert.Equal(t, StatusOK, r.Status, r.Message)
+	assert.Contains(t, r.Message, root)
+
+	strict := hostdisk.Options{WarnPercent: 0.0001, ErrorPercent: 0.0001, InodeWarnPercent: 0.0001, InodeErrorPercent: 101}
+	env := hostDiskEnv(root, strict)
+	assert.Equal(t, StatusError, checkHostDisk(context.Background(), env).Status)
+	if r := checkHostInodes(context.Background(), env); r.Short != "N/A" {
+		assert.Equal(t, StatusWarn, r.Status, r.Message)
+	}
+}
+
+func TestHostDiskRemoteDaemon(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "tcp://10.0.0.2:2376")
+	env := hostDiskEnv(t.TempDir(), hostdisk.DefaultOptions())
+
+	for _, check := range []func(context.Context, *Env) Result{checkHostDisk, checkHostInodes} {
+		r := check(context.Background(), env)
+		assert.Equal(t, StatusOK, r.Status)
+		assert.Equal(t, "N/A", r.Short)
+	}
+}
+
+func TestForecastDays(t *testing.T) {
+	assert.Equal(t, "less than a day", days(0.4))
+	assert.Equal(t, "1 day", days(1.2))
+	assert.Equal(t, "12 days", days(12.3))
+}
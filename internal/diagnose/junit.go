@@ -0,0 +1,108 @@
+package diagnose
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// JUnit XML report, one test case per check, as read by CI systems.
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Hostname  string          `xml:"hostname,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// WriteJUnit writes results as a JUnit XML report. Results at or above
+// threshold are failures; other warnings are noted in the case's output.
+// started is when the run began.
+func WriteJUnit(w io.Writer, results []Result, threshold Status, started time.Time) error {
+	suite := junitSuite{
+		Name:      "octo diagnose",
+		Tests:     len(results),
+		Timestamp: started.UTC().Format(time.RFC3339),
+	}
+	suite.Hostname, _ = os.Hostname()
+
+	var total time.Duration
+	for _, r := range results {
+		total += r.Duration
+		tc := junitTestCase{
+			Name:      r.Name,
+			ClassName: "octo.diagnose." + r.Category,
+			Time:      junitSeconds(r.Duration),
+		}
+		body := r.Message
+		if r.Details != "" {
+			body += "\n" + r.Details
+		}
+		if r.Remediation != "" {
+			body += "\nRemediation: " + r.Remediation
+		}
+		switch {
+		case r.Status == StatusOK:
+			tc.SystemOut = body
+		case r.Status.AtLeast(threshold):
+			suite.Failures++
+			tc.Failure = &junitFailure{Message: r.Message, Type: string(r.Status), Text: body}
+		default:
+			tc.SystemOut = strings.ToUpper(string(r.Status)) + ": " + body
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = junitSeconds(total)
+
+	report := junitSuites{
+		Name:     suite.Name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     suite.Time,
+		Suites:   []junitSuite{suite},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
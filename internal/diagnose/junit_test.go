@@ -0,0 +1,64 @@
+package diagnose
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	results := []Result{
+		{ID: "version", Name: "Docker Version", Category: "daemon", Status: StatusOK, Message: "Docker 27.0.1", Duration: 1500 * time.Millisecond},
+		{ID: "dangling-images", Name: "Dangling Images", Category: "disk", Status: StatusWarn, Message: "7 dangling images", Remediation: "Run 'octo cleanup --images'"},
+		{ID: "api-response", Name: "API Response", Category: "performance", Status: StatusError, Message: "Docker API timed out", Details: "context deadline exceeded"},
+	}
+
+	tests := []struct {
+		name         string
+		threshold    Status
+		wantFailures int
+	}{
+		{"errors fail", StatusError, 1},
+		{"warnings fail", StatusWarn, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteJUnit(&buf, results, tt.threshold, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+			assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte(xml.Header)))
+
+			var report junitSuites
+			require.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+			assert.Equal(t, 3, report.Tests)
+			assert.Equal(t, tt.wantFailures, report.Failures)
+			require.Len(t, report.Suites, 1)
+			suite := report.Suites[0]
+			assert.Equal(t, "2026-01-01T00:00:00Z", suite.Timestamp)
+			assert.Equal(t, "1.500", suite.Time)
+			require.Len(t, suite.Cases, 3)
+
+			assert.Equal(t, "Docker Version", suite.Cases[0].Name)
+			assert.Equal(t, "octo.diagnose.daemon", suite.Cases[0].ClassName)
+			assert.Nil(t, suite.Cases[0].Failure)
+
+			api := suite.Cases[2].Failure
+			require.NotNil(t, api)
+			assert.Equal(t, "error", api.Type)
+			assert.Equal(t, "Docker API timed out", api.Message)
+			assert.Contains(t, api.Text, "context deadline exceeded")
+
+			images := suite.Cases[1]
+			if tt.threshold == StatusWarn {
+				require.NotNil(t, images.Failure)
+				assert.Contains(t, images.Failure.Text, "Remediation: Run 'octo cleanup --images'")
+			} else {
+				assert.Nil(t, images.Failure)
+				assert.Equal(t, "WARN: 7 dangling images\nRemediation: Run 'octo cleanup --images'", images.SystemOut)
+			}
+		})
+	}
+}
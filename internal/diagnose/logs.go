@@ -0,0 +1,131 @@
+package diagnose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/logsize"
+)
+
+// Thresholds for the container log check.
+const (
+	LogTruncateThreshold = 100 * 1000 * 1000 // unbounded logs the fix truncates
+	maxListedLogs        = 5                 // largest logs named in the details
+)
+
+// logsCheck flags containers whose json-file logs are never rotated.
+var logsCheck = Check{
+	ID:          "container-logs",
+	Name:        "Container Logs",
+	Category:    CategoryContainers,
+	Severity:    StatusWarn,
+	Remediation: `Set "log-opts": {"max-size": "10m", "max-file": "3"} in daemon.json (new containers) or logging options per service`,
+	Run:         checkContainerLogs,
+	Fix:         truncateLogsFix,
+}
+
+// containerLogs returns the log usage of all containers, largest first.
+// Sizes are only known when the daemon runs on this host.
+func containerLogs(ctx context.Context, env *Env) ([]logsize.Usage, error) {
+	containers, err := env.InspectedContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return logsize.Collect(containers, docker.IsLocalDaemon()), nil
+}
+
+// describeLogs lists the largest logs with their sizes when known.
+func describeLogs(usages []logsize.Usage) string {
+	parts := make([]string, 0, maxListedLogs)
+	for i, u := range usages {
+		if i == maxListedLogs {
+			parts = append(parts, fmt.Sprintf("and %d more", len(usages)-maxListedLogs))
+			break
+		}
+		if u.SizeKnown {
+			parts = append(parts, fmt.Sprintf("%s (%s)", u.Container, humanize.Bytes(uint64(u.Size))))
+		} else {
+			parts = append(parts, u.Container)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+func checkContainerLogs(ctx context.Context, env *Env) Result {
+	usages, err := containerLogs(ctx, env)
+	if err != nil {
+		return Error("FAILED", "Cannot inspect containers", err.Error())
+	}
+	if unbounded := logsize.Unbounded(usages); len(unbounded) > 0 {
+		return Warn(fmt.Sprintf("%d", len(unbounded)),
+			fmt.Sprintf("%s log to json-file without rotation", plural(len(unbounded), "container")),
+			describeLogs(unbounded))
+	}
+	if len(usages) == 0 {
+		return OK("OK", "No containers")
+	}
+	r := OK("OK", "All container logs are rotated or shipped off the host")
+	if usages[0].SizeKnown {
+		r.Details = "Largest: " + describeLogs(usages[:1])
+	}
+	return r
+}
+
+// errNoLargeLogs is returned by the truncate fix when no log qualifies.
+var errNoLargeLogs = errors.New("no readable unrotated log over " + humanize.Bytes(LogTruncateThreshold))
+
+// largeUnboundedLogs returns the unrotated logs the truncate fix acts on.
+func largeUnboundedLogs(ctx context.Context, env *Env) ([]logsize.Usage, error) {
+	usages, err := containerLogs(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	var large []logsize.Usage
+	for _, u := range logsize.Unbounded(usages) {
+		if u.CanTruncate() && u.LiveSize >= LogTruncateThreshold {
+			large = append(large, u)
+		}
+	}
+	if len(large) == 0 {
+		return nil, errNoLargeLogs
+	}
+	return large, nil
+}
+
+// truncateLogsFix empties the largest unrotated logs. It does not add
+// rotation; that needs the container to be recreated.
+var truncateLogsFix = &Fix{
+	Title: "Truncate oversized container logs",
+	Plan: func(ctx context.Context, env *Env) (docker.ConfirmationInfo, error) {
+		large, err := largeUnboundedLogs(ctx, env)
+		if err != nil {
+			return docker.ConfirmationInfo{}, err
+		}
+		return logsize.TruncateDryRun(large...)
+	},
+	Apply: func(ctx context.Context, env *Env) (string, error) {
+		large, err := largeUnboundedLogs(ctx, env)
+		if err != nil {
+			return "", err
+		}
+		var freed int64
+		var failed []string
+		for _, u := range large {
+			n, err := logsize.Truncate(u)
+			if err != nil {
+				failed = append(failed, err.Error())
+				continue
+			}
+			freed += n
+		}
+		if len(failed) > 0 {
+			return "", fmt.Errorf("truncate failed: %s", strings.Join(failed, "; "))
+		}
+		return fmt.Sprintf("Truncated %s, freed %s", plural(len(large), "log"), humanize.Bytes(uint64(freed))), nil
+	},
+}
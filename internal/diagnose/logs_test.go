@@ -0,0 +1,98 @@
+package diagnose
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// logging returns a running container logging with driver to logPath.
+func logging(name, driver, maxSize, logPath string) types.ContainerJSON {
+	c := inspected(name, types.ContainerState{Status: "running", Running: true}, 0)
+	c.LogPath = logPath
+	c.HostConfig = &container.HostConfig{LogConfig: container.LogConfig{Type: driver}}
+	if maxSize != "" {
+		c.HostConfig.LogConfig.Config = map[string]string{"max-size": maxSize}
+	}
+	return c
+}
+
+// sparseLog creates a log file of size bytes without writing them.
+func sparseLog(t *testing.T, dir, name string, size int64) string {
+	t.Helper()
+	path := filepath.Join(dir, name+"-json.log")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, f.Truncate(size))
+	require.NoError(t, f.Close())
+	return path
+}
+
+func localDaemon(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("log files are only measured for a daemon on this Linux host")
+	}
+	t.Setenv("DOCKER_HOST", "")
+}
+
+func TestContainerLogsCheck(t *testing.T) {
+	localDaemon(t)
+	dir := t.TempDir()
+	env := containerEnv(
+		logging("api", "json-file", "", sparseLog(t, dir, "api", 300*1000*1000)),
+		logging("web", "json-file", "10m", sparseLog(t, dir, "web", 5000)),
+		logging("job", "json-file", "-1", filepath.Join(dir, "unreadable.log")),
+		logging("db", "journald", "", ""),
+	)
+
+	r := logsCheck.Run(context.Background(), env)
+	assert.Equal(t, StatusWarn, r.Status)
+	assert.Equal(t, "2 containers log to json-file without rotation", r.Message)
+	assert.Equal(t, "api (300 MB); job", r.Details)
+
+	r = logsCheck.Run(context.Background(), containerEnv(
+		logging("web", "json-file", "10m", sparseLog(t, dir, "web", 5000)),
+	))
+	assert.Equal(t, StatusOK, r.Status)
+	assert.Equal(t, "Largest: web (5.0 kB)", r.Details)
+}
+
+func TestTruncateLogsFix(t *testing.T) {
+	localDaemon(t)
+	dir := t.TempDir()
+	big := sparseLog(t, dir, "api", 300*1000*1000)
+	small := sparseLog(t, dir, "cron", 1000)
+	rotated := sparseLog(t, dir, "web", 200*1000*1000)
+	env := containerEnv(
+		logging("api", "json-file", "", big),
+		logging("cron", "json-file", "", small),
+		logging("web", "json-file", "10m", rotated),
+	)
+
+	info, err := truncateLogsFix.Plan(context.Background(), env)
+	require.NoError(t, err)
+	assert.Equal(t, docker.TierHighRisk, info.Tier)
+	assert.Equal(t, []string{"log: api (300 MB)"}, info.Resources)
+
+	summary, err := truncateLogsFix.Apply(context.Background(), env)
+	require.NoError(t, err)
+	assert.Equal(t, "Truncated 1 log, freed 300 MB", summary)
+	for path, want := range map[string]int64{big: 0, small: 1000, rotated: 200 * 1000 * 1000} {
+		st, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, want, st.Size(), path)
+	}
+
+	_, err = truncateLogsFix.Plan(context.Background(), containerEnv(logging("cron", "json-file", "", small)))
+	assert.ErrorIs(t, err, errNoLargeLogs)
+}
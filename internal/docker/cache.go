@@ -0,0 +1,182 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/system"
+)
+
+// Compile-time interface check
+var _ DockerService = (*CachedService)(nil)
+
+// CachedService is a DockerService shared by several views. List, disk usage
+// and server info results are reused for the TTL, so views refreshing at the
+// same time make one API call; any change made through it drops the cache.
+type CachedService struct {
+	DockerService
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value     any
+	err       error
+	fetchedAt time.Time
+}
+
+// NewCachedService wraps svc with a cache of the given TTL.
+func NewCachedService(svc DockerService, ttl time.Duration) *CachedService {
+	return &CachedService{DockerService: svc, ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+// Invalidate drops all cached results.
+func (s *CachedService) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.entries)
+}
+
+// cached returns the result stored under key, or fetches and stores it.
+// Errors are cached too, so a failing daemon is not hammered by every view.
+func cached[T any](s *CachedService, key string, fetch func() (T, error)) (T, error) {
+	s.mu.Lock()
+	e, ok := s.entries[key]
+	s.mu.Unlock()
+	if ok && time.Since(e.fetchedAt) < s.ttl {
+		v, _ := e.value.(T)
+		return v, e.err
+	}
+	v, err := fetch()
+	s.mu.Lock()
+	s.entries[key] = cacheEntry{value: v, err: err, fetchedAt: time.Now()}
+	s.mu.Unlock()
+	return v, err
+}
+
+// ListContainers returns a copy of the cached list: callers sort it in place.
+func (s *CachedService) ListContainers(ctx context.Context, all bool) ([]ContainerInfo, error) {
+	v, err := cached(s, fmt.Sprintf("containers/%t", all), func() ([]ContainerInfo, error) {
+		return s.DockerService.ListContainers(ctx, all)
+	})
+	return slices.Clone(v), err
+}
+
+func (s *CachedService) ListImages(ctx context.Context, all bool) ([]ImageInfo, error) {
+	v, err := cached(s, fmt.Sprintf("images/%t", all), func() ([]ImageInfo, error) {
+		return s.DockerService.ListImages(ctx, all)
+	})
+	return slices.Clone(v), err
+}
+
+func (s *CachedService) ListVolumes(ctx context.Context) ([]VolumeInfo, error) {
+	v, err := cached(s, "volumes", func() ([]VolumeInfo, error) {
+		return s.DockerService.ListVolumes(ctx)
+	})
+	return slices.Clone(v), err
+}
+
+func (s *CachedService) ListNetworks(ctx context.Context) ([]NetworkInfo, error) {
+	v, err := cached(s, "networks", func() ([]NetworkInfo, error) {
+		return s.DockerService.ListNetworks(ctx)
+	})
+	return slices.Clone(v), err
+}
+
+func (s *CachedService) GetDiskUsage(ctx context.Context) (*DiskUsageInfo, error) {
+	return cached(s, "diskusage", func() (*DiskUsageInfo, error) {
+		return s.DockerService.GetDiskUsage(ctx)
+	})
+}
+
+func (s *CachedService) GetServerInfo(ctx context.Context) (system.Info, error) {
+	return cached(s, "info", func() (system.Info, error) {
+		return s.DockerService.GetServerInfo(ctx)
+	})
+}
+
+// invalidating drops the cache after a change, whether it succeeded or
+// not: a failed change may still have changed something.
+func (s *CachedService) invalidating(err error) error {
+	s.Invalidate()
+	return err
+}
+
+func (s *CachedService) RemoveContainer(ctx context.Context, id string, force bool) error {
+	return s.invalidating(s.DockerService.RemoveContainer(ctx, id, force))
+}
+
+func (s *CachedService) RemoveImage(ctx context.Context, id string, force bool) error {
+	return s.invalidating(s.DockerService.RemoveImage(ctx, id, force))
+}
+
+func (s *CachedService) RemoveVolume(ctx context.Context, name string, force bool) error {
+	return s.invalidating(s.DockerService.RemoveVolume(ctx, name, force))
+}
+
+func (s *CachedService) RemoveNetwork(ctx context.Context, id string) error {
+	return s.invalidating(s.DockerService.RemoveNetwork(ctx, id))
+}
+
+func (s *CachedService) ConnectNetwork(ctx context.Context, networkID, containerID string) error {
+	return s.invalidating(s.DockerService.ConnectNetwork(ctx, networkID, containerID))
+}
+
+func (s *CachedService) DisconnectNetwork(ctx context.Context, networkID, containerID string) error {
+	return s.invalidating(s.DockerService.DisconnectNetwork(ctx, networkID, containerID))
+}
+
+func (s *CachedService) StartContainer(ctx context.Context, id string) error {
+	return s.invalidating(s.DockerService.StartContainer(ctx, id))
+}
+
+func (s *CachedService) StopContainer(ctx context.Context, id string) error {
+	return s.invalidating(s.DockerService.StopContainer(ctx, id))
+}
+
+func (s *CachedService) RestartContainer(ctx context.Context, id string) error {
+	return s.invalidating(s.DockerService.RestartContainer(ctx, id))
+}
+
+func (s *CachedService) PruneContainers(ctx context.Context) (uint64, error) {
+	n, err := s.DockerService.PruneContainers(ctx)
+	return n, s.invalidating(err)
+}
+
+func (s *CachedService) PruneImages(ctx context.Context, all bool) (uint64, error) {
+	n, err := s.DockerService.PruneImages(ctx, all)
+	return n, s.invalidating(err)
+}
+
+func (s *CachedService) PruneVolumes(ctx context.Context) (uint64, error) {
+	n, err := s.DockerService.PruneVolumes(ctx)
+	return n, s.invalidating(err)
+}
+
+func (s *CachedService) PruneNetworks(ctx context.Context) error {
+	return s.invalidating(s.DockerService.PruneNetworks(ctx))
+}
+
+func (s *CachedService) PruneBuildCache(ctx context.Context, all bool) (uint64, error) {
+	n, err := s.DockerService.PruneBuildCache(ctx, all)
+	return n, s.invalidating(err)
+}
+
+func (s *CachedService) StartComposeProject(ctx context.Context, projectName string) (int, error) {
+	n, err := s.DockerService.StartComposeProject(ctx, projectName)
+	return n, s.invalidating(err)
+}
+
+func (s *CachedService) StopComposeProject(ctx context.Context, projectName string) (int, error) {
+	n, err := s.DockerService.StopComposeProject(ctx, projectName)
+	return n, s.invalidating(err)
+}
+
+func (s *CachedService) RestartComposeProject(ctx context.Context, projectName string) (int, error) {
+	n, err := s.DockerService.RestartComposeProject(ctx, projectName)
+	return n, s.invalidating(err)
+}
@@ -0,0 +1,55 @@
+package docker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedService(t *testing.T) {
+	calls := 0
+	mock := &MockDockerService{
+		ListContainersFn: func(ctx context.Context, all bool) ([]ContainerInfo, error) {
+			calls++
+			return []ContainerInfo{{ID: "b"}, {ID: "a"}}, nil
+		},
+	}
+	svc := NewCachedService(mock, time.Minute)
+	ctx := context.Background()
+
+	first, err := svc.ListContainers(ctx, true)
+	require.NoError(t, err)
+	first[0].ID = "changed" // callers may modify their copy
+	second, _ := svc.ListContainers(ctx, true)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "b", second[0].ID)
+
+	_, _ = svc.ListContainers(ctx, false)
+	assert.Equal(t, 2, calls, "all and running-only are cached apart")
+
+	require.NoError(t, svc.StopContainer(ctx, "a"))
+	_, _ = svc.ListContainers(ctx, true)
+	assert.Equal(t, 3, calls, "a change drops the cache")
+
+	require.NoError(t, svc.DisconnectNetwork(ctx, "n", "a"))
+	_, _ = svc.ListContainers(ctx, true)
+	assert.Equal(t, 4, calls, "so does a network attachment")
+}
+
+func TestCachedServiceExpires(t *testing.T) {
+	calls := 0
+	mock := &MockDockerService{
+		ListVolumesFn: func(ctx context.Context) ([]VolumeInfo, error) {
+			calls++
+			return nil, nil
+		},
+	}
+	svc := NewCachedService(mock, 10*time.Millisecond)
+	_, _ = svc.ListVolumes(context.Background())
+	time.Sleep(20 * time.Millisecond)
+	_, _ = svc.ListVolumes(context.Background())
+	assert.Equal(t, 2, calls)
+}
@@ -14,8 +14,10 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/api/types/volume"
@@ -98,6 +100,11 @@ func (c *Client) GetServerInfo(ctx context.Context) (system.Info, error) {
 	return c.api.Info(ctx)
 }
 
+// InspectContainer returns the full configuration and state of a container.
+func (c *Client) InspectContainer(ctx context.Context, id string) (types.ContainerJSON, error) {
+	return c.api.ContainerInspect(ctx, id)
+}
+
 // ListContainers returns all containers (running and stopped).
 func (c *Client) ListContainers(ctx context.Context, all bool) ([]ContainerInfo, error) {
 	containers, err := c.api.ContainerList(ctx, container.ListOptions{All: all})
@@ -109,7 +116,7 @@ func (c *Client) ListContainers(ctx context.Context, all bool) ([]ContainerInfo,
 	for i, c := range containers {
 		name := extractContainerName(c.Names)
 
-		ports := formatPorts(c.Ports)
+		bindings := toPortBindings(c.Ports)
 
 		result[i] = ContainerInfo{
 			ID:      truncateID(c.ID, 12),
@@ -118,15 +125,45 @@ func (c *Client) ListContainers(ctx context.Context, all bool) ([]ContainerInfo,
 			Status:  c.Status,
 			State:   c.State,
 			Created: time.Unix(c.Created, 0),
-			Ports:   ports,
+			Ports:   formatPorts(bindings),
 			Size:    c.SizeRw,
 			Labels:  c.Labels,
+
+			PortBindings: bindings,
+
+			ImageID:  trimImageID(c.ImageID),
+			Volumes:  volumeNames(c.Mounts),
+			Networks: networkNames(c.NetworkSettings),
 		}
 	}
 
 	return result, nil
 }
 
+// volumeNames returns the named volumes among a container's mounts.
+func volumeNames(mounts []types.MountPoint) []string {
+	var names []string
+	for _, m := range mounts {
+		if m.Type == mount.TypeVolume && m.Name != "" {
+			names = append(names, m.Name)
+		}
+	}
+	return names
+}
+
+// networkNames returns the networks a container joined, sorted.
+func networkNames(settings *types.SummaryNetworkSettings) []string {
+	if settings == nil {
+		return nil
+	}
+	names := make([]string, 0, len(settings.Networks))
+	for name := range settings.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // ListImages returns all images.
 func (c *Client) ListImages(ctx context.Context, all bool) ([]ImageInfo, error) {
 	images, err := c.api.ImageList(ctx, image.ListOptions{All: all})
@@ -241,12 +278,195 @@ func (c *Client) ListNetworks(ctx context.Context) ([]NetworkInfo, error) {
 			Scope:      n.Scope,
 			Internal:   n.Internal,
 			Containers: len(n.Containers),
+			Labels:     n.Labels,
+			Subnets:    subnets(n.IPAM),
 		}
 	}
 
 	return result, nil
 }
 
+// subnets returns the subnets of a network's address pools.
+func subnets(ipam network.IPAM) []string {
+	var out []string
+	for _, cfg := range ipam.Config {
+		if cfg.Subnet != "" {
+			out = append(out, cfg.Subnet)
+		}
+	}
+	return out
+}
+
+// InspectNetwork returns a network's address pools and its containers with
+// their addresses and aliases. Only running containers have an endpoint in
+// the network; stopped ones are listed from their own settings.
+func (c *Client) InspectNetwork(ctx context.Context, id string) (NetworkDetails, error) {
+	n, err := c.api.NetworkInspect(ctx, id, network.InspectOptions{})
+	if err != nil {
+		return NetworkDetails{}, err
+	}
+	containers, err := c.api.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("network", n.ID)),
+	})
+	if err != nil {
+		return NetworkDetails{}, err
+	}
+
+	details := NetworkDetails{
+		NetworkInfo: NetworkInfo{
+			ID:         truncateID(n.ID, 12),
+			Name:       n.Name,
+			Driver:     n.Driver,
+			Scope:      n.Scope,
+			Internal:   n.Internal,
+			Containers: len(containers),
+			Labels:     n.Labels,
+			Subnets:    subnets(n.IPAM),
+		},
+		IPv6:       n.EnableIPv6,
+		Attachable: n.Attachable,
+		IPAMDriver: n.IPAM.Driver,
+	}
+	for _, cfg := range n.IPAM.Config {
+		details.IPAM = append(details.IPAM, IPAMPool{Subnet: cfg.Subnet, IPRange: cfg.IPRange, Gateway: cfg.Gateway})
+	}
+	for _, ct := range containers {
+		ep := NetworkEndpoint{
+			ContainerID: truncateID(ct.ID, 12),
+			Container:   extractContainerName(ct.Names),
+			State:       ct.State,
+		}
+		if ct.NetworkSettings != nil {
+			if s := ct.NetworkSettings.Networks[n.Name]; s != nil {
+				ep.MacAddress = s.MacAddress
+				ep.Aliases = s.Aliases
+				if s.IPAddress != "" {
+					ep.IPv4Address = fmt.Sprintf("%s/%d", s.IPAddress, s.IPPrefixLen)
+				}
+				if s.GlobalIPv6Address != "" {
+					ep.IPv6Address = fmt.Sprintf("%s/%d", s.GlobalIPv6Address, s.GlobalIPv6PrefixLen)
+				}
+			}
+		}
+		// The network's own view of the endpoint is current
+		if r, ok := n.Containers[ct.ID]; ok {
+			ep.IPv4Address = r.IPv4Address
+			ep.IPv6Address = r.IPv6Address
+			ep.MacAddress = r.MacAddress
+		}
+		details.Endpoints = append(details.Endpoints, ep)
+	}
+	sort.Slice(details.Endpoints, func(i, j int) bool {
+		return details.Endpoints[i].Container < details.Endpoints[j].Container
+	})
+	return details, nil
+}
+
+// ConnectNetwork attaches a container to a network.
+func (c *Client) ConnectNetwork(ctx context.Context, networkID, containerID string) error {
+	return c.api.NetworkConnect(ctx, networkID, containerID, nil)
+}
+
+// DisconnectNetwork detaches a container from a network.
+func (c *Client) DisconnectNetwork(ctx context.Context, networkID, containerID string) error {
+	return c.api.NetworkDisconnect(ctx, networkID, containerID, false)
+}
+
+// networkAttachment looks up a network and a container, and the
+// container's endpoint in the network if it is attached.
+func (c *Client) networkAttachment(ctx context.Context, networkID, containerID string) (network.Inspect, types.ContainerJSON, *network.EndpointSettings, error) {
+	n, err := c.api.NetworkInspect(ctx, networkID, network.InspectOptions{})
+	if err != nil {
+		return network.Inspect{}, types.ContainerJSON{}, nil, err
+	}
+	ct, err := c.api.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return network.Inspect{}, types.ContainerJSON{}, nil, err
+	}
+	var endpoint *network.EndpointSettings
+	if ct.NetworkSettings != nil {
+		endpoint = ct.NetworkSettings.Networks[n.Name]
+	}
+	return n, ct, endpoint, nil
+}
+
+// ConnectNetworkDryRun returns confirmation info for attaching a container
+// to a network without attaching it
+func (c *Client) ConnectNetworkDryRun(ctx context.Context, networkID, containerID string) (ConfirmationInfo, error) {
+	n, ct, endpoint, err := c.networkAttachment(ctx, networkID, containerID)
+	if err != nil {
+		return ConfirmationInfo{}, err
+	}
+	name := strings.TrimPrefix(ct.Name, "/")
+	if endpoint != nil {
+		return ConfirmationInfo{}, fmt.Errorf("container %s is already connected to %s", name, n.Name)
+	}
+
+	info := ConfirmationInfo{
+		Tier:        TierLowRisk,
+		Title:       "Connect Container?",
+		Description: fmt.Sprintf("Connect '%s' to network '%s' (%s)", name, n.Name, n.Driver),
+		Resources: []string{
+			fmt.Sprintf("container: %s", name),
+			fmt.Sprintf("network: %s", n.Name),
+		},
+		Reversible:       true,
+		UndoInstructions: "Disconnect the container from the network",
+		Warnings:         []string{},
+	}
+	if s := subnets(n.IPAM); len(s) > 0 {
+		info.Resources = append(info.Resources, fmt.Sprintf("subnet: %s", strings.Join(s, ", ")))
+	}
+	if ct.State != nil && !ct.State.Running {
+		info.Warnings = append(info.Warnings, "Container is not running; it gets an address when it starts")
+	}
+	if n.Internal {
+		info.Warnings = append(info.Warnings, "Internal network: no traffic to or from outside the host")
+	}
+	return info, nil
+}
+
+// DisconnectNetworkDryRun returns confirmation info for detaching a
+// container from a network without detaching it
+func (c *Client) DisconnectNetworkDryRun(ctx context.Context, networkID, containerID string) (ConfirmationInfo, error) {
+	n, ct, endpoint, err := c.networkAttachment(ctx, networkID, containerID)
+	if err != nil {
+		return ConfirmationInfo{}, err
+	}
+	name := strings.TrimPrefix(ct.Name, "/")
+	if endpoint == nil {
+		return ConfirmationInfo{}, fmt.Errorf("container %s is not connected to %s", name, n.Name)
+	}
+
+	info := ConfirmationInfo{
+		Tier:        TierModerate,
+		Title:       "Disconnect Container?",
+		Description: fmt.Sprintf("Disconnect '%s' from network '%s' (%s)", name, n.Name, n.Driver),
+		Resources: []string{
+			fmt.Sprintf("container: %s", name),
+			fmt.Sprintf("network: %s", n.Name),
+		},
+		Reversible:       true,
+		UndoInstructions: fmt.Sprintf("Reconnect with 'docker network connect %s %s'; the address may change", n.Name, name),
+		Warnings:         []string{},
+	}
+	if endpoint.IPAddress != "" {
+		info.Resources = append(info.Resources, fmt.Sprintf("address: %s/%d", endpoint.IPAddress, endpoint.IPPrefixLen))
+	}
+	if ct.State != nil && ct.State.Running {
+		info.Warnings = append(info.Warnings, "Connections over this network are cut; other containers cannot reach it here by name")
+	}
+	if len(endpoint.Aliases) > 0 {
+		info.Warnings = append(info.Warnings, fmt.Sprintf("Aliases %s are lost on reconnecting", strings.Join(endpoint.Aliases, ", ")))
+	}
+	if len(ct.NetworkSettings.Networks) == 1 {
+		info.Tier = TierHighRisk
+		info.Warnings = append(info.Warnings, "This is the container's only network: it is left without networking")
+	}
+	return info, nil
+}
+
 // GetDiskUsage returns Docker disk usage information.
 func (c *Client) GetDiskUsage(ctx context.Context) (*DiskUsageInfo, error) {
 	// Check cache first
@@ -906,6 +1126,13 @@ func (c *Client) GetContainerLogs(ctx context.Context, containerID string, tail
 // StreamContainerLogs streams live logs from a container.
 // Returns a channel of log entries, an error channel, and a cancel function.
 func (c *Client) StreamContainerLogs(ctx context.Context, containerID string) (<-chan LogEntry, <-chan error, func()) {
+	return c.StreamContainerLogsSince(ctx, containerID, time.Time{})
+}
+
+// StreamContainerLogsSince streams logs from a container starting at since
+// (inclusive), or only new logs when since is zero. It is used to resume a
+// stream after a disconnect without losing the lines written in between.
+func (c *Client) StreamContainerLogsSince(ctx context.Context, containerID string, since time.Time) (<-chan LogEntry, <-chan error, func()) {
 	logCh := make(chan LogEntry, 100)
 	errCh := make(chan error, 1)
 	ctx, cancel := context.WithCancel(ctx)
@@ -921,6 +1148,10 @@ func (c *Client) StreamContainerLogs(ctx context.Context, containerID string) (<
 			Follow:     true,
 			Tail:       "0", // Only new logs
 		}
+		if !since.IsZero() {
+			opts.Tail = ""
+			opts.Since = since.Format(time.RFC3339Nano)
+		}
 
 		reader, err := c.api.ContainerLogs(ctx, containerID, opts)
 		if err != nil {
@@ -929,32 +1160,120 @@ func (c *Client) StreamContainerLogs(ctx context.Context, containerID string) (<
 		}
 		defer func() { _ = reader.Close() }()
 
-		// Use a pipe to demux stdout/stderr
-		pr, pw := io.Pipe()
-		go func() {
-			_, err := stdcopy.StdCopy(pw, pw, reader)
-			if err != nil {
-				// TTY mode fallback: copy directly
-				_ = pw.Close()
-				return
-			}
-			_ = pw.Close()
-		}()
-
-		scanner := bufio.NewScanner(pr)
-		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024) // 1MB max line
-		for scanner.Scan() {
-			line := scanner.Text()
-			entry := parseTimestampedLine(line, "stdout")
+		// StdCopy writes the frames of both streams in the order they
+		// arrive, so lines keep their label and their relative order. A TTY
+		// container's log is not multiplexed; StdCopy then fails and the
+		// stream ends.
+		stdout := &logLineWriter{ctx: ctx, stream: "stdout", out: logCh}
+		stderr := &logLineWriter{ctx: ctx, stream: "stderr", out: logCh}
+		if _, err := stdcopy.StdCopy(stdout, stderr, reader); err == nil {
+			_ = stdout.flush()
+			_ = stderr.flush()
+		}
+	}()
+
+	return logCh, errCh, cancel
+}
+
+// maxLogLine is the longest followed log line; longer ones are split.
+const maxLogLine = 1024 * 1024
+
+// logLineWriter splits one demultiplexed log stream into lines and sends
+// them, labelled with the stream, until ctx is done.
+type logLineWriter struct {
+	ctx    context.Context
+	stream string
+	out    chan<- LogEntry
+	buf    []byte
+}
+
+func (w *logLineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	rest := w.buf
+	for {
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.send(rest[:i]); err != nil {
+			return 0, err
+		}
+		rest = rest[i+1:]
+	}
+	w.buf = append(w.buf[:0], rest...)
+	if len(w.buf) >= maxLogLine {
+		if err := w.flush(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// flush sends the last line if it did not end with a newline.
+func (w *logLineWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.send(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
+
+func (w *logLineWriter) send(line []byte) error {
+	select {
+	case w.out <- parseTimestampedLine(string(bytes.TrimSuffix(line, []byte("\r"))), w.stream):
+		return nil
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	}
+}
+
+// StreamEvents follows the daemon's events from now on until the returned
+// cancel func is called. A broken stream is reported on the error channel.
+func (c *Client) StreamEvents(ctx context.Context) (<-chan Event, <-chan error, func()) {
+	eventCh := make(chan Event, 100)
+	errCh := make(chan error, 1)
+	ctx, cancel := context.WithCancel(ctx)
+	msgs, errs := c.api.Events(ctx, events.ListOptions{})
+
+	go func() {
+		defer close(eventCh)
+		defer close(errCh)
+		for {
 			select {
-			case logCh <- entry:
 			case <-ctx.Done():
 				return
+			case err := <-errs:
+				if err != nil && ctx.Err() == nil {
+					errCh <- fmt.Errorf("event stream: %w", err)
+				}
+				return
+			case msg := <-msgs:
+				select {
+				case eventCh <- eventFromMessage(msg):
+				case <-ctx.Done():
+					return
+				}
 			}
 		}
 	}()
 
-	return logCh, errCh, cancel
+	return eventCh, errCh, cancel
+}
+
+// eventFromMessage converts an SDK event message.
+func eventFromMessage(msg events.Message) Event {
+	t := time.Unix(msg.Time, 0)
+	if msg.TimeNano != 0 {
+		t = time.Unix(0, msg.TimeNano)
+	}
+	return Event{
+		Time:   t,
+		Type:   string(msg.Type),
+		Action: string(msg.Action),
+		ID:     msg.Actor.ID,
+		Name:   msg.Actor.Attributes["name"],
+	}
 }
 
 // GetContainerStats returns real-time metrics for a container.
@@ -974,14 +1293,12 @@ func (c *Client) GetContainerStats(ctx context.Context, containerID string) (*Co
 	cpuPercent := 0.0
 	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage - stats.PreCPUStats.CPUUsage.TotalUsage)
 	systemDelta := float64(stats.CPUStats.SystemUsage - stats.PreCPUStats.SystemUsage)
-	if systemDelta > 0 && cpuDelta > 0 {
-		onlineCPUs := float64(stats.CPUStats.OnlineCPUs)
-		if onlineCPUs == 0 {
-			onlineCPUs = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
-		}
-		if onlineCPUs > 0 {
-			cpuPercent = (cpuDelta / systemDelta) * onlineCPUs * 100.0
-		}
+	onlineCPUs := stats.CPUStats.OnlineCPUs
+	if onlineCPUs == 0 {
+		onlineCPUs = uint32(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if systemDelta > 0 && cpuDelta > 0 && onlineCPUs > 0 {
+		cpuPercent = (cpuDelta / systemDelta) * float64(onlineCPUs) * 100.0
 	}
 
 	// Calculate memory
@@ -1021,6 +1338,10 @@ func (c *Client) GetContainerStats(ctx context.Context, containerID string) (*Co
 		BlockRead:     blockRead,
 		BlockWrite:    blockWrite,
 		PIDs:          stats.PidsStats.Current,
+
+		CPUTotalUsage:  stats.CPUStats.CPUUsage.TotalUsage,
+		SystemCPUUsage: stats.CPUStats.SystemUsage,
+		OnlineCPUs:     onlineCPUs,
 	}, nil
 }
 
@@ -1087,8 +1408,25 @@ func trimImageID(id string) string {
 	return truncateID(id, 12)
 }
 
-// formatPorts formats a slice of Port mappings into a human-readable string.
-func formatPorts(ports []types.Port) string {
+// toPortBindings converts the SDK's port list to the domain model.
+func toPortBindings(ports []types.Port) []PortBinding {
+	if len(ports) == 0 {
+		return nil
+	}
+	bindings := make([]PortBinding, len(ports))
+	for i, p := range ports {
+		bindings[i] = PortBinding{
+			HostIP:        p.IP,
+			HostPort:      p.PublicPort,
+			ContainerPort: p.PrivatePort,
+			Protocol:      p.Type,
+		}
+	}
+	return bindings
+}
+
+// formatPorts formats port bindings into a human-readable string.
+func formatPorts(ports []PortBinding) string {
 	if len(ports) == 0 {
 		return ""
 	}
@@ -1098,10 +1436,10 @@ func formatPorts(ports []types.Port) string {
 		if i > 0 {
 			b.WriteString(", ")
 		}
-		if p.PublicPort != 0 {
-			fmt.Fprintf(&b, "%d->%d/%s", p.PublicPort, p.PrivatePort, p.Type)
+		if p.Published() {
+			fmt.Fprintf(&b, "%d->%d/%s", p.HostPort, p.ContainerPort, p.Protocol)
 		} else {
-			fmt.Fprintf(&b, "%d/%s", p.PrivatePort, p.Type)
+			fmt.Fprintf(&b, "%d/%s", p.ContainerPort, p.Protocol)
 		}
 	}
 	return b.String()
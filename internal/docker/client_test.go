@@ -1,16 +1,22 @@
 package docker
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -182,6 +188,33 @@ func TestListContainers_HandlesEmptyNames(t *testing.T) {
 	assert.Equal(t, "", result[0].Name)
 }
 
+// TestListContainers_KeepsPortBindings tests structured port bindings
+func TestListContainers_KeepsPortBindings(t *testing.T) {
+	mock := &MockDockerAPI{
+		ContainerListFn: func(ctx context.Context, opts container.ListOptions) ([]types.Container, error) {
+			return []types.Container{{
+				ID:    "test123456789012",
+				Names: []string{"/web"},
+				Ports: []types.Port{
+					{IP: "127.0.0.1", PublicPort: 8080, PrivatePort: 80, Type: "tcp"},
+					{PrivatePort: 443, Type: "tcp"},
+				},
+			}}, nil
+		},
+	}
+
+	client := &Client{api: mock}
+	result, err := client.ListContainers(context.Background(), false)
+
+	require.NoError(t, err)
+	assert.Equal(t, []PortBinding{
+		{HostIP: "127.0.0.1", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+		{ContainerPort: 443, Protocol: "tcp"},
+	}, result[0].PortBindings)
+	assert.True(t, result[0].PortBindings[0].Published())
+	assert.False(t, result[0].PortBindings[1].Published())
+}
+
 // TestListContainers_FormatsPortsCorrectly tests port formatting
 func TestListContainers_FormatsPortsCorrectly(t *testing.T) {
 	tests := []struct {
@@ -1195,6 +1228,38 @@ func TestGroupByComposeProject(t *testing.T) {
 	})
 }
 
+// TestListContainers_PopulatesReferences tests that the image, named
+// volumes and networks of a container are listed
+func TestListContainers_PopulatesReferences(t *testing.T) {
+	sdkContainer := types.Container{
+		ID:      "abcdef1234567890abcdef1234567890",
+		Names:   []string{"/db"},
+		ImageID: "sha256:0123456789abcdef0123",
+		Mounts: []types.MountPoint{
+			{Type: mount.TypeVolume, Name: "pgdata"},
+			{Type: mount.TypeBind, Source: "/etc/hosts"},
+		},
+		NetworkSettings: &types.SummaryNetworkSettings{Networks: map[string]*network.EndpointSettings{
+			"shop_default": {}, "backend": {},
+		}},
+	}
+
+	mock := &MockDockerAPI{
+		ContainerListFn: func(ctx context.Context, opts container.ListOptions) ([]types.Container, error) {
+			return []types.Container{sdkContainer}, nil
+		},
+	}
+
+	client := &Client{api: mock}
+	result, err := client.ListContainers(context.Background(), true)
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "0123456789ab", result[0].ImageID)
+	assert.Equal(t, []string{"pgdata"}, result[0].Volumes)
+	assert.Equal(t, []string{"backend", "shop_default"}, result[0].Networks)
+}
+
 // TestListContainers_PopulatesLabels tests that Labels are populated from SDK data
 func TestListContainers_PopulatesLabels(t *testing.T) {
 	sdkContainer := types.Container{
@@ -1396,3 +1461,218 @@ func TestRestartComposeProject(t *testing.T) {
 	assert.Contains(t, restartedIDs, "bbb222bbb222")
 	assert.NotContains(t, restartedIDs, "ccc333ccc333")
 }
+
+// TestStreamEvents_ConvertsMessages tests SDK events are converted and errors reported
+func TestStreamEvents_ConvertsMessages(t *testing.T) {
+	msgs := make(chan events.Message, 1)
+	errs := make(chan error, 1)
+	mock := &MockDockerAPI{
+		EventsFn: func(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+			return msgs, errs
+		},
+	}
+	c := &Client{api: mock}
+	eventCh, errCh, cancel := c.StreamEvents(context.Background())
+	defer cancel()
+
+	msgs <- events.Message{
+		Type:     events.ContainerEventType,
+		Action:   events.ActionStart,
+		Actor:    events.Actor{ID: "abc", Attributes: map[string]string{"name": "web"}},
+		TimeNano: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).UnixNano(),
+	}
+	ev := <-eventCh
+	assert.Equal(t, "container", ev.Type)
+	assert.Equal(t, "start", ev.Action)
+	assert.Equal(t, "abc", ev.ID)
+	assert.Equal(t, "web", ev.Name)
+	assert.True(t, ev.Time.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+	errs <- errors.New("connection reset")
+	err := <-errCh
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection reset")
+	_, ok := <-eventCh
+	assert.False(t, ok)
+}
+
+// shopNetwork is a network with one running container attached
+func shopNetwork() network.Inspect {
+	return network.Inspect{
+		ID:     "net0123456789abcdef",
+		Name:   "shop",
+		Driver: "bridge",
+		IPAM: network.IPAM{Driver: "default", Config: []network.IPAMConfig{
+			{Subnet: "172.18.0.0/16", Gateway: "172.18.0.1"},
+		}},
+		Containers: map[string]network.EndpointResource{
+			"web0123456789abcdef": {Name: "web", IPv4Address: "172.18.0.2/16", MacAddress: "02:42:ac:12:00:02"},
+		},
+	}
+}
+
+// TestInspectNetwork tests endpoints come from the network, and stopped
+// containers from their own settings
+func TestInspectNetwork(t *testing.T) {
+	var listed container.ListOptions
+	mock := &MockDockerAPI{
+		NetworkInspectFn: func(ctx context.Context, id string, opts network.InspectOptions) (network.Inspect, error) {
+			return shopNetwork(), nil
+		},
+		ContainerListFn: func(ctx context.Context, opts container.ListOptions) ([]types.Container, error) {
+			listed = opts
+			return []types.Container{
+				{ID: "web0123456789abcdef", Names: []string{"/web"}, State: "running",
+					NetworkSettings: &types.SummaryNetworkSettings{Networks: map[string]*network.EndpointSettings{
+						"shop": {Aliases: []string{"web", "frontend"}},
+					}}},
+				{ID: "job0123456789abcdef", Names: []string{"/job"}, State: "exited",
+					NetworkSettings: &types.SummaryNetworkSettings{Networks: map[string]*network.EndpointSettings{
+						"shop": {MacAddress: "02:42:ac:12:00:09"},
+					}}},
+			}, nil
+		},
+	}
+
+	client := &Client{api: mock}
+	details, err := client.InspectNetwork(context.Background(), "shop")
+
+	require.NoError(t, err)
+	assert.True(t, listed.All)
+	assert.Equal(t, []string{"net0123456789abcdef"}, listed.Filters.Get("network"))
+	assert.Equal(t, "net012345678", details.ID)
+	assert.Equal(t, 2, details.Containers)
+	assert.Equal(t, []string{"172.18.0.0/16"}, details.Subnets)
+	assert.Equal(t, []IPAMPool{{Subnet: "172.18.0.0/16", Gateway: "172.18.0.1"}}, details.IPAM)
+	assert.Equal(t, "default", details.IPAMDriver)
+	assert.Equal(t, []NetworkEndpoint{
+		{ContainerID: "job012345678", Container: "job", State: "exited", MacAddress: "02:42:ac:12:00:09"},
+		{ContainerID: "web012345678", Container: "web", State: "running", IPv4Address: "172.18.0.2/16",
+			MacAddress: "02:42:ac:12:00:02", Aliases: []string{"web", "frontend"}},
+	}, details.Endpoints)
+}
+
+// TestConnectNetworkDryRun tests connecting warns about stopped containers
+// and refuses containers already attached
+func TestConnectNetworkDryRun(t *testing.T) {
+	inspected := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{Name: "/job", State: &types.ContainerState{Running: false}},
+		NetworkSettings:   &types.NetworkSettings{Networks: map[string]*network.EndpointSettings{"bridge": {}}},
+	}
+	mock := &MockDockerAPI{
+		NetworkInspectFn: func(ctx context.Context, id string, opts network.InspectOptions) (network.Inspect, error) {
+			return shopNetwork(), nil
+		},
+		ContainerInspectFn: func(ctx context.Context, id string) (types.ContainerJSON, error) {
+			return inspected, nil
+		},
+	}
+
+	client := &Client{api: mock}
+	info, err := client.ConnectNetworkDryRun(context.Background(), "shop", "job")
+
+	require.NoError(t, err)
+	assert.Equal(t, TierLowRisk, info.Tier)
+	assert.True(t, info.Reversible)
+	assert.Contains(t, info.Resources, "subnet: 172.18.0.0/16")
+	assert.Contains(t, info.Warnings, "Container is not running; it gets an address when it starts")
+
+	inspected.NetworkSettings.Networks["shop"] = &network.EndpointSettings{}
+	_, err = client.ConnectNetworkDryRun(context.Background(), "shop", "job")
+	assert.EqualError(t, err, "container job is already connected to shop")
+}
+
+// TestDisconnectNetworkDryRun tests disconnecting a container from its only
+// network is high risk
+func TestDisconnectNetworkDryRun(t *testing.T) {
+	inspected := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{Name: "/web", State: &types.ContainerState{Running: true}},
+		NetworkSettings: &types.NetworkSettings{Networks: map[string]*network.EndpointSettings{
+			"shop": {IPAddress: "172.18.0.2", IPPrefixLen: 16, Aliases: []string{"frontend"}},
+		}},
+	}
+	mock := &MockDockerAPI{
+		NetworkInspectFn: func(ctx context.Context, id string, opts network.InspectOptions) (network.Inspect, error) {
+			return shopNetwork(), nil
+		},
+		ContainerInspectFn: func(ctx context.Context, id string) (types.ContainerJSON, error) {
+			return inspected, nil
+		},
+	}
+
+	client := &Client{api: mock}
+	info, err := client.DisconnectNetworkDryRun(context.Background(), "shop", "web")
+
+	require.NoError(t, err)
+	assert.Equal(t, TierHighRisk, info.Tier)
+	assert.Contains(t, info.Resources, "address: 172.18.0.2/16")
+	assert.Contains(t, info.Warnings, "Aliases frontend are lost on reconnecting")
+	assert.Contains(t, info.UndoInstructions, "docker network connect shop web")
+
+	inspected.NetworkSettings.Networks["bridge"] = &network.EndpointSettings{}
+	info, err = client.DisconnectNetworkDryRun(context.Background(), "shop", "web")
+	require.NoError(t, err)
+	assert.Equal(t, TierModerate, info.Tier)
+
+	delete(inspected.NetworkSettings.Networks, "shop")
+	_, err = client.DisconnectNetworkDryRun(context.Background(), "shop", "web")
+	assert.EqualError(t, err, "container web is not connected to shop")
+}
+
+// TestStreamContainerLogs_KeepsStderrLabel tests followed lines keep the
+// stream they were written to, so the stderr ratio can be measured
+func TestStreamContainerLogs_KeepsStderrLabel(t *testing.T) {
+	var muxed bytes.Buffer
+	_, _ = stdcopy.NewStdWriter(&muxed, stdcopy.Stdout).Write([]byte("2024-01-01T00:00:00.000000000Z started\n"))
+	_, _ = stdcopy.NewStdWriter(&muxed, stdcopy.Stderr).Write([]byte("2024-01-01T00:00:01.000000000Z failed\n"))
+
+	mock := &MockDockerAPI{
+		ContainerLogsFn: func(ctx context.Context, ctr string, opts container.LogsOptions) (io.ReadCloser, error) {
+			return io.NopCloser(&muxed), nil
+		},
+	}
+	client := &Client{api: mock}
+	logCh, _, cancel := client.StreamContainerLogs(context.Background(), "web")
+	defer cancel()
+
+	streams := map[string]string{}
+	stderr := 0
+	for e := range logCh {
+		streams[e.Content] = e.Stream
+		if e.Stream == "stderr" {
+			stderr++
+		}
+	}
+	assert.Equal(t, map[string]string{"started": "stdout", "failed": "stderr"}, streams)
+	assert.Equal(t, 0.5, float64(stderr)/float64(len(streams)), "stderr ratio")
+}
+
+// TestStreamContainerLogs_KeepsOrder tests interleaved stdout and stderr
+// lines are delivered in the order the daemon sent them, including lines
+// split across frames
+func TestStreamContainerLogs_KeepsOrder(t *testing.T) {
+	var muxed bytes.Buffer
+	stdout := stdcopy.NewStdWriter(&muxed, stdcopy.Stdout)
+	stderr := stdcopy.NewStdWriter(&muxed, stdcopy.Stderr)
+	_, _ = stdout.Write([]byte("2024-01-01T00:00:00.000000000Z one\n"))
+	_, _ = stderr.Write([]byte("2024-01-01T00:00:00.000000000Z two\n"))
+	_, _ = stdout.Write([]byte("2024-01-01T00:00:00.000000000Z thr"))
+	_, _ = stdout.Write([]byte("ee\n"))
+	_, _ = stderr.Write([]byte("2024-01-01T00:00:00.000000000Z four\r\n"))
+	_, _ = stdout.Write([]byte("2024-01-01T00:00:01.000000000Z five"))
+
+	mock := &MockDockerAPI{
+		ContainerLogsFn: func(ctx context.Context, ctr string, opts container.LogsOptions) (io.ReadCloser, error) {
+			return io.NopCloser(&muxed), nil
+		},
+	}
+	client := &Client{api: mock}
+	logCh, _, cancel := client.StreamContainerLogs(context.Background(), "web")
+	defer cancel()
+
+	var got []string
+	for e := range logCh {
+		got = append(got, e.Stream+" "+e.Content)
+	}
+	assert.Equal(t, []string{"stdout one", "stderr two", "stdout three", "stderr four", "stdout five"}, got)
+}
@@ -0,0 +1,294 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+)
+
+// Backoff bounds used by FollowContainerLogs between reconnection attempts.
+const (
+	DefaultFollowMinBackoff = 500 * time.Millisecond
+	DefaultFollowMaxBackoff = 30 * time.Second
+)
+
+// FollowEventKind identifies what happened to a followed log stream.
+type FollowEventKind int
+
+const (
+	// FollowDisconnected means the stream ended; a reconnect follows after Delay.
+	FollowDisconnected FollowEventKind = iota
+	// FollowReconnected means entries are flowing again after a disconnect.
+	FollowReconnected
+	// FollowSwitched means the Compose service was recreated and the stream
+	// now follows the new container.
+	FollowSwitched
+)
+
+// FollowEvent reports a change in the state of a followed log stream.
+type FollowEvent struct {
+	Kind        FollowEventKind
+	ContainerID string        // container being followed after the event
+	Attempt     int           // consecutive reconnect attempts without entries
+	Delay       time.Duration // wait before the next attempt (FollowDisconnected)
+	Err         error         // stream error that caused the disconnect, if any
+}
+
+// String renders the event as a short status line.
+func (e FollowEvent) String() string {
+	switch e.Kind {
+	case FollowDisconnected:
+		s := fmt.Sprintf("Log stream for %s ended, reconnecting in %s (attempt %d)", e.ContainerID, e.Delay.Round(100*time.Millisecond), e.Attempt)
+		if e.Err != nil {
+			s += ": " + e.Err.Error()
+		}
+		return s
+	case FollowReconnected:
+		return fmt.Sprintf("Log stream for %s reconnected", e.ContainerID)
+	case FollowSwitched:
+		return fmt.Sprintf("Service recreated, now following %s", e.ContainerID)
+	default:
+		return ""
+	}
+}
+
+// FollowOptions configures FollowContainerLogs.
+type FollowOptions struct {
+	// Since resumes from this timestamp; entries at or before it are treated
+	// as already seen. Zero streams only new entries.
+	Since time.Time
+	// MinBackoff and MaxBackoff bound the exponential delay between
+	// reconnection attempts (Default* values when zero).
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// TrackService follows the container's Compose service (by its project
+	// and service labels) when the container is recreated under a new ID.
+	TrackService bool
+}
+
+// FollowContainerLogs streams a container's logs and keeps the stream alive:
+// when it ends (daemon restart, container restart or recreation) it
+// reconnects with exponential backoff, resuming from the last seen timestamp
+// and dropping entries that were already delivered. Both channels close
+// when ctx is cancelled or the returned cancel function is called.
+func FollowContainerLogs(ctx context.Context, svc DockerService, containerID string, opts FollowOptions) (<-chan LogEntry, <-chan FollowEvent, func()) {
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = DefaultFollowMinBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = max(DefaultFollowMaxBackoff, opts.MinBackoff)
+	}
+
+	out := make(chan LogEntry, 100)
+	events := make(chan FollowEvent, 16)
+	ctx, cancel := context.WithCancel(ctx)
+
+	go func() {
+		defer close(out)
+		defer close(events)
+
+		f := &follower{svc: svc, target: containerID, last: opts.Since, events: events}
+		if opts.TrackService {
+			f.project, f.service = composeIdentity(ctx, svc, containerID)
+		}
+
+		attempt := 0
+		for {
+			started := time.Now()
+			received, err := f.stream(ctx, out)
+			if ctx.Err() != nil {
+				return
+			}
+			if received {
+				attempt = 0
+			}
+			if f.last.IsZero() {
+				// Nothing seen yet: resume from when this stream started
+				// so lines written during the gap are not lost.
+				f.last = started
+				f.seen = make(map[string]int)
+			}
+			attempt++
+			delay := followBackoff(attempt, opts.MinBackoff, opts.MaxBackoff)
+			if !sendEvent(ctx, events, FollowEvent{Kind: FollowDisconnected, ContainerID: f.target, Attempt: attempt, Delay: delay, Err: err}) {
+				return
+			}
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+
+			if f.service != "" {
+				if id := resolveComposeService(ctx, svc, f.project, f.service); id != "" && id != f.target {
+					f.target = id
+					if !sendEvent(ctx, events, FollowEvent{Kind: FollowSwitched, ContainerID: id}) {
+						return
+					}
+				}
+			}
+			f.reconnecting = true
+		}
+	}()
+
+	return out, events, cancel
+}
+
+// follower is the state FollowContainerLogs carries across reconnects.
+type follower struct {
+	svc              DockerService
+	target           string
+	project, service string
+
+	last time.Time      // timestamp of the newest delivered entry
+	seen map[string]int // entries delivered at exactly last; nil means all of them
+
+	// The resume point of the current stream and the entries at it still
+	// to be dropped as repeats; nil means all of them. resuming ends with
+	// the first entry past since.
+	since    time.Time
+	skip     map[string]int
+	resuming bool
+
+	reconnecting bool // a FollowReconnected event is due on the next entry
+	events       chan<- FollowEvent
+}
+
+// stream pumps one connection until it ends. It reports whether any new
+// entry was delivered and the stream error, if one was reported.
+func (f *follower) stream(ctx context.Context, out chan<- LogEntry) (bool, error) {
+	f.since, f.skip, f.resuming = f.last, maps.Clone(f.seen), !f.last.IsZero()
+	logCh, errCh, stop := f.svc.StreamContainerLogsSince(ctx, f.target, f.since)
+	defer stop()
+
+	received := false
+	for {
+		select {
+		case entry, ok := <-logCh:
+			if !ok {
+				return received, nil
+			}
+			if !f.accept(entry) {
+				continue
+			}
+			if f.reconnecting {
+				f.reconnecting = false
+				if !sendEvent(ctx, f.events, FollowEvent{Kind: FollowReconnected, ContainerID: f.target}) {
+					return received, nil
+				}
+			}
+			select {
+			case out <- entry:
+				received = true
+			case <-ctx.Done():
+				return received, nil
+			}
+		case err, ok := <-errCh:
+			if ok && err != nil {
+				return received, err
+			}
+			errCh = nil // closed without error; wait for logCh to close
+		case <-ctx.Done():
+			return received, nil
+		}
+	}
+}
+
+// accept reports whether an entry is new, updating the resume point.
+// Resuming with Since is inclusive, so until the stream passes the resume
+// point its entries are compared against those already delivered. Past it
+// every entry is new, even out of order or repeated in the same instant.
+func (f *follower) accept(e LogEntry) bool {
+	if e.Timestamp.IsZero() {
+		return true // no timestamp to deduplicate on
+	}
+	key := e.Stream + "\x00" + e.Content
+	if f.resuming {
+		switch {
+		case e.Timestamp.After(f.since):
+			f.resuming = false
+		case e.Timestamp.Before(f.since), f.skip == nil:
+			return false
+		case f.skip[key] > 0:
+			f.skip[key]--
+			return false
+		}
+	}
+	switch {
+	case f.last.IsZero() || e.Timestamp.After(f.last):
+		f.last = e.Timestamp
+		f.seen = map[string]int{key: 1}
+	case e.Timestamp.Equal(f.last):
+		if f.seen == nil {
+			f.seen = make(map[string]int)
+		}
+		f.seen[key]++
+	}
+	return true
+}
+
+// followBackoff returns the delay before reconnect attempt n (1-based).
+func followBackoff(n int, minDelay, maxDelay time.Duration) time.Duration {
+	d := minDelay
+	for i := 1; i < n && d < maxDelay; i++ {
+		d *= 2
+	}
+	return min(d, maxDelay)
+}
+
+func sendEvent(ctx context.Context, events chan<- FollowEvent, e FollowEvent) bool {
+	select {
+	case events <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// composeIdentity returns the Compose project and service labels of the
+// container referenced by ID prefix or name, or empty strings.
+func composeIdentity(ctx context.Context, svc DockerService, ref string) (string, string) {
+	listCtx, cancel := context.WithTimeout(ctx, TimeoutList)
+	defer cancel()
+	containers, err := svc.ListContainers(listCtx, true)
+	if err != nil {
+		return "", ""
+	}
+	ref = strings.TrimPrefix(ref, "/")
+	for _, c := range containers {
+		if c.Name == ref || (len(ref) >= 4 && (strings.HasPrefix(c.ID, ref) || strings.HasPrefix(ref, c.ID))) {
+			return c.Labels[ComposeProjectLabel], c.Labels[ComposeServiceLabel]
+		}
+	}
+	return "", ""
+}
+
+// resolveComposeService returns the container currently backing a Compose
+// service: a running one if any, otherwise the most recently created.
+func resolveComposeService(ctx context.Context, svc DockerService, project, service string) string {
+	listCtx, cancel := context.WithTimeout(ctx, TimeoutList)
+	defer cancel()
+	containers, err := svc.ListContainers(listCtx, true)
+	if err != nil {
+		return ""
+	}
+	var best *ContainerInfo
+	for i := range containers {
+		c := &containers[i]
+		if c.Labels[ComposeProjectLabel] != project || c.Labels[ComposeServiceLabel] != service {
+			continue
+		}
+		if best == nil ||
+			(c.State == "running" && best.State != "running") ||
+			(c.State == best.State && c.Created.After(best.Created)) {
+			best = c
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.ID
+}
@@ -0,0 +1,164 @@
+package docker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedStreams returns a StreamContainerLogsSinceFn that serves one batch
+// of entries per call (then closes the stream), recording each call.
+func scriptedStreams(batches ...[]LogEntry) (func(context.Context, string, time.Time) (<-chan LogEntry, <-chan error, func()), func() []streamCall) {
+	var mu sync.Mutex
+	var calls []streamCall
+	fn := func(ctx context.Context, id string, since time.Time) (<-chan LogEntry, <-chan error, func()) {
+		mu.Lock()
+		n := len(calls)
+		calls = append(calls, streamCall{id: id, since: since})
+		mu.Unlock()
+
+		logCh := make(chan LogEntry, 10)
+		errCh := make(chan error)
+		if n < len(batches) {
+			for _, e := range batches[n] {
+				logCh <- e
+			}
+			close(logCh)
+		}
+		// Past the script the stream stays open until cancelled.
+		return logCh, errCh, func() {}
+	}
+	return fn, func() []streamCall {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]streamCall(nil), calls...)
+	}
+}
+
+type streamCall struct {
+	id    string
+	since time.Time
+}
+
+func logAt(sec int, content string) LogEntry {
+	return LogEntry{Timestamp: testTime.Add(time.Duration(sec) * time.Second), Stream: "stdout", Content: content}
+}
+
+func receiveEntries(t *testing.T, ch <-chan LogEntry, n int) []LogEntry {
+	t.Helper()
+	var got []LogEntry
+	for len(got) < n {
+		select {
+		case e := <-ch:
+			got = append(got, e)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out after %d of %d entries", len(got), n)
+		}
+	}
+	return got
+}
+
+func TestFollowContainerLogs_ResumesWithoutDuplicates(t *testing.T) {
+	streamFn, calls := scriptedStreams(
+		[]LogEntry{logAt(1, "a"), logAt(2, "b")},
+		// Resumed stream repeats the entry at the resume timestamp
+		[]LogEntry{logAt(2, "b"), logAt(2, "b2"), logAt(3, "c")},
+	)
+	mock := &MockDockerService{StreamContainerLogsSinceFn: streamFn}
+
+	out, events, cancel := FollowContainerLogs(context.Background(), mock, "web", FollowOptions{MinBackoff: time.Millisecond})
+	defer cancel()
+
+	got := receiveEntries(t, out, 4)
+	var contents []string
+	for _, e := range got {
+		contents = append(contents, e.Content)
+	}
+	assert.Equal(t, []string{"a", "b", "b2", "c"}, contents)
+
+	ev := <-events
+	assert.Equal(t, FollowDisconnected, ev.Kind)
+	assert.Equal(t, 1, ev.Attempt)
+	assert.Equal(t, FollowReconnected, (<-events).Kind)
+
+	c := calls()
+	require.GreaterOrEqual(t, len(c), 2)
+	assert.True(t, c[0].since.IsZero(), "first stream starts with new lines only")
+	assert.True(t, c[1].since.Equal(testTime.Add(2*time.Second)), "resume from last timestamp")
+}
+
+func TestFollowContainerLogs_KeepsLiveOutOfOrderAndRepeatedLines(t *testing.T) {
+	streamFn, _ := scriptedStreams(
+		// Live: a late line and the same line twice in one instant
+		[]LogEntry{logAt(1, "a"), logAt(3, "b"), logAt(2, "late"), logAt(3, "x"), logAt(3, "x")},
+		// Resumed at 3: the repeats of what was delivered at 3 are dropped,
+		// a third "x" at 3 and everything past the resume point is not
+		[]LogEntry{logAt(2, "late"), logAt(3, "b"), logAt(3, "x"), logAt(3, "x"), logAt(3, "x"), logAt(4, "y"), logAt(4, "z"), logAt(4, "z")},
+	)
+	mock := &MockDockerService{StreamContainerLogsSinceFn: streamFn}
+
+	out, _, cancel := FollowContainerLogs(context.Background(), mock, "web", FollowOptions{MinBackoff: time.Millisecond})
+	defer cancel()
+
+	var contents []string
+	for _, e := range receiveEntries(t, out, 9) {
+		contents = append(contents, e.Content)
+	}
+	assert.Equal(t, []string{"a", "b", "late", "x", "x", "x", "y", "z", "z"}, contents)
+	select {
+	case e := <-out:
+		t.Fatalf("unexpected entry %q", e.Content)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestFollowContainerLogs_TracksComposeService(t *testing.T) {
+	labels := map[string]string{ComposeProjectLabel: "shop", ComposeServiceLabel: "api"}
+	var mu sync.Mutex
+	recreated := false
+	mock := &MockDockerService{
+		ListContainersFn: func(_ context.Context, _ bool) ([]ContainerInfo, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if !recreated {
+				return []ContainerInfo{{ID: "aaaaaaaaaaaa", Name: "shop-api-1", State: "running", Labels: labels}}, nil
+			}
+			return []ContainerInfo{
+				{ID: "aaaaaaaaaaaa", Name: "shop-api-1", State: "exited", Labels: labels, Created: testTime},
+				{ID: "bbbbbbbbbbbb", Name: "shop-api-1", State: "running", Labels: labels, Created: testTime.Add(time.Minute)},
+				{ID: "cccccccccccc", Name: "other", State: "running", Created: testTime.Add(time.Hour)},
+			}, nil
+		},
+	}
+	streamFn, calls := scriptedStreams([]LogEntry{logAt(1, "old")}, []LogEntry{logAt(5, "new")})
+	mock.StreamContainerLogsSinceFn = func(ctx context.Context, id string, since time.Time) (<-chan LogEntry, <-chan error, func()) {
+		mu.Lock()
+		recreated = true
+		mu.Unlock()
+		return streamFn(ctx, id, since)
+	}
+
+	out, events, cancel := FollowContainerLogs(context.Background(), mock, "shop-api-1", FollowOptions{MinBackoff: time.Millisecond, TrackService: true})
+	defer cancel()
+
+	receiveEntries(t, out, 2)
+	assert.Equal(t, FollowDisconnected, (<-events).Kind)
+	ev := <-events
+	assert.Equal(t, FollowSwitched, ev.Kind)
+	assert.Equal(t, "bbbbbbbbbbbb", ev.ContainerID)
+
+	c := calls()
+	require.GreaterOrEqual(t, len(c), 2)
+	assert.Equal(t, "shop-api-1", c[0].id)
+	assert.Equal(t, "bbbbbbbbbbbb", c[1].id)
+}
+
+func TestFollowBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, followBackoff(1, time.Second, 10*time.Second))
+	assert.Equal(t, 4*time.Second, followBackoff(3, time.Second, 10*time.Second))
+	assert.Equal(t, 10*time.Second, followBackoff(20, time.Second, 10*time.Second))
+}
@@ -3,9 +3,11 @@ package docker
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
@@ -28,10 +30,14 @@ type DockerAPI interface {
 	ImageRemove(ctx context.Context, imageID string, options image.RemoveOptions) ([]image.DeleteResponse, error)
 	VolumeRemove(ctx context.Context, volumeID string, force bool) error
 	NetworkRemove(ctx context.Context, networkID string) error
+	NetworkInspect(ctx context.Context, networkID string, options network.InspectOptions) (network.Inspect, error)
+	NetworkConnect(ctx context.Context, networkID, containerID string, config *network.EndpointSettings) error
+	NetworkDisconnect(ctx context.Context, networkID, containerID string, force bool) error
 	ContainerStart(ctx context.Context, containerID string, options container.StartOptions) error
 	ContainerStop(ctx context.Context, containerID string, options container.StopOptions) error
 	ContainerRestart(ctx context.Context, containerID string, options container.StopOptions) error
 	ContainerLogs(ctx context.Context, container string, options container.LogsOptions) (io.ReadCloser, error)
+	ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error)
 	ContainerStatsOneShot(ctx context.Context, containerID string) (container.StatsResponseReader, error)
 	ContainersPrune(ctx context.Context, pruneFilters filters.Args) (container.PruneReport, error)
 	ImagesPrune(ctx context.Context, pruneFilters filters.Args) (image.PruneReport, error)
@@ -43,6 +49,7 @@ type DockerAPI interface {
 	ContainerExecResize(ctx context.Context, execID string, options container.ResizeOptions) error
 	ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error)
 	ContainerExecStart(ctx context.Context, execID string, config container.ExecStartOptions) error
+	Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error)
 }
 
 // DockerService interface provides domain-level Docker operations.
@@ -52,6 +59,7 @@ type DockerService interface {
 	Close() error
 	GetServerInfo(ctx context.Context) (system.Info, error)
 	ListContainers(ctx context.Context, all bool) ([]ContainerInfo, error)
+	InspectContainer(ctx context.Context, id string) (types.ContainerJSON, error)
 	ListImages(ctx context.Context, all bool) ([]ImageInfo, error)
 	ListVolumes(ctx context.Context) ([]VolumeInfo, error)
 	ListNetworks(ctx context.Context) ([]NetworkInfo, error)
@@ -74,6 +82,9 @@ type DockerService interface {
 	// Log methods
 	GetContainerLogs(ctx context.Context, containerID string, tail int) ([]LogEntry, error)
 	StreamContainerLogs(ctx context.Context, containerID string) (<-chan LogEntry, <-chan error, func())
+	StreamContainerLogsSince(ctx context.Context, containerID string, since time.Time) (<-chan LogEntry, <-chan error, func())
+	// StreamEvents follows the daemon's event stream from now on
+	StreamEvents(ctx context.Context) (<-chan Event, <-chan error, func())
 	// Metrics methods
 	GetContainerStats(ctx context.Context, containerID string) (*ContainerMetrics, error)
 	// DryRun methods return what WOULD be deleted without actually deleting
@@ -89,6 +100,12 @@ type DockerService interface {
 	StartComposeProject(ctx context.Context, projectName string) (int, error)
 	StopComposeProject(ctx context.Context, projectName string) (int, error)
 	RestartComposeProject(ctx context.Context, projectName string) (int, error)
+	// Network attachments
+	InspectNetwork(ctx context.Context, id string) (NetworkDetails, error)
+	ConnectNetwork(ctx context.Context, networkID, containerID string) error
+	DisconnectNetwork(ctx context.Context, networkID, containerID string) error
+	ConnectNetworkDryRun(ctx context.Context, networkID, containerID string) (ConfirmationInfo, error)
+	DisconnectNetworkDryRun(ctx context.Context, networkID, containerID string) (ConfirmationInfo, error)
 	// API returns the underlying DockerAPI for direct access (used by exec)
 	API() DockerAPI
 }
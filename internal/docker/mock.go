@@ -7,6 +7,7 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
@@ -25,44 +26,52 @@ var _ DockerService = (*MockDockerService)(nil)
 // MockDockerService is a hand-rolled mock implementation of DockerService.
 // Use it in tests by setting the function fields to return specific values.
 type MockDockerService struct {
-	PingFn                  func(ctx context.Context) error
-	CloseFn                 func() error
-	GetServerInfoFn         func(ctx context.Context) (system.Info, error)
-	ListContainersFn        func(ctx context.Context, all bool) ([]ContainerInfo, error)
-	ListImagesFn            func(ctx context.Context, all bool) ([]ImageInfo, error)
-	ListVolumesFn           func(ctx context.Context) ([]VolumeInfo, error)
-	ListNetworksFn          func(ctx context.Context) ([]NetworkInfo, error)
-	GetDanglingImagesFn     func(ctx context.Context) ([]ImageInfo, error)
-	GetStoppedContainersFn  func(ctx context.Context) ([]ContainerInfo, error)
-	GetUnusedVolumesFn      func(ctx context.Context) ([]VolumeInfo, error)
-	GetDiskUsageFn          func(ctx context.Context) (*DiskUsageInfo, error)
-	RemoveContainerFn       func(ctx context.Context, id string, force bool) error
-	RemoveImageFn           func(ctx context.Context, id string, force bool) error
-	RemoveVolumeFn          func(ctx context.Context, name string, force bool) error
-	RemoveNetworkFn         func(ctx context.Context, id string) error
-	StartContainerFn        func(ctx context.Context, id string) error
-	StopContainerFn         func(ctx context.Context, id string) error
-	RestartContainerFn      func(ctx context.Context, id string) error
-	PruneContainersFn       func(ctx context.Context) (uint64, error)
-	PruneImagesFn           func(ctx context.Context, all bool) (uint64, error)
-	PruneVolumesFn          func(ctx context.Context) (uint64, error)
-	PruneNetworksFn         func(ctx context.Context) error
-	PruneBuildCacheFn       func(ctx context.Context, all bool) (uint64, error)
-	RemoveContainerDryRunFn func(ctx context.Context, id string) (ConfirmationInfo, error)
-	RemoveImageDryRunFn     func(ctx context.Context, id string) (ConfirmationInfo, error)
-	RemoveVolumeDryRunFn    func(ctx context.Context, name string) (ConfirmationInfo, error)
-	RemoveNetworkDryRunFn   func(ctx context.Context, id string) (ConfirmationInfo, error)
-	PruneContainersDryRunFn func(ctx context.Context) (ConfirmationInfo, error)
-	PruneImagesDryRunFn     func(ctx context.Context, all bool) (ConfirmationInfo, error)
-	PruneVolumesDryRunFn    func(ctx context.Context) (ConfirmationInfo, error)
-	PruneNetworksDryRunFn   func(ctx context.Context) (ConfirmationInfo, error)
-	GetContainerLogsFn      func(ctx context.Context, containerID string, tail int) ([]LogEntry, error)
-	StreamContainerLogsFn   func(ctx context.Context, containerID string) (<-chan LogEntry, <-chan error, func())
-	GetContainerStatsFn     func(ctx context.Context, containerID string) (*ContainerMetrics, error)
-	StartComposeProjectFn   func(ctx context.Context, projectName string) (int, error)
-	StopComposeProjectFn    func(ctx context.Context, projectName string) (int, error)
-	RestartComposeProjectFn func(ctx context.Context, projectName string) (int, error)
-	APIFn                   func() DockerAPI
+	PingFn                     func(ctx context.Context) error
+	CloseFn                    func() error
+	GetServerInfoFn            func(ctx context.Context) (system.Info, error)
+	ListContainersFn           func(ctx context.Context, all bool) ([]ContainerInfo, error)
+	InspectContainerFn         func(ctx context.Context, id string) (types.ContainerJSON, error)
+	ListImagesFn               func(ctx context.Context, all bool) ([]ImageInfo, error)
+	ListVolumesFn              func(ctx context.Context) ([]VolumeInfo, error)
+	ListNetworksFn             func(ctx context.Context) ([]NetworkInfo, error)
+	GetDanglingImagesFn        func(ctx context.Context) ([]ImageInfo, error)
+	GetStoppedContainersFn     func(ctx context.Context) ([]ContainerInfo, error)
+	GetUnusedVolumesFn         func(ctx context.Context) ([]VolumeInfo, error)
+	GetDiskUsageFn             func(ctx context.Context) (*DiskUsageInfo, error)
+	RemoveContainerFn          func(ctx context.Context, id string, force bool) error
+	RemoveImageFn              func(ctx context.Context, id string, force bool) error
+	RemoveVolumeFn             func(ctx context.Context, name string, force bool) error
+	RemoveNetworkFn            func(ctx context.Context, id string) error
+	StartContainerFn           func(ctx context.Context, id string) error
+	StopContainerFn            func(ctx context.Context, id string) error
+	RestartContainerFn         func(ctx context.Context, id string) error
+	PruneContainersFn          func(ctx context.Context) (uint64, error)
+	PruneImagesFn              func(ctx context.Context, all bool) (uint64, error)
+	PruneVolumesFn             func(ctx context.Context) (uint64, error)
+	PruneNetworksFn            func(ctx context.Context) error
+	PruneBuildCacheFn          func(ctx context.Context, all bool) (uint64, error)
+	RemoveContainerDryRunFn    func(ctx context.Context, id string) (ConfirmationInfo, error)
+	RemoveImageDryRunFn        func(ctx context.Context, id string) (ConfirmationInfo, error)
+	RemoveVolumeDryRunFn       func(ctx context.Context, name string) (ConfirmationInfo, error)
+	RemoveNetworkDryRunFn      func(ctx context.Context, id string) (ConfirmationInfo, error)
+	PruneContainersDryRunFn    func(ctx context.Context) (ConfirmationInfo, error)
+	PruneImagesDryRunFn        func(ctx context.Context, all bool) (ConfirmationInfo, error)
+	PruneVolumesDryRunFn       func(ctx context.Context) (ConfirmationInfo, error)
+	PruneNetworksDryRunFn      func(ctx context.Context) (ConfirmationInfo, error)
+	GetContainerLogsFn         func(ctx context.Context, containerID string, tail int) ([]LogEntry, error)
+	StreamContainerLogsFn      func(ctx context.Context, containerID string) (<-chan LogEntry, <-chan error, func())
+	StreamContainerLogsSinceFn func(ctx context.Context, containerID string, since time.Time) (<-chan LogEntry, <-chan error, func())
+	StreamEventsFn             func(ctx context.Context) (<-chan Event, <-chan error, func())
+	GetContainerStatsFn        func(ctx context.Context, containerID string) (*ContainerMetrics, error)
+	StartComposeProjectFn      func(ctx context.Context, projectName string) (int, error)
+	StopComposeProjectFn       func(ctx context.Context, projectName string) (int, error)
+	RestartComposeProjectFn    func(ctx context.Context, projectName string) (int, error)
+	InspectNetworkFn           func(ctx context.Context, id string) (NetworkDetails, error)
+	ConnectNetworkFn           func(ctx context.Context, networkID, containerID string) error
+	DisconnectNetworkFn        func(ctx context.Context, networkID, containerID string) error
+	ConnectNetworkDryRunFn     func(ctx context.Context, networkID, containerID string) (ConfirmationInfo, error)
+	DisconnectNetworkDryRunFn  func(ctx context.Context, networkID, containerID string) (ConfirmationInfo, error)
+	APIFn                      func() DockerAPI
 }
 
 func (m *MockDockerService) Ping(ctx context.Context) error {
@@ -86,6 +95,16 @@ func (m *MockDockerService) GetServerInfo(ctx context.Context) (system.Info, err
 	return system.Info{}, nil
 }
 
+func (m *MockDockerService) InspectContainer(ctx context.Context, id string) (types.ContainerJSON, error) {
+	if m.InspectContainerFn != nil {
+		return m.InspectContainerFn(ctx, id)
+	}
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{ID: id, Name: "/" + id, State: &types.ContainerState{Status: "running", Running: true}},
+		Config:            &container.Config{},
+	}, nil
+}
+
 func (m *MockDockerService) ListContainers(ctx context.Context, all bool) ([]ContainerInfo, error) {
 	if m.ListContainersFn != nil {
 		return m.ListContainersFn(ctx, all)
@@ -305,6 +324,26 @@ func (m *MockDockerService) StreamContainerLogs(ctx context.Context, containerID
 	return logCh, errCh, func() {}
 }
 
+// StreamContainerLogsSince falls back to StreamContainerLogs when no
+// StreamContainerLogsSinceFn is set.
+func (m *MockDockerService) StreamContainerLogsSince(ctx context.Context, containerID string, since time.Time) (<-chan LogEntry, <-chan error, func()) {
+	if m.StreamContainerLogsSinceFn != nil {
+		return m.StreamContainerLogsSinceFn(ctx, containerID, since)
+	}
+	return m.StreamContainerLogs(ctx, containerID)
+}
+
+// StreamEvents returns an empty, closed stream when no StreamEventsFn is set.
+func (m *MockDockerService) StreamEvents(ctx context.Context) (<-chan Event, <-chan error, func()) {
+	if m.StreamEventsFn != nil {
+		return m.StreamEventsFn(ctx)
+	}
+	eventCh := make(chan Event)
+	errCh := make(chan error)
+	close(eventCh)
+	return eventCh, errCh, func() {}
+}
+
 func (m *MockDockerService) GetContainerStats(ctx context.Context, containerID string) (*ContainerMetrics, error) {
 	if m.GetContainerStatsFn != nil {
 		return m.GetContainerStatsFn(ctx, containerID)
@@ -339,6 +378,41 @@ func (m *MockDockerService) RestartComposeProject(ctx context.Context, projectNa
 	return 0, nil
 }
 
+func (m *MockDockerService) InspectNetwork(ctx context.Context, id string) (NetworkDetails, error) {
+	if m.InspectNetworkFn != nil {
+		return m.InspectNetworkFn(ctx, id)
+	}
+	return NetworkDetails{}, nil
+}
+
+func (m *MockDockerService) ConnectNetwork(ctx context.Context, networkID, containerID string) error {
+	if m.ConnectNetworkFn != nil {
+		return m.ConnectNetworkFn(ctx, networkID, containerID)
+	}
+	return nil
+}
+
+func (m *MockDockerService) DisconnectNetwork(ctx context.Context, networkID, containerID string) error {
+	if m.DisconnectNetworkFn != nil {
+		return m.DisconnectNetworkFn(ctx, networkID, containerID)
+	}
+	return nil
+}
+
+func (m *MockDockerService) ConnectNetworkDryRun(ctx context.Context, networkID, containerID string) (ConfirmationInfo, error) {
+	if m.ConnectNetworkDryRunFn != nil {
+		return m.ConnectNetworkDryRunFn(ctx, networkID, containerID)
+	}
+	return ConfirmationInfo{}, nil
+}
+
+func (m *MockDockerService) DisconnectNetworkDryRun(ctx context.Context, networkID, containerID string) (ConfirmationInfo, error) {
+	if m.DisconnectNetworkDryRunFn != nil {
+		return m.DisconnectNetworkDryRunFn(ctx, networkID, containerID)
+	}
+	return ConfirmationInfo{}, nil
+}
+
 func (m *MockDockerService) API() DockerAPI {
 	if m.APIFn != nil {
 		return m.APIFn()
@@ -364,6 +438,9 @@ type MockDockerAPI struct {
 	ImageRemoveFn           func(ctx context.Context, imageID string, options image.RemoveOptions) ([]image.DeleteResponse, error)
 	VolumeRemoveFn          func(ctx context.Context, volumeID string, force bool) error
 	NetworkRemoveFn         func(ctx context.Context, networkID string) error
+	NetworkInspectFn        func(ctx context.Context, networkID string, options network.InspectOptions) (network.Inspect, error)
+	NetworkConnectFn        func(ctx context.Context, networkID, containerID string, config *network.EndpointSettings) error
+	NetworkDisconnectFn     func(ctx context.Context, networkID, containerID string, force bool) error
 	ContainerStartFn        func(ctx context.Context, containerID string, options container.StartOptions) error
 	ContainerStopFn         func(ctx context.Context, containerID string, options container.StopOptions) error
 	ContainerRestartFn      func(ctx context.Context, containerID string, options container.StopOptions) error
@@ -374,11 +451,13 @@ type MockDockerAPI struct {
 	BuildCachePruneFn       func(ctx context.Context, opts types.BuildCachePruneOptions) (*types.BuildCachePruneReport, error)
 	ContainerLogsFn         func(ctx context.Context, ctr string, options container.LogsOptions) (io.ReadCloser, error)
 	ContainerStatsOneShotFn func(ctx context.Context, containerID string) (container.StatsResponseReader, error)
+	ContainerInspectFn      func(ctx context.Context, containerID string) (types.ContainerJSON, error)
 	ContainerExecCreateFn   func(ctx context.Context, containerID string, options container.ExecOptions) (types.IDResponse, error)
 	ContainerExecAttachFn   func(ctx context.Context, execID string, config container.ExecAttachOptions) (types.HijackedResponse, error)
 	ContainerExecResizeFn   func(ctx context.Context, execID string, options container.ResizeOptions) error
 	ContainerExecInspectFn  func(ctx context.Context, execID string) (container.ExecInspect, error)
 	ContainerExecStartFn    func(ctx context.Context, execID string, config container.ExecStartOptions) error
+	EventsFn                func(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error)
 }
 
 func (m *MockDockerAPI) Ping(ctx context.Context) (types.Ping, error) {
@@ -465,6 +544,27 @@ func (m *MockDockerAPI) NetworkRemove(ctx context.Context, networkID string) err
 	return nil
 }
 
+func (m *MockDockerAPI) NetworkInspect(ctx context.Context, networkID string, options network.InspectOptions) (network.Inspect, error) {
+	if m.NetworkInspectFn != nil {
+		return m.NetworkInspectFn(ctx, networkID, options)
+	}
+	return network.Inspect{}, nil
+}
+
+func (m *MockDockerAPI) NetworkConnect(ctx context.Context, networkID, containerID string, config *network.EndpointSettings) error {
+	if m.NetworkConnectFn != nil {
+		return m.NetworkConnectFn(ctx, networkID, containerID, config)
+	}
+	return nil
+}
+
+func (m *MockDockerAPI) NetworkDisconnect(ctx context.Context, networkID, containerID string, force bool) error {
+	if m.NetworkDisconnectFn != nil {
+		return m.NetworkDisconnectFn(ctx, networkID, containerID, force)
+	}
+	return nil
+}
+
 func (m *MockDockerAPI) ContainerStart(ctx context.Context, containerID string, options container.StartOptions) error {
 	if m.ContainerStartFn != nil {
 		return m.ContainerStartFn(ctx, containerID, options)
@@ -535,6 +635,13 @@ func (m *MockDockerAPI) ContainerStatsOneShot(ctx context.Context, containerID s
 	return container.StatsResponseReader{Body: io.NopCloser(io.LimitReader(nil, 0))}, nil
 }
 
+func (m *MockDockerAPI) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	if m.ContainerInspectFn != nil {
+		return m.ContainerInspectFn(ctx, containerID)
+	}
+	return types.ContainerJSON{}, nil
+}
+
 func (m *MockDockerAPI) ContainerExecCreate(ctx context.Context, containerID string, options container.ExecOptions) (types.IDResponse, error) {
 	if m.ContainerExecCreateFn != nil {
 		return m.ContainerExecCreateFn(ctx, containerID, options)
@@ -569,3 +676,12 @@ func (m *MockDockerAPI) ContainerExecStart(ctx context.Context, execID string, c
 	}
 	return nil
 }
+
+func (m *MockDockerAPI) Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+	if m.EventsFn != nil {
+		return m.EventsFn(ctx, options)
+	}
+	msgCh := make(chan events.Message)
+	errCh := make(chan error)
+	return msgCh, errCh
+}
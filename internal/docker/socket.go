@@ -4,8 +4,22 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 )
 
+// IsLocalHost reports whether a DOCKER_HOST value reaches the daemon over a
+// Unix socket on this machine. An empty value means the detected socket.
+func IsLocalHost(dockerHost string) bool {
+	return dockerHost == "" || strings.HasPrefix(dockerHost, "unix://")
+}
+
+// IsLocalDaemon reports whether the daemon shares this host's filesystem and
+// network namespace: a Linux host reached over a local socket. On macOS and
+// Windows the socket leads into Docker Desktop's VM.
+func IsLocalDaemon() bool {
+	return runtime.GOOS == "linux" && IsLocalHost(os.Getenv("DOCKER_HOST"))
+}
+
 // detectDockerSocket returns the Docker socket path based on platform
 func detectDockerSocket() string {
 	switch runtime.GOOS {
@@ -17,6 +17,26 @@ type ContainerInfo struct {
 	Ports   string            `json:"containerPorts" yaml:"containerPorts"`
 	Size    int64             `json:"containerSize" yaml:"containerSize"`
 	Labels  map[string]string `json:"containerLabels" yaml:"containerLabels"` // Container labels (includes Compose metadata)
+
+	PortBindings []PortBinding `json:"containerPortBindings" yaml:"containerPortBindings"`
+
+	ImageID  string   `json:"containerImageId" yaml:"containerImageId"`
+	Volumes  []string `json:"containerVolumes" yaml:"containerVolumes"`   // Named volumes mounted
+	Networks []string `json:"containerNetworks" yaml:"containerNetworks"` // Networks joined, sorted
+}
+
+// PortBinding is an exposed container port, published on the host when
+// HostPort is non-zero. Docker reports IPv4 and IPv6 bindings separately.
+type PortBinding struct {
+	HostIP        string `json:"portHostIp" yaml:"portHostIp"`
+	HostPort      uint16 `json:"portHostPort" yaml:"portHostPort"`
+	ContainerPort uint16 `json:"portContainerPort" yaml:"portContainerPort"`
+	Protocol      string `json:"portProtocol" yaml:"portProtocol"` // tcp, udp or sctp
+}
+
+// Published reports whether the port is reachable from the host.
+func (p PortBinding) Published() bool {
+	return p.HostPort != 0
 }
 
 // ImageInfo holds image details for display
@@ -49,6 +69,39 @@ type NetworkInfo struct {
 	Scope      string `json:"networkScope" yaml:"networkScope"`
 	Internal   bool   `json:"networkInternal" yaml:"networkInternal"`
 	Containers int    `json:"networkContainers" yaml:"networkContainers"`
+
+	Labels  map[string]string `json:"networkLabels" yaml:"networkLabels"`
+	Subnets []string          `json:"networkSubnets" yaml:"networkSubnets"`
+}
+
+// NetworkDetails holds a network's address management and the containers
+// attached to it, stopped ones included
+type NetworkDetails struct {
+	NetworkInfo `yaml:",inline"`
+	IPv6        bool              `json:"networkIPv6" yaml:"networkIPv6"`
+	Attachable  bool              `json:"networkAttachable" yaml:"networkAttachable"`
+	IPAMDriver  string            `json:"networkIpamDriver" yaml:"networkIpamDriver"`
+	IPAM        []IPAMPool        `json:"networkIpam" yaml:"networkIpam"`
+	Endpoints   []NetworkEndpoint `json:"networkEndpoints" yaml:"networkEndpoints"`
+}
+
+// IPAMPool is an address range of a network
+type IPAMPool struct {
+	Subnet  string `json:"ipamSubnet" yaml:"ipamSubnet"`
+	IPRange string `json:"ipamIpRange,omitempty" yaml:"ipamIpRange,omitempty"`
+	Gateway string `json:"ipamGateway,omitempty" yaml:"ipamGateway,omitempty"`
+}
+
+// NetworkEndpoint is a container's attachment to a network. Stopped
+// containers have no addresses.
+type NetworkEndpoint struct {
+	ContainerID string   `json:"endpointContainerId" yaml:"endpointContainerId"`
+	Container   string   `json:"endpointContainer" yaml:"endpointContainer"`
+	State       string   `json:"endpointState" yaml:"endpointState"`
+	IPv4Address string   `json:"endpointIPv4" yaml:"endpointIPv4"` // with prefix length, e.g. 172.18.0.2/16
+	IPv6Address string   `json:"endpointIPv6,omitempty" yaml:"endpointIPv6,omitempty"`
+	MacAddress  string   `json:"endpointMac" yaml:"endpointMac"`
+	Aliases     []string `json:"endpointAliases" yaml:"endpointAliases"`
 }
 
 // DiskUsageInfo holds Docker disk usage summary
@@ -96,6 +149,16 @@ type LogEntry struct {
 	Content   string    `json:"logContent" yaml:"logContent"`
 }
 
+// Event is a change reported by the Docker daemon, such as a container
+// starting or an image being pulled
+type Event struct {
+	Time   time.Time `json:"eventTime" yaml:"eventTime"`
+	Type   string    `json:"eventType" yaml:"eventType"` // container, image, volume, network, ...
+	Action string    `json:"eventAction" yaml:"eventAction"`
+	ID     string    `json:"eventId" yaml:"eventId"`
+	Name   string    `json:"eventName" yaml:"eventName"` // actor name, when the daemon reports one
+}
+
 // ContainerMetrics holds real-time metrics for a container
 type ContainerMetrics struct {
 	ContainerID   string  `json:"metricsContainerId" yaml:"metricsContainerId"`
@@ -108,6 +171,10 @@ type ContainerMetrics struct {
 	BlockRead     uint64  `json:"metricsBlockRead" yaml:"metricsBlockRead"`
 	BlockWrite    uint64  `json:"metricsBlockWrite" yaml:"metricsBlockWrite"`
 	PIDs          uint64  `json:"metricsPids" yaml:"metricsPids"`
+	// Raw CPU counters, for computing usage between two samples
+	CPUTotalUsage  uint64 `json:"metricsCpuTotalUsage" yaml:"metricsCpuTotalUsage"`
+	SystemCPUUsage uint64 `json:"metricsSystemCpuUsage" yaml:"metricsSystemCpuUsage"`
+	OnlineCPUs     uint32 `json:"metricsOnlineCpus" yaml:"metricsOnlineCpus"`
 }
 
 // DiskUsageCache caches DiskUsage API results with TTL
@@ -151,6 +218,8 @@ type ExecOptions struct {
 const (
 	ComposeProjectLabel = "com.docker.compose.project"
 	ComposeServiceLabel = "com.docker.compose.service"
+	// ComposeConfigFilesLabel lists the project's Compose files, comma separated
+	ComposeConfigFilesLabel = "com.docker.compose.project.config_files"
 )
 
 // ComposeGroup groups containers belonging to the same Docker Compose project
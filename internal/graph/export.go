@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dotShapes draw each kind of resource differently.
+var dotShapes = map[Kind]string{
+	KindContainer: "box",
+	KindImage:     "component",
+	KindVolume:    "cylinder",
+	KindNetwork:   "hexagon",
+}
+
+// DOT renders the graph in the Graphviz language, containers on the left
+// and what they use on the right. Stopped containers and unused resources
+// are dashed.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph docker {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontname=\"Helvetica\"];\n")
+	for _, n := range g.Nodes {
+		attrs := fmt.Sprintf("label=%s, shape=%s", dotQuote(n.Name), dotShapes[n.Kind])
+		if n.State != "" && n.State != "running" {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(n.ID), attrs)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(e.Relation()))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote quotes s as a DOT string.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// mermaidShapes open and close the node of each kind of resource.
+var mermaidShapes = map[Kind][2]string{
+	KindContainer: {"[", "]"},
+	KindImage:     {"([", "])"},
+	KindVolume:    {"[(", ")]"},
+	KindNetwork:   {"{{", "}}"},
+}
+
+// Mermaid renders the graph as a Mermaid flowchart. Nodes get short
+// identifiers, as Mermaid's cannot hold the slashes and colons of ours.
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+		shape := mermaidShapes[n.Kind]
+		label := n.Name
+		if n.State != "" && n.State != "running" {
+			label += " (" + n.State + ")"
+		}
+		fmt.Fprintf(&b, "  %s%s%s%s\n", ids[n.ID], shape[0], mermaidQuote(label), shape[1])
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[e.From], e.Relation(), ids[e.To])
+	}
+	return b.String()
+}
+
+// mermaidQuote quotes s as a Mermaid label.
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
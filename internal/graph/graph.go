@@ -0,0 +1,282 @@
+// Package graph relates Docker resources: the image a container runs, the
+// volumes it mounts and the networks it joins. It shows what depends on a
+// resource before it is removed, and exports the whole as Graphviz DOT or
+// Mermaid.
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// Kind is a kind of Docker resource.
+type Kind string
+
+const (
+	KindContainer Kind = "container"
+	KindImage     Kind = "image"
+	KindVolume    Kind = "volume"
+	KindNetwork   Kind = "network"
+)
+
+// kindOrder sorts nodes: containers, then what they use.
+var kindOrder = map[Kind]int{KindContainer: 0, KindImage: 1, KindVolume: 2, KindNetwork: 3}
+
+// Node is a resource. Containers and images are keyed by short ID,
+// volumes and networks by name, as the containers refer to them.
+type Node struct {
+	ID    string `json:"id" yaml:"id"` // kind/key, e.g. "volume/pgdata"
+	Kind  Kind   `json:"kind" yaml:"kind"`
+	Name  string `json:"name" yaml:"name"`
+	State string `json:"state,omitempty" yaml:"state,omitempty"` // running, exited, unused...
+}
+
+// Edge points from a container to a resource it uses.
+type Edge struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+// Relation names how the source of an edge uses its target.
+func (e Edge) Relation() string {
+	switch {
+	case strings.HasPrefix(e.To, string(KindImage)+"/"):
+		return "runs"
+	case strings.HasPrefix(e.To, string(KindVolume)+"/"):
+		return "mounts"
+	default:
+		return "joins"
+	}
+}
+
+// Graph holds the resources and their relations.
+type Graph struct {
+	Nodes []Node `json:"nodes" yaml:"nodes"`
+	Edges []Edge `json:"edges" yaml:"edges"`
+
+	index map[string]int
+}
+
+// ID returns the node ID of a resource.
+func ID(kind Kind, key string) string {
+	return string(kind) + "/" + key
+}
+
+// Load lists the resources of the daemon and relates them.
+func Load(ctx context.Context, svc docker.DockerService) (*Graph, error) {
+	containers, err := svc.ListContainers(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("listing containers: %w", err)
+	}
+	images, err := svc.ListImages(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("listing images: %w", err)
+	}
+	volumes, err := svc.ListVolumes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing volumes: %w", err)
+	}
+	networks, err := svc.ListNetworks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing networks: %w", err)
+	}
+	return Build(containers, images, volumes, networks), nil
+}
+
+// Build relates listed resources. Resources a container refers to that are
+// not listed, such as the image of a container whose tag moved on, are
+// added under the name the container knows them by. Images and volumes no
+// container uses are marked "unused"; the listings' own counts are not
+// relied on, as image lists leave them unset.
+func Build(containers []docker.ContainerInfo, images []docker.ImageInfo, volumes []docker.VolumeInfo, networks []docker.NetworkInfo) *Graph {
+	g := &Graph{index: make(map[string]int)}
+	for _, img := range images {
+		id := ID(KindImage, img.ID)
+		if _, ok := g.index[id]; ok {
+			continue // another tag of the same image
+		}
+		g.add(Node{ID: id, Kind: KindImage, Name: imageName(img)})
+	}
+	for _, v := range volumes {
+		g.add(Node{ID: ID(KindVolume, v.Name), Kind: KindVolume, Name: v.Name})
+	}
+	for _, n := range networks {
+		g.add(Node{ID: ID(KindNetwork, n.Name), Kind: KindNetwork, Name: n.Name})
+	}
+
+	for _, c := range containers {
+		from := ID(KindContainer, c.ID)
+		g.add(Node{ID: from, Kind: KindContainer, Name: c.Name, State: c.State})
+		if c.ImageID != "" {
+			g.link(from, Node{ID: ID(KindImage, c.ImageID), Kind: KindImage, Name: c.Image})
+		}
+		for _, v := range c.Volumes {
+			g.link(from, Node{ID: ID(KindVolume, v), Kind: KindVolume, Name: v})
+		}
+		for _, n := range c.Networks {
+			g.link(from, Node{ID: ID(KindNetwork, n), Kind: KindNetwork, Name: n})
+		}
+	}
+
+	g.sort()
+	for i, n := range g.Nodes {
+		if (n.Kind == KindImage || n.Kind == KindVolume) && len(g.UsedBy(n.ID)) == 0 {
+			g.Nodes[i].State = "unused"
+		}
+	}
+	return g
+}
+
+// imageName is the name an image is listed by.
+func imageName(img docker.ImageInfo) string {
+	if img.Dangling || img.Repository == "" {
+		return "<none>"
+	}
+	if img.Tag == "" {
+		return img.Repository
+	}
+	return img.Repository + ":" + img.Tag
+}
+
+// add adds a node unless one with its ID exists.
+func (g *Graph) add(n Node) {
+	if _, ok := g.index[n.ID]; ok {
+		return
+	}
+	g.index[n.ID] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, n)
+}
+
+// link adds an edge from a node to another, adding the latter if needed.
+func (g *Graph) link(from string, to Node) {
+	g.add(to)
+	g.Edges = append(g.Edges, Edge{From: from, To: to.ID})
+}
+
+// sort orders nodes by kind and name, and edges by their ends, so that
+// exports are stable.
+func (g *Graph) sort() {
+	sort.SliceStable(g.Nodes, func(i, j int) bool {
+		a, b := g.Nodes[i], g.Nodes[j]
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+	for i, n := range g.Nodes {
+		g.index[n.ID] = i
+	}
+	sort.SliceStable(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return g.index[a.From] < g.index[b.From]
+		}
+		return g.index[a.To] < g.index[b.To]
+	})
+}
+
+// Node returns the node with an ID.
+func (g *Graph) Node(id string) (Node, bool) {
+	i, ok := g.index[id]
+	if !ok {
+		return Node{}, false
+	}
+	return g.Nodes[i], true
+}
+
+// Find returns the node named name, or with that ID or ID prefix. Names
+// shared by several kinds, say a volume and a network, are ambiguous.
+func (g *Graph) Find(name string) (Node, error) {
+	var found []Node
+	for _, n := range g.Nodes {
+		if n.Name == name || n.ID == name {
+			found = append(found, n)
+		}
+	}
+	if len(found) == 0 {
+		for _, n := range g.Nodes {
+			key := strings.TrimPrefix(n.ID, string(n.Kind)+"/")
+			if (n.Kind == KindContainer || n.Kind == KindImage) && strings.HasPrefix(key, name) {
+				found = append(found, n)
+			}
+		}
+	}
+	switch len(found) {
+	case 0:
+		return Node{}, fmt.Errorf("no container, image, volume or network named %q", name)
+	case 1:
+		return found[0], nil
+	}
+	ids := make([]string, len(found))
+	for i, n := range found {
+		ids[i] = n.ID
+	}
+	return Node{}, fmt.Errorf("%q is ambiguous: %s", name, strings.Join(ids, ", "))
+}
+
+// Uses returns the resources the node uses: for a container its image,
+// volumes and networks.
+func (g *Graph) Uses(id string) []Node {
+	var out []Node
+	for _, e := range g.Edges {
+		if e.From == id {
+			out = append(out, g.Nodes[g.index[e.To]])
+		}
+	}
+	return out
+}
+
+// UsedBy returns the containers using the node.
+func (g *Graph) UsedBy(id string) []Node {
+	var out []Node
+	for _, e := range g.Edges {
+		if e.To == id {
+			out = append(out, g.Nodes[g.index[e.From]])
+		}
+	}
+	return out
+}
+
+// Around returns the part of the graph within two edges of a node, in
+// either direction: for a volume, the containers mounting it and what
+// those run and join; for a container, what it uses and who shares it.
+func (g *Graph) Around(id string) *Graph {
+	keep := map[string]bool{id: true}
+	frontier := []string{id}
+	for hop := 0; hop < 2; hop++ {
+		var next []string
+		for _, e := range g.Edges {
+			for _, n := range frontier {
+				if e.From == n && !keep[e.To] {
+					keep[e.To] = true
+					next = append(next, e.To)
+				}
+				if e.To == n && !keep[e.From] {
+					keep[e.From] = true
+					next = append(next, e.From)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	sub := &Graph{index: make(map[string]int)}
+	for _, n := range g.Nodes {
+		if keep[n.ID] {
+			sub.add(n)
+		}
+	}
+	for _, e := range g.Edges {
+		if keep[e.From] && keep[e.To] {
+			sub.Edges = append(sub.Edges, e)
+		}
+	}
+	return sub
+}
@@ -0,0 +1,160 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+func testGraph() *Graph {
+	return Build(
+		[]docker.ContainerInfo{
+			{ID: "c1", Name: "shop-db", State: "running", Image: "postgres:16", ImageID: "i1", Volumes: []string{"pgdata"}, Networks: []string{"shop"}},
+			{ID: "c2", Name: "shop-web", State: "exited", Image: "nginx", ImageID: "i2", Networks: []string{"shop"}},
+			{ID: "c3", Name: "legacy", State: "running", Image: "app:old", ImageID: "i9"},
+		},
+		[]docker.ImageInfo{
+			// As ListImages returns them: the daemon leaves the container
+			// count unset (-1) for tagged images.
+			{ID: "i1", Repository: "postgres", Tag: "16", Containers: -1},
+			{ID: "i1", Repository: "postgres", Tag: "latest", Containers: -1},
+			{ID: "i2", Repository: "nginx", Tag: "latest", Containers: -1},
+			{ID: "i3", Dangling: true},
+			{ID: "i4", Repository: "redis", Tag: "7", Containers: -1},
+		},
+		[]docker.VolumeInfo{{Name: "pgdata"}, {Name: "scratch"}},
+		[]docker.NetworkInfo{{Name: "shop"}, {Name: "bridge"}},
+	)
+}
+
+func names(nodes []Node) []string {
+	var out []string
+	for _, n := range nodes {
+		out = append(out, n.Name)
+	}
+	return out
+}
+
+func TestBuild(t *testing.T) {
+	g := testGraph()
+
+	assert.Equal(t, []string{
+		"legacy", "shop-db", "shop-web",
+		"<none>", "app:old", "nginx:latest", "postgres:16", "redis:7",
+		"pgdata", "scratch",
+		"bridge", "shop",
+	}, names(g.Nodes))
+
+	state := func(id string) string {
+		n, ok := g.Node(id)
+		require.True(t, ok, id)
+		return n.State
+	}
+	assert.Equal(t, "unused", state(ID(KindImage, "i3")))
+	assert.Equal(t, "unused", state(ID(KindImage, "i4")))
+	assert.Empty(t, state(ID(KindImage, "i1")), "used, whatever the listed count")
+	assert.Empty(t, state(ID(KindImage, "i2")), "used by a stopped container")
+	assert.Empty(t, state(ID(KindVolume, "pgdata")))
+	assert.Equal(t, "unused", state(ID(KindVolume, "scratch")))
+
+	assert.Equal(t, []string{"postgres:16", "pgdata", "shop"}, names(g.Uses(ID(KindContainer, "c1"))))
+	assert.Equal(t, []string{"shop-db", "shop-web"}, names(g.UsedBy(ID(KindNetwork, "shop"))))
+	assert.Equal(t, []string{"legacy"}, names(g.UsedBy(ID(KindImage, "i9"))), "unlisted image added by name")
+	assert.Empty(t, g.UsedBy(ID(KindVolume, "scratch")))
+}
+
+func TestAround(t *testing.T) {
+	g := testGraph()
+
+	// The volume, its container, and what that container runs and joins
+	sub := g.Around(ID(KindVolume, "pgdata"))
+	assert.Equal(t, []string{"shop-db", "postgres:16", "pgdata", "shop"}, names(sub.Nodes))
+	assert.Len(t, sub.Edges, 3)
+
+	// The image, its container, and the containers sharing a network
+	sub = g.Around(ID(KindImage, "i2"))
+	assert.Equal(t, []string{"shop-web", "nginx:latest", "shop"}, names(sub.Nodes))
+}
+
+func TestFind(t *testing.T) {
+	g := Build(
+		[]docker.ContainerInfo{{ID: "abc123def456", Name: "web"}},
+		nil,
+		[]docker.VolumeInfo{{Name: "data"}},
+		[]docker.NetworkInfo{{Name: "data"}},
+	)
+
+	n, err := g.Find("web")
+	require.NoError(t, err)
+	assert.Equal(t, "container/abc123def456", n.ID)
+
+	n, err = g.Find("abc1")
+	require.NoError(t, err)
+	assert.Equal(t, "web", n.Name)
+
+	n, err = g.Find("volume/data")
+	require.NoError(t, err)
+	assert.Equal(t, KindVolume, n.Kind)
+
+	_, err = g.Find("data")
+	assert.ErrorContains(t, err, "ambiguous")
+	_, err = g.Find("nope")
+	assert.ErrorContains(t, err, `no container, image, volume or network named "nope"`)
+}
+
+func TestDOT(t *testing.T) {
+	g := Build(
+		[]docker.ContainerInfo{{ID: "c1", Name: `we"b`, State: "exited", Image: "nginx", ImageID: "i1", Volumes: []string{"v"}}},
+		[]docker.ImageInfo{{ID: "i1", Repository: "nginx", Tag: "latest", Containers: -1}},
+		[]docker.VolumeInfo{{Name: "v", InUse: true}},
+		nil,
+	)
+	assert.Equal(t, `digraph docker {
+  rankdir=LR;
+  node [fontname="Helvetica"];
+  "container/c1" [label="we\"b", shape=box, style=dashed];
+  "image/i1" [label="nginx:latest", shape=component];
+  "volume/v" [label="v", shape=cylinder];
+  "container/c1" -> "image/i1" [label="runs"];
+  "container/c1" -> "volume/v" [label="mounts"];
+}
+`, g.DOT())
+}
+
+func TestMermaid(t *testing.T) {
+	g := Build(
+		[]docker.ContainerInfo{{ID: "c1", Name: "web", State: "running", ImageID: "i1", Networks: []string{"front"}}},
+		[]docker.ImageInfo{{ID: "i1", Repository: "nginx", Tag: "latest", Containers: -1}},
+		nil,
+		[]docker.NetworkInfo{{Name: "front"}},
+	)
+	assert.Equal(t, `flowchart LR
+  n0["web"]
+  n1(["nginx:latest"])
+  n2{{"front"}}
+  n0 -->|runs| n1
+  n0 -->|joins| n2
+`, g.Mermaid())
+}
+
+func TestLoad(t *testing.T) {
+	mock := &docker.MockDockerService{
+		ListContainersFn: func(context.Context, bool) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{{ID: "c1", Name: "web", Volumes: []string{"v"}}}, nil
+		},
+	}
+	g, err := Load(context.Background(), mock)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web", "v"}, names(g.Nodes))
+
+	mock.ListVolumesFn = func(context.Context) ([]docker.VolumeInfo, error) {
+		return nil, errors.New("daemon gone")
+	}
+	_, err = Load(context.Background(), mock)
+	assert.ErrorContains(t, err, "listing volumes: daemon gone")
+}
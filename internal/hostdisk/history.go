@@ -0,0 +1,159 @@
+package hostdisk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// SampleInterval is the minimum time between stored samples of a path.
+	SampleInterval = time.Hour
+	// Retention is how long samples are kept.
+	Retention = 90 * 24 * time.Hour
+	// MinForecastSpan is how much history a forecast needs.
+	MinForecastSpan = 24 * time.Hour
+)
+
+// Sample is one stored measurement of a filesystem.
+type Sample struct {
+	Path  string    `json:"path"`
+	Time  time.Time `json:"time"`
+	Used  uint64    `json:"used_bytes"`
+	Avail uint64    `json:"avail_bytes"`
+}
+
+type historyFile struct {
+	Samples []Sample `json:"samples"`
+}
+
+// DefaultHistoryPath returns ~/.octo/disk-history.json.
+func DefaultHistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".octo", "disk-history.json")
+	}
+	return filepath.Join(home, ".octo", "disk-history.json")
+}
+
+// LoadHistory reads all samples from file. A missing file has no samples.
+func LoadHistory(file string) ([]Sample, error) {
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var h historyFile
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", file, err)
+	}
+	return h.Samples, nil
+}
+
+// Record adds u to the history in file unless the path was sampled less
+// than SampleInterval ago, and drops samples older than Retention. It
+// returns the samples of u.Path, oldest first, ending with u itself even
+// when u was not stored.
+func Record(file string, u Usage, now time.Time) ([]Sample, error) {
+	all, err := LoadHistory(file)
+	if err != nil {
+		return nil, err
+	}
+	current := Sample{Path: u.Path, Time: now.UTC(), Used: u.Used(), Avail: u.Avail}
+
+	var kept, forPath []Sample
+	var last time.Time
+	for _, s := range all {
+		if now.Sub(s.Time) > Retention {
+			continue
+		}
+		kept = append(kept, s)
+		if s.Path == u.Path {
+			forPath = append(forPath, s)
+			if s.Time.After(last) {
+				last = s.Time
+			}
+		}
+	}
+	forPath = append(forPath, current)
+
+	store := last.IsZero() || now.Sub(last) >= SampleInterval
+	if store {
+		kept = append(kept, current)
+	} else if len(kept) == len(all) {
+		return forPath, nil // nothing to write
+	}
+	return forPath, saveHistory(file, kept)
+}
+
+// saveHistory writes the samples through a temporary file so that a
+// concurrent reader never sees a partial file.
+func saveHistory(file string, samples []Sample) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(historyFile{Samples: samples})
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".disk-history-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// Forecast projects when a filesystem fills at its recent growth rate.
+type Forecast struct {
+	GrowthPerDay float64 `json:"growth_bytes_per_day" yaml:"growth_bytes_per_day"`
+	DaysLeft     float64 `json:"days_until_full" yaml:"days_until_full"`
+}
+
+// Estimate fits a line through used bytes over time (least squares) and
+// extrapolates until the available space of the latest sample runs out. It
+// reports false when the samples span less than MinForecastSpan or usage is
+// not growing.
+func Estimate(samples []Sample) (Forecast, bool) {
+	if len(samples) < 2 {
+		return Forecast{}, false
+	}
+	first, latest := samples[0], samples[len(samples)-1]
+	if latest.Time.Sub(first.Time) < MinForecastSpan {
+		return Forecast{}, false
+	}
+
+	// x in days since the first sample, y in bytes used.
+	var sumX, sumY, sumXY, sumXX float64
+	n := float64(len(samples))
+	for _, s := range samples {
+		x := s.Time.Sub(first.Time).Hours() / 24
+		y := float64(s.Used)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return Forecast{}, false
+	}
+	slope := (n*sumXY - sumX*sumY) / denom
+	if slope <= 0 {
+		return Forecast{}, false
+	}
+	return Forecast{GrowthPerDay: slope, DaysLeft: float64(latest.Avail) / slope}, true
+}
@@ -0,0 +1,190 @@
+// Package hostdisk measures free space and inodes on the host filesystem
+// backing Docker's data root, rates them against thresholds and forecasts
+// when the filesystem fills from a locally stored growth history.
+package hostdisk
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/docker/docker/api/types/system"
+
+	"github.com/bsisduck/octo/internal/config"
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// ErrUnsupported is returned by Stat on platforms without statfs.
+var ErrUnsupported = errors.New("filesystem statistics not supported on " + runtime.GOOS)
+
+// Usage is a statfs snapshot of the filesystem holding Path.
+type Usage struct {
+	Path       string
+	Total      uint64 // bytes
+	Free       uint64 // bytes free, including space reserved for root
+	Avail      uint64 // bytes available to unprivileged users
+	Inodes     uint64
+	InodesFree uint64
+}
+
+// Used returns the bytes in use.
+func (u Usage) Used() uint64 {
+	if u.Free > u.Total {
+		return 0
+	}
+	return u.Total - u.Free
+}
+
+// UsedPercent returns used space the way df reports it: relative to the
+// space usable by unprivileged users, so that 100% means writes fail.
+func (u Usage) UsedPercent() float64 {
+	used := u.Used()
+	if used+u.Avail == 0 {
+		return 0
+	}
+	return float64(used) / float64(used+u.Avail) * 100
+}
+
+// InodesUsedPercent returns the share of inodes in use. Filesystems without
+// a fixed inode table (btrfs, some network filesystems) report zero inodes.
+func (u Usage) InodesUsedPercent() float64 {
+	if u.Inodes == 0 || u.InodesFree > u.Inodes {
+		return 0
+	}
+	return float64(u.Inodes-u.InodesFree) / float64(u.Inodes) * 100
+}
+
+// Level rates a measurement against the thresholds.
+type Level int
+
+const (
+	LevelOK Level = iota
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "ok"
+	}
+}
+
+// MarshalText encodes the level by name in JSON and YAML output.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// Options holds the thresholds and where growth history is kept.
+type Options struct {
+	WarnPercent       float64 // used space
+	ErrorPercent      float64
+	InodeWarnPercent  float64
+	InodeErrorPercent float64
+	WarnDays          float64 // warn when the forecast fills the disk sooner
+	HistoryFile       string  // empty disables growth history
+}
+
+// DefaultOptions returns the thresholds used when the config sets none.
+func DefaultOptions() Options {
+	return Options{
+		WarnPercent:       85,
+		ErrorPercent:      95,
+		InodeWarnPercent:  85,
+		InodeErrorPercent: 95,
+		WarnDays:          7,
+		HistoryFile:       DefaultHistoryPath(),
+	}
+}
+
+// OptionsFrom applies the disk section of the config over DefaultOptions.
+func OptionsFrom(cfg config.DiskConfig) Options {
+	opts := DefaultOptions()
+	for _, v := range []struct {
+		dst *float64
+		src float64
+	}{
+		{&opts.WarnPercent, cfg.WarnPercent},
+		{&opts.ErrorPercent, cfg.ErrorPercent},
+		{&opts.InodeWarnPercent, cfg.InodeWarnPercent},
+		{&opts.InodeErrorPercent, cfg.InodeErrorPercent},
+		{&opts.WarnDays, cfg.WarnDays},
+	} {
+		if v.src > 0 {
+			*v.dst = v.src
+		}
+	}
+	if cfg.HistoryFile != "" {
+		opts.HistoryFile = cfg.HistoryFile
+	}
+	if cfg.History != nil && !*cfg.History {
+		opts.HistoryFile = ""
+	}
+	return opts
+}
+
+func rate(percent, warn, err float64) Level {
+	switch {
+	case percent >= err:
+		return LevelError
+	case percent >= warn:
+		return LevelWarn
+	}
+	return LevelOK
+}
+
+// Report is a rated measurement of the Docker data root.
+type Report struct {
+	Usage
+	SpaceLevel Level
+	InodeLevel Level
+	Forecast   *Forecast // nil until the history spans MinForecastSpan with growth
+}
+
+// Measure stats path, records it in the growth history and rates the
+// result. History is best effort: a history file that cannot be read or
+// written only means no forecast.
+func Measure(path string, opts Options, now time.Time) (Report, error) {
+	usage, err := Stat(path)
+	if err != nil {
+		return Report{}, err
+	}
+	r := Report{
+		Usage:      usage,
+		SpaceLevel: rate(usage.UsedPercent(), opts.WarnPercent, opts.ErrorPercent),
+		InodeLevel: rate(usage.InodesUsedPercent(), opts.InodeWarnPercent, opts.InodeErrorPercent),
+	}
+	if opts.HistoryFile != "" {
+		samples, _ := Record(opts.HistoryFile, usage, now)
+		if f, ok := Estimate(samples); ok {
+			r.Forecast = &f
+			if f.DaysLeft <= opts.WarnDays && r.SpaceLevel < LevelWarn {
+				r.SpaceLevel = LevelWarn
+			}
+		}
+	}
+	return r, nil
+}
+
+// DataRoot returns the daemon's data root when it is on this host's
+// filesystem: the daemon is reached over a local socket on Linux and the
+// directory exists. Docker Desktop and remote daemons keep it in a VM or on
+// another machine, where statting the same path would be meaningless.
+func DataRoot(info system.Info) (string, bool) {
+	return localRoot(os.Getenv("DOCKER_HOST"), runtime.GOOS, info.DockerRootDir)
+}
+
+func localRoot(dockerHost, goos, root string) (string, bool) {
+	if root == "" || goos != "linux" || !docker.IsLocalHost(dockerHost) {
+		return "", false
+	}
+	if _, err := os.Stat(root); err != nil {
+		return "", false
+	}
+	return root, true
+}
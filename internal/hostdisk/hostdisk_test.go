@@ -0,0 +1,147 @@
+package hostdisk
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/config"
+)
+
+func TestUsagePercentages(t *testing.T) {
+	u := Usage{Total: 100, Free: 20, Avail: 10, Inodes: 1000, InodesFree: 250}
+	assert.Equal(t, uint64(80), u.Used())
+	assert.InDelta(t, 88.9, u.UsedPercent(), 0.1, "reserved blocks count as unavailable")
+	assert.InDelta(t, 75.0, u.InodesUsedPercent(), 0.01)
+
+	assert.Zero(t, Usage{}.UsedPercent())
+	assert.Zero(t, Usage{Total: 100, Free: 100}.InodesUsedPercent(), "no inode table")
+}
+
+func TestOptionsFrom(t *testing.T) {
+	off := false
+	opts := OptionsFrom(config.DiskConfig{WarnPercent: 70, InodeErrorPercent: 99, History: &off})
+	assert.Equal(t, 70.0, opts.WarnPercent)
+	assert.Equal(t, 95.0, opts.ErrorPercent, "default kept")
+	assert.Equal(t, 99.0, opts.InodeErrorPercent)
+	assert.Empty(t, opts.HistoryFile)
+
+	opts = OptionsFrom(config.DiskConfig{HistoryFile: "/tmp/h.json"})
+	assert.Equal(t, "/tmp/h.json", opts.HistoryFile)
+}
+
+func TestRate(t *testing.T) {
+	assert.Equal(t, LevelOK, rate(50, 85, 95))
+	assert.Equal(t, LevelWarn, rate(85, 85, 95))
+	assert.Equal(t, LevelError, rate(99, 85, 95))
+}
+
+func TestLocalRoot(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name       string
+		dockerHost string
+		goos       string
+		root       string
+		want       bool
+	}{
+		{"default socket", "", "linux", dir, true},
+		{"unix socket", "unix:///run/docker.sock", "linux", dir, true},
+		{"tcp host", "tcp://10.0.0.2:2376", "linux", dir, false},
+		{"ssh host", "ssh://build@ci", "linux", dir, false},
+		{"docker desktop", "", "darwin", dir, false},
+		{"missing root", "", "linux", filepath.Join(dir, "nope"), false},
+		{"unreported root", "", "linux", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := localRoot(tt.dockerHost, tt.goos, tt.root)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
+
+func TestRecordThrottlesAndPrunes(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sub", "history.json")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	u := Usage{Path: "/var/lib/docker", Total: 100, Free: 60, Avail: 60}
+
+	samples, err := Record(file, u, now)
+	require.NoError(t, err)
+	assert.Len(t, samples, 1)
+
+	samples, err = Record(file, u, now.Add(10*time.Minute))
+	require.NoError(t, err)
+	assert.Len(t, samples, 2, "current measurement is returned")
+	stored, err := LoadHistory(file)
+	require.NoError(t, err)
+	assert.Len(t, stored, 1, "but not stored within the sample interval")
+
+	_, err = Record(file, Usage{Path: "/other"}, now.Add(time.Minute))
+	require.NoError(t, err)
+	_, err = Record(file, u, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	stored, err = LoadHistory(file)
+	require.NoError(t, err)
+	assert.Len(t, stored, 3)
+
+	samples, err = Record(file, u, now.Add(Retention+90*time.Minute))
+	require.NoError(t, err)
+	assert.Len(t, samples, 2, "sample older than retention dropped")
+	stored, err = LoadHistory(file)
+	require.NoError(t, err)
+	assert.Len(t, stored, 2)
+}
+
+func TestEstimate(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	const gb = 1_000_000_000
+	var samples []Sample
+	for day := 0; day <= 4; day++ {
+		used := uint64(50+2*day) * gb
+		samples = append(samples, Sample{Time: start.Add(time.Duration(day) * 24 * time.Hour), Used: used, Avail: 100*gb - used})
+	}
+
+	f, ok := Estimate(samples)
+	require.True(t, ok)
+	assert.InDelta(t, 2*gb, f.GrowthPerDay, 1)
+	assert.InDelta(t, 21.0, f.DaysLeft, 0.01)
+
+	_, ok = Estimate(samples[:1])
+	assert.False(t, ok, "single sample")
+
+	short := []Sample{{Time: start, Used: 1}, {Time: start.Add(time.Hour), Used: 2}}
+	_, ok = Estimate(short)
+	assert.False(t, ok, "history shorter than MinForecastSpan")
+
+	shrinking := []Sample{{Time: start, Used: 10}, {Time: start.Add(48 * time.Hour), Used: 5}}
+	_, ok = Estimate(shrinking)
+	assert.False(t, ok, "usage not growing")
+}
+
+func TestMeasure(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("statfs not available")
+	}
+	dir := t.TempDir()
+	opts := Options{WarnPercent: 0.0001, ErrorPercent: 101, InodeWarnPercent: 101, InodeErrorPercent: 101,
+		HistoryFile: filepath.Join(dir, "history.json")}
+
+	r, err := Measure(dir, opts, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, dir, r.Path)
+	assert.Positive(t, r.Total)
+	assert.Equal(t, LevelWarn, r.SpaceLevel)
+	assert.Equal(t, LevelOK, r.InodeLevel)
+	assert.Nil(t, r.Forecast)
+	_, err = os.Stat(opts.HistoryFile)
+	assert.NoError(t, err, "sample recorded")
+
+	_, err = Measure(filepath.Join(dir, "missing"), opts, time.Now())
+	assert.Error(t, err)
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !freebsd
+
+package hostdisk
+
+// Stat is not implemented on this platform; the data root is only measured
+// for local Linux daemons.
+func Stat(_ string) (Usage, error) {
+	return Usage{}, ErrUnsupported
+}
@@ -0,0 +1,22 @@
+//go:build linux || darwin || freebsd
+
+package hostdisk
+
+import "syscall"
+
+// Stat returns the usage of the filesystem holding path.
+func Stat(path string) (Usage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return Usage{}, err
+	}
+	bsize := uint64(st.Bsize)
+	return Usage{
+		Path:       path,
+		Total:      uint64(st.Blocks) * bsize,
+		Free:       uint64(st.Bfree) * bsize,
+		Avail:      uint64(st.Bavail) * bsize,
+		Inodes:     uint64(st.Files),
+		InodesFree: uint64(st.Ffree),
+	}, nil
+}
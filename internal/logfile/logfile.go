@@ -0,0 +1,236 @@
+// Package logfile writes container log entries to disk as plain text or
+// NDJSON, optionally gzip-compressed and rotated by size.
+package logfile
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// Format selects how log entries are encoded.
+type Format int
+
+const (
+	FormatText Format = iota
+	FormatNDJSON
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatNDJSON:
+		return "ndjson"
+	default:
+		return "text"
+	}
+}
+
+// Options configures a log file Writer.
+type Options struct {
+	Format   Format
+	Gzip     bool
+	MaxSize  int64 // rotate once the current file reaches this many bytes (0 = never)
+	MaxFiles int   // number of rotated files to keep (path.1 .. path.N)
+}
+
+// Preset is a named combination of format and compression offered in the TUI.
+type Preset struct {
+	Name      string
+	Extension string
+	Options   Options
+}
+
+// Presets lists the export formats in the order the TUI cycles through them.
+var Presets = []Preset{
+	{Name: "text", Extension: ".log", Options: Options{Format: FormatText}},
+	{Name: "ndjson", Extension: ".ndjson", Options: Options{Format: FormatNDJSON}},
+	{Name: "gzip", Extension: ".log.gz", Options: Options{Format: FormatText, Gzip: true}},
+}
+
+// OptionsFromPath infers format and compression from a file name:
+// a ".gz" suffix enables gzip, and ".ndjson"/".jsonl"/".json" select NDJSON.
+func OptionsFromPath(path string) Options {
+	var opts Options
+	name := strings.ToLower(filepath.Base(path))
+	if strings.HasSuffix(name, ".gz") {
+		opts.Gzip = true
+		name = strings.TrimSuffix(name, ".gz")
+	}
+	switch filepath.Ext(name) {
+	case ".ndjson", ".jsonl", ".json":
+		opts.Format = FormatNDJSON
+	}
+	return opts
+}
+
+// DefaultName returns a timestamped file name such as "web-20260101-150405.log"
+// so repeated exports of the same container never overwrite each other.
+func DefaultName(container string, preset Preset, now time.Time) string {
+	container = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, container)
+	if container == "" {
+		container = "container"
+	}
+	return fmt.Sprintf("%s-%s%s", container, now.Format("20060102-150405"), preset.Extension)
+}
+
+// FormatLine renders an entry the same way the logs views display it.
+func FormatLine(e docker.LogEntry) string {
+	ts := e.Timestamp.Format("2006-01-02 15:04:05")
+	return fmt.Sprintf("%s  %-6s  %s", ts, e.Stream, e.Content)
+}
+
+// record is the NDJSON representation of a log entry.
+type record struct {
+	Timestamp string `json:"timestamp"`
+	Stream    string `json:"stream"`
+	Content   string `json:"content"`
+}
+
+// Encode writes a single entry in the given format, including the trailing newline.
+func Encode(w io.Writer, e docker.LogEntry, format Format) (int, error) {
+	if format == FormatNDJSON {
+		data, err := json.Marshal(record{
+			Timestamp: e.Timestamp.UTC().Format(time.RFC3339Nano),
+			Stream:    e.Stream,
+			Content:   e.Content,
+		})
+		if err != nil {
+			return 0, err
+		}
+		return w.Write(append(data, '\n'))
+	}
+	return fmt.Fprintln(w, FormatLine(e))
+}
+
+// Writer appends log entries to a file, rotating it when MaxSize is exceeded.
+// It is not safe for concurrent use.
+type Writer struct {
+	path string
+	opts Options
+	file *os.File
+	gz   *gzip.Writer
+	out  io.Writer
+	size int64 // uncompressed bytes written to the current file
+}
+
+// Create opens path for writing, truncating any existing file and creating
+// parent directories as needed.
+func Create(path string, opts Options) (*Writer, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create dir: %w", err)
+		}
+	}
+	w := &Writer{path: path, opts: opts}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Writer) open() error {
+	f, err := os.Create(w.path)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+	w.file = f
+	w.out = f
+	w.gz = nil
+	if w.opts.Gzip {
+		w.gz = gzip.NewWriter(f)
+		w.out = w.gz
+	}
+	w.size = 0
+	return nil
+}
+
+// Path returns the file currently being written.
+func (w *Writer) Path() string {
+	return w.path
+}
+
+// Write encodes one entry, rotating first if the size limit has been reached.
+func (w *Writer) Write(e docker.LogEntry) error {
+	if w.opts.MaxSize > 0 && w.size >= w.opts.MaxSize {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := Encode(w.out, e, w.opts.Format)
+	w.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	return nil
+}
+
+// rotate closes the current file, shifts path.N-1 -> path.N down to
+// path -> path.1, and reopens an empty file at path.
+func (w *Writer) rotate() error {
+	if err := w.closeFile(); err != nil {
+		return err
+	}
+	keep := w.opts.MaxFiles
+	if keep < 1 {
+		keep = 1
+	}
+	_ = os.Remove(fmt.Sprintf("%s.%d", w.path, keep))
+	for i := keep - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", w.path, i)
+		if _, err := os.Stat(src); err == nil {
+			if err := os.Rename(src, fmt.Sprintf("%s.%d", w.path, i+1)); err != nil {
+				return fmt.Errorf("rotate: %w", err)
+			}
+		}
+	}
+	if err := os.Rename(w.path, w.path+".1"); err != nil {
+		return fmt.Errorf("rotate: %w", err)
+	}
+	return w.open()
+}
+
+func (w *Writer) closeFile() error {
+	if w.gz != nil {
+		if err := w.gz.Close(); err != nil {
+			_ = w.file.Close()
+			return fmt.Errorf("close gzip: %w", err)
+		}
+	}
+	return w.file.Close()
+}
+
+// Close flushes and closes the current file.
+func (w *Writer) Close() error {
+	return w.closeFile()
+}
+
+// WriteAll writes entries to a fresh file at path and returns how many were written.
+func WriteAll(path string, entries []docker.LogEntry, opts Options) (int, error) {
+	opts.MaxSize = 0
+	w, err := Create(path, opts)
+	if err != nil {
+		return 0, err
+	}
+	for i, e := range entries {
+		if err := w.Write(e); err != nil {
+			_ = w.Close()
+			return i, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return len(entries), err
+	}
+	return len(entries), nil
+}
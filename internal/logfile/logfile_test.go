@@ -0,0 +1,123 @@
+package logfile
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+var testTime = time.Date(2026, 1, 1, 12, 30, 45, 123000000, time.UTC)
+
+func testEntries() []docker.LogEntry {
+	return []docker.LogEntry{
+		{Timestamp: testTime, Stream: "stdout", Content: "hello"},
+		{Timestamp: testTime.Add(time.Second), Stream: "stderr", Content: "oops"},
+	}
+}
+
+func TestOptionsFromPath(t *testing.T) {
+	tests := []struct {
+		path   string
+		format Format
+		gzip   bool
+	}{
+		{"web.log", FormatText, false},
+		{"web.txt", FormatText, false},
+		{"web.ndjson", FormatNDJSON, false},
+		{"web.jsonl", FormatNDJSON, false},
+		{"web.log.gz", FormatText, true},
+		{"/tmp/WEB.NDJSON.GZ", FormatNDJSON, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			opts := OptionsFromPath(tt.path)
+			assert.Equal(t, tt.format, opts.Format)
+			assert.Equal(t, tt.gzip, opts.Gzip)
+		})
+	}
+}
+
+func TestDefaultName(t *testing.T) {
+	assert.Equal(t, "web-20260101-123045.log", DefaultName("web", Presets[0], testTime))
+	assert.Equal(t, "a_b-20260101-123045.ndjson", DefaultName("a/b", Presets[1], testTime))
+	assert.Equal(t, "container-20260101-123045.log.gz", DefaultName("", Presets[2], testTime))
+}
+
+func TestWriteAll_Text(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.log")
+	n, err := WriteAll(path, testEntries(), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "2026-01-01 12:30:45  stdout  hello", lines[0])
+	assert.Equal(t, "2026-01-01 12:30:46  stderr  oops", lines[1])
+}
+
+func TestWriteAll_NDJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "out.ndjson")
+	_, err := WriteAll(path, testEntries(), OptionsFromPath(path))
+	require.NoError(t, err)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var got []record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		got = append(got, r)
+	}
+	require.Len(t, got, 2)
+	assert.Equal(t, "2026-01-01T12:30:45.123Z", got[0].Timestamp)
+	assert.Equal(t, "stderr", got[1].Stream)
+	assert.Equal(t, "oops", got[1].Content)
+}
+
+func TestWriteAll_Gzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.log.gz")
+	_, err := WriteAll(path, testEntries(), OptionsFromPath(path))
+	require.NoError(t, err)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	var b strings.Builder
+	_, err = bufio.NewReader(gz).WriteTo(&b)
+	require.NoError(t, err)
+	assert.Contains(t, b.String(), "stdout  hello")
+}
+
+func TestWriter_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "follow.log")
+	w, err := Create(path, Options{MaxSize: 40, MaxFiles: 2})
+	require.NoError(t, err)
+
+	// Each line is ~34 bytes, so every second write rotates.
+	for i := 0; i < 6; i++ {
+		require.NoError(t, w.Write(docker.LogEntry{Timestamp: testTime, Stream: "stdout", Content: "line"}))
+	}
+	require.NoError(t, w.Close())
+
+	assert.FileExists(t, path)
+	assert.FileExists(t, path+".1")
+	assert.FileExists(t, path+".2")
+	assert.NoFileExists(t, path+".3")
+}
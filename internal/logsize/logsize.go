@@ -0,0 +1,205 @@
+// Package logsize reports how each container logs: the log driver, its
+// rotation settings and, when the daemon is local, the size of the log files
+// on disk. It also truncates json-file logs that have grown too large.
+package logsize
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/dustin/go-humanize"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// Usage describes the logging of one container.
+type Usage struct {
+	ContainerID string `json:"container_id" yaml:"container_id"`
+	Container   string `json:"container" yaml:"container"`
+	Running     bool   `json:"running" yaml:"running"`
+	Driver      string `json:"driver" yaml:"driver"`
+	MaxSize     string `json:"max_size,omitempty" yaml:"max_size,omitempty"`
+	MaxFile     string `json:"max_file,omitempty" yaml:"max_file,omitempty"`
+	Path        string `json:"path,omitempty" yaml:"path,omitempty"`
+	Size        int64  `json:"size_bytes" yaml:"size_bytes"` // live and rotated files
+	LiveSize    int64  `json:"live_size_bytes" yaml:"live_size_bytes"`
+	Files       int    `json:"files" yaml:"files"`
+	SizeKnown   bool   `json:"size_known" yaml:"size_known"`
+}
+
+// Unbounded reports whether the logs grow without limit on the daemon's
+// disk: json-file without max-size. The local driver rotates by default and
+// the other drivers ship logs elsewhere.
+func (u Usage) Unbounded() bool {
+	return u.Driver == "json-file" && (u.MaxSize == "" || u.MaxSize == "-1")
+}
+
+// Rotation renders the rotation settings.
+func (u Usage) Rotation() string {
+	switch {
+	case u.Unbounded():
+		return "no rotation"
+	case u.MaxSize == "" && u.Driver == "local":
+		return "default rotation"
+	case u.MaxSize == "":
+		return ""
+	case u.MaxFile != "":
+		return fmt.Sprintf("max-size %s, max-file %s", u.MaxSize, u.MaxFile)
+	}
+	return "max-size " + u.MaxSize
+}
+
+// CanTruncate reports whether Truncate can act: a json-file log whose file
+// was readable.
+func (u Usage) CanTruncate() bool {
+	return u.Driver == "json-file" && u.Path != "" && u.SizeKnown
+}
+
+// FromInspect builds the usage of a container. Log files are only measured
+// when local is set, since LogPath is a path on the daemon's host.
+func FromInspect(c types.ContainerJSON, local bool) Usage {
+	u := Usage{}
+	if c.ContainerJSONBase == nil {
+		return u
+	}
+	u.ContainerID = c.ID
+	u.Container = strings.TrimPrefix(c.Name, "/")
+	u.Running = c.State != nil && c.State.Running
+	if c.HostConfig != nil {
+		u.Driver = c.HostConfig.LogConfig.Type
+		u.MaxSize = c.HostConfig.LogConfig.Config["max-size"]
+		u.MaxFile = c.HostConfig.LogConfig.Config["max-file"]
+	}
+	if u.Driver == "json-file" && c.LogPath != "" {
+		u.Path = c.LogPath
+		if local {
+			u.LiveSize, u.Size, u.Files, u.SizeKnown = measure(c.LogPath)
+		}
+	}
+	return u
+}
+
+// measure returns the size of the live log file and the total including its
+// rotated siblings (path.1, path.2.gz, ...).
+func measure(path string) (live, total int64, files int, ok bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	live, total, files = info.Size(), info.Size(), 1
+	rotated, _ := filepath.Glob(path + ".*")
+	for _, r := range rotated {
+		if ri, err := os.Stat(r); err == nil {
+			total += ri.Size()
+			files++
+		}
+	}
+	return live, total, files, true
+}
+
+// Collect builds the usage of each container, largest logs first; unknown
+// sizes sort last, by name.
+func Collect(containers []types.ContainerJSON, local bool) []Usage {
+	usages := make([]Usage, 0, len(containers))
+	for _, c := range containers {
+		usages = append(usages, FromInspect(c, local))
+	}
+	sort.SliceStable(usages, func(i, j int) bool {
+		a, b := usages[i], usages[j]
+		if a.SizeKnown != b.SizeKnown {
+			return a.SizeKnown
+		}
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		return a.Container < b.Container
+	})
+	return usages
+}
+
+// List inspects all containers and returns their log usage, largest first.
+// Sizes are only known when the daemon runs on this host. Containers removed
+// meanwhile are skipped.
+func List(ctx context.Context, svc docker.DockerService) ([]Usage, error) {
+	containers, err := svc.ListContainers(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	inspected := make([]types.ContainerJSON, 0, len(containers))
+	for _, c := range containers {
+		details, err := svc.InspectContainer(ctx, c.ID)
+		if errdefs.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("inspecting %s: %w", c.Name, err)
+		}
+		inspected = append(inspected, details)
+	}
+	return Collect(inspected, docker.IsLocalDaemon()), nil
+}
+
+// Unbounded returns the usages without rotation, in order.
+func Unbounded(usages []Usage) []Usage {
+	var out []Usage
+	for _, u := range usages {
+		if u.Unbounded() {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// ErrNotTruncatable is returned for logs Truncate cannot act on.
+var ErrNotTruncatable = errors.New("only json-file logs readable on this host can be truncated")
+
+// TruncateDryRun describes truncating the live log files of usages.
+func TruncateDryRun(usages ...Usage) (docker.ConfirmationInfo, error) {
+	var total int64
+	resources := make([]string, 0, len(usages))
+	for _, u := range usages {
+		if !u.CanTruncate() {
+			return docker.ConfirmationInfo{}, fmt.Errorf("%s: %w", u.Container, ErrNotTruncatable)
+		}
+		total += u.LiveSize
+		resources = append(resources, fmt.Sprintf("log: %s (%s)", u.Container, humanize.Bytes(uint64(u.LiveSize))))
+	}
+	info := docker.ConfirmationInfo{
+		Tier:             docker.TierHighRisk,
+		Title:            "Truncate Container Log?",
+		Description:      fmt.Sprintf("Empty the log of %s, up to %s", usages[0].Container, humanize.Bytes(uint64(total))),
+		Resources:        resources,
+		Reversible:       false,
+		UndoInstructions: "Truncated log lines cannot be recovered; the container keeps running and logging",
+		Warnings:         []string{"Rotated log files are kept; 'docker logs' will only show new output"},
+	}
+	if len(usages) > 1 {
+		info.Tier = docker.TierBulkDestructive
+		info.Title = "Truncate Container Logs?"
+		info.Description = fmt.Sprintf("Empty the logs of %d containers, up to %s", len(usages), humanize.Bytes(uint64(total)))
+	}
+	return info, nil
+}
+
+// Truncate empties the live log file and returns the bytes freed. Docker
+// writes with O_APPEND, so it carries on at the start of the file.
+func Truncate(u Usage) (int64, error) {
+	if !u.CanTruncate() {
+		return 0, ErrNotTruncatable
+	}
+	info, err := os.Stat(u.Path)
+	if err != nil {
+		return 0, fmt.Errorf("truncating log of %s: %w", u.Container, err)
+	}
+	if err := os.Truncate(u.Path, 0); err != nil {
+		return 0, fmt.Errorf("truncating log of %s: %w", u.Container, err)
+	}
+	return info.Size(), nil
+}
@@ -0,0 +1,117 @@
+package logsize
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+func inspected(name, driver string, opts map[string]string, logPath string) types.ContainerJSON {
+	return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{
+		ID:         name + "-id",
+		Name:       "/" + name,
+		State:      &types.ContainerState{Running: true},
+		LogPath:    logPath,
+		HostConfig: &container.HostConfig{LogConfig: container.LogConfig{Type: driver, Config: opts}},
+	}}
+}
+
+func writeLog(t *testing.T, path string, size int) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", size)), 0o640))
+}
+
+func TestRotation(t *testing.T) {
+	tests := []struct {
+		usage     Usage
+		unbounded bool
+		rotation  string
+	}{
+		{Usage{Driver: "json-file"}, true, "no rotation"},
+		{Usage{Driver: "json-file", MaxSize: "-1"}, true, "no rotation"},
+		{Usage{Driver: "json-file", MaxSize: "10m"}, false, "max-size 10m"},
+		{Usage{Driver: "json-file", MaxSize: "10m", MaxFile: "3"}, false, "max-size 10m, max-file 3"},
+		{Usage{Driver: "local"}, false, "default rotation"},
+		{Usage{Driver: "journald"}, false, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.unbounded, tt.usage.Unbounded(), tt.usage.Driver+" "+tt.usage.MaxSize)
+		assert.Equal(t, tt.rotation, tt.usage.Rotation())
+	}
+}
+
+func TestCollectMeasuresAndRanks(t *testing.T) {
+	dir := t.TempDir()
+	big, small := filepath.Join(dir, "big-json.log"), filepath.Join(dir, "small-json.log")
+	writeLog(t, big, 300)
+	writeLog(t, big+".1", 200)
+	writeLog(t, small, 400)
+
+	usages := Collect([]types.ContainerJSON{
+		inspected("syslog", "syslog", nil, ""),
+		inspected("small", "json-file", map[string]string{"max-size": "10m"}, small),
+		inspected("gone", "json-file", nil, filepath.Join(dir, "missing.log")),
+		inspected("big", "json-file", nil, big),
+	}, true)
+
+	require.Len(t, usages, 4)
+	assert.Equal(t, "big", usages[0].Container)
+	assert.Equal(t, int64(500), usages[0].Size)
+	assert.Equal(t, int64(300), usages[0].LiveSize)
+	assert.Equal(t, 2, usages[0].Files)
+	assert.Equal(t, "small", usages[1].Container)
+	assert.False(t, usages[2].SizeKnown, "unreadable logs sort last")
+	assert.Equal(t, "", usages[3].Path, "other drivers have no log file")
+
+	unbounded := Unbounded(usages)
+	require.Len(t, unbounded, 2)
+	assert.Equal(t, "big", unbounded[0].Container)
+
+	remote := Collect([]types.ContainerJSON{inspected("big", "json-file", nil, big)}, false)
+	assert.False(t, remote[0].SizeKnown, "remote daemon logs are not measured")
+	assert.False(t, remote[0].CanTruncate())
+}
+
+func TestTruncate(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.log"), filepath.Join(dir, "b.log")
+	writeLog(t, a, 1000)
+	writeLog(t, a+".1", 50)
+	writeLog(t, b, 2000)
+	usages := Collect([]types.ContainerJSON{
+		inspected("a", "json-file", nil, a),
+		inspected("b", "json-file", nil, b),
+	}, true)
+
+	info, err := TruncateDryRun(usages[0])
+	require.NoError(t, err)
+	assert.Equal(t, docker.TierHighRisk, info.Tier)
+	assert.False(t, info.Reversible)
+	assert.Equal(t, []string{"log: b (2.0 kB)"}, info.Resources)
+
+	info, err = TruncateDryRun(usages...)
+	require.NoError(t, err)
+	assert.Equal(t, docker.TierBulkDestructive, info.Tier)
+	assert.Contains(t, info.Description, "3.0 kB", "rotated files are not counted")
+
+	_, err = TruncateDryRun(Usage{Container: "j", Driver: "journald"})
+	assert.ErrorIs(t, err, ErrNotTruncatable)
+
+	freed, err := Truncate(usages[1])
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), freed)
+	st, err := os.Stat(a)
+	require.NoError(t, err)
+	assert.Zero(t, st.Size())
+	st, err = os.Stat(a + ".1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), st.Size(), "rotated files are kept")
+}
@@ -0,0 +1,209 @@
+package logwatch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// DefaultCooldown is the minimum interval between two alerts from the same rule.
+const DefaultCooldown = 10 * time.Second
+
+// Rule describes when to raise an alert and what to do about it.
+// A rule matches when its Pattern (if set) matches the entry content and
+// the detected level is at least MinLevel (if set).
+type Rule struct {
+	Name     string
+	Pattern  *regexp.Regexp
+	MinLevel Level
+	Exec     string        // command run through the shell on each alert
+	File     string        // file the alert line is appended to
+	Cooldown time.Duration // rate limit; DefaultCooldown when zero
+}
+
+// ParseRule builds a rule from a spec string: "level:<name>" creates a level
+// threshold rule, anything else is compiled as a regular expression.
+func ParseRule(spec string) (Rule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return Rule{}, fmt.Errorf("empty alert pattern")
+	}
+	if rest, ok := strings.CutPrefix(spec, "level:"); ok {
+		level, err := ParseLevel(rest)
+		if err != nil {
+			return Rule{}, err
+		}
+		return Rule{Name: "level>=" + level.String(), MinLevel: level}, nil
+	}
+	re, err := regexp.Compile(spec)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid alert pattern %q: %w", spec, err)
+	}
+	return Rule{Name: spec, Pattern: re}, nil
+}
+
+// Matches reports whether the entry triggers the rule.
+func (r Rule) Matches(e docker.LogEntry) bool {
+	if r.Pattern == nil && r.MinLevel == LevelUnknown {
+		return false
+	}
+	if r.Pattern != nil && !r.Pattern.MatchString(e.Content) {
+		return false
+	}
+	if r.MinLevel != LevelUnknown && DetectLevel(e) < r.MinLevel {
+		return false
+	}
+	return true
+}
+
+func (r Rule) cooldown() time.Duration {
+	if r.Cooldown > 0 {
+		return r.Cooldown
+	}
+	return DefaultCooldown
+}
+
+// Alert is a fired rule together with the entry that triggered it.
+type Alert struct {
+	Rule       Rule
+	Entry      docker.LogEntry
+	Time       time.Time
+	Suppressed int // matches dropped by rate limiting since the previous alert
+}
+
+// String renders the alert as a single line for banners and alert files.
+func (a Alert) String() string {
+	s := fmt.Sprintf("%s [%s] %s", a.Time.Format("15:04:05"), a.Rule.Name, a.Entry.Content)
+	if a.Suppressed > 0 {
+		s += fmt.Sprintf(" (+%d suppressed)", a.Suppressed)
+	}
+	return s
+}
+
+// Watcher evaluates entries against a set of rules, rate limiting each rule
+// independently. It is safe for concurrent use.
+type Watcher struct {
+	mu         sync.Mutex
+	rules      []Rule
+	lastFired  []time.Time
+	suppressed []int
+	now        func() time.Time
+}
+
+// NewWatcher creates a watcher for the given rules.
+func NewWatcher(rules ...Rule) *Watcher {
+	w := &Watcher{now: time.Now}
+	for _, r := range rules {
+		w.Add(r)
+	}
+	return w
+}
+
+// Add registers another rule.
+func (w *Watcher) Add(r Rule) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.rules = append(w.rules, r)
+	w.lastFired = append(w.lastFired, time.Time{})
+	w.suppressed = append(w.suppressed, 0)
+}
+
+// Rules returns a copy of the registered rules.
+func (w *Watcher) Rules() []Rule {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Rule(nil), w.rules...)
+}
+
+// Len returns the number of registered rules.
+func (w *Watcher) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.rules)
+}
+
+// Check returns the alerts the entry fires. Matches that arrive within a
+// rule's cooldown are counted as suppressed instead of firing again.
+func (w *Watcher) Check(e docker.LogEntry) []Alert {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var alerts []Alert
+	now := w.now()
+	for i, r := range w.rules {
+		if !r.Matches(e) {
+			continue
+		}
+		if !w.lastFired[i].IsZero() && now.Sub(w.lastFired[i]) < r.cooldown() {
+			w.suppressed[i]++
+			continue
+		}
+		alerts = append(alerts, Alert{Rule: r, Entry: e, Time: now, Suppressed: w.suppressed[i]})
+		w.lastFired[i] = now
+		w.suppressed[i] = 0
+	}
+	return alerts
+}
+
+// DispatchTimeout bounds the actions of one alert.
+const DispatchTimeout = 30 * time.Second
+
+// Dispatch performs the alert's side effects: appending to the rule's file
+// and running its command. The command runs through the shell with the
+// triggering line on stdin and OCTO_ALERT_* variables in its environment.
+func Dispatch(ctx context.Context, a Alert, container string) error {
+	var errs []string
+	if a.Rule.File != "" {
+		if err := appendAlert(a.Rule.File, a, container); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if a.Rule.Exec != "" {
+		if err := runAlertCommand(ctx, a, container); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("alert %s: %s", a.Rule.Name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func appendAlert(path string, a Alert, container string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open alert file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	_, err = fmt.Fprintf(f, "%s %s %s\n", a.Time.Format(time.RFC3339), container, a.String())
+	return err
+}
+
+func runAlertCommand(ctx context.Context, a Alert, container string) error {
+	name, args := "sh", []string{"-c", a.Rule.Exec}
+	if runtime.GOOS == "windows" {
+		name, args = "cmd", []string{"/C", a.Rule.Exec}
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(a.Entry.Content + "\n")
+	cmd.Env = append(os.Environ(),
+		"OCTO_ALERT_RULE="+a.Rule.Name,
+		"OCTO_ALERT_CONTAINER="+container,
+		"OCTO_ALERT_STREAM="+a.Entry.Stream,
+		"OCTO_ALERT_TIMESTAMP="+a.Entry.Timestamp.Format(time.RFC3339Nano),
+		"OCTO_ALERT_LINE="+a.Entry.Content,
+		fmt.Sprintf("OCTO_ALERT_SUPPRESSED=%d", a.Suppressed),
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("exec %q: %w: %s", a.Rule.Exec, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
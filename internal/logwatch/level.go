@@ -0,0 +1,86 @@
+// Package logwatch inspects container log streams: it classifies entries by
+// severity and raises rate-limited alerts when registered patterns match.
+package logwatch
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// Level is the severity of a log entry, detected from its content.
+type Level int
+
+const (
+	LevelUnknown Level = iota
+	LevelTrace
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelTrace:
+		return "trace"
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	case LevelFatal:
+		return "fatal"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseLevel converts a level name (case-insensitive, common aliases allowed)
+// into a Level.
+func ParseLevel(s string) (Level, error) {
+	if l := levelFromWord(strings.ToLower(strings.TrimSpace(s))); l != LevelUnknown {
+		return l, nil
+	}
+	return LevelUnknown, fmt.Errorf("unknown log level %q (use trace, debug, info, warn, error, fatal)", s)
+}
+
+func levelFromWord(w string) Level {
+	switch w {
+	case "trace":
+		return LevelTrace
+	case "debug", "dbg":
+		return LevelDebug
+	case "info", "notice":
+		return LevelInfo
+	case "warn", "warning":
+		return LevelWarn
+	case "error", "err":
+		return LevelError
+	case "fatal", "panic", "critical", "crit", "emerg", "alert":
+		return LevelFatal
+	default:
+		return LevelUnknown
+	}
+}
+
+// levelPattern matches level tokens as whole words, which covers plain
+// prefixes ("ERROR ..."), bracketed forms ("[warn]"), logfmt ("level=info")
+// and JSON ("\"level\":\"debug\"").
+var levelPattern = regexp.MustCompile(`(?i)\b(trace|debug|dbg|info|notice|warn|warning|error|err|fatal|panic|critical|crit|emerg|alert)\b`)
+
+// DetectLevel returns the first level token found in the entry content,
+// or LevelUnknown when the line carries no recognizable severity.
+func DetectLevel(e docker.LogEntry) Level {
+	m := levelPattern.FindString(e.Content)
+	if m == "" {
+		return LevelUnknown
+	}
+	return levelFromWord(strings.ToLower(m))
+}
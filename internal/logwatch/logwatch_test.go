@@ -0,0 +1,146 @@
+package logwatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+var testTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func entry(content string) docker.LogEntry {
+	return docker.LogEntry{Timestamp: testTime, Stream: "stdout", Content: content}
+}
+
+func TestDetectLevel(t *testing.T) {
+	tests := []struct {
+		content string
+		want    Level
+	}{
+		{"ERROR connection refused", LevelError},
+		{"2026/01/01 [warn] slow query", LevelWarn},
+		{`level=info msg="started"`, LevelInfo},
+		{`{"level":"debug","msg":"tick"}`, LevelDebug},
+		{"panic: runtime error: index out of range", LevelFatal},
+		{"GET /healthz 200", LevelUnknown},
+		{"terrorist", LevelUnknown}, // word boundaries only
+	}
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectLevel(entry(tt.content)))
+		})
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	r, err := ParseRule("panic|OOM")
+	require.NoError(t, err)
+	assert.True(t, r.Matches(entry("fatal OOM killer invoked")))
+	assert.False(t, r.Matches(entry("all good")))
+
+	r, err = ParseRule("level:warn")
+	require.NoError(t, err)
+	assert.Equal(t, LevelWarn, r.MinLevel)
+	assert.True(t, r.Matches(entry("ERROR disk full")))
+	assert.True(t, r.Matches(entry("WARN retrying")))
+	assert.False(t, r.Matches(entry("INFO ready")))
+
+	_, err = ParseRule("level:loud")
+	assert.Error(t, err)
+	_, err = ParseRule("([")
+	assert.Error(t, err)
+	_, err = ParseRule("  ")
+	assert.Error(t, err)
+}
+
+func TestWatcher_RateLimits(t *testing.T) {
+	rule, err := ParseRule("panic")
+	require.NoError(t, err)
+	rule.Cooldown = time.Minute
+
+	now := testTime
+	w := NewWatcher(rule)
+	w.now = func() time.Time { return now }
+
+	assert.Len(t, w.Check(entry("panic: boom")), 1)
+	assert.Empty(t, w.Check(entry("panic: again")))
+	assert.Empty(t, w.Check(entry("panic: and again")))
+	assert.Empty(t, w.Check(entry("unrelated")))
+
+	now = now.Add(2 * time.Minute)
+	alerts := w.Check(entry("panic: later"))
+	require.Len(t, alerts, 1)
+	assert.Equal(t, 2, alerts[0].Suppressed)
+	assert.Contains(t, alerts[0].String(), "(+2 suppressed)")
+}
+
+func TestDispatch_FileAndExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	dir := t.TempDir()
+	alertFile := filepath.Join(dir, "alerts.log")
+	execOut := filepath.Join(dir, "exec.out")
+
+	rule, err := ParseRule("OOM")
+	require.NoError(t, err)
+	rule.File = alertFile
+	rule.Exec = `printf '%s|%s' "$OCTO_ALERT_CONTAINER" "$OCTO_ALERT_LINE" > ` + execOut
+
+	a := Alert{Rule: rule, Entry: entry("OOM killed"), Time: testTime}
+	require.NoError(t, Dispatch(context.Background(), a, "web"))
+
+	data, err := os.ReadFile(alertFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "web")
+	assert.Contains(t, string(data), "[OOM] OOM killed")
+
+	data, err = os.ReadFile(execOut)
+	require.NoError(t, err)
+	assert.Equal(t, "web|OOM killed", strings.TrimSpace(string(data)))
+}
+
+func TestStats_SlidingWindow(t *testing.T) {
+	now := testTime
+	s := NewStats(10 * time.Second)
+	s.now = func() time.Time { return now }
+	s.start = now
+
+	s.Add(entry("INFO started"))
+	s.Add(docker.LogEntry{Stream: "stderr", Content: "ERROR boom"})
+	now = now.Add(time.Second)
+	s.Add(entry("GET /"))
+	s.Add(entry("WARN slow"))
+
+	snap := s.Snapshot()
+	assert.Equal(t, 4, snap.Lines)
+	assert.Equal(t, len("INFO started")+len("ERROR boom")+len("GET /")+len("WARN slow"), snap.Bytes)
+	assert.InDelta(t, 2.0, snap.LinesPerSec, 0.001) // two seconds elapsed
+	assert.InDelta(t, 0.25, snap.StderrRatio, 0.001)
+	assert.Equal(t, 1, snap.Levels[LevelError])
+	assert.Equal(t, 1, snap.Levels[LevelUnknown])
+	require.Len(t, snap.Rate, 10)
+	assert.Equal(t, []int{2, 2}, snap.Rate[8:])
+
+	// Buckets older than the window fall out
+	now = now.Add(10 * time.Second)
+	s.Add(entry("later"))
+	snap = s.Snapshot()
+	assert.Equal(t, 1, snap.Lines)
+	assert.InDelta(t, 0.1, snap.LinesPerSec, 0.001)
+}
+
+func TestSnapshot_LevelSummary(t *testing.T) {
+	snap := Snapshot{Levels: map[Level]int{LevelInfo: 40, LevelError: 3, LevelUnknown: 2}}
+	assert.Equal(t, "error 3  info 40  other 2", snap.LevelSummary())
+	assert.Empty(t, Snapshot{}.LevelSummary())
+}
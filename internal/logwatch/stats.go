@@ -0,0 +1,128 @@
+package logwatch
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// DefaultStatsWindow is the sliding window used for log rate statistics.
+const DefaultStatsWindow = time.Minute
+
+// bucket aggregates the entries received during one second.
+type bucket struct {
+	sec    int64 // unix second the bucket covers
+	lines  int
+	bytes  int
+	stderr int
+	levels [LevelFatal + 1]int
+}
+
+// Stats computes log rate and volume statistics over a sliding window of
+// one-second buckets. Entries are bucketed by arrival time. It is safe for
+// concurrent use.
+type Stats struct {
+	mu      sync.Mutex
+	buckets []bucket
+	start   time.Time // when observation began, for spans shorter than the window
+	now     func() time.Time
+}
+
+// NewStats creates a statistics accumulator for the given window, rounded
+// up to whole seconds.
+func NewStats(window time.Duration) *Stats {
+	if window <= 0 {
+		window = DefaultStatsWindow
+	}
+	secs := int((window + time.Second - 1) / time.Second)
+	return &Stats{buckets: make([]bucket, secs), start: time.Now(), now: time.Now}
+}
+
+// Add records an entry.
+func (s *Stats) Add(e docker.LogEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sec := s.now().Unix()
+	b := &s.buckets[int(sec%int64(len(s.buckets)))]
+	if b.sec != sec {
+		*b = bucket{sec: sec}
+	}
+	b.lines++
+	b.bytes += len(e.Content)
+	if e.Stream == "stderr" {
+		b.stderr++
+	}
+	b.levels[DetectLevel(e)]++
+}
+
+// Snapshot is a point-in-time view of the statistics.
+type Snapshot struct {
+	Window      time.Duration
+	Lines       int
+	Bytes       int
+	Stderr      int
+	LinesPerSec float64
+	BytesPerSec float64
+	StderrRatio float64
+	Levels      map[Level]int // entries per detected level, LevelUnknown included
+	Rate        []int         // lines per second across the window, oldest first
+}
+
+// Snapshot summarizes the entries received within the window ending now.
+// Rates are averaged over the part of the window since observation began,
+// so a stream watched for five seconds is not diluted by a one-minute window.
+func (s *Stats) Snapshot() Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.buckets)
+	now := s.now().Unix()
+	snap := Snapshot{
+		Window: time.Duration(n) * time.Second,
+		Levels: make(map[Level]int),
+		Rate:   make([]int, n),
+	}
+	for i := 0; i < n; i++ {
+		sec := now - int64(n-1-i)
+		b := s.buckets[int(sec%int64(n))]
+		if b.sec != sec {
+			continue
+		}
+		snap.Rate[i] = b.lines
+		snap.Lines += b.lines
+		snap.Bytes += b.bytes
+		snap.Stderr += b.stderr
+		for l, c := range b.levels {
+			if c > 0 {
+				snap.Levels[Level(l)] += c
+			}
+		}
+	}
+
+	elapsed := min(max(int(now-s.start.Unix())+1, 1), n)
+	snap.LinesPerSec = float64(snap.Lines) / float64(elapsed)
+	snap.BytesPerSec = float64(snap.Bytes) / float64(elapsed)
+	if snap.Lines > 0 {
+		snap.StderrRatio = float64(snap.Stderr) / float64(snap.Lines)
+	}
+	return snap
+}
+
+// LevelSummary renders the level histogram most severe first, e.g.
+// "error 3  warn 5  info 40  other 12". Levels without entries are omitted.
+func (s Snapshot) LevelSummary() string {
+	var parts []string
+	for l := LevelFatal; l >= LevelTrace; l-- {
+		if c := s.Levels[l]; c > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", l, c))
+		}
+	}
+	if c := s.Levels[LevelUnknown]; c > 0 {
+		parts = append(parts, fmt.Sprintf("other %d", c))
+	}
+	return strings.Join(parts, "  ")
+}
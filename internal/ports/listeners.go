@@ -0,0 +1,186 @@
+package ports
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Listener is a socket accepting connections (TCP) or datagrams (UDP) on
+// the host, read from /proc/net.
+type Listener struct {
+	Protocol string `json:"protocol" yaml:"protocol"`
+	IP       string `json:"ip" yaml:"ip"`
+	Port     uint16 `json:"port" yaml:"port"`
+	Inode    uint64 `json:"-" yaml:"-"`
+	PID      int    `json:"pid,omitempty" yaml:"pid,omitempty"`
+	Process  string `json:"process,omitempty" yaml:"process,omitempty"` // empty when the owner is not visible
+}
+
+// dockerProcesses are the host processes that hold published ports on
+// Docker's behalf.
+var dockerProcesses = map[string]bool{
+	"docker-proxy":             true,
+	"dockerd":                  true,
+	"rootlesskit":              true,
+	"rootlesskit-docker-proxy": true,
+}
+
+// Docker reports whether the socket belongs to Docker's port forwarding.
+func (l Listener) Docker() bool {
+	return dockerProcesses[l.Process]
+}
+
+// ErrNoProcNet is returned by HostListeners where /proc/net is unavailable.
+var ErrNoProcNet = errors.New("host sockets are only readable from /proc/net on Linux")
+
+// HostListeners returns the listening TCP and bound UDP sockets of this
+// host's network namespace. Owners are resolved through /proc/<pid>/fd,
+// which only covers other users' processes when running as root.
+func HostListeners() ([]Listener, error) {
+	return readListeners("/proc")
+}
+
+// procNetFiles maps /proc/net files to protocols.
+var procNetFiles = []struct{ file, proto string }{
+	{"tcp", "tcp"}, {"tcp6", "tcp"}, {"udp", "udp"}, {"udp6", "udp"},
+}
+
+func readListeners(procRoot string) ([]Listener, error) {
+	var listeners []Listener
+	found := false
+	for _, f := range procNetFiles {
+		file, err := os.Open(filepath.Join(procRoot, "net", f.file))
+		if errors.Is(err, os.ErrNotExist) {
+			continue // no IPv6, or not Linux
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+		ls, err := parseProcNet(file, f.proto)
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading /proc/net/%s: %w", f.file, err)
+		}
+		listeners = append(listeners, ls...)
+	}
+	if !found {
+		return nil, ErrNoProcNet
+	}
+	resolveOwners(procRoot, listeners)
+	return listeners, nil
+}
+
+// Socket states in /proc/net: TCP_LISTEN, and TCP_CLOSE for unconnected
+// UDP sockets.
+const (
+	stateListen = "0A"
+	stateClose  = "07"
+)
+
+// parseProcNet parses a /proc/net/{tcp,udp}[6] table, keeping listening
+// TCP and unconnected UDP sockets.
+func parseProcNet(r io.Reader, proto string) ([]Listener, error) {
+	want := stateListen
+	if proto == "udp" {
+		want = stateClose
+	}
+	var listeners []Listener
+	scanner := bufio.NewScanner(r)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != want {
+			continue
+		}
+		ip, port, err := parseHexAddr(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		inode, _ := strconv.ParseUint(fields[9], 10, 64)
+		listeners = append(listeners, Listener{Protocol: proto, IP: ip, Port: port, Inode: inode})
+	}
+	return listeners, scanner.Err()
+}
+
+// parseHexAddr decodes "0100007F:1F90" style addresses. The address is
+// stored as 32-bit words in host byte order (little-endian on the
+// platforms Docker runs on).
+func parseHexAddr(s string) (string, uint16, error) {
+	host, portHex, ok := strings.Cut(s, ":")
+	if !ok {
+		return "", 0, fmt.Errorf("malformed address %q", s)
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("malformed port in %q", s)
+	}
+	raw, err := hex.DecodeString(host)
+	if err != nil || (len(raw) != 4 && len(raw) != 16) {
+		return "", 0, fmt.Errorf("malformed address %q", s)
+	}
+	for i := 0; i < len(raw); i += 4 {
+		raw[i], raw[i+1], raw[i+2], raw[i+3] = raw[i+3], raw[i+2], raw[i+1], raw[i]
+	}
+	addr, _ := netip.AddrFromSlice(raw)
+	return addr.Unmap().String(), uint16(port), nil
+}
+
+// resolveOwners fills PID and Process from the socket inodes held open by
+// visible processes. Processes that cannot be read are skipped.
+func resolveOwners(procRoot string, listeners []Listener) {
+	byInode := make(map[uint64][]int, len(listeners))
+	for i, l := range listeners {
+		if l.Inode != 0 {
+			byInode[l.Inode] = append(byInode[l.Inode], i)
+		}
+	}
+	if len(byInode) == 0 {
+		return
+	}
+	procs, err := os.ReadDir(procRoot)
+	if err != nil {
+		return
+	}
+	for _, p := range procs {
+		pid, err := strconv.Atoi(p.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join(procRoot, p.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		var comm string
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(target, "socket:[") {
+				continue
+			}
+			inode, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(target, "socket:["), "]"), 10, 64)
+			if err != nil {
+				continue
+			}
+			idxs, ok := byInode[inode]
+			if !ok {
+				continue
+			}
+			if comm == "" {
+				data, _ := os.ReadFile(filepath.Join(procRoot, p.Name(), "comm"))
+				comm = strings.TrimSpace(string(data))
+			}
+			for _, i := range idxs {
+				listeners[i].PID, listeners[i].Process = pid, comm
+			}
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// Package ports maps published host ports to the containers and Compose
+// services that own them, and flags ports claimed twice: by two containers
+// or by a container and another process listening on the host.
+package ports
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// Entry is a host port published by one container. IPv4 and IPv6 bindings
+// of the same mapping are merged into one entry.
+type Entry struct {
+	Protocol      string   `json:"protocol" yaml:"protocol"`
+	HostPort      uint16   `json:"host_port" yaml:"host_port"`
+	HostIPs       []string `json:"host_ips" yaml:"host_ips"`
+	ContainerPort uint16   `json:"container_port" yaml:"container_port"`
+	Container     string   `json:"container" yaml:"container"`
+	ContainerID   string   `json:"container_id" yaml:"container_id"`
+	Project       string   `json:"project,omitempty" yaml:"project,omitempty"`
+	Service       string   `json:"service,omitempty" yaml:"service,omitempty"`
+	Running       bool     `json:"running" yaml:"running"`
+	Conflicts     []string `json:"conflicts,omitempty" yaml:"conflicts,omitempty"`
+}
+
+// Address renders the bind addresses, with "*" for the IPv4 and IPv6
+// wildcard pair Docker binds by default.
+func (e Entry) Address() string {
+	skip := make(map[string]bool)
+	var parts []string
+	if contains(e.HostIPs, "0.0.0.0") && contains(e.HostIPs, "::") {
+		parts = append(parts, "*")
+		skip["0.0.0.0"], skip["::"] = true, true
+	}
+	for _, ip := range e.HostIPs {
+		switch {
+		case skip[ip]:
+		case strings.Contains(ip, ":"):
+			parts = append(parts, "["+ip+"]")
+		default:
+			parts = append(parts, ip)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Owner renders the container, with its Compose service when it has one.
+func (e Entry) Owner() string {
+	if e.Service != "" {
+		return fmt.Sprintf("%s (%s/%s)", e.Container, e.Project, e.Service)
+	}
+	return e.Container
+}
+
+// Map lists the published ports of containers, sorted by port, and flags
+// conflicts against each other and against host listeners (nil when the
+// host's sockets are unknown).
+func Map(containers []docker.ContainerInfo, listeners []Listener) []Entry {
+	type key struct {
+		id, proto            string
+		hostPort, targetPort uint16
+	}
+	index := make(map[key]int)
+	var entries []Entry
+	for _, c := range containers {
+		for _, b := range c.PortBindings {
+			if !b.Published() {
+				continue
+			}
+			ip := b.HostIP
+			if ip == "" {
+				ip = "0.0.0.0"
+			}
+			k := key{c.ID, b.Protocol, b.HostPort, b.ContainerPort}
+			if i, ok := index[k]; ok {
+				entries[i].HostIPs = append(entries[i].HostIPs, ip)
+				continue
+			}
+			index[k] = len(entries)
+			entries = append(entries, Entry{
+				Protocol:      b.Protocol,
+				HostPort:      b.HostPort,
+				HostIPs:       []string{ip},
+				ContainerPort: b.ContainerPort,
+				Container:     c.Name,
+				ContainerID:   c.ID,
+				Project:       c.Labels[docker.ComposeProjectLabel],
+				Service:       c.Labels[docker.ComposeServiceLabel],
+				Running:       c.State == "running",
+			})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].HostPort != entries[j].HostPort {
+			return entries[i].HostPort < entries[j].HostPort
+		}
+		if entries[i].Protocol != entries[j].Protocol {
+			return entries[i].Protocol < entries[j].Protocol
+		}
+		return entries[i].Container < entries[j].Container
+	})
+
+	for i := range entries {
+		e := &entries[i]
+		for j, other := range entries {
+			if i == j || other.ContainerID == e.ContainerID || !e.collides(other.Protocol, other.HostPort, other.HostIPs...) {
+				continue
+			}
+			e.addConflict("also published by " + other.Container)
+		}
+		for _, l := range listeners {
+			if !e.collides(l.Protocol, l.Port, l.IP) {
+				continue
+			}
+			if msg := e.listenerConflict(l, entries); msg != "" {
+				e.addConflict(msg)
+			}
+		}
+	}
+	return entries
+}
+
+// addConflict records msg once; a process listening on both IPv4 and IPv6
+// is seen twice.
+func (e *Entry) addConflict(msg string) {
+	if !contains(e.Conflicts, msg) {
+		e.Conflicts = append(e.Conflicts, msg)
+	}
+}
+
+// listenerConflict explains why a host listener on the entry's port is a
+// problem, or returns "" when Docker itself holds the socket.
+func (e Entry) listenerConflict(l Listener, entries []Entry) string {
+	if l.Process != "" && !l.Docker() {
+		return fmt.Sprintf("also bound by %s (pid %d)", l.Process, l.PID)
+	}
+	if e.Running {
+		// Docker's proxy holds the sockets of running containers; an
+		// unidentified owner (not root) is most likely that proxy.
+		return ""
+	}
+	for _, other := range entries {
+		if other.Running && other.collides(l.Protocol, l.Port, l.IP) {
+			return "" // reported as a container conflict
+		}
+	}
+	if l.Process != "" {
+		return fmt.Sprintf("in use by %s (pid %d); container cannot start", l.Process, l.PID)
+	}
+	return "in use by another process; container cannot start"
+}
+
+// collides reports whether a socket on proto/port/ips overlaps the entry.
+func (e Entry) collides(proto string, port uint16, ips ...string) bool {
+	if proto != e.Protocol || port != e.HostPort {
+		return false
+	}
+	for _, a := range e.HostIPs {
+		for _, b := range ips {
+			if overlaps(a, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// overlaps reports whether sockets bound to addresses a and b compete for
+// the same connections. An IPv6 wildcard accepts IPv4 too (dual-stack).
+func overlaps(a, b string) bool {
+	if a == "::" || b == "::" {
+		return true
+	}
+	pa, errA := netip.ParseAddr(a)
+	pb, errB := netip.ParseAddr(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	pa, pb = pa.Unmap(), pb.Unmap()
+	if pa.Is4() != pb.Is4() {
+		return false
+	}
+	return pa.IsUnspecified() || pb.IsUnspecified() || pa == pb
+}
+
+// Configured returns the port bindings a container requests in its host
+// config. A stopped container has no live bindings, but these are what it
+// claims when started. Ranges and ephemeral (empty) host ports are skipped.
+func Configured(c types.ContainerJSON) []docker.PortBinding {
+	if c.HostConfig == nil {
+		return nil
+	}
+	var bindings []docker.PortBinding
+	for port, hostBindings := range c.HostConfig.PortBindings {
+		for _, hb := range hostBindings {
+			hostPort, err := strconv.ParseUint(hb.HostPort, 10, 16)
+			if err != nil || hostPort == 0 {
+				continue
+			}
+			bindings = append(bindings, docker.PortBinding{
+				HostIP:        hb.HostIP,
+				HostPort:      uint16(hostPort),
+				ContainerPort: uint16(port.Int()),
+				Protocol:      port.Proto(),
+			})
+		}
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].HostPort < bindings[j].HostPort
+	})
+	return bindings
+}
+
+// Conflicted returns the entries with at least one conflict.
+func Conflicted(entries []Entry) []Entry {
+	var out []Entry
+	for _, e := range entries {
+		if len(e.Conflicts) > 0 {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// WithConfigured returns containers with each stopped container's
+// configured bindings filled in from inspect, so that Map can flag the ports
+// they will claim when started. Containers removed meanwhile are skipped.
+func WithConfigured(ctx context.Context, svc docker.DockerService, containers []docker.ContainerInfo) ([]docker.ContainerInfo, error) {
+	out := make([]docker.ContainerInfo, 0, len(containers))
+	for _, c := range containers {
+		if c.State != "running" && len(c.PortBindings) == 0 {
+			inspectCtx, cancel := context.WithTimeout(ctx, docker.TimeoutList)
+			details, err := svc.InspectContainer(inspectCtx, c.ID)
+			cancel()
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("inspecting %s: %w", c.Name, err)
+			}
+			c.PortBindings = Configured(details)
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// LocalListeners returns the host's listening sockets when the daemon
+// shares this host's network namespace. It reports false when the sockets
+// are unknown: a remote or VM daemon, or no /proc/net.
+func LocalListeners() ([]Listener, bool) {
+	if !docker.IsLocalDaemon() {
+		return nil, false
+	}
+	listeners, err := HostListeners()
+	if err != nil {
+		return nil, false
+	}
+	return listeners, true
+}
@@ -0,0 +1,159 @@
+package ports
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+func published(hostIP string, hostPort, containerPort uint16) docker.PortBinding {
+	return docker.PortBinding{HostIP: hostIP, HostPort: hostPort, ContainerPort: containerPort, Protocol: "tcp"}
+}
+
+func ctr(name, state string, bindings ...docker.PortBinding) docker.ContainerInfo {
+	return docker.ContainerInfo{ID: name + "-id", Name: name, State: state, PortBindings: bindings}
+}
+
+func TestMapMergesAndSorts(t *testing.T) {
+	web := ctr("web", "running",
+		published("0.0.0.0", 8080, 80), published("::", 8080, 80),
+		docker.PortBinding{ContainerPort: 9000, Protocol: "tcp"}, // exposed only
+	)
+	web.Labels = map[string]string{docker.ComposeProjectLabel: "shop", docker.ComposeServiceLabel: "web"}
+	db := ctr("db", "running", published("127.0.0.1", 5432, 5432))
+
+	entries := Map([]docker.ContainerInfo{web, db}, nil)
+
+	require.Len(t, entries, 2)
+	assert.Equal(t, uint16(5432), entries[0].HostPort)
+	assert.Equal(t, "127.0.0.1", entries[0].Address())
+	assert.Equal(t, "db", entries[0].Owner())
+	assert.Equal(t, []string{"0.0.0.0", "::"}, entries[1].HostIPs)
+	assert.Equal(t, "*", entries[1].Address())
+	assert.Equal(t, "web (shop/web)", entries[1].Owner())
+	assert.Empty(t, Conflicted(entries))
+}
+
+func TestMapContainerConflicts(t *testing.T) {
+	entries := Map([]docker.ContainerInfo{
+		ctr("api", "running", published("0.0.0.0", 3000, 3000)),
+		ctr("api-old", "exited", published("127.0.0.1", 3000, 8080)),
+		ctr("other", "exited", published("10.0.0.5", 3001, 3000)),
+		ctr("v6", "exited", published("fe80::1", 3000, 3000)),
+	}, nil)
+
+	byName := make(map[string]Entry)
+	for _, e := range entries {
+		byName[e.Container] = e
+	}
+	assert.Equal(t, []string{"also published by api-old"}, byName["api"].Conflicts)
+	assert.Equal(t, []string{"also published by api"}, byName["api-old"].Conflicts)
+	assert.Empty(t, byName["other"].Conflicts)
+	assert.Empty(t, byName["v6"].Conflicts, "IPv4 wildcard does not cover IPv6 addresses")
+}
+
+func TestMapListenerConflicts(t *testing.T) {
+	containers := []docker.ContainerInfo{
+		ctr("web", "running", published("0.0.0.0", 80, 80)),
+		ctr("mail", "running", published("0.0.0.0", 25, 25)),
+		ctr("pg", "exited", published("127.0.0.1", 5432, 5432)),
+		ctr("cache", "exited", published("0.0.0.0", 6379, 6379)),
+	}
+	listeners := []Listener{
+		{Protocol: "tcp", IP: "0.0.0.0", Port: 80, PID: 10, Process: "docker-proxy"},
+		{Protocol: "tcp", IP: "::", Port: 25, PID: 11, Process: "exim4"},
+		{Protocol: "tcp", IP: "0.0.0.0", Port: 25, PID: 11, Process: "exim4"},
+		{Protocol: "tcp", IP: "0.0.0.0", Port: 5432, PID: 12, Process: "postgres"},
+		{Protocol: "tcp", IP: "127.0.0.1", Port: 6379}, // owner not visible
+		{Protocol: "udp", IP: "0.0.0.0", Port: 80, PID: 13, Process: "dnsmasq"},
+	}
+
+	byName := make(map[string]Entry)
+	for _, e := range Map(containers, listeners) {
+		byName[e.Container] = e
+	}
+	assert.Empty(t, byName["web"].Conflicts, "docker-proxy and other protocols are fine")
+	assert.Equal(t, []string{"also bound by exim4 (pid 11)"}, byName["mail"].Conflicts)
+	assert.Equal(t, []string{"also bound by postgres (pid 12)"}, byName["pg"].Conflicts)
+	assert.Equal(t, []string{"in use by another process; container cannot start"}, byName["cache"].Conflicts)
+}
+
+func TestOverlaps(t *testing.T) {
+	assert.True(t, overlaps("0.0.0.0", "127.0.0.1"))
+	assert.True(t, overlaps("::", "127.0.0.1"))
+	assert.True(t, overlaps("::ffff:127.0.0.1", "127.0.0.1"))
+	assert.False(t, overlaps("127.0.0.1", "10.0.0.1"))
+	assert.False(t, overlaps("0.0.0.0", "::1"))
+}
+
+func TestConfigured(t *testing.T) {
+	c := types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{HostConfig: &container.HostConfig{
+		PortBindings: nat.PortMap{
+			"80/tcp":   {{HostIP: "", HostPort: "8080"}},
+			"53/udp":   {{HostIP: "127.0.0.1", HostPort: "5353"}},
+			"9000/tcp": {{HostPort: ""}},
+		},
+	}}}
+
+	assert.Equal(t, []docker.PortBinding{
+		{HostIP: "127.0.0.1", HostPort: 5353, ContainerPort: 53, Protocol: "udp"},
+		{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+	}, Configured(c))
+	assert.Nil(t, Configured(types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{}}))
+}
+
+const procNetTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 0100007F:D431 01 00000000:00000000 00:00000000 00000000     0        0 1002 1 0000000000000000 20 4 30 10 -1
+`
+
+const procNetTCP6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:0050 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 2001 1 0000000000000000 100 0 0 10 0
+   1: 0000000000000000FFFF00000100007F:0019 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 2002 1 0000000000000000 100 0 0 10 0
+`
+
+func TestParseProcNet(t *testing.T) {
+	ls, err := parseProcNet(strings.NewReader(procNetTCP), "tcp")
+	require.NoError(t, err)
+	require.Len(t, ls, 1, "established connections are skipped")
+	assert.Equal(t, Listener{Protocol: "tcp", IP: "127.0.0.1", Port: 8080, Inode: 1001}, ls[0])
+
+	ls, err = parseProcNet(strings.NewReader(procNetTCP6), "tcp")
+	require.NoError(t, err)
+	require.Len(t, ls, 2)
+	assert.Equal(t, "::", ls[0].IP)
+	assert.Equal(t, uint16(80), ls[0].Port)
+	assert.Equal(t, "127.0.0.1", ls[1].IP, "v4-mapped address unmapped")
+
+	_, err = parseProcNet(strings.NewReader("header\n 0: zz:1 0 0A a b c d e 1\n"), "tcp")
+	assert.Error(t, err)
+}
+
+func TestReadListenersResolvesOwners(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "net"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "net", "tcp"), []byte(procNetTCP), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "42", "fd"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "42", "comm"), []byte("nginx\n"), 0o644))
+	require.NoError(t, os.Symlink("socket:[1001]", filepath.Join(root, "42", "fd", "3")))
+	require.NoError(t, os.Symlink("/dev/null", filepath.Join(root, "42", "fd", "0")))
+
+	ls, err := readListeners(root)
+	require.NoError(t, err)
+	require.Len(t, ls, 1)
+	assert.Equal(t, 42, ls[0].PID)
+	assert.Equal(t, "nginx", ls[0].Process)
+	assert.False(t, ls[0].Docker())
+
+	_, err = readListeners(t.TempDir())
+	assert.ErrorIs(t, err, ErrNoProcNet)
+}
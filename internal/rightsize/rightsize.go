@@ -0,0 +1,236 @@
+// Package rightsize compares the CPU and memory containers use over a
+// sampling window with the limits they are configured with, flags missing,
+// oversized and nearly exhausted limits, and suggests values per Compose
+// service.
+package rightsize
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/dustin/go-humanize"
+)
+
+// Thresholds for findings and suggestions.
+const (
+	Headroom       = 1.5      // suggested limit over the observed peak
+	OversizeFactor = 4.0      // limit over peak before it counts as oversized
+	NearLimitRatio = 0.9      // memory peak over limit before it counts as near the limit
+	MinCPUs        = 0.1      // smallest CPU limit suggested
+	cpuStep        = 0.05     // CPU suggestions are rounded up to this
+	MinMemory      = 32 << 20 // smallest memory limit suggested
+	memoryStep     = 16 << 20 // memory suggestions are rounded up to this
+)
+
+// Finding kinds.
+const (
+	FindingNoLimits  = "no-limits"
+	FindingOversized = "oversized"
+	FindingNearLimit = "near-limit"
+)
+
+// Limits are the CPU and memory limits of a container. Zero means
+// unlimited.
+type Limits struct {
+	CPUs   float64 `json:"cpus" yaml:"cpus"`
+	Memory uint64  `json:"memory_bytes" yaml:"memory_bytes"`
+}
+
+// LimitsFrom reads the limits from a container's host config: --cpus, or
+// the equivalent CFS quota, and --memory.
+func LimitsFrom(c types.ContainerJSON) Limits {
+	if c.ContainerJSONBase == nil || c.HostConfig == nil {
+		return Limits{}
+	}
+	r := c.HostConfig.Resources
+	l := Limits{Memory: uint64(max(r.Memory, 0))}
+	switch {
+	case r.NanoCPUs > 0:
+		l.CPUs = float64(r.NanoCPUs) / 1e9
+	case r.CPUQuota > 0:
+		period := r.CPUPeriod
+		if period <= 0 {
+			period = 100000 // CFS default
+		}
+		l.CPUs = float64(r.CPUQuota) / float64(period)
+	}
+	return l
+}
+
+// Target is a running container to sample.
+type Target struct {
+	ID      string
+	Name    string
+	Project string
+	Service string
+	Limits  Limits
+}
+
+// Finding is one problem with a service's limits.
+type Finding struct {
+	Kind    string `json:"kind" yaml:"kind"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// Report is the right-sizing result for one Compose service, or one
+// container outside Compose. Replicas are combined: the peaks are the
+// highest of any replica. Suggested.CPUs is 0 when CPU was not measured.
+type Report struct {
+	Name       string    `json:"name" yaml:"name"`
+	Project    string    `json:"project,omitempty" yaml:"project,omitempty"`
+	Service    string    `json:"service,omitempty" yaml:"service,omitempty"`
+	Containers []string  `json:"containers" yaml:"containers"`
+	Limits     Limits    `json:"limits" yaml:"limits"`
+	Observed   Observed  `json:"observed" yaml:"observed"`
+	Findings   []Finding `json:"findings,omitempty" yaml:"findings,omitempty"`
+	Suggested  *Limits   `json:"suggested,omitempty" yaml:"suggested,omitempty"`
+}
+
+// Analyze groups the targets by Compose service and compares what each
+// service used with its limits. Services with findings come first.
+func Analyze(targets []Target, observed map[string]Observed) []Report {
+	index := make(map[string]int)
+	var reports []Report
+	for _, t := range targets {
+		key, name := t.ID, t.Name
+		if t.Service != "" {
+			key = t.Project + "/" + t.Service
+			name = key
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(reports)
+			index[key] = i
+			reports = append(reports, Report{Name: name, Project: t.Project, Service: t.Service})
+		}
+		r := &reports[i]
+		r.Containers = append(r.Containers, t.Name)
+		// Replicas normally share limits; keep the largest if they differ.
+		r.Limits.CPUs = max(r.Limits.CPUs, t.Limits.CPUs)
+		r.Limits.Memory = max(r.Limits.Memory, t.Limits.Memory)
+		r.Observed = r.Observed.merge(observed[t.ID])
+	}
+	for i := range reports {
+		reports[i].evaluate()
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		if (len(reports[i].Findings) > 0) != (len(reports[j].Findings) > 0) {
+			return len(reports[i].Findings) > 0
+		}
+		return reports[i].Name < reports[j].Name
+	})
+	return reports
+}
+
+// evaluate fills the findings and the suggested limits.
+func (r *Report) evaluate() {
+	o, l := r.Observed, r.Limits
+	if o.Samples == 0 {
+		return
+	}
+	switch {
+	case l.CPUs == 0 && l.Memory == 0:
+		r.add(FindingNoLimits, "no CPU or memory limit")
+	case l.Memory == 0:
+		r.add(FindingNoLimits, "no memory limit")
+	case l.CPUs == 0:
+		r.add(FindingNoLimits, "no CPU limit")
+	}
+	if l.Memory > 0 && o.MemoryPeak > 0 {
+		ratio := float64(o.MemoryPeak) / float64(l.Memory)
+		switch {
+		case ratio >= NearLimitRatio:
+			r.add(FindingNearLimit, fmt.Sprintf("memory peaked at %.0f%% of the %s limit", ratio*100, humanize.IBytes(l.Memory)))
+		case float64(l.Memory) > OversizeFactor*float64(o.MemoryPeak):
+			r.add(FindingOversized, fmt.Sprintf("memory limit %s is %.0f× the %s peak",
+				humanize.IBytes(l.Memory), 1/ratio, humanize.IBytes(o.MemoryPeak)))
+		}
+	}
+	if o.CPUSamples > 0 && l.CPUs > 0 && l.CPUs > OversizeFactor*max(o.CPUPeak, MinCPUs) {
+		r.add(FindingOversized, fmt.Sprintf("CPU limit %s is far above the %s peak",
+			FormatCPUs(l.CPUs), FormatCPUs(o.CPUPeak)))
+	}
+	if len(r.Findings) > 0 {
+		r.Suggested = &Limits{Memory: suggestMemory(o.MemoryPeak)}
+		if o.CPUSamples > 0 {
+			r.Suggested.CPUs = suggestCPUs(o.CPUPeak)
+		}
+	}
+}
+
+func (r *Report) add(kind, message string) {
+	r.Findings = append(r.Findings, Finding{Kind: kind, Message: message})
+}
+
+// suggestCPUs adds headroom to the peak and rounds up to a cpuStep.
+func suggestCPUs(peak float64) float64 {
+	cpus := math.Ceil(peak*Headroom/cpuStep-1e-9) * cpuStep
+	return math.Round(max(cpus, MinCPUs)*100) / 100
+}
+
+// suggestMemory adds headroom to the peak and rounds up to a memoryStep.
+func suggestMemory(peak uint64) uint64 {
+	mem := uint64(math.Ceil(float64(peak)*Headroom/memoryStep)) * memoryStep
+	return max(mem, MinMemory)
+}
+
+// FormatCPUs renders a CPU count as accepted by --cpus and Compose.
+func FormatCPUs(cpus float64) string {
+	return strconv.FormatFloat(math.Round(cpus*100)/100, 'f', -1, 64)
+}
+
+// FormatMemory renders a memory size in the unit suffixes accepted by
+// --memory and Compose ("512m", "2g").
+func FormatMemory(bytes uint64) string {
+	const mib, gib = 1 << 20, 1 << 30
+	if bytes >= gib && bytes%gib == 0 {
+		return fmt.Sprintf("%dg", bytes/gib)
+	}
+	return fmt.Sprintf("%dm", (bytes+mib-1)/mib)
+}
+
+// UpdateCommand returns the docker update command applying the suggested
+// limits to a container outside Compose.
+func (r Report) UpdateCommand() string {
+	if r.Suggested == nil {
+		return ""
+	}
+	cmd := "docker update"
+	if r.Suggested.CPUs > 0 {
+		cmd += " --cpus " + FormatCPUs(r.Suggested.CPUs)
+	}
+	return fmt.Sprintf("%s --memory %s %s", cmd, FormatMemory(r.Suggested.Memory), strings.Join(r.Containers, " "))
+}
+
+// ComposeSnippet renders the suggested limits of the Compose services in
+// reports, by project, as a docker-compose override.
+func ComposeSnippet(reports []Report) string {
+	byProject := make(map[string][]Report)
+	var projects []string
+	for _, r := range reports {
+		if r.Service == "" || r.Suggested == nil {
+			continue
+		}
+		if _, ok := byProject[r.Project]; !ok {
+			projects = append(projects, r.Project)
+		}
+		byProject[r.Project] = append(byProject[r.Project], r)
+	}
+	sort.Strings(projects)
+	var b strings.Builder
+	for _, p := range projects {
+		fmt.Fprintf(&b, "# %s\nservices:\n", p)
+		for _, r := range byProject[p] {
+			fmt.Fprintf(&b, "  %s:\n    deploy:\n      resources:\n        limits:\n", r.Service)
+			if r.Suggested.CPUs > 0 {
+				fmt.Fprintf(&b, "          cpus: %q\n", FormatCPUs(r.Suggested.CPUs))
+			}
+			fmt.Fprintf(&b, "          memory: %s\n", strings.ToUpper(FormatMemory(r.Suggested.Memory)))
+		}
+	}
+	return b.String()
+}
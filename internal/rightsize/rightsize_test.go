@@ -0,0 +1,150 @@
+package rightsize
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+const mib = 1 << 20
+
+func TestLimitsFrom(t *testing.T) {
+	withResources := func(r container.Resources) types.ContainerJSON {
+		return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{HostConfig: &container.HostConfig{Resources: r}}}
+	}
+	assert.Equal(t, Limits{CPUs: 1.5, Memory: 512 * mib}, LimitsFrom(withResources(container.Resources{NanoCPUs: 1.5e9, Memory: 512 * mib})))
+	assert.Equal(t, Limits{CPUs: 0.5}, LimitsFrom(withResources(container.Resources{CPUQuota: 50000})))
+	assert.Equal(t, Limits{CPUs: 2}, LimitsFrom(withResources(container.Resources{CPUQuota: 400000, CPUPeriod: 200000})))
+	assert.Equal(t, Limits{}, LimitsFrom(types.ContainerJSON{}))
+}
+
+func TestSamplerUsesCounterDeltas(t *testing.T) {
+	var s Sampler
+	// The first sample only carries counters since start.
+	s.Add(docker.ContainerMetrics{CPUPercent: 80, CPUTotalUsage: 1000, SystemCPUUsage: 10000, OnlineCPUs: 4, MemoryUsage: 100 * mib})
+	s.Add(docker.ContainerMetrics{CPUTotalUsage: 1500, SystemCPUUsage: 12000, OnlineCPUs: 4, MemoryUsage: 300 * mib})
+	s.Add(docker.ContainerMetrics{CPUTotalUsage: 1600, SystemCPUUsage: 14000, OnlineCPUs: 4, MemoryUsage: 200 * mib})
+
+	o := s.Observed()
+	assert.Equal(t, 3, o.Samples)
+	assert.Equal(t, 2, o.CPUSamples)
+	assert.InDelta(t, 1.0, o.CPUPeak, 1e-9)
+	assert.InDelta(t, 0.6, o.CPUAvg, 1e-9)
+	assert.Equal(t, uint64(300*mib), o.MemoryPeak)
+	assert.Equal(t, uint64(200*mib), o.MemoryAvg)
+
+	var plain Sampler
+	plain.Add(docker.ContainerMetrics{CPUPercent: 25, MemoryUsage: mib})
+	assert.InDelta(t, 0.25, plain.Observed().CPUPeak, 1e-9, "CPUPercent without raw counters")
+}
+
+func TestAnalyze(t *testing.T) {
+	targets := []Target{
+		{ID: "w1", Name: "shop-web-1", Project: "shop", Service: "web"},
+		{ID: "w2", Name: "shop-web-2", Project: "shop", Service: "web"},
+		{ID: "db", Name: "shop-db-1", Project: "shop", Service: "db", Limits: Limits{CPUs: 1, Memory: 1024 * mib}},
+		{ID: "cache", Name: "cache", Limits: Limits{CPUs: 4, Memory: 2048 * mib}},
+		{ID: "ok", Name: "ok", Limits: Limits{CPUs: 0.5, Memory: 256 * mib}},
+		{ID: "new", Name: "new"},
+	}
+	observed := map[string]Observed{
+		"w1":    {Samples: 2, CPUSamples: 2, CPUPeak: 0.2, CPUAvg: 0.1, MemoryPeak: 100 * mib, MemoryAvg: 80 * mib},
+		"w2":    {Samples: 2, CPUSamples: 2, CPUPeak: 0.3, CPUAvg: 0.2, MemoryPeak: 90 * mib, MemoryAvg: 60 * mib},
+		"db":    {Samples: 4, CPUSamples: 4, CPUPeak: 0.5, MemoryPeak: 1000 * mib},
+		"cache": {Samples: 4, CPUSamples: 4, CPUPeak: 0.2, MemoryPeak: 100 * mib},
+		"ok":    {Samples: 4, CPUSamples: 4, CPUPeak: 0.3, MemoryPeak: 150 * mib},
+	}
+
+	reports := Analyze(targets, observed)
+	require.Len(t, reports, 5)
+	byName := make(map[string]Report)
+	for _, r := range reports {
+		byName[r.Name] = r
+	}
+	assert.Equal(t, []string{"cache", "shop/db", "shop/web", "new", "ok"},
+		[]string{reports[0].Name, reports[1].Name, reports[2].Name, reports[3].Name, reports[4].Name})
+
+	web := byName["shop/web"]
+	assert.Equal(t, []string{"shop-web-1", "shop-web-2"}, web.Containers)
+	assert.Equal(t, 4, web.Observed.Samples)
+	assert.InDelta(t, 0.3, web.Observed.CPUPeak, 1e-9)
+	assert.InDelta(t, 0.15, web.Observed.CPUAvg, 1e-9)
+	assert.Equal(t, []Finding{{FindingNoLimits, "no CPU or memory limit"}}, web.Findings)
+	require.NotNil(t, web.Suggested)
+	assert.Equal(t, Limits{CPUs: 0.45, Memory: 160 * mib}, *web.Suggested)
+
+	assert.Equal(t, FindingNearLimit, byName["shop/db"].Findings[0].Kind)
+	assert.Equal(t, "memory peaked at 98% of the 1.0 GiB limit", byName["shop/db"].Findings[0].Message)
+
+	cache := byName["cache"]
+	require.Len(t, cache.Findings, 2)
+	assert.Equal(t, "memory limit 2.0 GiB is 20× the 100 MiB peak", cache.Findings[0].Message)
+	assert.Equal(t, FindingOversized, cache.Findings[1].Kind)
+	assert.Equal(t, "docker update --cpus 0.3 --memory 160m cache", cache.UpdateCommand())
+
+	assert.Empty(t, byName["ok"].Findings)
+	assert.Nil(t, byName["ok"].Suggested)
+	assert.Empty(t, byName["new"].Findings, "no samples, no verdict")
+
+	assert.Equal(t, `# shop
+services:
+  db:
+    deploy:
+      resources:
+        limits:
+          cpus: "0.75"
+          memory: 1504M
+  web:
+    deploy:
+      resources:
+        limits:
+          cpus: "0.45"
+          memory: 160M
+`, ComposeSnippet(reports))
+}
+
+func TestAnalyzeSingleSample(t *testing.T) {
+	// The first sample has raw counters but nothing to diff them against.
+	var s Sampler
+	s.Add(docker.ContainerMetrics{CPUPercent: 80, CPUTotalUsage: 1000, SystemCPUUsage: 10000, OnlineCPUs: 4, MemoryUsage: 100 * mib})
+	o := s.Observed()
+	assert.Equal(t, 1, o.Samples)
+	assert.Zero(t, o.CPUSamples)
+
+	reports := Analyze([]Target{{ID: "api", Name: "api", Limits: Limits{CPUs: 2, Memory: 1024 * mib}}}, map[string]Observed{"api": o})
+	require.Len(t, reports, 1)
+	r := reports[0]
+	require.Len(t, r.Findings, 1, "no CPU finding without a CPU measurement")
+	assert.Equal(t, "memory limit 1.0 GiB is 10× the 100 MiB peak", r.Findings[0].Message)
+	require.NotNil(t, r.Suggested)
+	assert.Equal(t, Limits{Memory: 160 * mib}, *r.Suggested)
+	assert.Equal(t, "docker update --memory 160m api", r.UpdateCommand())
+}
+
+func TestFormatMemory(t *testing.T) {
+	assert.Equal(t, "512m", FormatMemory(512*mib))
+	assert.Equal(t, "2g", FormatMemory(2048*mib))
+	assert.Equal(t, "1m", FormatMemory(1000))
+}
+
+func TestSample(t *testing.T) {
+	var calls int
+	mock := &docker.MockDockerService{
+		GetContainerStatsFn: func(_ context.Context, id string) (*docker.ContainerMetrics, error) {
+			calls++
+			return &docker.ContainerMetrics{ContainerID: id, CPUPercent: 50, MemoryUsage: uint64(calls) * mib}, nil
+		},
+	}
+	observed := Sample(context.Background(), mock, []Target{{ID: "a"}}, 50*time.Millisecond, 10*time.Millisecond)
+	o := observed["a"]
+	assert.GreaterOrEqual(t, o.Samples, 2)
+	assert.Equal(t, uint64(o.Samples)*mib, o.MemoryPeak)
+	assert.InDelta(t, 0.5, o.CPUPeak, 1e-9)
+}
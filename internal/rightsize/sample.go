@@ -0,0 +1,164 @@
+package rightsize
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// Observed summarizes the samples of a container. CPU is in cores (1.5 is
+// one and a half CPUs busy). CPUSamples counts the samples CPU use could be
+// computed from; with none, CPUPeak and CPUAvg are unknown rather than 0.
+type Observed struct {
+	Samples    int     `json:"samples" yaml:"samples"`
+	CPUSamples int     `json:"cpu_samples" yaml:"cpu_samples"`
+	CPUPeak    float64 `json:"cpu_peak" yaml:"cpu_peak"`
+	CPUAvg     float64 `json:"cpu_avg" yaml:"cpu_avg"`
+	MemoryPeak uint64  `json:"memory_peak_bytes" yaml:"memory_peak_bytes"`
+	MemoryAvg  uint64  `json:"memory_avg_bytes" yaml:"memory_avg_bytes"`
+}
+
+// merge combines the observations of two replicas: the higher peaks and
+// the averages weighted by samples.
+func (o Observed) merge(other Observed) Observed {
+	if o.Samples == 0 {
+		return other
+	}
+	if other.Samples == 0 {
+		return o
+	}
+	weighted := func(a, b float64, na, nb int) float64 {
+		if na+nb == 0 {
+			return 0
+		}
+		return (a*float64(na) + b*float64(nb)) / float64(na+nb)
+	}
+	return Observed{
+		Samples:    o.Samples + other.Samples,
+		CPUSamples: o.CPUSamples + other.CPUSamples,
+		CPUPeak:    max(o.CPUPeak, other.CPUPeak),
+		CPUAvg:     weighted(o.CPUAvg, other.CPUAvg, o.CPUSamples, other.CPUSamples),
+		MemoryPeak: max(o.MemoryPeak, other.MemoryPeak),
+		MemoryAvg:  uint64(weighted(float64(o.MemoryAvg), float64(other.MemoryAvg), o.Samples, other.Samples)),
+	}
+}
+
+// Sampler accumulates the metrics of one container.
+type Sampler struct {
+	obs    Observed
+	cpuSum float64
+	memSum float64
+	prev   *docker.ContainerMetrics
+}
+
+// Add records a sample. CPU use is computed from the raw counters of
+// consecutive samples; a one-shot CPUPercent only averages since the
+// container started. Without raw counters CPUPercent is used as is.
+func (s *Sampler) Add(m docker.ContainerMetrics) {
+	s.obs.Samples++
+	s.memSum += float64(m.MemoryUsage)
+	s.obs.MemoryPeak = max(s.obs.MemoryPeak, m.MemoryUsage)
+
+	cores, ok := m.CPUPercent/100, m.CPUTotalUsage == 0
+	if p := s.prev; p != nil && m.CPUTotalUsage >= p.CPUTotalUsage && m.SystemCPUUsage > p.SystemCPUUsage && m.OnlineCPUs > 0 {
+		cores = float64(m.CPUTotalUsage-p.CPUTotalUsage) / float64(m.SystemCPUUsage-p.SystemCPUUsage) * float64(m.OnlineCPUs)
+		ok = true
+	}
+	s.prev = &m
+	if ok {
+		s.obs.CPUSamples++
+		s.cpuSum += cores
+		s.obs.CPUPeak = max(s.obs.CPUPeak, cores)
+	}
+}
+
+// Observed returns the summary of the samples so far.
+func (s *Sampler) Observed() Observed {
+	o := s.obs
+	if o.Samples > 0 {
+		o.MemoryAvg = uint64(s.memSum / float64(o.Samples))
+	}
+	if o.CPUSamples > 0 {
+		o.CPUAvg = s.cpuSum / float64(o.CPUSamples)
+	}
+	return o
+}
+
+// Targets returns the running containers with their configured limits.
+// Containers removed meanwhile are skipped.
+func Targets(ctx context.Context, svc docker.DockerService) ([]Target, error) {
+	listCtx, cancel := context.WithTimeout(ctx, docker.TimeoutList)
+	containers, err := svc.ListContainers(listCtx, false)
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("listing containers: %w", err)
+	}
+	var targets []Target
+	for _, c := range containers {
+		if c.State != "running" {
+			continue
+		}
+		inspectCtx, cancel := context.WithTimeout(ctx, docker.TimeoutList)
+		details, err := svc.InspectContainer(inspectCtx, c.ID)
+		cancel()
+		if errdefs.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("inspecting %s: %w", c.Name, err)
+		}
+		targets = append(targets, Target{
+			ID:      c.ID,
+			Name:    c.Name,
+			Project: c.Labels[docker.ComposeProjectLabel],
+			Service: c.Labels[docker.ComposeServiceLabel],
+			Limits:  LimitsFrom(details),
+		})
+	}
+	return targets, nil
+}
+
+// Sample polls the stats of every target each interval until the window
+// ends or ctx is cancelled, and returns the observations by container ID.
+// A container that stops meanwhile keeps the samples taken so far.
+func Sample(ctx context.Context, svc docker.DockerService, targets []Target, window, interval time.Duration) map[string]Observed {
+	ctx, cancel := context.WithTimeout(ctx, window)
+	defer cancel()
+
+	samplers := make([]Sampler, len(targets))
+	poll := func() {
+		var wg sync.WaitGroup
+		for i, t := range targets {
+			wg.Add(1)
+			go func(s *Sampler, id string) {
+				defer wg.Done()
+				statsCtx, cancel := context.WithTimeout(ctx, docker.TimeoutStats)
+				defer cancel()
+				if m, err := svc.GetContainerStats(statsCtx, id); err == nil && m != nil {
+					s.Add(*m)
+				}
+			}(&samplers[i], t.ID)
+		}
+		wg.Wait()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for poll(); ; {
+		select {
+		case <-ctx.Done():
+			observed := make(map[string]Observed, len(targets))
+			for i, t := range targets {
+				observed[t.ID] = samplers[i].Observed()
+			}
+			return observed
+		case <-ticker.C:
+			poll()
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package analyze
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/tui/common"
+	"github.com/bsisduck/octo/internal/tui/palette"
+)
+
+// listActions are the actions of the resource list, in the order a key
+// bound to several of them is tried: restart goes before refresh, so "r"
+// restarts the selected container and refreshes elsewhere.
+var listActions = []common.Action{
+	common.ActQuit, common.ActCancel, common.ActHelp, common.ActPalette,
+	common.ActUp, common.ActDown, common.ActOpen, common.ActBack,
+	common.ActFilter, common.ActLogs, common.ActRelations, common.ActMark, common.ActMarkAll,
+	common.ActSort, common.ActSortReverse, common.ActColumns,
+	common.ActDelete, common.ActStart, common.ActStop, common.ActRestart,
+	common.ActCopy, common.ActCopyID, common.ActShell, common.ActRefresh,
+}
+
+// helpSections group the list actions in the help overlay.
+var helpSections = []common.HelpSection{
+	{Title: "Navigation", Actions: []common.Action{common.ActUp, common.ActDown, common.ActOpen, common.ActBack, common.ActFilter}},
+	{Title: "Containers", Actions: []common.Action{common.ActStart, common.ActStop, common.ActRestart, common.ActLogs, common.ActShell}},
+	{Title: "Resources", Actions: []common.Action{common.ActDelete, common.ActRelations, common.ActCopy, common.ActCopyID, common.ActMark, common.ActMarkAll}},
+	{Title: "View", Actions: []common.Action{common.ActSort, common.ActSortReverse, common.ActColumns, common.ActRefresh}},
+	{Title: "General", Actions: []common.Action{common.ActPalette, common.ActHelp, common.ActQuit, common.ActCancel}},
+}
+
+// do runs an action of the resource list. It reports false when the action
+// does not apply, so that another action bound to the same key can run.
+func (m Model) do(a common.Action) (Model, tea.Cmd, bool) {
+	switch a {
+	case common.ActQuit:
+		return m, tea.Quit, true
+	case common.ActCancel:
+		if m.bulkResults != nil {
+			m.bulkResults = nil
+			return m, nil, true
+		}
+		if len(m.marked) > 0 {
+			m.marked = nil
+			return m, nil, true
+		}
+		if m.filterText != "" {
+			m.filterText = ""
+			m.filteredEntries = nil
+			m.selected = 0
+			m.offset = 0
+			return m, nil, true
+		}
+		if m.filterType != ResourceAll && !m.fixedType {
+			m.filterType = ResourceAll
+			m.loading = true
+			return m, m.fetchResources(), true
+		}
+		return m, tea.Quit, true
+	case common.ActHelp:
+		m.showHelp = true
+		return m, nil, true
+	case common.ActPalette:
+		p := palette.New(m.Commands())
+		m.palette = &p
+		return m, nil, true
+	case common.ActFilter:
+		m.filtering = true
+		m.filterText = ""
+		return m, nil, true
+	case common.ActLogs:
+		if m.canOperateOnSelected() && m.selectedEntry().Type == ResourceContainers {
+			entry := m.selectedEntry()
+			m.viewMode = viewLogs
+			m.logContainer = entry.Name
+			m.logContainerID = entry.ID
+			m.logEntries = nil
+			m.logOffset = 0
+			m.logFollowing = false
+			m.logFilterText = ""
+			m.logFiltering = false
+			return m, m.fetchLogs(entry.ID, 200), true
+		}
+	case common.ActRelations:
+		if m.canOperateOnSelected() && graphID(m.selectedEntry()) != "" {
+			return m, m.openRelations(m.selectedEntry()), true
+		}
+	case common.ActUp:
+		m.moveSelection(-1)
+		return m, nil, true
+	case common.ActDown:
+		m.moveSelection(1)
+		return m, nil, true
+	case common.ActOpen:
+		visible := m.visibleEntries()
+		if m.selected < len(visible) {
+			entry := visible[m.selected]
+			if entry.IsProjectHeader {
+				m.toggleProject(entry.ProjectName)
+				return m, nil, true
+			}
+			if entry.Type == ResourceNetworks && entry.Selectable && !entry.IsCategory {
+				return m, m.openNetwork(entry), true
+			}
+			if entry.IsCategory {
+				m.filterType = entry.Type
+				m.filterText = ""
+				m.filteredEntries = nil
+				m.loading = true
+				return m, m.fetchResources(), true
+			}
+		}
+	case common.ActBack:
+		if m.filterType != ResourceAll && !m.fixedType {
+			m.filterType = ResourceAll
+			m.loading = true
+			return m, m.fetchResources(), true
+		}
+	case common.ActMark:
+		m.toggleMark()
+		m.moveSelection(1)
+		return m, nil, true
+	case common.ActMarkAll:
+		m.toggleMarkAll()
+		return m, nil, true
+	case common.ActSort:
+		return m, m.cycleSort(), true
+	case common.ActSortReverse:
+		return m, m.reverseSort(), true
+	case common.ActColumns:
+		m.pickingColumns = true
+		m.columnCursor = 0
+		return m, nil, true
+	case common.ActDelete:
+		if len(m.markedEntries()) > 0 {
+			return m, m.planBulk(BulkDelete), true
+		}
+		visible := m.visibleEntries()
+		if m.selected < len(visible) {
+			entry := visible[m.selected]
+			if entry.Selectable && !entry.IsCategory {
+				m.deleteTarget = &entry
+				// Phase 1: Call DryRun to get confirmation info (and re-check state)
+				return m, m.reCheckAndShowConfirmation(), true
+			}
+		}
+	case common.ActStart:
+		if len(m.markedEntries()) > 0 {
+			return m, m.planBulk(BulkStart), true
+		}
+		if m.canOperateOnSelected() {
+			entry := m.selectedEntry()
+			if entry.IsProjectHeader {
+				return m, m.startComposeProject(entry.ProjectName), true
+			} else if entry.Type == ResourceContainers {
+				return m, m.startSelectedContainer(), true
+			}
+		}
+	case common.ActStop:
+		if len(m.markedEntries()) > 0 {
+			return m, m.planBulk(BulkStop), true
+		}
+		if m.canOperateOnSelected() {
+			entry := m.selectedEntry()
+			if entry.IsProjectHeader {
+				return m, m.stopComposeProject(entry.ProjectName), true
+			} else if entry.Type == ResourceContainers {
+				return m, m.stopSelectedContainer(), true
+			}
+		}
+	case common.ActRestart:
+		if len(m.markedEntries()) > 0 {
+			return m, m.planBulk(BulkRestart), true
+		}
+		if m.canOperateOnSelected() {
+			entry := m.selectedEntry()
+			if entry.IsProjectHeader {
+				return m, m.restartComposeProject(entry.ProjectName), true
+			} else if entry.Type == ResourceContainers {
+				return m, m.restartSelectedContainer(), true
+			}
+		}
+	case common.ActRefresh:
+		m.loading = true
+		return m, m.fetchResources(), true
+	case common.ActCopy:
+		if m.canOperateOnSelected() {
+			return m, copyText(m.selectedEntry().ClipboardText()), true
+		}
+	case common.ActCopyID:
+		if m.canOperateOnSelected() && m.selectedEntry().ID != "" {
+			return m, copyText(m.selectedEntry().ID), true
+		}
+	case common.ActShell:
+		if m.canOperateOnSelected() && m.selectedEntry().Type == ResourceContainers {
+			if m.canExecOnSelected() {
+				entry := m.selectedEntry()
+				api := m.docker.API()
+				cmd := docker.NewDockerExecCommand(api, entry.ID, "/bin/sh")
+				return m, tea.Exec(cmd, func(err error) tea.Msg {
+					return common.ExecFinishedMsg{Err: err}
+				}), true
+			}
+			m.statusMessage = "Cannot exec: container is not running"
+			return m, tea.Tick(3*time.Second, func(t time.Time) tea.Msg {
+				return common.ClearStatusMsg{}
+			}), true
+		}
+	}
+	return m, nil, false
+}
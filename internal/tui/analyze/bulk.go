@@ -0,0 +1,312 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/ui/format"
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+// BulkAction is an action applied to every marked entry.
+type BulkAction int
+
+const (
+	BulkDelete BulkAction = iota
+	BulkStart
+	BulkStop
+	BulkRestart
+)
+
+func (a BulkAction) String() string {
+	switch a {
+	case BulkStart:
+		return "start"
+	case BulkStop:
+		return "stop"
+	case BulkRestart:
+		return "restart"
+	default:
+		return "delete"
+	}
+}
+
+// bulkItem is a marked entry with the tier its dry run reported, compared
+// again before the action runs.
+type bulkItem struct {
+	Entry ResourceEntry
+	Tier  docker.SafetyTier
+}
+
+// BulkResult is the outcome of a bulk action on one entry. Skipped explains
+// why the action was not attempted.
+type BulkResult struct {
+	Entry   ResourceEntry
+	Err     error
+	Skipped string
+}
+
+// BulkConfirmationMsg carries the aggregated dry run of a bulk action.
+type BulkConfirmationMsg struct {
+	Action  BulkAction
+	Items   []bulkItem
+	Info    docker.ConfirmationInfo
+	Skipped []BulkResult
+}
+
+// BulkDoneMsg carries the per-entry results of a bulk action and the
+// refreshed resources.
+type BulkDoneMsg struct {
+	Action  BulkAction
+	Results []BulkResult
+	Data    DataMsg
+}
+
+// entryKey identifies an entry across refreshes.
+func entryKey(e ResourceEntry) string {
+	return fmt.Sprintf("%d/%s", e.Type, e.ID)
+}
+
+// markable reports whether an entry can be part of a bulk selection.
+func markable(e ResourceEntry) bool {
+	return e.Selectable && !e.IsCategory && !e.IsProjectHeader && e.ID != ""
+}
+
+// toggleMark marks or unmarks the selected entry.
+func (m *Model) toggleMark() {
+	visible := m.visibleEntries()
+	if m.selected >= len(visible) || !markable(visible[m.selected]) {
+		return
+	}
+	key := entryKey(visible[m.selected])
+	if m.marked[key] {
+		delete(m.marked, key)
+		return
+	}
+	if m.marked == nil {
+		m.marked = make(map[string]bool)
+	}
+	m.marked[key] = true
+}
+
+// toggleMarkAll marks every visible markable entry, or unmarks them all when
+// they already are. Entries hidden by the filter are left alone.
+func (m *Model) toggleMarkAll() {
+	var keys []string
+	all := true
+	for _, e := range m.visibleEntries() {
+		if markable(e) {
+			keys = append(keys, entryKey(e))
+			all = all && m.marked[entryKey(e)]
+		}
+	}
+	if m.marked == nil {
+		m.marked = make(map[string]bool)
+	}
+	for _, k := range keys {
+		if all {
+			delete(m.marked, k)
+		} else {
+			m.marked[k] = true
+		}
+	}
+}
+
+// markedEntries returns the marked entries in list order, including those
+// hidden by the filter.
+func (m Model) markedEntries() []ResourceEntry {
+	var out []ResourceEntry
+	for _, e := range m.entries {
+		if markable(e) && m.marked[entryKey(e)] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// pruneMarks drops marks of entries that no longer exist.
+func (m *Model) pruneMarks() {
+	present := make(map[string]bool, len(m.entries))
+	for _, e := range m.entries {
+		present[entryKey(e)] = true
+	}
+	for k := range m.marked {
+		if !present[k] {
+			delete(m.marked, k)
+		}
+	}
+}
+
+// planBulk is Phase 1 of a bulk action: it runs the dry run of every marked
+// entry and aggregates them into one confirmation.
+func (m Model) planBulk(action BulkAction) tea.Cmd {
+	entries := m.markedEntries()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), docker.TimeoutList)
+		defer cancel()
+
+		msg := BulkConfirmationMsg{Action: action}
+		var infos []docker.ConfirmationInfo
+		for _, e := range entries {
+			if action != BulkDelete && e.Type != ResourceContainers {
+				msg.Skipped = append(msg.Skipped, BulkResult{Entry: e, Skipped: "not a container"})
+				continue
+			}
+			info, err := m.bulkDryRun(ctx, action, e)
+			if err != nil {
+				msg.Skipped = append(msg.Skipped, BulkResult{Entry: e, Skipped: err.Error()})
+				continue
+			}
+			msg.Items = append(msg.Items, bulkItem{Entry: e, Tier: info.Tier})
+			infos = append(infos, info)
+		}
+		msg.Info = aggregateConfirmation(action, msg.Items, infos, msg.Skipped)
+		return msg
+	}
+}
+
+// bulkDryRun describes the action on one entry. Start, stop and restart
+// have no dry run in the Docker service; stopping or restarting a running
+// container is moderate risk.
+func (m Model) bulkDryRun(ctx context.Context, action BulkAction, e ResourceEntry) (docker.ConfirmationInfo, error) {
+	if action == BulkDelete {
+		return m.removeDryRun(ctx, e)
+	}
+	info := docker.ConfirmationInfo{Tier: docker.TierLowRisk, Reversible: true}
+	if action != BulkStart && !e.IsUnused {
+		info.Tier = docker.TierModerate
+		info.Warnings = []string{"Running services are interrupted"}
+	}
+	return info, nil
+}
+
+// aggregateConfirmation combines the dry runs of a bulk action: the highest
+// tier wins, sizes are summed and warnings are merged.
+func aggregateConfirmation(action BulkAction, items []bulkItem, infos []docker.ConfirmationInfo, skipped []BulkResult) docker.ConfirmationInfo {
+	agg := docker.ConfirmationInfo{Tier: docker.TierInformational, Reversible: true}
+	var size int64
+	seen := make(map[string]bool)
+	for i, item := range items {
+		info := infos[i]
+		agg.Tier = max(agg.Tier, info.Tier)
+		agg.Reversible = agg.Reversible && info.Reversible
+		size += item.Entry.Size
+		resource := fmt.Sprintf("%s: %s", strings.ToLower(strings.TrimSuffix(item.Entry.Type.String(), "s")), item.Entry.Name)
+		if item.Entry.Size > 0 {
+			resource += fmt.Sprintf(" (%s)", format.Size(uint64(item.Entry.Size)))
+		}
+		agg.Resources = append(agg.Resources, resource)
+		for _, w := range info.Warnings {
+			if !seen[w] {
+				seen[w] = true
+				agg.Warnings = append(agg.Warnings, w)
+			}
+		}
+	}
+	for _, s := range skipped {
+		agg.Warnings = append(agg.Warnings, fmt.Sprintf("Skipping %s: %s", s.Entry.Name, s.Skipped))
+	}
+
+	verb := strings.ToUpper(action.String()[:1]) + action.String()[1:]
+	noun := "Resources"
+	if action != BulkDelete {
+		noun = "Containers"
+	}
+	agg.Title = fmt.Sprintf("%s %d %s?", verb, len(items), noun)
+	agg.Description = fmt.Sprintf("%s %d marked %s", verb, len(items), strings.ToLower(noun))
+	if size > 0 {
+		agg.Description += fmt.Sprintf(", %s in total", format.Size(uint64(size)))
+	}
+	switch {
+	case action != BulkDelete:
+		agg.UndoInstructions = "Containers keep their configuration and data"
+	case agg.Reversible:
+		agg.UndoInstructions = "All marked resources can be recreated"
+	default:
+		agg.UndoInstructions = "Some marked resources cannot be recovered once deleted"
+	}
+	return agg
+}
+
+// executeBulk is Phase 2 of a bulk action. Each entry is checked again and
+// skipped when its tier changed since the confirmation; failures do not stop
+// the remaining entries.
+func (m Model) executeBulk(plan BulkConfirmationMsg) tea.Cmd {
+	return func() tea.Msg {
+		results := append([]BulkResult(nil), plan.Skipped...)
+		for _, item := range plan.Items {
+			results = append(results, m.applyBulk(plan.Action, item))
+		}
+		data, _ := m.fetchResources()().(DataMsg)
+		return BulkDoneMsg{Action: plan.Action, Results: results, Data: data}
+	}
+}
+
+func (m Model) applyBulk(action BulkAction, item bulkItem) BulkResult {
+	e := item.Entry
+	switch action {
+	case BulkStart, BulkStop, BulkRestart:
+		ctx, cancel := context.WithTimeout(context.Background(), docker.TimeoutAction)
+		defer cancel()
+		var err error
+		switch action {
+		case BulkStart:
+			err = m.docker.StartContainer(ctx, e.ID)
+		case BulkStop:
+			err = m.docker.StopContainer(ctx, e.ID)
+		default:
+			err = m.docker.RestartContainer(ctx, e.ID)
+		}
+		return BulkResult{Entry: e, Err: err}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), docker.TimeoutRemove)
+	defer cancel()
+	current, err := m.removeDryRun(ctx, e)
+	if err != nil {
+		return BulkResult{Entry: e, Skipped: fmt.Sprintf("state changed: %v", err)}
+	}
+	if current.Tier != item.Tier {
+		return BulkResult{Entry: e, Skipped: fmt.Sprintf("state changed from %s to %s", item.Tier, current.Tier)}
+	}
+	return BulkResult{Entry: e, Err: m.remove(ctx, e)}
+}
+
+// updateBulkConfirm handles keys while a bulk confirmation is shown.
+func (m Model) updateBulkConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y", "enter":
+		plan := *m.bulkPlan
+		m.bulkPlan = nil
+		return m, m.executeBulk(plan)
+	case "n", "N", "esc", "q":
+		m.bulkPlan = nil
+	}
+	return m, nil
+}
+
+// renderBulkResults renders the per-entry report of the last bulk action.
+func (m Model) renderBulkResults() string {
+	var b strings.Builder
+	var ok, failed, skipped int
+	for _, r := range m.bulkResults {
+		switch {
+		case r.Skipped != "":
+			skipped++
+			b.WriteString(styles.Help.Render(fmt.Sprintf("  – %s: skipped (%s)", r.Entry.Name, r.Skipped)))
+		case r.Err != nil:
+			failed++
+			b.WriteString(styles.Error.Render(fmt.Sprintf("  ✗ %s: %v", r.Entry.Name, r.Err)))
+		default:
+			ok++
+			b.WriteString(styles.Success.Render(fmt.Sprintf("  ✓ %s", r.Entry.Name)))
+		}
+		b.WriteString("\n")
+	}
+	summary := fmt.Sprintf("Bulk %s: %d done, %d failed, %d skipped", m.bulkAction, ok, failed, skipped)
+	return styles.Section.Render(summary) + "\n" + b.String()
+}
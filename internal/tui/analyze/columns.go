@@ -0,0 +1,374 @@
+package analyze
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+
+	"github.com/bsisduck/octo/internal/config"
+	"github.com/bsisduck/octo/internal/tui/common"
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+// SortKey is the field entries are sorted by within their group.
+type SortKey int
+
+const (
+	SortDefault SortKey = iota // Compose grouping, images by size
+	SortName
+	SortSize
+	SortCreated
+	SortCPU
+	SortMemory
+	SortStatus
+)
+
+var sortKeyNames = []string{"default", "name", "size", "created", "cpu", "memory", "status"}
+
+func (k SortKey) String() string {
+	if int(k) < len(sortKeyNames) {
+		return sortKeyNames[k]
+	}
+	return sortKeyNames[SortDefault]
+}
+
+// parseSortKey returns the sort key named s, or SortDefault.
+func parseSortKey(s string) SortKey {
+	for i, name := range sortKeyNames {
+		if strings.EqualFold(s, name) {
+			return SortKey(i)
+		}
+	}
+	return SortDefault
+}
+
+// Column is an optional field shown next to an entry's name and size.
+type Column string
+
+const (
+	ColumnID      Column = "id"
+	ColumnCreated Column = "created"
+	ColumnPorts   Column = "ports"
+	ColumnImage   Column = "image"
+	ColumnService Column = "service"
+)
+
+// allColumns lists the optional columns in display order.
+var allColumns = []Column{ColumnService, ColumnID, ColumnImage, ColumnPorts, ColumnCreated}
+
+// defaultColumns are shown when the config does not list any.
+var defaultColumns = []Column{ColumnService}
+
+// columnSet returns the columns named in names, ignoring unknown ones. Nil
+// names select the defaults.
+func columnSet(names []string) map[Column]bool {
+	set := make(map[Column]bool)
+	if names == nil {
+		for _, c := range defaultColumns {
+			set[c] = true
+		}
+		return set
+	}
+	for _, n := range names {
+		for _, c := range allColumns {
+			if strings.EqualFold(n, string(c)) {
+				set[c] = true
+			}
+		}
+	}
+	return set
+}
+
+// sortEntries returns entries sorted by key. Category headers and Compose
+// project headers stay in place: only the entries between them, of the same
+// type and project, are reordered. Names sort ascending, status puts running
+// entries first, and the other keys put the largest or newest first; reverse
+// flips the order. SortDefault keeps the fetched order.
+func sortEntries(entries []ResourceEntry, key SortKey, reverse bool) []ResourceEntry {
+	sorted := append([]ResourceEntry(nil), entries...)
+	if key == SortDefault {
+		return sorted
+	}
+	less := func(a, b ResourceEntry) bool {
+		switch key {
+		case SortName:
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		case SortSize:
+			return a.Size > b.Size
+		case SortCreated:
+			return a.Created.After(b.Created)
+		case SortCPU:
+			return a.CPUPercent > b.CPUPercent
+		case SortMemory:
+			return a.MemUsage > b.MemUsage
+		default:
+			if a.IsUnused != b.IsUnused {
+				return !a.IsUnused
+			}
+			return a.Status < b.Status
+		}
+	}
+	group := func(e ResourceEntry) string {
+		if e.inProject() {
+			return fmt.Sprintf("%d/%s", e.Type, e.ComposeProject)
+		}
+		return fmt.Sprintf("%d", e.Type)
+	}
+
+	for start := 0; start < len(sorted); {
+		if sorted[start].IsCategory || sorted[start].IsProjectHeader {
+			start++
+			continue
+		}
+		end := start + 1
+		for end < len(sorted) && !sorted[end].IsCategory && !sorted[end].IsProjectHeader &&
+			group(sorted[end]) == group(sorted[start]) {
+			end++
+		}
+		run := sorted[start:end]
+		sort.SliceStable(run, func(i, j int) bool {
+			if reverse {
+				return less(run[j], run[i])
+			}
+			return less(run[i], run[j])
+		})
+		start = end
+	}
+	return sorted
+}
+
+// resort applies the sort order to the fetched entries and the filter,
+// keeping the selected entry selected.
+func (m *Model) resort() {
+	selected := m.selectedEntry()
+	m.entries = sortEntries(m.source, m.sortKey, m.sortReverse)
+	if m.filterText != "" {
+		m.applyFilter()
+	}
+	for i, e := range m.visibleEntries() {
+		if entryKey(e) == entryKey(selected) && e.Name == selected.Name {
+			m.selected = i
+			m.moveSelection(0)
+			break
+		}
+	}
+}
+
+// cycleSort switches to the next sort key.
+func (m *Model) cycleSort() tea.Cmd {
+	m.sortKey = (m.sortKey + 1) % SortKey(len(sortKeyNames))
+	m.resort()
+	return m.saveView()
+}
+
+// reverseSort flips the sort direction.
+func (m *Model) reverseSort() tea.Cmd {
+	m.sortReverse = !m.sortReverse
+	m.resort()
+	return m.saveView()
+}
+
+// viewSavedMsg reports the result of persisting the view settings.
+type viewSavedMsg struct {
+	Err error
+}
+
+// viewConfig returns the sort order and columns as saved in the config.
+func (m Model) viewConfig() config.AnalyzeConfig {
+	cfg := config.AnalyzeConfig{Columns: []string{}}
+	if m.sortKey != SortDefault {
+		cfg.Sort = m.sortKey.String()
+	}
+	cfg.SortReverse = m.sortReverse
+	for _, c := range allColumns {
+		if m.columns[c] {
+			cfg.Columns = append(cfg.Columns, string(c))
+		}
+	}
+	return cfg
+}
+
+// saveView persists the view settings when the model was given a way to.
+func (m Model) saveView() tea.Cmd {
+	if m.persist == nil {
+		return nil
+	}
+	persist, cfg := m.persist, m.viewConfig()
+	return func() tea.Msg {
+		return viewSavedMsg{Err: persist(cfg)}
+	}
+}
+
+// sortHeadings are the clickable headings above the list: name and size
+// line up with their columns, the other sort keys follow.
+var sortHeadings = []struct {
+	key   SortKey
+	label string
+	width int // left-aligned, or right-aligned if negative; 0 for none
+}{
+	{SortName, "NAME", 32},
+	{SortSize, "SIZE", -10},
+	{SortCreated, "CREATED", 0},
+	{SortStatus, "STATUS", 0},
+	{SortCPU, "CPU", 0},
+	{SortMemory, "MEMORY", 0},
+}
+
+// headingCell is a sort heading and the screen columns it covers.
+type headingCell struct {
+	key        SortKey
+	text       string
+	start, end int
+}
+
+// headingCells lays out the sort headings. They are indented like the
+// entries, which are padded by one and, when marks are shown, by the box.
+func (m Model) headingCells() []headingCell {
+	x := 1
+	if len(m.marked) > 0 {
+		x += 4
+	}
+	cells := make([]headingCell, 0, len(sortHeadings))
+	for i, h := range sortHeadings {
+		label := h.label
+		if h.key == m.sortKey {
+			if m.sortReverse {
+				label += "↑"
+			} else {
+				label += "↓"
+			}
+		}
+		switch {
+		case h.width > 0:
+			label = fmt.Sprintf("%-*s", h.width, label)
+		case h.width < 0:
+			label = strings.Repeat(" ", max(0, -h.width-lipgloss.Width(label))) + label
+		}
+		// One space follows the name column, as in the entries; two
+		// separate the others.
+		if i > 0 {
+			x++
+			if sortHeadings[i-1].width <= 0 {
+				x++
+			}
+		}
+		w := lipgloss.Width(label)
+		cells = append(cells, headingCell{key: h.key, text: label, start: x, end: x + w})
+		x += w
+	}
+	return cells
+}
+
+// renderSortHeader renders the sort headings, the active one highlighted.
+func (m Model) renderSortHeader() string {
+	var b strings.Builder
+	pos := 0
+	for _, c := range m.headingCells() {
+		b.WriteString(strings.Repeat(" ", c.start-pos))
+		if c.key == m.sortKey {
+			b.WriteString(styles.Title.Render(c.text))
+		} else {
+			b.WriteString(styles.Label.Render(c.text))
+		}
+		pos = c.end
+	}
+	return b.String()
+}
+
+// clickHeading sorts by the heading at screen column x; clicking the
+// active heading reverses the order.
+func (m *Model) clickHeading(x int) tea.Cmd {
+	for _, c := range m.headingCells() {
+		if x < c.start || x >= c.end {
+			continue
+		}
+		if c.key == m.sortKey {
+			return m.reverseSort()
+		}
+		m.sortKey = c.key
+		m.sortReverse = false
+		m.resort()
+		return m.saveView()
+	}
+	return nil
+}
+
+// updateColumnPicker handles keys while the column picker is open.
+func (m Model) updateColumnPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := common.Keys
+	switch {
+	case keys.Is(msg, common.ActUp):
+		if m.columnCursor > 0 {
+			m.columnCursor--
+		}
+	case keys.Is(msg, common.ActDown):
+		if m.columnCursor < len(allColumns)-1 {
+			m.columnCursor++
+		}
+	case keys.Is(msg, common.ActMark), keys.Is(msg, common.ActChoose):
+		if m.columns == nil {
+			m.columns = make(map[Column]bool)
+		}
+		c := allColumns[m.columnCursor]
+		m.columns[c] = !m.columns[c]
+	case keys.Is(msg, common.ActCancel), keys.Is(msg, common.ActColumns), keys.Is(msg, common.ActQuit):
+		m.pickingColumns = false
+		return m, m.saveView()
+	}
+	return m, nil
+}
+
+// renderColumnPicker renders the list of optional columns.
+func (m Model) renderColumnPicker() string {
+	var b strings.Builder
+	b.WriteString(styles.Section.Render("Columns"))
+	b.WriteString("\n")
+	for i, c := range allColumns {
+		box := "[ ]"
+		if m.columns[c] {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("  %s %s", box, c)
+		if i == m.columnCursor {
+			line = styles.Selected.Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.Help.Render("  " + common.ShortHelp(
+		common.Item("toggle", common.ActMark),
+		common.Item("done", common.ActCancel),
+	)))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// columnCells renders the enabled optional columns of an entry, other than
+// the Compose service shown next to the name.
+func (m Model) columnCells(entry ResourceEntry) string {
+	var cells []string
+	if m.columns[ColumnID] && entry.ID != "" && entry.ID != entry.Name {
+		id := strings.TrimPrefix(entry.ID, "sha256:")
+		if len(id) > 12 {
+			id = id[:12]
+		}
+		cells = append(cells, id)
+	}
+	if m.columns[ColumnImage] && entry.Type == ResourceContainers && entry.Extra != "" {
+		cells = append(cells, entry.Extra)
+	}
+	if m.columns[ColumnPorts] && entry.Ports != "" {
+		cells = append(cells, entry.Ports)
+	}
+	if m.columns[ColumnCreated] && !entry.Created.IsZero() {
+		cells = append(cells, humanize.Time(entry.Created))
+	}
+	if len(cells) == 0 {
+		return ""
+	}
+	return styles.Label.Render("  " + strings.Join(cells, "  "))
+}
@@ -0,0 +1,267 @@
+package analyze
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/ui/format"
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+// projectTree builds the Compose project nodes: each header is followed by
+// the project's containers, then the volumes and networks labelled with its
+// name. The volumes and networks of no listed project are returned for
+// their own categories.
+func (m Model) projectTree(groups []docker.ComposeGroup, volumes []docker.VolumeInfo, networks []docker.NetworkInfo) ([]ResourceEntry, []docker.VolumeInfo, []docker.NetworkInfo) {
+	projects := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		projects[g.ProjectName] = true
+	}
+	ownVolumes := make(map[string][]docker.VolumeInfo)
+	var restVolumes []docker.VolumeInfo
+	for _, v := range volumes {
+		if p := v.Labels[docker.ComposeProjectLabel]; projects[p] {
+			ownVolumes[p] = append(ownVolumes[p], v)
+		} else {
+			restVolumes = append(restVolumes, v)
+		}
+	}
+	ownNetworks := make(map[string][]docker.NetworkInfo)
+	var restNetworks []docker.NetworkInfo
+	for _, n := range networks {
+		if p := n.Labels[docker.ComposeProjectLabel]; projects[p] {
+			ownNetworks[p] = append(ownNetworks[p], n)
+		} else {
+			restNetworks = append(restNetworks, n)
+		}
+	}
+
+	var entries []ResourceEntry
+	for _, g := range groups {
+		running := 0
+		for _, c := range g.Containers {
+			if c.State == "running" {
+				running++
+			}
+		}
+		summary := []string{fmt.Sprintf("%d/%d running", running, len(g.Containers))}
+		if n := len(ownVolumes[g.ProjectName]); n > 0 {
+			summary = append(summary, plural(n, "volume"))
+		}
+		if n := len(ownNetworks[g.ProjectName]); n > 0 {
+			summary = append(summary, plural(n, "network"))
+		}
+		// Project header (selectable for project-level operations)
+		entries = append(entries, ResourceEntry{
+			Type:            ResourceContainers,
+			Name:            g.ProjectName,
+			Extra:           strings.Join(summary, ", "),
+			IsProjectHeader: true,
+			ProjectName:     g.ProjectName,
+			IsUnused:        running == 0,
+			Selectable:      true,
+		})
+
+		for _, c := range g.Containers {
+			if m.showDangling && c.State == "running" {
+				continue
+			}
+			entries = append(entries, containerEntry(c, g.ProjectName))
+		}
+		for _, v := range ownVolumes[g.ProjectName] {
+			if m.showVolume(v) {
+				e := volumeEntry(v)
+				e.ComposeProject = g.ProjectName
+				entries = append(entries, e)
+			}
+		}
+		for _, n := range ownNetworks[g.ProjectName] {
+			if m.showNetwork(n) {
+				e := networkEntry(n)
+				e.ComposeProject = g.ProjectName
+				entries = append(entries, e)
+			}
+		}
+	}
+	return entries, restVolumes, restNetworks
+}
+
+// sumProjects totals the size, CPU and memory of each project's entries on
+// its header, once the container metrics are in.
+func sumProjects(entries []ResourceEntry) {
+	for i := range entries {
+		if !entries[i].IsProjectHeader {
+			continue
+		}
+		h := &entries[i]
+		h.Size, h.CPUPercent, h.MemUsage = 0, 0, 0
+		for _, e := range entries[i+1:] {
+			if !e.inProject() || e.ComposeProject != h.ProjectName {
+				break
+			}
+			h.Size += e.Size
+			h.CPUPercent += e.CPUPercent
+			h.MemUsage += e.MemUsage
+		}
+	}
+}
+
+// containerEntry lists a container, in the Compose project named, if any.
+func containerEntry(c docker.ContainerInfo, project string) ResourceEntry {
+	e := ResourceEntry{
+		Type:       ResourceContainers,
+		ID:         c.ID,
+		Name:       c.Name,
+		Size:       c.Size,
+		Status:     c.Status,
+		Created:    c.Created,
+		Extra:      c.Image,
+		Ports:      c.Ports,
+		IsUnused:   c.State != "running",
+		Selectable: true,
+	}
+	if project != "" {
+		e.ComposeProject = project
+		e.ComposeService = c.Labels[docker.ComposeServiceLabel]
+	}
+	return e
+}
+
+// volumeEntry lists a volume.
+func volumeEntry(v docker.VolumeInfo) ResourceEntry {
+	return ResourceEntry{
+		Type:       ResourceVolumes,
+		ID:         v.Name,
+		Name:       v.Name,
+		Size:       v.Size,
+		Created:    v.Created,
+		Extra:      v.Driver,
+		IsUnused:   !v.InUse,
+		Selectable: true,
+	}
+}
+
+// networkEntry lists a network with its driver and subnets.
+func networkEntry(n docker.NetworkInfo) ResourceEntry {
+	extra := n.Driver
+	if len(n.Subnets) > 0 {
+		extra += " " + strings.Join(n.Subnets, ", ")
+	}
+	return ResourceEntry{
+		Type:       ResourceNetworks,
+		ID:         n.ID,
+		Name:       n.Name,
+		Extra:      fmt.Sprintf("%s (%d containers)", extra, n.Containers),
+		IsUnused:   n.Containers == 0,
+		Selectable: true,
+	}
+}
+
+// showVolume reports whether a volume is listed: all are, or only unused
+// ones in dangling mode.
+func (m Model) showVolume(v docker.VolumeInfo) bool {
+	return !m.showDangling || !v.InUse
+}
+
+// showNetwork reports whether a network is listed. Default networks never
+// are.
+func (m Model) showNetwork(n docker.NetworkInfo) bool {
+	if n.Name == "bridge" || n.Name == "host" || n.Name == "none" {
+		return false
+	}
+	return !m.showDangling || n.Containers == 0
+}
+
+// inProject reports whether e is nested under a Compose project header.
+// Ports name their project too, but are listed by port.
+func (e ResourceEntry) inProject() bool {
+	if e.IsProjectHeader || e.ComposeProject == "" {
+		return false
+	}
+	return e.Type == ResourceContainers || e.Type == ResourceVolumes || e.Type == ResourceNetworks
+}
+
+// folded reports whether e is hidden in a collapsed Compose project.
+func (m *Model) folded(e ResourceEntry) bool {
+	return e.inProject() && m.collapsed[e.ComposeProject]
+}
+
+// toggleProject collapses or expands the Compose project of a header.
+func (m *Model) toggleProject(name string) {
+	if m.collapsed[name] {
+		delete(m.collapsed, name)
+		return
+	}
+	if m.collapsed == nil {
+		m.collapsed = make(map[string]bool)
+	}
+	m.collapsed[name] = true
+}
+
+// foldAllMsg collapses or expands every Compose project.
+type foldAllMsg struct {
+	fold bool
+}
+
+// foldAll collapses or expands every Compose project, keeping the selected
+// entry selected, or its header if it gets hidden.
+func (m *Model) foldAll(fold bool) {
+	selected := m.selectedEntry()
+	m.collapsed = nil
+	if fold {
+		m.collapsed = make(map[string]bool)
+		for _, e := range m.entries {
+			if e.IsProjectHeader {
+				m.collapsed[e.ProjectName] = true
+			}
+		}
+	}
+	ref := entryRef(selected)
+	if m.folded(selected) {
+		ref = "project/" + selected.ComposeProject
+	}
+	if idx := indexOfRef(m.visibleEntries(), ref); idx >= 0 {
+		m.selected = idx
+	}
+	m.moveSelection(0)
+}
+
+// hasProjects reports whether any Compose project is listed.
+func (m Model) hasProjects() bool {
+	for _, e := range m.entries {
+		if e.IsProjectHeader {
+			return true
+		}
+	}
+	return false
+}
+
+// renderProjectHeader renders a project node: its fold state, name, what
+// it holds and the totals of its entries.
+func (m Model) renderProjectHeader(entry ResourceEntry) string {
+	fold := "▾"
+	if m.collapsed[entry.ProjectName] {
+		fold = "▸"
+	}
+	line := styles.Section.Render(fmt.Sprintf("  %s [compose] %s", fold, entry.Name))
+	summary := "  " + entry.Extra
+	if entry.Size > 0 {
+		summary += "  " + format.Size(uint64(entry.Size))
+	}
+	if entry.CPUPercent > 0 || entry.MemUsage > 0 {
+		summary += fmt.Sprintf("  CPU: %.1f%%  MEM: %s", entry.CPUPercent, format.Size(entry.MemUsage))
+	}
+	if entry.IsUnused {
+		return line + styles.Warning.Render(summary)
+	}
+	return line + styles.Label.Render(summary)
+}
+
+// plural formats a count of things, e.g. "2 volumes".
+func plural(n int, thing string) string {
+	if n == 1 {
+		return "1 " + thing
+	}
+	return fmt.Sprintf("%d %ss", n, thing)
+}
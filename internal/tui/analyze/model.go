@@ -11,8 +11,13 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/bsisduck/octo/internal/clipboard"
+	"github.com/bsisduck/octo/internal/config"
 	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/graph"
+	"github.com/bsisduck/octo/internal/logsize"
+	"github.com/bsisduck/octo/internal/ports"
 	"github.com/bsisduck/octo/internal/tui/common"
+	"github.com/bsisduck/octo/internal/tui/palette"
 	"github.com/bsisduck/octo/internal/ui/format"
 	"github.com/bsisduck/octo/internal/ui/styles"
 )
@@ -26,6 +31,8 @@ const (
 	ResourceImages
 	ResourceVolumes
 	ResourceNetworks
+	ResourcePorts
+	ResourceLogs
 )
 
 func (r ResourceType) String() string {
@@ -38,6 +45,10 @@ func (r ResourceType) String() string {
 		return "Volumes"
 	case ResourceNetworks:
 		return "Networks"
+	case ResourcePorts:
+		return "Ports"
+	case ResourceLogs:
+		return "Logs"
 	default:
 		return "Overview"
 	}
@@ -65,6 +76,9 @@ type ResourceEntry struct {
 	MemUsage        uint64
 	MemLimit        uint64
 	MemPercent      float64
+	Conflicts       []string // Port conflicts (ports view)
+	Unrotated       bool     // Log grows without rotation (logs view)
+	Ports           string   // Published ports of a container
 }
 
 // ClipboardText formats a human-readable string for clipboard copy.
@@ -92,6 +106,9 @@ func (e ResourceEntry) ClipboardText() string {
 	if e.ComposeService != "" {
 		parts = append(parts, fmt.Sprintf("Compose Service: %s", e.ComposeService))
 	}
+	for _, c := range e.Conflicts {
+		parts = append(parts, fmt.Sprintf("Conflict: %s", c))
+	}
 	return strings.Join(parts, "\n")
 }
 
@@ -117,7 +134,7 @@ type Model struct {
 	filterText      string
 	filteredEntries []ResourceEntry
 	// Logs view
-	viewMode       int // 0=list, 1=logs
+	viewMode       int // 0=list, 1=logs, 2=relationships, 3=network
 	logEntries     []docker.LogEntry
 	logOffset      int
 	logContainer   string
@@ -126,11 +143,46 @@ type Model struct {
 	logCancelFn    func()
 	logFilterText  string
 	logFiltering   bool
+	// Bulk selection
+	marked      map[string]bool // entryKey of marked entries
+	bulkPlan    *BulkConfirmationMsg
+	bulkAction  BulkAction
+	bulkResults []BulkResult
+	// Sorting and columns
+	source         []ResourceEntry // entries in fetched order
+	sortKey        SortKey
+	sortReverse    bool
+	columns        map[Column]bool
+	pickingColumns bool
+	columnCursor   int
+	persist        func(config.AnalyzeConfig) error
+	fixedType      bool
+	// Command palette, nil when closed
+	palette  *palette.Model
+	showHelp bool
+	// Compose projects whose containers are hidden, by name
+	collapsed map[string]bool
+	// Relationships view; graph is nil while loading
+	graph         *graph.Graph
+	graphRoot     string
+	graphHistory  []string // roots to go back to
+	graphSelected int
+	// Network view; network is nil while loading
+	network         *docker.NetworkDetails
+	networkID       string
+	networkSelected int
+	networkChanged  bool // containers were connected or disconnected
+	networkPending  *networkOp
+	networkConfirm  *docker.ConfirmationInfo
+	connectChoices  []docker.ContainerInfo // nil unless picking one to connect
+	connectCursor   int
 }
 
 const (
-	viewList = 0
-	viewLogs = 1
+	viewList    = 0
+	viewLogs    = 1
+	viewGraph   = 2
+	viewNetwork = 3
 )
 
 // spinnerTick is a message for animating the loading spinner
@@ -169,6 +221,14 @@ type ConfirmationMsg struct {
 type Options struct {
 	TypeFilter string
 	Dangling   bool
+	// View is the saved sort order and columns.
+	View config.AnalyzeConfig
+	// Persist saves the view settings when they change; nil keeps them
+	// for the session only.
+	Persist func(config.AnalyzeConfig) error
+	// FixedType keeps the view on TypeFilter: going back does not open
+	// the overview.
+	FixedType bool
 }
 
 func New(service docker.DockerService, opts Options) Model {
@@ -182,6 +242,10 @@ func New(service docker.DockerService, opts Options) Model {
 		filterType = ResourceVolumes
 	case "networks", "network", "n":
 		filterType = ResourceNetworks
+	case "ports", "port", "p":
+		filterType = ResourcePorts
+	case "logs", "log":
+		filterType = ResourceLogs
 	default:
 		filterType = ResourceAll
 	}
@@ -191,9 +255,19 @@ func New(service docker.DockerService, opts Options) Model {
 		loading:      true,
 		filterType:   filterType,
 		showDangling: opts.Dangling,
+		sortKey:      parseSortKey(opts.View.Sort),
+		sortReverse:  opts.View.SortReverse,
+		columns:      columnSet(opts.View.Columns),
+		persist:      opts.Persist,
+		fixedType:    opts.FixedType,
 	}
 }
 
+// CapturingInput reports whether keys are being typed into a text input.
+func (m Model) CapturingInput() bool {
+	return m.filtering || m.logFiltering || m.palette != nil
+}
+
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(m.fetchResources(), m.tickSpinner())
 }
@@ -212,8 +286,22 @@ func (m Model) fetchResources() tea.Cmd {
 		var warnings []string
 		var entries []ResourceEntry
 
+		// Compose projects gather the volumes and networks labelled with
+		// their name, so those are listed whenever containers are.
+		listContainers := m.filterType == ResourceAll || m.filterType == ResourceContainers
+		var volumes []docker.VolumeInfo
+		var volumesErr error
+		if listContainers || m.filterType == ResourceVolumes {
+			volumes, volumesErr = m.docker.ListVolumes(ctx)
+		}
+		var networks []docker.NetworkInfo
+		var networksErr error
+		if listContainers || m.filterType == ResourceNetworks {
+			networks, networksErr = m.docker.ListNetworks(ctx)
+		}
+
 		// Fetch all resource types
-		if m.filterType == ResourceAll || m.filterType == ResourceContainers {
+		if listContainers {
 			containers, err := m.docker.ListContainers(ctx, true)
 			if err != nil {
 				warnings = append(warnings, fmt.Sprintf("containers: %v", err))
@@ -228,63 +316,23 @@ func (m Model) fetchResources() tea.Cmd {
 
 				// Group by Compose project
 				groups, ungrouped := docker.GroupByComposeProject(containers)
-
-				// Add grouped containers
-				for _, group := range groups {
-					// Project header (selectable for project-level operations)
-					entries = append(entries, ResourceEntry{
-						Type:            ResourceContainers,
-						Name:            fmt.Sprintf("[%s] (%d containers)", group.ProjectName, len(group.Containers)),
-						IsProjectHeader: true,
-						ProjectName:     group.ProjectName,
-						Selectable:      true,
-					})
-					for _, c := range group.Containers {
-						if m.showDangling && c.State == "running" {
-							continue
-						}
-						serviceName := ""
-						if c.Labels != nil {
-							serviceName = c.Labels[docker.ComposeServiceLabel]
-						}
-						entries = append(entries, ResourceEntry{
-							Type:           ResourceContainers,
-							ID:             c.ID,
-							Name:           c.Name,
-							Size:           c.Size,
-							Status:         c.Status,
-							Created:        c.Created,
-							Extra:          c.Image,
-							IsUnused:       c.State != "running",
-							Selectable:     true,
-							ComposeProject: group.ProjectName,
-							ComposeService: serviceName,
-						})
-					}
-				}
+				var tree []ResourceEntry
+				tree, volumes, networks = m.projectTree(groups, volumes, networks)
+				entries = append(entries, tree...)
 
 				// Add ungrouped containers
 				for _, c := range ungrouped {
 					if m.showDangling && c.State == "running" {
 						continue
 					}
-					entries = append(entries, ResourceEntry{
-						Type:       ResourceContainers,
-						ID:         c.ID,
-						Name:       c.Name,
-						Size:       c.Size,
-						Status:     c.Status,
-						Created:    c.Created,
-						Extra:      c.Image,
-						IsUnused:   c.State != "running",
-						Selectable: true,
-					})
+					entries = append(entries, containerEntry(c, ""))
 				}
 			}
 		}
 
 		// Fetch metrics for running containers (cap at 20 to avoid excessive API calls)
 		entries = enrichContainerMetrics(ctx, m.docker, entries)
+		sumProjects(entries)
 
 		if m.filterType == ResourceAll || m.filterType == ResourceImages {
 			images, err := m.docker.ListImages(ctx, true)
@@ -329,9 +377,8 @@ func (m Model) fetchResources() tea.Cmd {
 		}
 
 		if m.filterType == ResourceAll || m.filterType == ResourceVolumes {
-			volumes, err := m.docker.ListVolumes(ctx)
-			if err != nil {
-				warnings = append(warnings, fmt.Sprintf("volumes: %v", err))
+			if volumesErr != nil {
+				warnings = append(warnings, fmt.Sprintf("volumes: %v", volumesErr))
 			} else {
 				if m.filterType == ResourceAll {
 					entries = append(entries, ResourceEntry{
@@ -341,27 +388,16 @@ func (m Model) fetchResources() tea.Cmd {
 					})
 				}
 				for _, v := range volumes {
-					if m.showDangling && v.InUse {
-						continue
+					if m.showVolume(v) {
+						entries = append(entries, volumeEntry(v))
 					}
-					entries = append(entries, ResourceEntry{
-						Type:       ResourceVolumes,
-						ID:         v.Name,
-						Name:       v.Name,
-						Size:       v.Size,
-						Created:    v.Created,
-						Extra:      v.Driver,
-						IsUnused:   !v.InUse,
-						Selectable: true,
-					})
 				}
 			}
 		}
 
 		if m.filterType == ResourceAll || m.filterType == ResourceNetworks {
-			networks, err := m.docker.ListNetworks(ctx)
-			if err != nil {
-				warnings = append(warnings, fmt.Sprintf("networks: %v", err))
+			if networksErr != nil {
+				warnings = append(warnings, fmt.Sprintf("networks: %v", networksErr))
 			} else {
 				if m.filterType == ResourceAll {
 					entries = append(entries, ResourceEntry{
@@ -371,20 +407,71 @@ func (m Model) fetchResources() tea.Cmd {
 					})
 				}
 				for _, n := range networks {
-					// Skip default networks
-					if n.Name == "bridge" || n.Name == "host" || n.Name == "none" {
+					if m.showNetwork(n) {
+						entries = append(entries, networkEntry(n))
+					}
+				}
+			}
+		}
+
+		if m.filterType == ResourceAll || m.filterType == ResourcePorts {
+			portEntries, err := fetchPorts(ctx, m.docker)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("ports: %v", err))
+			} else {
+				if m.filterType == ResourceAll {
+					entries = append(entries, ResourceEntry{
+						Type:       ResourcePorts,
+						Name:       "Ports",
+						IsCategory: true,
+					})
+				}
+				for _, p := range portEntries {
+					if m.showDangling && p.Running {
 						continue
 					}
-					if m.showDangling && n.Containers > 0 {
+					entries = append(entries, ResourceEntry{
+						Type:           ResourcePorts,
+						ID:             p.ContainerID,
+						Name:           fmt.Sprintf("%s:%d/%s", p.Address(), p.HostPort, p.Protocol),
+						Extra:          fmt.Sprintf("%s → %d", p.Container, p.ContainerPort),
+						IsUnused:       !p.Running,
+						ComposeProject: p.Project,
+						ComposeService: p.Service,
+						Conflicts:      p.Conflicts,
+					})
+				}
+			}
+		}
+
+		if m.filterType == ResourceAll || m.filterType == ResourceLogs {
+			usages, err := logsize.List(ctx, m.docker)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("logs: %v", err))
+			} else {
+				if m.filterType == ResourceAll {
+					entries = append(entries, ResourceEntry{
+						Type:       ResourceLogs,
+						Name:       "Logs",
+						IsCategory: true,
+					})
+				}
+				for _, u := range usages {
+					if m.showDangling && !u.Unbounded() {
 						continue
 					}
+					extra := u.Driver
+					if r := u.Rotation(); r != "" {
+						extra += ", " + r
+					}
 					entries = append(entries, ResourceEntry{
-						Type:       ResourceNetworks,
-						ID:         n.ID,
-						Name:       n.Name,
-						Extra:      fmt.Sprintf("%s (%d containers)", n.Driver, n.Containers),
-						IsUnused:   n.Containers == 0,
-						Selectable: true,
+						Type:       ResourceLogs,
+						ID:         u.ContainerID,
+						Name:       u.Container,
+						Size:       u.Size,
+						Extra:      extra,
+						Unrotated:  u.Unbounded(),
+						Selectable: u.CanTruncate(),
 					})
 				}
 			}
@@ -394,6 +481,30 @@ func (m Model) fetchResources() tea.Cmd {
 	}
 }
 
+// containerLog returns the current log usage of one container.
+func containerLog(ctx context.Context, svc docker.DockerService, id string) (logsize.Usage, error) {
+	details, err := svc.InspectContainer(ctx, id)
+	if err != nil {
+		return logsize.Usage{}, err
+	}
+	return logsize.FromInspect(details, docker.IsLocalDaemon()), nil
+}
+
+// fetchPorts maps the published ports of all containers, including those
+// stopped containers claim when started, against the host's listeners.
+func fetchPorts(ctx context.Context, svc docker.DockerService) ([]ports.Entry, error) {
+	containers, err := svc.ListContainers(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	containers, err = ports.WithConfigured(ctx, svc, containers)
+	if err != nil {
+		return nil, err
+	}
+	listeners, _ := ports.LocalListeners()
+	return ports.Map(containers, listeners), nil
+}
+
 // enrichContainerMetrics fetches CPU/memory stats for running containers.
 // Caps at 20 running containers to avoid excessive API calls.
 func enrichContainerMetrics(ctx context.Context, svc docker.DockerService, entries []ResourceEntry) []ResourceEntry {
@@ -452,6 +563,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.viewMode == viewLogs {
 			return m.updateLogsView(msg)
 		}
+		if m.viewMode == viewGraph {
+			return m.updateRelationsView(msg)
+		}
+		if m.viewMode == viewNetwork {
+			return m.updateNetworkView(msg)
+		}
 
 		if m.deleteConfirm {
 			switch msg.String() {
@@ -465,6 +582,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.bulkPlan != nil {
+			return m.updateBulkConfirm(msg)
+		}
+		if m.pickingColumns {
+			return m.updateColumnPicker(msg)
+		}
+		if m.palette != nil {
+			p, cmd := m.palette.Update(msg)
+			m.palette = &p
+			return m, cmd
+		}
+
 		// Filter mode key handling
 		if m.filtering {
 			switch msg.Type {
@@ -492,157 +621,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		switch msg.String() {
-		case "q", "ctrl+c":
-			return m, tea.Quit
-		case "esc":
-			if m.filterText != "" {
-				m.filterText = ""
-				m.filteredEntries = nil
-				m.selected = 0
-				m.offset = 0
-				return m, nil
-			}
-			if m.filterType != ResourceAll {
-				m.filterType = ResourceAll
-				m.loading = true
-				return m, m.fetchResources()
-			}
-			return m, tea.Quit
-		case "/":
-			m.filtering = true
-			m.filterText = ""
+		if m.showHelp {
+			m.showHelp = false
 			return m, nil
-		case "l":
-			if m.canOperateOnSelected() && m.selectedEntry().Type == ResourceContainers {
-				entry := m.selectedEntry()
-				m.viewMode = viewLogs
-				m.logContainer = entry.Name
-				m.logContainerID = entry.ID
-				m.logEntries = nil
-				m.logOffset = 0
-				m.logFollowing = false
-				m.logFilterText = ""
-				m.logFiltering = false
-				return m, m.fetchLogs(entry.ID, 200)
-			}
-		case "up", "k":
-			m.moveSelection(-1)
-		case "down", "j":
-			m.moveSelection(1)
-		case "enter", "right":
-			visible := m.visibleEntries()
-			if m.selected < len(visible) {
-				entry := visible[m.selected]
-				if entry.IsCategory {
-					m.filterType = entry.Type
-					m.filterText = ""
-					m.filteredEntries = nil
-					m.loading = true
-					return m, m.fetchResources()
-				}
-			}
-		case "h", "left":
-			if m.filterType != ResourceAll {
-				m.filterType = ResourceAll
-				m.loading = true
-				return m, m.fetchResources()
-			}
-		case "d", "delete", "backspace":
-			visible := m.visibleEntries()
-			if m.selected < len(visible) {
-				entry := visible[m.selected]
-				if entry.Selectable && !entry.IsCategory {
-					m.deleteTarget = &entry
-					// Phase 1: Call DryRun to get confirmation info (and re-check state)
-					return m, m.reCheckAndShowConfirmation()
-				}
-			}
-		case "s":
-			if m.canOperateOnSelected() {
-				entry := m.selectedEntry()
-				if entry.IsProjectHeader {
-					return m, m.startComposeProject(entry.ProjectName)
-				} else if entry.Type == ResourceContainers {
-					return m, m.startSelectedContainer()
-				}
-			}
-		case "t":
-			if m.canOperateOnSelected() {
-				entry := m.selectedEntry()
-				if entry.IsProjectHeader {
-					return m, m.stopComposeProject(entry.ProjectName)
-				} else if entry.Type == ResourceContainers {
-					return m, m.stopSelectedContainer()
-				}
-			}
-		case "r":
-			if m.canOperateOnSelected() {
-				entry := m.selectedEntry()
-				if entry.IsProjectHeader {
-					return m, m.restartComposeProject(entry.ProjectName)
-				} else if entry.Type == ResourceContainers {
-					return m, m.restartSelectedContainer()
-				}
-			} else {
-				m.loading = true
-				return m, m.fetchResources()
-			}
-		case "y":
-			if m.canOperateOnSelected() {
-				entry := m.selectedEntry()
-				text := entry.ClipboardText()
-				return m, func() tea.Msg {
-					err := clipboard.Copy(text)
-					return common.ClipboardMsg{
-						Success: err == nil,
-						Text:    text,
-						Err:     err,
-					}
-				}
-			}
-		case "x":
-			if m.canOperateOnSelected() && m.selectedEntry().Type == ResourceContainers {
-				if m.canExecOnSelected() {
-					entry := m.selectedEntry()
-					api := m.docker.API()
-					cmd := docker.NewDockerExecCommand(api, entry.ID, "/bin/sh")
-					return m, tea.Exec(cmd, func(err error) tea.Msg {
-						return common.ExecFinishedMsg{Err: err}
-					})
+		}
+		for _, a := range listActions {
+			if common.Keys.Is(msg, a) {
+				if next, cmd, ok := m.do(a); ok {
+					return next, cmd
 				}
-				m.statusMessage = "Cannot exec: container is not running"
-				return m, tea.Tick(3*time.Second, func(t time.Time) tea.Msg {
-					return common.ClearStatusMsg{}
-				})
 			}
 		}
 
 	case tea.MouseMsg:
-		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
-			if !m.deleteConfirm { // Don't process clicks during confirmation
-				visible := m.visibleEntries()
-				headerLines := 3 // title + separator + blank
-				if m.filtering || m.filterText != "" {
-					headerLines += 2 // filter bar + blank
-				}
-				idx := msg.Y - headerLines + m.offset
-				if idx >= 0 && idx < len(visible) {
-					m.selected = idx
-					// Adjust viewport to follow selection
-					viewport := m.height - 12
-					if viewport < 5 {
-						viewport = 5
-					}
-					if m.selected < m.offset {
-						m.offset = m.selected
-					}
-					if m.selected >= m.offset+viewport {
-						m.offset = m.selected - viewport + 1
-					}
-				}
-			}
-		}
+		return m.updateMouse(msg)
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -654,7 +646,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Err != nil {
 			m.err = msg.Err
 		} else {
-			m.entries = msg.Entries
+			m.source = msg.Entries
+			m.entries = sortEntries(msg.Entries, m.sortKey, m.sortReverse)
+			m.pruneMarks()
 			m.selected = 0
 			m.offset = 0
 			// Skip to first selectable item
@@ -666,6 +660,58 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case BulkConfirmationMsg:
+		if len(msg.Items) == 0 {
+			// Nothing the action applies to; report why
+			m.bulkAction = msg.Action
+			m.bulkResults = msg.Skipped
+		} else {
+			m.bulkPlan = &msg
+		}
+
+	case paletteRunMsg:
+		return m.runCommand(msg)
+
+	case palette.ChosenMsg:
+		m.palette = nil
+		return m.Update(msg.Command.Msg)
+
+	case palette.ClosedMsg:
+		m.palette = nil
+
+	case graphLoadedMsg:
+		if m.viewMode != viewGraph || msg.root != m.graphRoot {
+			return m, nil // closed or moved on meanwhile
+		}
+		if msg.err != nil {
+			m.closeRelations()
+			m.statusMessage = fmt.Sprintf("Relationships: %v", msg.err)
+			return m, nil
+		}
+		m.graph = msg.graph
+		m.graphSelected = 0
+
+	case networkLoadedMsg, connectChoicesMsg, networkConfirmMsg, networkDoneMsg:
+		if m.viewMode != viewNetwork {
+			return m, nil // closed meanwhile
+		}
+		return m.updateNetworkMsg(msg)
+
+	case foldAllMsg:
+		m.foldAll(msg.fold)
+		return m, nil
+
+	case viewSavedMsg:
+		if msg.Err != nil {
+			m.statusMessage = fmt.Sprintf("View settings not saved: %v", msg.Err)
+		}
+
+	case BulkDoneMsg:
+		m.bulkAction = msg.Action
+		m.bulkResults = msg.Results
+		m.marked = nil
+		return m.Update(msg.Data)
+
 	case ConfirmationMsg:
 		// Phase 1 completion: DryRun returned, show confirmation dialog
 		if msg.Err != nil {
@@ -738,12 +784,34 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// visibleEntries returns filtered entries if a filter is active, otherwise all entries.
+// copyText copies text to the clipboard.
+func copyText(text string) tea.Cmd {
+	return func() tea.Msg {
+		err := clipboard.Copy(text)
+		return common.ClipboardMsg{
+			Success: err == nil,
+			Text:    text,
+			Err:     err,
+		}
+	}
+}
+
+// visibleEntries returns filtered entries if a filter is active, otherwise
+// all entries but the containers of collapsed Compose projects.
 func (m *Model) visibleEntries() []ResourceEntry {
 	if m.filterText != "" && m.filteredEntries != nil {
 		return m.filteredEntries
 	}
-	return m.entries
+	if len(m.collapsed) == 0 {
+		return m.entries
+	}
+	var shown []ResourceEntry
+	for _, e := range m.entries {
+		if !m.folded(e) {
+			shown = append(shown, e)
+		}
+	}
+	return shown
 }
 
 // applyFilter filters entries by the current filterText.
@@ -823,30 +891,59 @@ func (m Model) reCheckAndShowConfirmation() tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), docker.TimeoutList)
 		defer cancel()
 
-		var info docker.ConfirmationInfo
-		var err error
-
-		switch m.deleteTarget.Type {
-		case ResourceContainers:
-			info, err = m.docker.RemoveContainerDryRun(ctx, m.deleteTarget.ID)
-		case ResourceImages:
-			info, err = m.docker.RemoveImageDryRun(ctx, m.deleteTarget.ID)
-		case ResourceVolumes:
-			info, err = m.docker.RemoveVolumeDryRun(ctx, m.deleteTarget.ID)
-		case ResourceNetworks:
-			info, err = m.docker.RemoveNetworkDryRun(ctx, m.deleteTarget.ID)
-		default:
-			err = fmt.Errorf("unsupported resource type for deletion: %v", m.deleteTarget.Type)
-		}
-
+		info, err := m.removeDryRun(ctx, *m.deleteTarget)
 		if err != nil {
 			return ConfirmationMsg{Err: err}
 		}
+		info.Warnings = append(info.Warnings, referenceWarnings(ctx, m.docker, *m.deleteTarget)...)
 
 		return ConfirmationMsg{Info: &info}
 	}
 }
 
+// removeDryRun describes deleting an entry (truncating, for logs) without
+// changing anything.
+func (m Model) removeDryRun(ctx context.Context, entry ResourceEntry) (docker.ConfirmationInfo, error) {
+	switch entry.Type {
+	case ResourceContainers:
+		return m.docker.RemoveContainerDryRun(ctx, entry.ID)
+	case ResourceImages:
+		return m.docker.RemoveImageDryRun(ctx, entry.ID)
+	case ResourceVolumes:
+		return m.docker.RemoveVolumeDryRun(ctx, entry.ID)
+	case ResourceNetworks:
+		return m.docker.RemoveNetworkDryRun(ctx, entry.ID)
+	case ResourceLogs:
+		usage, err := containerLog(ctx, m.docker, entry.ID)
+		if err != nil {
+			return docker.ConfirmationInfo{}, err
+		}
+		return logsize.TruncateDryRun(usage)
+	}
+	return docker.ConfirmationInfo{}, fmt.Errorf("unsupported resource type for deletion: %v", entry.Type)
+}
+
+// remove deletes an entry, or truncates it for logs.
+func (m Model) remove(ctx context.Context, entry ResourceEntry) error {
+	switch entry.Type {
+	case ResourceContainers:
+		return m.docker.RemoveContainer(ctx, entry.ID, false)
+	case ResourceImages:
+		return m.docker.RemoveImage(ctx, entry.ID, false)
+	case ResourceVolumes:
+		return m.docker.RemoveVolume(ctx, entry.ID, false)
+	case ResourceNetworks:
+		return m.docker.RemoveNetwork(ctx, entry.ID)
+	case ResourceLogs:
+		usage, err := containerLog(ctx, m.docker, entry.ID)
+		if err == nil {
+			_, err = logsize.Truncate(usage)
+		}
+		return err
+	}
+	return fmt.Errorf("unsupported resource type: %v", entry.Type)
+}
+
 // deleteResource is Phase 2: Execute the actual deletion after user confirmation
 // Re-checks state one more time before executing (TOCTOU protection)
 func (m Model) deleteResource() tea.Cmd {
@@ -860,22 +957,7 @@ func (m Model) deleteResource() tea.Cmd {
 
 		// Phase 2: Re-check state one final time before execution (TOCTOU protection)
 		// Call DryRun again to verify state hasn't changed unexpectedly
-		var currentInfo docker.ConfirmationInfo
-		var err error
-
-		switch m.deleteTarget.Type {
-		case ResourceContainers:
-			currentInfo, err = m.docker.RemoveContainerDryRun(ctx, m.deleteTarget.ID)
-		case ResourceImages:
-			currentInfo, err = m.docker.RemoveImageDryRun(ctx, m.deleteTarget.ID)
-		case ResourceVolumes:
-			currentInfo, err = m.docker.RemoveVolumeDryRun(ctx, m.deleteTarget.ID)
-		case ResourceNetworks:
-			currentInfo, err = m.docker.RemoveNetworkDryRun(ctx, m.deleteTarget.ID)
-		default:
-			return DataMsg{Entries: m.entries, Warnings: append(m.warnings, fmt.Sprintf("unsupported resource type: %v", m.deleteTarget.Type))}
-		}
-
+		currentInfo, err := m.removeDryRun(ctx, *m.deleteTarget)
 		if err != nil {
 			return DataMsg{Entries: m.entries, Warnings: append(m.warnings, fmt.Sprintf("State changed during confirmation (TOCTOU): %v", err))}
 		}
@@ -886,16 +968,7 @@ func (m Model) deleteResource() tea.Cmd {
 		}
 
 		// Now execute the actual deletion
-		switch m.deleteTarget.Type {
-		case ResourceContainers:
-			err = m.docker.RemoveContainer(ctx, m.deleteTarget.ID, false)
-		case ResourceImages:
-			err = m.docker.RemoveImage(ctx, m.deleteTarget.ID, false)
-		case ResourceVolumes:
-			err = m.docker.RemoveVolume(ctx, m.deleteTarget.ID, false)
-		case ResourceNetworks:
-			err = m.docker.RemoveNetwork(ctx, m.deleteTarget.ID)
-		}
+		err = m.remove(ctx, *m.deleteTarget)
 
 		// Clear confirmation state
 		m.deleteConfirm = false
@@ -1095,8 +1168,9 @@ func (m Model) updateLogsView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	switch msg.String() {
-	case "esc", "q":
+	keys := common.Keys
+	switch {
+	case keys.Is(msg, common.ActCancel), keys.Is(msg, common.ActQuit):
 		// Stop stream if following
 		if m.logCancelFn != nil {
 			m.logCancelFn()
@@ -1108,21 +1182,15 @@ func (m Model) updateLogsView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.logFilterText = ""
 		m.logFiltering = false
 		return m, nil
-	case "up", "k":
-		if m.logOffset > 0 {
-			m.logOffset--
-		}
-		m.logFollowing = false
-	case "down", "j":
-		maxOffset := max(0, len(m.visibleLogEntries())-m.logViewportHeight())
-		if m.logOffset < maxOffset {
-			m.logOffset++
-		}
-	case "G":
+	case keys.Is(msg, common.ActUp):
+		m.scrollLogs(-1)
+	case keys.Is(msg, common.ActDown):
+		m.scrollLogs(1)
+	case keys.Is(msg, common.ActBottom):
 		m.logOffset = max(0, len(m.visibleLogEntries())-m.logViewportHeight())
-	case "g":
+	case keys.Is(msg, common.ActTop):
 		m.logOffset = 0
-	case "f":
+	case keys.Is(msg, common.ActFollow):
 		if m.logFollowing {
 			// Stop following
 			if m.logCancelFn != nil {
@@ -1133,7 +1201,7 @@ func (m Model) updateLogsView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		} else {
 			return m, m.startLogStream()
 		}
-	case "/":
+	case keys.Is(msg, common.ActFilter):
 		m.logFiltering = true
 		m.logFilterText = ""
 	}
@@ -1169,41 +1237,24 @@ func (m Model) View() string {
 	if m.viewMode == viewLogs {
 		return m.renderLogsView()
 	}
-
-	var b strings.Builder
-
-	// Header
-	title := fmt.Sprintf("🐙 Octo Analyzer - %s", m.filterType.String())
-	if m.showDangling {
-		title += " (unused only)"
+	if m.viewMode == viewGraph {
+		return m.renderRelationsView()
 	}
-	b.WriteString(styles.Title.Render(title))
-	b.WriteString("\n")
-	b.WriteString(strings.Repeat("─", 60))
-	b.WriteString("\n\n")
-
-	// Filter bar
-	if m.filtering || m.filterText != "" {
-		filterDisplay := "Filter: " + m.filterText
-		if m.filtering {
-			filterDisplay += "█"
-		}
-		b.WriteString(styles.Info.Render(filterDisplay))
-		b.WriteString("\n\n")
+	if m.viewMode == viewNetwork {
+		return m.renderNetworkView()
 	}
-
-	// Delete confirmation dialog (detailed)
-	if m.deleteConfirm && m.deleteTarget != nil && m.deleteConfirmInfo != nil {
-		b.WriteString(m.renderConfirmationDialog(*m.deleteConfirmInfo))
-		b.WriteString("\n\n")
+	if m.showHelp {
+		return common.RenderHelp("Octo Analyzer", helpSections...)
 	}
 
-	// Calculate viewport
-	viewport := m.height - 12
-	if viewport < 5 {
-		viewport = 5
+	if m.palette != nil {
+		return m.renderTitle() + m.palette.View()
 	}
 
+	var b strings.Builder
+	b.WriteString(m.renderListTop())
+	viewport := m.listViewport()
+
 	visible := m.visibleEntries()
 
 	// Render entries
@@ -1213,8 +1264,12 @@ func (m Model) View() string {
 
 		if entry.IsCategory {
 			line = styles.Section.Render(fmt.Sprintf("► %s", entry.Name))
+		} else if entry.Type == ResourcePorts {
+			line = renderPortLine(entry)
+		} else if entry.Type == ResourceLogs {
+			line = renderLogLine(entry)
 		} else if entry.IsProjectHeader {
-			line = styles.Section.Render(fmt.Sprintf("  [compose] %s", entry.Name))
+			line = m.renderProjectHeader(entry)
 		} else {
 			name := entry.Name
 			maxNameLen := 30
@@ -1251,18 +1306,35 @@ func (m Model) View() string {
 			}
 
 			if entry.ComposeProject != "" {
-				// Indent compose-grouped containers with service label
+				// Indent compose-grouped entries with service label, or
+				// the kind of the project's volumes and networks
 				serviceLabel := ""
-				if entry.ComposeService != "" {
+				switch {
+				case entry.Type == ResourceVolumes:
+					serviceLabel = " (volume)"
+				case entry.Type == ResourceNetworks:
+					serviceLabel = " (network)"
+				case m.columns[ColumnService] && entry.ComposeService != "":
 					serviceLabel = fmt.Sprintf(" (%s)", entry.ComposeService)
 				}
-				line = fmt.Sprintf("    %-28s%s %s%s%s", name, serviceLabel, styles.Label.Render(sizeStr), statusStr, metricsStr)
+				line = fmt.Sprintf("    %-28s%s %s%s%s%s", name, serviceLabel, styles.Label.Render(sizeStr), m.columnCells(entry), statusStr, metricsStr)
 			} else {
-				line = fmt.Sprintf("%-32s %s%s%s", name, styles.Label.Render(sizeStr), statusStr, metricsStr)
+				line = fmt.Sprintf("%-32s %s%s%s%s", name, styles.Label.Render(sizeStr), m.columnCells(entry), statusStr, metricsStr)
 			}
 			line = styles.Normal.Render(line)
 		}
 
+		if len(m.marked) > 0 {
+			switch {
+			case !markable(entry):
+				line = "    " + line
+			case m.marked[entryKey(entry)]:
+				line = styles.Success.Render("[x] ") + line
+			default:
+				line = "[ ] " + line
+			}
+		}
+
 		if i == m.selected {
 			line = styles.Selected.Render(line)
 		}
@@ -1292,11 +1364,105 @@ func (m Model) View() string {
 	b.WriteString("\n")
 	b.WriteString(strings.Repeat("─", 60))
 	b.WriteString("\n")
-	b.WriteString(styles.Help.Render("↑↓/jk: navigate | /: filter | l: logs | s/t/r: start/stop/restart | x: shell | y: copy | d: delete | q: quit"))
+	b.WriteString(styles.Help.Render(common.ShortHelp(
+		common.Item("navigate", common.ActUp, common.ActDown),
+		common.Item("filter", common.ActFilter),
+		common.Item("logs", common.ActLogs),
+		common.Item("start/stop/restart", common.ActStart, common.ActStop, common.ActRestart),
+		common.Item("delete", common.ActDelete),
+		common.Item("mark", common.ActMark),
+		common.Item("commands", common.ActPalette),
+		common.Item("help", common.ActHelp),
+		common.Item("quit", common.ActQuit),
+	)))
+
+	return b.String()
+}
+
+// renderTitle renders the title line and separator of the list.
+func (m Model) renderTitle() string {
+	var b strings.Builder
+	title := fmt.Sprintf("🐙 Octo Analyzer - %s", m.filterType.String())
+	if m.showDangling {
+		title += " (unused only)"
+	}
+	if m.sortKey != SortDefault {
+		arrow := "↓"
+		if m.sortReverse {
+			arrow = "↑"
+		}
+		title += fmt.Sprintf(" · by %s %s", m.sortKey, arrow)
+	}
+	if n := len(m.markedEntries()); n > 0 {
+		title += fmt.Sprintf(" [%d marked]", n)
+	}
+	b.WriteString(styles.Title.Render(title))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", 60))
+	b.WriteString("\n\n")
+	return b.String()
+}
+
+// renderListTop renders everything above the entries: title, filter bar,
+// dialogs and the sort headings. Clicks are mapped to entries by its height.
+func (m Model) renderListTop() string {
+	var b strings.Builder
+	b.WriteString(m.renderTitle())
 
+	// Filter bar
+	if m.filtering || m.filterText != "" {
+		filterDisplay := "Filter: " + m.filterText
+		if m.filtering {
+			filterDisplay += "█"
+		}
+		b.WriteString(styles.Info.Render(filterDisplay))
+		b.WriteString("\n\n")
+	}
+
+	// Delete confirmation dialog (detailed)
+	if m.deleteConfirm && m.deleteTarget != nil && m.deleteConfirmInfo != nil {
+		b.WriteString(common.RenderConfirmation(*m.deleteConfirmInfo, m.confirmAction()))
+		b.WriteString("\n\n")
+	}
+	if m.bulkPlan != nil {
+		b.WriteString(common.RenderConfirmation(m.bulkPlan.Info, m.bulkPlan.Action.String()))
+		b.WriteString("\n\n")
+	}
+	if m.bulkResults != nil {
+		b.WriteString(m.renderBulkResults())
+		b.WriteString("\n")
+	}
+	if m.pickingColumns {
+		b.WriteString(m.renderColumnPicker())
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.renderSortHeader())
+	b.WriteString("\n")
 	return b.String()
 }
 
+// listViewport returns how many entries fit in the list.
+func (m Model) listViewport() int {
+	return max(m.height-12, 5)
+}
+
+// renderPortLine renders a published port with its owner and conflicts.
+func renderPortLine(entry ResourceEntry) string {
+	owner := entry.Extra
+	if entry.ComposeService != "" {
+		owner += fmt.Sprintf(" (%s/%s)", entry.ComposeProject, entry.ComposeService)
+	}
+	line := fmt.Sprintf("%-32s %s", entry.Name, owner)
+	if entry.IsUnused {
+		line += styles.Warning.Render(" (stopped)")
+	}
+	if len(entry.Conflicts) > 0 {
+		return styles.Error.Render(line) + styles.Warning.Render("  ⚠ "+strings.Join(entry.Conflicts, "; "))
+	}
+	return styles.Normal.Render(line)
+}
+
 // renderLogsView renders the logs viewer.
 func (m Model) renderLogsView() string {
 	var b strings.Builder
@@ -1362,53 +1528,38 @@ func (m Model) renderLogsView() string {
 	// Footer
 	b.WriteString(strings.Repeat("─", 60))
 	b.WriteString("\n")
-	b.WriteString(styles.Help.Render("↑↓/jk: scroll | g/G: top/bottom | f: follow | /: filter | esc: back"))
+	b.WriteString(styles.Help.Render(common.ShortHelp(
+		common.Item("scroll", common.ActUp, common.ActDown),
+		common.Item("top/bottom", common.ActTop, common.ActBottom),
+		common.Item("follow", common.ActFollow),
+		common.Item("filter", common.ActFilter),
+		common.Item("back", common.ActCancel),
+	)))
 
 	return b.String()
 }
 
-// renderConfirmationDialog renders a detailed confirmation dialog with safety tier colors
-func (m Model) renderConfirmationDialog(info docker.ConfirmationInfo) string {
-	var b strings.Builder
-
-	// Tier-colored title
-	tierStyle := styles.TierStyle(int(info.Tier))
-	b.WriteString(tierStyle.Render(fmt.Sprintf("⚠ %s\n", info.Title)))
-
-	// Description
-	b.WriteString(styles.Info.Render(fmt.Sprintf("   %s\n", info.Description)))
-
-	// Resources list
-	if len(info.Resources) > 0 {
-		b.WriteString(styles.Help.Render("   Resources:\n"))
-		for _, r := range info.Resources {
-			b.WriteString(styles.Help.Render(fmt.Sprintf("     • %s\n", r)))
-		}
+// renderLogLine renders a container's log size and rotation settings.
+func renderLogLine(entry ResourceEntry) string {
+	name := entry.Name
+	if len(name) > 30 {
+		name = name[:27] + "..."
 	}
-
-	// Reversibility status
-	if info.Reversible {
-		b.WriteString(styles.Success.Render("   ✓ Reversible\n"))
-		b.WriteString(styles.Help.Render(fmt.Sprintf("   %s\n", info.UndoInstructions)))
-	} else {
-		b.WriteString(tierStyle.Render("   ✗ NOT REVERSIBLE - Data will be permanently lost\n"))
-		b.WriteString(styles.Help.Render(fmt.Sprintf("   %s\n", info.UndoInstructions)))
+	sizeStr := "-"
+	if entry.Size > 0 || entry.Selectable {
+		sizeStr = format.Size(uint64(entry.Size))
 	}
-
-	// Warnings
-	if len(info.Warnings) > 0 {
-		b.WriteString(styles.Warning.Render("   ⚠ Warnings:\n"))
-		for _, w := range info.Warnings {
-			b.WriteString(styles.Warning.Render(fmt.Sprintf("     • %s\n", w)))
-		}
+	line := fmt.Sprintf("%-32s %10s  %s", name, sizeStr, entry.Extra)
+	if entry.Unrotated {
+		return styles.Warning.Render(line)
 	}
+	return styles.Normal.Render(line)
+}
 
-	// Safety tier indicator
-	b.WriteString(tierStyle.Render(fmt.Sprintf("   Safety Level: %s\n", info.Tier.String())))
-
-	// Confirmation prompt
-	b.WriteString("\n")
-	b.WriteString(styles.DeleteConfirm.Render("   Confirm deletion? [y] Yes  [n] No"))
-
-	return b.String()
+// confirmAction names the pending single-entry action in the prompt.
+func (m Model) confirmAction() string {
+	if m.deleteTarget != nil && m.deleteTarget.Type == ResourceLogs {
+		return "truncation"
+	}
+	return "deletion"
 }
@@ -4,12 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/bsisduck/octo/internal/config"
 	"github.com/bsisduck/octo/internal/docker"
 	"github.com/bsisduck/octo/internal/tui/common"
 )
@@ -170,6 +179,8 @@ func TestAnalyze_ResourceTypeString(t *testing.T) {
 		{ResourceImages, "Images"},
 		{ResourceVolumes, "Volumes"},
 		{ResourceNetworks, "Networks"},
+		{ResourcePorts, "Ports"},
+		{ResourceLogs, "Logs"},
 	}
 
 	for _, tt := range tests {
@@ -351,17 +362,17 @@ func createAnalyzeModelWithEntries() Model {
 
 // TestMouseClickSelectsEntry tests that left-clicking selects the correct entry in analyze view
 func TestMouseClickSelectsEntry(t *testing.T) {
-	// Header is 3 lines (title + separator + blank)
-	// Click on second visible entry (index 1 in entries, at Y=3+1=4)
+	// Header is 4 lines (title + separator + blank + sort headings)
+	// Click on second visible entry (index 1 in entries, at Y=4+1=5)
 	tests := []struct {
 		name        string
 		clickY      int
 		expectedIdx int
 	}{
-		{"click on first entry (category)", 3, 0},
-		{"click on second entry (web-app)", 4, 1},
-		{"click on third entry (db-server)", 5, 2},
-		{"click on fourth entry (Images category)", 6, 3},
+		{"click on first entry (category)", 4, 0},
+		{"click on second entry (web-app)", 5, 1},
+		{"click on third entry (db-server)", 6, 2},
+		{"click on fourth entry (Images category)", 7, 3},
 	}
 
 	for _, tt := range tests {
@@ -407,19 +418,19 @@ func TestMouseClickWithScrollOffset(t *testing.T) {
 	// Simulate scrolling down by setting offset manually
 	model.offset = 10
 
-	// Click at Y=3 (first visible line after header)
+	// Click at Y=4 (first visible line after header)
 	// With offset=10, this should select entry at index 10
 	clickMsg := tea.MouseMsg{
 		Action: tea.MouseActionPress,
 		Button: tea.MouseButtonLeft,
 		X:      10,
-		Y:      3, // header is 3 lines
+		Y:      4, // header is 4 lines
 	}
 
 	updated2, _ := model.Update(clickMsg)
 	model2 := updated2.(Model)
 
-	// idx = Y(3) - headerLines(3) + offset(10) = 10
+	// idx = Y(4) - headerLines(4) + offset(10) = 10
 	assert.Equal(t, 10, model2.selected)
 
 	// Click at Y=5 with same offset
@@ -428,13 +439,13 @@ func TestMouseClickWithScrollOffset(t *testing.T) {
 		Action: tea.MouseActionPress,
 		Button: tea.MouseButtonLeft,
 		X:      10,
-		Y:      5,
+		Y:      6,
 	}
 
 	updated3, _ := model.Update(clickMsg2)
 	model3 := updated3.(Model)
 
-	// idx = Y(5) - headerLines(3) + offset(10) = 12
+	// idx = Y(6) - headerLines(4) + offset(10) = 12
 	assert.Equal(t, 12, model3.selected)
 }
 
@@ -497,8 +508,8 @@ func TestMouseClickOutOfBoundsAnalyze(t *testing.T) {
 	}{
 		{"click on header", 0},
 		{"click on separator", 1},
-		{"click below all entries", 3 + len(m.entries) + 5},
-		{"negative index after offset calc", 2}, // Y=2 - 3 + 0 = -1
+		{"click below all entries", 4 + len(m.entries) + 5},
+		{"negative index after offset calc", 2}, // Y=2 - 4 + 0 = -2
 	}
 
 	for _, tt := range tests {
@@ -838,3 +849,758 @@ func TestAnalyze_MetricsDisplay(t *testing.T) {
 	view := m.View()
 	assert.Contains(t, view, "CPU: 25.5%")
 }
+
+func TestAnalyze_PortsView(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "tcp://remote:2376") // host listeners not read
+	mock := &docker.MockDockerService{
+		ListContainersFn: func(context.Context, bool) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{
+				{ID: "web", Name: "web", State: "running", PortBindings: []docker.PortBinding{
+					{HostIP: "0.0.0.0", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+					{HostIP: "::", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+				}},
+				{ID: "old", Name: "web-old", State: "exited"},
+			}, nil
+		},
+		InspectContainerFn: func(context.Context, string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{HostConfig: &container.HostConfig{
+				PortBindings: nat.PortMap{"80/tcp": {{HostPort: "8080"}}},
+			}}}, nil
+		},
+	}
+	m := New(mock, Options{TypeFilter: "ports"})
+	assert.Equal(t, ResourcePorts, m.filterType)
+
+	msg := m.fetchResources()()
+	updated, _ := m.Update(msg)
+	model := updated.(Model)
+	require.Len(t, model.entries, 2)
+	assert.Equal(t, "*:8080/tcp", model.entries[0].Name)
+	assert.Equal(t, []string{"also published by web-old"}, model.entries[0].Conflicts)
+	assert.Equal(t, "0.0.0.0:8080/tcp", model.entries[1].Name, "configured binding of the stopped container")
+	assert.True(t, model.entries[1].IsUnused)
+
+	model.width, model.height = 100, 40
+	view := model.View()
+	assert.Contains(t, view, "Ports")
+	assert.Contains(t, view, "web → 80")
+	assert.Contains(t, view, "(stopped)")
+	assert.Contains(t, view, "also published by web")
+}
+
+// TestAnalyze_ComposeTree tests that project nodes nest the project's
+// volumes and networks and total its entries
+func TestAnalyze_ComposeTree(t *testing.T) {
+	project := map[string]string{docker.ComposeProjectLabel: "shop"}
+	mock := &docker.MockDockerService{
+		ListContainersFn: func(context.Context, bool) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{
+				{ID: "w", Name: "shop-web", State: "running", Size: 100, Labels: map[string]string{docker.ComposeProjectLabel: "shop", docker.ComposeServiceLabel: "web"}},
+				{ID: "d", Name: "shop-db", State: "running", Size: 200, Labels: project},
+				{ID: "j", Name: "shop-job", State: "exited", Labels: project},
+				{ID: "s", Name: "solo", State: "running"},
+			}, nil
+		},
+		GetContainerStatsFn: func(_ context.Context, id string) (*docker.ContainerMetrics, error) {
+			return &docker.ContainerMetrics{CPUPercent: 10, MemoryUsage: 1000}, nil
+		},
+		ListVolumesFn: func(context.Context) ([]docker.VolumeInfo, error) {
+			return []docker.VolumeInfo{
+				{Name: "shop_data", Size: 700, InUse: true, Labels: project},
+				{Name: "scratch"},
+			}, nil
+		},
+		ListNetworksFn: func(context.Context) ([]docker.NetworkInfo, error) {
+			return []docker.NetworkInfo{
+				{ID: "n1", Name: "shop_default", Containers: 3, Labels: project},
+				{ID: "n2", Name: "bridge"},
+			}, nil
+		},
+	}
+	m := New(mock, Options{})
+	updated, _ := m.Update(m.fetchResources()())
+	m = updated.(Model)
+	m.width, m.height = 120, 40
+
+	var names []string
+	for _, e := range m.entries {
+		names = append(names, e.Name)
+	}
+	assert.Equal(t, []string{
+		"Containers", "shop", "shop-web", "shop-db", "shop-job", "shop_data", "shop_default", "solo",
+		"Images", "Volumes", "scratch", "Networks",
+	}, names[:12])
+
+	header := m.entries[1]
+	assert.True(t, header.IsProjectHeader)
+	assert.Equal(t, "2/3 running, 1 volume, 1 network", header.Extra)
+	assert.Equal(t, int64(1000), header.Size, "containers and volumes")
+	assert.Equal(t, 20.0, header.CPUPercent)
+	assert.Equal(t, uint64(2000), header.MemUsage)
+	assert.Equal(t, "shop", m.entries[5].ComposeProject)
+
+	view := m.View()
+	assert.Contains(t, view, "▾ [compose] shop  2/3 running, 1 volume, 1 network")
+	assert.Contains(t, view, "CPU: 20.0%")
+	assert.Regexp(t, `shop_data +\(volume\)`, view)
+
+	// Enter on the header folds the whole node
+	m.selected = 1
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	assert.True(t, m.collapsed["shop"])
+	view = m.View()
+	assert.Contains(t, view, "▸ [compose] shop")
+	assert.NotContains(t, view, "shop_data")
+	assert.Contains(t, view, "solo")
+
+	// The palette unfolds every project, keeping the selection
+	assert.Equal(t, "unfold project shop", m.Commands()[0].Title)
+	updated, _ = m.Update(foldAllMsg{fold: false})
+	m = updated.(Model)
+	assert.Empty(t, m.collapsed)
+	assert.Equal(t, "shop", m.selectedEntry().Name)
+
+	// Folding all moves the selection from a hidden entry to its header
+	m.selected = 5
+	m.foldAll(true)
+	assert.Equal(t, "shop", m.selectedEntry().Name)
+}
+
+// TestAnalyze_LogsView tests the logs view and the confirmed truncate
+func TestAnalyze_LogsView(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("log files are only measured for a daemon on this Linux host")
+	}
+	t.Setenv("DOCKER_HOST", "")
+	logPath := filepath.Join(t.TempDir(), "api-json.log")
+	require.NoError(t, os.WriteFile(logPath, []byte(strings.Repeat("x", 5000)), 0o640))
+
+	logging := map[string]types.ContainerJSON{
+		"api": {ContainerJSONBase: &types.ContainerJSONBase{
+			ID: "api", Name: "/api", LogPath: logPath,
+			HostConfig: &container.HostConfig{LogConfig: container.LogConfig{Type: "json-file"}},
+		}},
+		"db": {ContainerJSONBase: &types.ContainerJSONBase{
+			ID: "db", Name: "/db",
+			HostConfig: &container.HostConfig{LogConfig: container.LogConfig{Type: "journald"}},
+		}},
+	}
+	mock := &docker.MockDockerService{
+		ListContainersFn: func(context.Context, bool) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{{ID: "db", Name: "db"}, {ID: "api", Name: "api"}}, nil
+		},
+		InspectContainerFn: func(_ context.Context, id string) (types.ContainerJSON, error) {
+			return logging[id], nil
+		},
+	}
+	m := New(mock, Options{TypeFilter: "logs"})
+	assert.Equal(t, ResourceLogs, m.filterType)
+
+	updated, _ := m.Update(m.fetchResources()())
+	model := updated.(Model)
+	require.Len(t, model.entries, 2)
+	assert.Equal(t, "api", model.entries[0].Name)
+	assert.Equal(t, int64(5000), model.entries[0].Size)
+	assert.True(t, model.entries[0].Unrotated)
+	assert.True(t, model.entries[0].Selectable)
+	assert.False(t, model.entries[1].Selectable, "journald logs cannot be truncated")
+
+	model.width, model.height = 100, 40
+	assert.Contains(t, model.View(), "json-file, no rotation")
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	require.NotNil(t, cmd)
+	updated, _ = updated.(Model).Update(cmd())
+	model = updated.(Model)
+	require.True(t, model.deleteConfirm)
+	assert.Equal(t, docker.TierHighRisk, model.deleteConfirmInfo.Tier)
+	assert.Contains(t, model.View(), "Confirm truncation?")
+
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	require.NotNil(t, cmd)
+	cmd()
+	st, err := os.Stat(logPath)
+	require.NoError(t, err)
+	assert.Zero(t, st.Size())
+}
+
+// bulkModel returns a model listing entries, as after a fetch
+func bulkModel(mock *docker.MockDockerService, entries ...ResourceEntry) Model {
+	m := New(mock, Options{})
+	updated, _ := m.Update(DataMsg{Entries: entries})
+	return updated.(Model)
+}
+
+func key(s string) tea.KeyMsg {
+	if s == " " {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// TestAnalyze_MarkAllRespectsFilter tests select-all only marks visible entries
+func TestAnalyze_MarkAllRespectsFilter(t *testing.T) {
+	m := bulkModel(&docker.MockDockerService{},
+		ResourceEntry{Type: ResourceContainers, Name: "Containers", IsCategory: true},
+		ResourceEntry{Type: ResourceContainers, ID: "w1", Name: "web-1", Selectable: true},
+		ResourceEntry{Type: ResourceContainers, ID: "w2", Name: "web-2", Selectable: true},
+		ResourceEntry{Type: ResourceContainers, ID: "db", Name: "db", Selectable: true},
+	)
+	m.filterText = "web"
+	m.applyFilter()
+
+	updated, _ := m.Update(key("a"))
+	model := updated.(Model)
+	marked := model.markedEntries()
+	require.Len(t, marked, 2)
+	assert.Equal(t, "web-1", marked[0].Name)
+	assert.Equal(t, "web-2", marked[1].Name)
+	assert.Contains(t, model.View(), "[2 marked]")
+
+	// Toggle again unmarks them; space marks the selected entry
+	updated, _ = model.Update(key("a"))
+	model = updated.(Model)
+	assert.Empty(t, model.markedEntries())
+	model.selected = 1
+	updated, _ = model.Update(key(" "))
+	model = updated.(Model)
+	require.Len(t, model.markedEntries(), 1)
+	assert.Equal(t, "web-1", model.markedEntries()[0].Name)
+
+	// Esc clears the marks before the filter
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(Model)
+	assert.Empty(t, model.markedEntries())
+	assert.Equal(t, "web", model.filterText)
+}
+
+// TestAnalyze_BulkDelete tests the aggregated confirmation and per-item results
+func TestAnalyze_BulkDelete(t *testing.T) {
+	var removed []string
+	imageTier := docker.TierHighRisk
+	mock := &docker.MockDockerService{
+		RemoveContainerDryRunFn: func(context.Context, string) (docker.ConfirmationInfo, error) {
+			return docker.ConfirmationInfo{Tier: docker.TierModerate, Reversible: true, Warnings: []string{"Container is currently running"}}, nil
+		},
+		RemoveImageDryRunFn: func(_ context.Context, id string) (docker.ConfirmationInfo, error) {
+			return docker.ConfirmationInfo{Tier: imageTier, Warnings: []string{"Image is used"}}, nil
+		},
+		RemoveVolumeDryRunFn: func(context.Context, string) (docker.ConfirmationInfo, error) {
+			return docker.ConfirmationInfo{}, errors.New("volume in use")
+		},
+		RemoveContainerFn: func(_ context.Context, id string, _ bool) error {
+			removed = append(removed, id)
+			return nil
+		},
+		RemoveImageFn: func(_ context.Context, id string, _ bool) error {
+			removed = append(removed, id)
+			return nil
+		},
+	}
+	m := bulkModel(mock,
+		ResourceEntry{Type: ResourceContainers, ID: "web", Name: "web", Size: 1000, Selectable: true},
+		ResourceEntry{Type: ResourceImages, ID: "sha-1", Name: "nginx", Size: 2000, Selectable: true},
+		ResourceEntry{Type: ResourceImages, ID: "sha-2", Name: "redis", Size: 4000, Selectable: true},
+		ResourceEntry{Type: ResourceVolumes, ID: "data", Name: "data", Selectable: true},
+	)
+	m.marked = map[string]bool{
+		entryKey(m.entries[0]): true, entryKey(m.entries[1]): true, entryKey(m.entries[3]): true,
+	}
+
+	_, cmd := m.Update(key("d"))
+	require.NotNil(t, cmd)
+	msg := cmd()
+	plan, ok := msg.(BulkConfirmationMsg)
+	require.True(t, ok)
+	assert.Equal(t, docker.TierHighRisk, plan.Info.Tier, "highest tier wins")
+	assert.False(t, plan.Info.Reversible)
+	assert.Equal(t, "Delete 2 Resources?", plan.Info.Title)
+	assert.Contains(t, plan.Info.Description, "3.0 kB")
+	assert.Equal(t, []string{"container: web (1.0 kB)", "image: nginx (2.0 kB)"}, plan.Info.Resources)
+	assert.Contains(t, plan.Info.Warnings, "Skipping data: volume in use")
+
+	updated, _ := m.Update(msg)
+	model := updated.(Model)
+	require.NotNil(t, model.bulkPlan)
+	assert.Contains(t, model.View(), "Confirm delete?")
+
+	// The image became riskier between confirmation and execution
+	imageTier = docker.TierBulkDestructive
+	updated, cmd = model.Update(key("y"))
+	require.NotNil(t, cmd)
+	updated, _ = updated.(Model).Update(cmd())
+	model = updated.(Model)
+
+	assert.Equal(t, []string{"web"}, removed)
+	require.Len(t, model.bulkResults, 3)
+	assert.Empty(t, model.marked)
+	view := model.View()
+	assert.Contains(t, view, "Bulk delete: 1 done, 0 failed, 2 skipped")
+	assert.Contains(t, view, "nginx: skipped (state changed from High Risk to Bulk Destructive)")
+}
+
+// TestAnalyze_BulkStop tests container actions skip other resource types
+func TestAnalyze_BulkStop(t *testing.T) {
+	var stopped []string
+	mock := &docker.MockDockerService{
+		StopContainerFn: func(_ context.Context, id string) error {
+			stopped = append(stopped, id)
+			if id == "api" {
+				return errors.New("timeout")
+			}
+			return nil
+		},
+	}
+	m := bulkModel(mock,
+		ResourceEntry{Type: ResourceContainers, ID: "web", Name: "web", Selectable: true},
+		ResourceEntry{Type: ResourceContainers, ID: "api", Name: "api", Selectable: true},
+		ResourceEntry{Type: ResourceImages, ID: "sha-1", Name: "nginx", Selectable: true},
+	)
+	updated, _ := m.Update(key("a"))
+	_, cmd := updated.(Model).Update(key("t"))
+	plan := cmd().(BulkConfirmationMsg)
+	require.Len(t, plan.Items, 2)
+	assert.Equal(t, docker.TierModerate, plan.Info.Tier)
+	assert.Equal(t, "Stop 2 Containers?", plan.Info.Title)
+
+	updated, _ = updated.(Model).Update(plan)
+	_, cmd = updated.(Model).Update(key("y"))
+	updated, _ = updated.(Model).Update(cmd())
+	view := updated.(Model).View()
+
+	assert.Equal(t, []string{"web", "api"}, stopped)
+	assert.Contains(t, view, "Bulk stop: 1 done, 1 failed, 1 skipped")
+	assert.Contains(t, view, "api: timeout")
+	assert.Contains(t, view, "nginx: skipped (not a container)")
+}
+
+// TestMouseWheelMovesSelection tests that the wheel moves the selection
+func TestMouseWheelMovesSelection(t *testing.T) {
+	m := createAnalyzeModelWithEntries()
+	wheel := func(m Model, b tea.MouseButton) Model {
+		updated, _ := m.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: b})
+		return updated.(Model)
+	}
+
+	m = wheel(m, tea.MouseButtonWheelDown)
+	m = wheel(m, tea.MouseButtonWheelDown)
+	assert.Equal(t, 2, m.selected)
+	m = wheel(m, tea.MouseButtonWheelUp)
+	assert.Equal(t, 1, m.selected)
+}
+
+// TestMouseClickHeadingSorts tests that clicking a sort heading sorts by it
+// and clicking it again reverses the order
+func TestMouseClickHeadingSorts(t *testing.T) {
+	m := createAnalyzeModelWithEntries()
+	headingY := strings.Count(m.renderListTop(), "\n") - 1
+	click := func(m Model, heading string) Model {
+		line := strings.Split(m.View(), "\n")[headingY]
+		x := strings.Index(line, heading)
+		require.GreaterOrEqual(t, x, 0, "heading %s in %q", heading, line)
+		updated, _ := m.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonLeft, X: x, Y: headingY})
+		return updated.(Model)
+	}
+
+	m = click(m, "SIZE")
+	assert.Equal(t, SortSize, m.sortKey)
+	assert.False(t, m.sortReverse)
+	assert.Equal(t, "db-server", m.entries[1].Name, "largest first")
+	assert.Contains(t, m.View(), "SIZE↓")
+
+	m = click(m, "SIZE")
+	assert.Equal(t, SortSize, m.sortKey)
+	assert.True(t, m.sortReverse)
+	assert.Equal(t, "web-app", m.entries[1].Name)
+
+	m = click(m, "NAME")
+	assert.Equal(t, SortName, m.sortKey)
+	assert.False(t, m.sortReverse)
+}
+
+// TestMouseClickProjectHeaderFolds tests that clicking a Compose project
+// header collapses and expands its containers
+func TestMouseClickProjectHeaderFolds(t *testing.T) {
+	m := New(&docker.MockDockerService{}, Options{})
+	updated, _ := m.Update(DataMsg{Entries: []ResourceEntry{
+		{Type: ResourceContainers, Name: "[shop] (2 containers)", ProjectName: "shop", IsProjectHeader: true, Selectable: true},
+		{Type: ResourceContainers, ID: "1", Name: "shop-web", ComposeProject: "shop", Selectable: true},
+		{Type: ResourceContainers, ID: "2", Name: "shop-db", ComposeProject: "shop", Selectable: true},
+		{Type: ResourceContainers, ID: "3", Name: "solo", Selectable: true},
+	}})
+	m = updated.(Model)
+	m.height = 40
+	top := strings.Count(m.renderListTop(), "\n")
+	click := func(m Model) Model {
+		updated, _ := m.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonLeft, X: 4, Y: top})
+		return updated.(Model)
+	}
+
+	m = click(m)
+	assert.Equal(t, 0, m.selected)
+	require.Len(t, m.visibleEntries(), 2)
+	assert.Equal(t, "solo", m.visibleEntries()[1].Name)
+	assert.Contains(t, m.View(), "▸ [compose]")
+	assert.NotContains(t, m.View(), "shop-web")
+
+	// A filter searches the folded containers too
+	m.filterText = "web"
+	m.applyFilter()
+	assert.Equal(t, "shop-web", m.visibleEntries()[0].Name)
+	m.filterText = ""
+	m.applyFilter()
+
+	m = click(m)
+	assert.Len(t, m.visibleEntries(), 4)
+	assert.Contains(t, m.View(), "▾ [compose]")
+}
+
+// TestMouseWheelScrollsLogs tests that the wheel scrolls the logs view
+func TestMouseWheelScrollsLogs(t *testing.T) {
+	m := createAnalyzeModelWithEntries()
+	m.height = 20
+	m.viewMode = viewLogs
+	for i := 0; i < 50; i++ {
+		m.logEntries = append(m.logEntries, docker.LogEntry{Content: fmt.Sprintf("line %d", i)})
+	}
+	m.logFollowing = true
+
+	updated, _ := m.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown})
+	m = updated.(Model)
+	assert.Equal(t, 3, m.logOffset)
+
+	updated, _ = m.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelUp})
+	m = updated.(Model)
+	assert.Equal(t, 0, m.logOffset)
+	assert.False(t, m.logFollowing)
+}
+
+// TestSortEntries tests sorting stays within categories and Compose projects
+func TestSortEntries(t *testing.T) {
+	entries := []ResourceEntry{
+		{Type: ResourceContainers, Name: "Containers", IsCategory: true},
+		{Type: ResourceContainers, Name: "[shop]", IsProjectHeader: true},
+		{Type: ResourceContainers, ID: "1", Name: "shop-web", ComposeProject: "shop", CPUPercent: 5},
+		{Type: ResourceContainers, ID: "2", Name: "shop-db", ComposeProject: "shop", CPUPercent: 40},
+		{Type: ResourceContainers, ID: "3", Name: "zeta", CPUPercent: 1},
+		{Type: ResourceContainers, ID: "4", Name: "alpha", CPUPercent: 90},
+		{Type: ResourceImages, Name: "Images", IsCategory: true},
+		{Type: ResourceImages, ID: "i1", Name: "old", Size: 10, Created: time.Unix(100, 0)},
+		{Type: ResourceImages, ID: "i2", Name: "new", Size: 5, Created: time.Unix(200, 0)},
+	}
+	names := func(es []ResourceEntry) []string {
+		var out []string
+		for _, e := range es {
+			out = append(out, e.Name)
+		}
+		return out
+	}
+
+	assert.Equal(t, names(entries), names(sortEntries(entries, SortDefault, true)))
+	assert.Equal(t,
+		[]string{"Containers", "[shop]", "shop-db", "shop-web", "alpha", "zeta", "Images", "new", "old"},
+		names(sortEntries(entries, SortName, false)))
+	assert.Equal(t,
+		[]string{"Containers", "[shop]", "shop-db", "shop-web", "alpha", "zeta", "Images", "old", "new"},
+		names(sortEntries(entries, SortCPU, false)))
+	assert.Equal(t,
+		[]string{"Containers", "[shop]", "shop-web", "shop-db", "zeta", "alpha", "Images", "old", "new"},
+		names(sortEntries(entries, SortCreated, true)))
+}
+
+// TestAnalyze_SortAndColumnsPersist tests the sort and column keys save the view
+func TestAnalyze_SortAndColumnsPersist(t *testing.T) {
+	var saved []config.AnalyzeConfig
+	m := New(&docker.MockDockerService{}, Options{
+		View: config.AnalyzeConfig{Sort: "size"},
+		Persist: func(c config.AnalyzeConfig) error {
+			saved = append(saved, c)
+			return nil
+		},
+	})
+	updated, _ := m.Update(DataMsg{Entries: []ResourceEntry{
+		{Type: ResourceContainers, ID: "abc123def456789", Name: "small", Size: 1, Ports: "0.0.0.0:80->80/tcp", Selectable: true},
+		{Type: ResourceContainers, ID: "fedcba987654321", Name: "large", Size: 100, Selectable: true},
+	}})
+	model := updated.(Model)
+	assert.Equal(t, "large", model.entries[0].Name)
+	assert.Contains(t, model.View(), "by size ↓")
+
+	updated, cmd := model.Update(key("O"))
+	model = updated.(Model)
+	require.NotNil(t, cmd)
+	cmd()
+	assert.Equal(t, "small", model.entries[0].Name)
+	assert.Equal(t, config.AnalyzeConfig{Sort: "size", SortReverse: true, Columns: []string{"service"}}, saved[0])
+
+	// Turn on the ID column, then close the picker
+	updated, _ = model.Update(key("c"))
+	updated, _ = updated.(Model).Update(key("j"))
+	updated, _ = updated.(Model).Update(key(" "))
+	model = updated.(Model)
+	assert.Contains(t, model.View(), "[x] id")
+	updated, cmd = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(Model)
+	require.NotNil(t, cmd)
+	cmd()
+	assert.False(t, model.pickingColumns)
+	assert.Equal(t, []string{"service", "id"}, saved[1].Columns)
+	view := model.View()
+	assert.Contains(t, view, "abc123def456")
+	assert.NotContains(t, view, "0.0.0.0:80->80/tcp", "ports column is off")
+
+	updated, _ = model.Update(viewSavedMsg{Err: errors.New("read-only file system")})
+	assert.Contains(t, updated.(Model).View(), "View settings not saved: read-only file system")
+}
+
+// TestAnalyze_PaletteActsOnNamedEntry tests palette commands select their
+// entry and leave the marks alone
+func TestAnalyze_PaletteActsOnNamedEntry(t *testing.T) {
+	var stopped []string
+	mock := &docker.MockDockerService{
+		StopContainerFn: func(_ context.Context, id string) error {
+			stopped = append(stopped, id)
+			return nil
+		},
+	}
+	m := bulkModel(mock,
+		ResourceEntry{Type: ResourceContainers, ID: "web", Name: "web", Selectable: true},
+		ResourceEntry{Type: ResourceContainers, ID: "api", Name: "api", Selectable: true},
+	)
+	updated, _ := m.Update(key(" ")) // mark web
+	updated, _ = updated.(Model).Update(key(":"))
+	for _, r := range "stop api" {
+		updated, _ = updated.(Model).Update(key(string(r)))
+	}
+	model := updated.(Model)
+	assert.True(t, model.CapturingInput())
+	assert.Contains(t, model.View(), "stop api")
+	assert.Equal(t, "stop 1 marked", model.Commands()[1].Title)
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated, cmd = model.Update(cmd())
+	require.NotNil(t, cmd)
+	cmd()
+	model = updated.(Model)
+	assert.Equal(t, []string{"api"}, stopped)
+	assert.Equal(t, "api", model.selectedEntry().Name)
+	assert.Len(t, model.markedEntries(), 1)
+	assert.False(t, model.CapturingInput())
+}
+
+// TestAnalyze_CustomKeyBindings tests actions follow the configured keymap
+// and the help overlay lists it
+func TestAnalyze_CustomKeyBindings(t *testing.T) {
+	defer common.SetKeyMap(common.Keys)
+	keys, err := common.NewKeyMap("", map[string][]string{"stop": {"T"}})
+	require.NoError(t, err)
+	common.SetKeyMap(keys)
+
+	var stopped []string
+	mock := &docker.MockDockerService{
+		StopContainerFn: func(_ context.Context, id string) error {
+			stopped = append(stopped, id)
+			return nil
+		},
+	}
+	m := bulkModel(mock, ResourceEntry{Type: ResourceContainers, ID: "web", Name: "web", Selectable: true})
+
+	_, cmd := m.Update(key("t"))
+	assert.Nil(t, cmd)
+	_, cmd = m.Update(key("T"))
+	require.NotNil(t, cmd)
+	cmd()
+	assert.Equal(t, []string{"web"}, stopped)
+
+	updated, _ := m.Update(key("?"))
+	help := updated.(Model).View()
+	assert.Contains(t, help, "Octo Analyzer - Keys")
+	assert.Contains(t, help, "Stop container or project")
+	assert.Contains(t, updated.(Model).Commands()[1].Key, "T")
+
+	updated, _ = updated.(Model).Update(key("x"))
+	assert.Contains(t, updated.(Model).View(), "s/T/r: start/stop/restart")
+}
+
+// relationsMock serves a volume mounted by two containers of one image
+func relationsMock() *docker.MockDockerService {
+	return &docker.MockDockerService{
+		ListContainersFn: func(context.Context, bool) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{
+				{ID: "c1", Name: "db", State: "running", Image: "postgres", ImageID: "i1", Volumes: []string{"pgdata"}, Networks: []string{"shop"}},
+				{ID: "c2", Name: "backup", State: "exited", Image: "postgres", ImageID: "i1", Volumes: []string{"pgdata"}},
+			}, nil
+		},
+		ListImagesFn: func(context.Context, bool) ([]docker.ImageInfo, error) {
+			return []docker.ImageInfo{{ID: "i1", Repository: "postgres", Tag: "16", Containers: -1}}, nil
+		},
+		ListVolumesFn: func(context.Context) ([]docker.VolumeInfo, error) {
+			return []docker.VolumeInfo{{Name: "pgdata", InUse: true}}, nil
+		},
+		ListNetworksFn: func(context.Context) ([]docker.NetworkInfo, error) {
+			return []docker.NetworkInfo{{ID: "n1", Name: "shop"}}, nil
+		},
+		RemoveVolumeDryRunFn: func(context.Context, string) (docker.ConfirmationInfo, error) {
+			return docker.ConfirmationInfo{Tier: docker.TierHighRisk, Title: "Delete Volume?"}, nil
+		},
+	}
+}
+
+// TestAnalyze_RelationsView tests the relationships of a volume, moving to
+// those of a container and back
+func TestAnalyze_RelationsView(t *testing.T) {
+	m := bulkModel(relationsMock(),
+		ResourceEntry{Type: ResourceVolumes, ID: "pgdata", Name: "pgdata", Selectable: true})
+	m.width, m.height = 100, 40
+
+	updated, cmd := m.Update(key("R"))
+	m = updated.(Model)
+	require.NotNil(t, cmd)
+	assert.Contains(t, m.View(), "Loading relationships...")
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	view := m.View()
+	assert.Contains(t, view, "[volume] pgdata")
+	assert.Contains(t, view, "Used by")
+	assert.Contains(t, view, "[container] db (running)  mounts")
+	assert.Contains(t, view, "[container] backup (exited)  mounts")
+	assert.Contains(t, view, "└ [network] shop  joins")
+	assert.NotContains(t, view, "Uses")
+
+	// The first container, backup, is below the heading; enter shows its
+	// relations
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	view = m.View()
+	assert.Contains(t, view, "Uses")
+	assert.Contains(t, view, "[image] postgres:16  runs")
+	assert.Contains(t, view, "└ [container] db (running)  also mounts")
+
+	// Back returns to the volume, then to the list
+	updated, _ = m.Update(key("h"))
+	m = updated.(Model)
+	assert.Equal(t, "volume/pgdata", m.graphRoot)
+	updated, _ = m.Update(key("h"))
+	m = updated.(Model)
+	assert.Equal(t, viewList, m.viewMode)
+	assert.Contains(t, m.View(), "Octo Analyzer")
+}
+
+// TestAnalyze_DeleteWarnsOfReferences tests deleting a volume lists the
+// containers mounting it
+func TestAnalyze_DeleteWarnsOfReferences(t *testing.T) {
+	m := bulkModel(relationsMock(),
+		ResourceEntry{Type: ResourceVolumes, ID: "pgdata", Name: "pgdata", Selectable: true})
+
+	updated, cmd := m.Update(key("d"))
+	require.NotNil(t, cmd)
+	msg := cmd().(ConfirmationMsg)
+	require.NoError(t, msg.Err)
+	assert.Equal(t, []string{
+		"Used by container backup (exited)",
+		"Used by container db (running)",
+	}, msg.Info.Warnings)
+
+	updated, _ = updated.(Model).Update(msg)
+	assert.Contains(t, updated.(Model).View(), "Used by container backup (exited)")
+}
+
+// TestAnalyze_NetworkView tests the network drill-down lists addresses and
+// connects and disconnects containers after confirmation
+func TestAnalyze_NetworkView(t *testing.T) {
+	var connected, disconnected []string
+	disconnectTier := docker.TierModerate
+	mock := &docker.MockDockerService{
+		InspectNetworkFn: func(_ context.Context, id string) (docker.NetworkDetails, error) {
+			assert.Equal(t, "n1", id)
+			return docker.NetworkDetails{
+				NetworkInfo: docker.NetworkInfo{ID: "n1", Name: "shop", Driver: "bridge", Scope: "local"},
+				IPAMDriver:  "default",
+				IPAM:        []docker.IPAMPool{{Subnet: "172.18.0.0/16", Gateway: "172.18.0.1"}},
+				Endpoints: []docker.NetworkEndpoint{
+					{ContainerID: "c1", Container: "db", State: "running", IPv4Address: "172.18.0.2/16", MacAddress: "02:42:ac:12:00:02", Aliases: []string{"db", "postgres"}},
+					{ContainerID: "c2", Container: "job", State: "exited"},
+				},
+			}, nil
+		},
+		ListContainersFn: func(context.Context, bool) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{
+				{ID: "c1", Name: "db", State: "running"},
+				{ID: "c3", Name: "web", State: "running"},
+			}, nil
+		},
+		DisconnectNetworkDryRunFn: func(_ context.Context, network, ctr string) (docker.ConfirmationInfo, error) {
+			return docker.ConfirmationInfo{Tier: disconnectTier, Title: "Disconnect Container?", Description: "Disconnect '" + ctr + "'"}, nil
+		},
+		ConnectNetworkDryRunFn: func(_ context.Context, network, ctr string) (docker.ConfirmationInfo, error) {
+			return docker.ConfirmationInfo{Tier: docker.TierLowRisk, Title: "Connect Container?"}, nil
+		},
+		DisconnectNetworkFn: func(_ context.Context, network, ctr string) error {
+			disconnected = append(disconnected, network+"/"+ctr)
+			return nil
+		},
+		ConnectNetworkFn: func(_ context.Context, network, ctr string) error {
+			connected = append(connected, network+"/"+ctr)
+			return nil
+		},
+	}
+	m := bulkModel(mock, ResourceEntry{Type: ResourceNetworks, ID: "n1", Name: "shop", Selectable: true})
+	m.width, m.height = 120, 40
+
+	// run feeds the commands' messages back, as the runtime would
+	run := func(m Model, msg tea.Msg) Model {
+		updated, cmd := m.Update(msg)
+		for cmd != nil {
+			updated, cmd = updated.Update(cmd())
+		}
+		return updated.(Model)
+	}
+
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, viewNetwork, m.viewMode)
+	view := m.View()
+	assert.Contains(t, view, "Network: shop")
+	assert.Contains(t, view, "Subnet: 172.18.0.0/16   Gateway: 172.18.0.1")
+	assert.Contains(t, view, "Containers (2)")
+	assert.Regexp(t, `db +172\.18\.0\.2/16 +02:42:ac:12:00:02 +db, postgres`, view)
+	assert.Regexp(t, `job +- +\(exited\)`, view)
+
+	// Disconnecting asks first; declining changes nothing
+	m = run(m, key("D"))
+	assert.Contains(t, m.View(), "Disconnect 'c1'")
+	m = run(m, key("n"))
+	assert.Empty(t, disconnected)
+	m = run(m, key("D"))
+	m = run(m, key("y"))
+	assert.Equal(t, []string{"n1/c1"}, disconnected)
+	assert.Contains(t, m.View(), "Done: disconnect db")
+
+	// Connecting offers the containers not attached
+	m = run(m, key("C"))
+	view = m.View()
+	assert.Contains(t, view, "Connect container")
+	assert.Regexp(t, `web +running`, view)
+	assert.NotRegexp(t, `db +running`, view)
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Contains(t, m.View(), "Connect Container?")
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, []string{"n1/c3"}, connected)
+
+	// A disconnect whose risk rose during confirmation is aborted
+	m = run(m, key("D"))
+	disconnectTier = docker.TierHighRisk
+	m = run(m, key("y"))
+	assert.Equal(t, []string{"n1/c1"}, disconnected)
+	assert.Contains(t, m.View(), "aborted for safety")
+
+	// Leaving refreshes the list, as attachments changed
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	assert.Equal(t, viewList, m.viewMode)
+	assert.True(t, m.loading)
+	assert.NotNil(t, cmd)
+}
@@ -0,0 +1,72 @@
+package analyze
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bsisduck/octo/internal/tui/common"
+)
+
+// updateMouse handles the mouse: the wheel scrolls, and a click selects an
+// entry, sorts by a heading or folds a Compose project.
+func (m Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.loading || m.err != nil || m.palette != nil || m.showHelp {
+		return m, nil
+	}
+	if m.viewMode == viewLogs {
+		if d := common.Wheel(msg); d != 0 {
+			m.scrollLogs(d * common.WheelLines)
+		}
+		return m, nil
+	}
+	if m.viewMode == viewNetwork {
+		if d := common.Wheel(msg); d != 0 && m.networkConfirm == nil && m.connectChoices == nil {
+			m.moveEndpoint(d)
+		}
+		return m, nil
+	}
+	if m.viewMode == viewGraph {
+		if d := common.Wheel(msg); d != 0 && m.graph != nil {
+			m.moveRelation(d)
+		}
+		return m, nil
+	}
+	if m.deleteConfirm || m.bulkPlan != nil || m.pickingColumns {
+		return m, nil // Don't process the mouse during confirmation
+	}
+	if d := common.Wheel(msg); d != 0 {
+		m.moveSelection(d)
+		return m, nil
+	}
+	if !common.LeftClick(msg) {
+		return m, nil
+	}
+
+	// The sort headings are the last line above the entries.
+	top := strings.Count(m.renderListTop(), "\n")
+	if msg.Y == top-1 {
+		return m, m.clickHeading(msg.X)
+	}
+	row := msg.Y - top
+	visible := m.visibleEntries()
+	idx := row + m.offset
+	if row < 0 || row >= m.listViewport() || idx >= len(visible) {
+		return m, nil
+	}
+	m.selected = idx
+	if e := visible[idx]; e.IsProjectHeader {
+		m.toggleProject(e.ProjectName)
+	}
+	return m, nil
+}
+
+// scrollLogs moves the logs view by delta lines; scrolling up stops
+// following.
+func (m *Model) scrollLogs(delta int) {
+	maxOffset := max(0, len(m.visibleLogEntries())-m.logViewportHeight())
+	m.logOffset = min(max(m.logOffset+delta, 0), maxOffset)
+	if delta < 0 {
+		m.logFollowing = false
+	}
+}
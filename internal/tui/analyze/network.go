@@ -0,0 +1,409 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/tui/common"
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+// networkOp is a container to connect to or disconnect from the network
+// shown.
+type networkOp struct {
+	connect     bool
+	containerID string
+	container   string
+}
+
+func (op networkOp) String() string {
+	if op.connect {
+		return "connect " + op.container
+	}
+	return "disconnect " + op.container
+}
+
+// networkLoadedMsg carries the details of the network view.
+type networkLoadedMsg struct {
+	details docker.NetworkDetails
+	err     error
+}
+
+// connectChoicesMsg carries the containers that can be connected.
+type connectChoicesMsg struct {
+	containers []docker.ContainerInfo
+	err        error
+}
+
+// networkConfirmMsg carries the dry run of a connect or disconnect.
+type networkConfirmMsg struct {
+	op   networkOp
+	info docker.ConfirmationInfo
+	err  error
+}
+
+// networkDoneMsg reports a connect or disconnect.
+type networkDoneMsg struct {
+	op  networkOp
+	err error
+}
+
+// openNetwork switches to the view of a network.
+func (m *Model) openNetwork(e ResourceEntry) tea.Cmd {
+	m.viewMode = viewNetwork
+	m.network = nil
+	m.networkID = e.ID
+	m.networkSelected = 0
+	m.networkChanged = false
+	m.networkPending = nil
+	m.networkConfirm = nil
+	m.connectChoices = nil
+	return m.loadNetwork()
+}
+
+// loadNetwork inspects the network of the view.
+func (m Model) loadNetwork() tea.Cmd {
+	id := m.networkID
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), docker.TimeoutList)
+		defer cancel()
+		details, err := m.docker.InspectNetwork(ctx, id)
+		return networkLoadedMsg{details: details, err: err}
+	}
+}
+
+// loadConnectChoices lists the containers not attached to the network.
+func (m Model) loadConnectChoices() tea.Cmd {
+	attached := make(map[string]bool)
+	for _, ep := range m.network.Endpoints {
+		attached[ep.ContainerID] = true
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), docker.TimeoutList)
+		defer cancel()
+		containers, err := m.docker.ListContainers(ctx, true)
+		if err != nil {
+			return connectChoicesMsg{err: err}
+		}
+		var choices []docker.ContainerInfo
+		for _, c := range containers {
+			if !attached[c.ID] {
+				choices = append(choices, c)
+			}
+		}
+		return connectChoicesMsg{containers: choices}
+	}
+}
+
+// planNetworkOp dry-runs a connect or disconnect for confirmation.
+func (m Model) planNetworkOp(op networkOp) tea.Cmd {
+	networkID := m.networkID
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), docker.TimeoutList)
+		defer cancel()
+		info, err := m.networkDryRun(ctx, networkID, op)
+		return networkConfirmMsg{op: op, info: info, err: err}
+	}
+}
+
+// networkDryRun assesses a connect or disconnect.
+func (m Model) networkDryRun(ctx context.Context, networkID string, op networkOp) (docker.ConfirmationInfo, error) {
+	if op.connect {
+		return m.docker.ConnectNetworkDryRun(ctx, networkID, op.containerID)
+	}
+	return m.docker.DisconnectNetworkDryRun(ctx, networkID, op.containerID)
+}
+
+// runNetworkOp connects or disconnects the container, confirmed at tier.
+// As for deletes, the dry run is repeated first and the operation aborted
+// if the container's state changed its tier meanwhile.
+func (m Model) runNetworkOp(op networkOp, tier docker.SafetyTier) tea.Cmd {
+	networkID := m.networkID
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), docker.TimeoutAction)
+		defer cancel()
+		current, err := m.networkDryRun(ctx, networkID, op)
+		if err != nil {
+			return networkDoneMsg{op: op, err: fmt.Errorf("state changed during confirmation: %w", err)}
+		}
+		if current.Tier != tier {
+			return networkDoneMsg{op: op, err: fmt.Errorf("state changed from %s to %s; aborted for safety", tier, current.Tier)}
+		}
+		if op.connect {
+			err = m.docker.ConnectNetwork(ctx, networkID, op.containerID)
+		} else {
+			err = m.docker.DisconnectNetwork(ctx, networkID, op.containerID)
+		}
+		return networkDoneMsg{op: op, err: err}
+	}
+}
+
+// updateNetworkMsg handles the results of the network view's commands.
+func (m Model) updateNetworkMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case networkLoadedMsg:
+		if msg.err != nil {
+			m.closeNetwork()
+			m.statusMessage = fmt.Sprintf("Network: %v", msg.err)
+			return m, nil
+		}
+		m.network = &msg.details
+		m.networkSelected = min(m.networkSelected, max(0, len(msg.details.Endpoints)-1))
+	case connectChoicesMsg:
+		switch {
+		case msg.err != nil:
+			m.statusMessage = fmt.Sprintf("Listing containers: %v", msg.err)
+		case len(msg.containers) == 0:
+			m.statusMessage = "Every container is connected already"
+		default:
+			m.connectChoices = msg.containers
+			m.connectCursor = 0
+		}
+	case networkConfirmMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Cannot %s: %v", msg.op, msg.err)
+			return m, nil
+		}
+		m.networkPending = &msg.op
+		m.networkConfirm = &msg.info
+	case networkDoneMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Failed to %s: %v", msg.op, msg.err)
+		} else {
+			m.statusMessage = fmt.Sprintf("Done: %s", msg.op)
+			m.networkChanged = true
+		}
+		return m, m.loadNetwork()
+	}
+	return m, nil
+}
+
+// closeNetwork returns to the resource list, refreshing it if containers
+// were connected or disconnected.
+func (m *Model) closeNetwork() tea.Cmd {
+	m.viewMode = viewList
+	m.network = nil
+	m.networkPending = nil
+	m.networkConfirm = nil
+	m.connectChoices = nil
+	if m.networkChanged {
+		m.networkChanged = false
+		m.loading = true
+		return m.fetchResources()
+	}
+	return nil
+}
+
+// updateNetworkView handles keys in the network view.
+func (m Model) updateNetworkView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := common.Keys
+	if m.networkConfirm != nil {
+		switch msg.String() {
+		case "y", "Y", "enter":
+			op, tier := *m.networkPending, m.networkConfirm.Tier
+			m.networkPending, m.networkConfirm = nil, nil
+			return m, m.runNetworkOp(op, tier)
+		case "n", "N", "esc", "q":
+			m.networkPending, m.networkConfirm = nil, nil
+		}
+		return m, nil
+	}
+	if m.connectChoices != nil {
+		switch {
+		case keys.Is(msg, common.ActUp):
+			m.connectCursor = max(0, m.connectCursor-1)
+		case keys.Is(msg, common.ActDown):
+			m.connectCursor = min(len(m.connectChoices)-1, m.connectCursor+1)
+		case keys.Is(msg, common.ActChoose):
+			c := m.connectChoices[m.connectCursor]
+			m.connectChoices = nil
+			return m, m.planNetworkOp(networkOp{connect: true, containerID: c.ID, container: c.Name})
+		case keys.Is(msg, common.ActCancel), keys.Is(msg, common.ActConnect), keys.Is(msg, common.ActQuit):
+			m.connectChoices = nil
+		}
+		return m, nil
+	}
+
+	switch {
+	case keys.Is(msg, common.ActCancel), keys.Is(msg, common.ActQuit), keys.Is(msg, common.ActBack):
+		return m, m.closeNetwork()
+	case m.network == nil:
+	case keys.Is(msg, common.ActUp):
+		m.moveEndpoint(-1)
+	case keys.Is(msg, common.ActDown):
+		m.moveEndpoint(1)
+	case keys.Is(msg, common.ActConnect):
+		return m, m.loadConnectChoices()
+	case keys.Is(msg, common.ActDisconnect):
+		if m.networkSelected < len(m.network.Endpoints) {
+			ep := m.network.Endpoints[m.networkSelected]
+			return m, m.planNetworkOp(networkOp{containerID: ep.ContainerID, container: ep.Container})
+		}
+	case keys.Is(msg, common.ActCopy):
+		if m.networkSelected < len(m.network.Endpoints) {
+			return m, copyText(endpointText(m.network.Endpoints[m.networkSelected]))
+		}
+	case keys.Is(msg, common.ActRefresh):
+		return m, m.loadNetwork()
+	}
+	return m, nil
+}
+
+// moveEndpoint moves the selection among the attached containers.
+func (m *Model) moveEndpoint(delta int) {
+	if m.network == nil || len(m.network.Endpoints) == 0 {
+		return
+	}
+	m.networkSelected = min(max(m.networkSelected+delta, 0), len(m.network.Endpoints)-1)
+}
+
+// endpointText formats an attachment for the clipboard.
+func endpointText(ep docker.NetworkEndpoint) string {
+	parts := []string{fmt.Sprintf("Container: %s", ep.Container)}
+	if ep.IPv4Address != "" {
+		parts = append(parts, fmt.Sprintf("IPv4: %s", ep.IPv4Address))
+	}
+	if ep.IPv6Address != "" {
+		parts = append(parts, fmt.Sprintf("IPv6: %s", ep.IPv6Address))
+	}
+	if ep.MacAddress != "" {
+		parts = append(parts, fmt.Sprintf("MAC: %s", ep.MacAddress))
+	}
+	if len(ep.Aliases) > 0 {
+		parts = append(parts, fmt.Sprintf("Aliases: %s", strings.Join(ep.Aliases, ", ")))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// renderNetworkView renders the network's addressing and its containers.
+func (m Model) renderNetworkView() string {
+	var b strings.Builder
+	name := m.networkID
+	if m.network != nil {
+		name = m.network.Name
+	}
+	b.WriteString(styles.Title.Render("Network: " + name))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", 60))
+	b.WriteString("\n\n")
+
+	if m.network == nil {
+		b.WriteString(styles.Info.Render("Loading network..."))
+		b.WriteString("\n")
+		return b.String()
+	}
+	n := m.network
+
+	field := func(label, value string) string {
+		return styles.Label.Render(label+": ") + value
+	}
+	b.WriteString(field("Driver", n.Driver) + "   " + field("Scope", n.Scope) + "   " + field("IPAM", n.IPAMDriver))
+	b.WriteString("\n")
+	for _, pool := range n.IPAM {
+		line := field("Subnet", pool.Subnet)
+		if pool.Gateway != "" {
+			line += "   " + field("Gateway", pool.Gateway)
+		}
+		if pool.IPRange != "" {
+			line += "   " + field("Range", pool.IPRange)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	var flags []string
+	if n.Internal {
+		flags = append(flags, "internal")
+	}
+	if n.IPv6 {
+		flags = append(flags, "IPv6")
+	}
+	if n.Attachable {
+		flags = append(flags, "attachable")
+	}
+	if len(flags) > 0 {
+		b.WriteString(field("Flags", strings.Join(flags, ", ")))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if m.networkConfirm != nil {
+		b.WriteString(common.RenderConfirmation(*m.networkConfirm, m.networkPending.String()))
+		b.WriteString("\n\n")
+	}
+	if m.connectChoices != nil {
+		b.WriteString(m.renderConnectPicker())
+		b.WriteString("\n")
+	}
+
+	b.WriteString(styles.Section.Render(fmt.Sprintf("Containers (%d)", len(n.Endpoints))))
+	b.WriteString("\n")
+	if len(n.Endpoints) == 0 {
+		b.WriteString(styles.Label.Render("  No containers attached"))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(styles.Label.Render(fmt.Sprintf("  %-24s %-20s %-18s %s", "NAME", "ADDRESS", "MAC", "ALIASES")))
+		b.WriteString("\n")
+	}
+	for i, ep := range n.Endpoints {
+		address := ep.IPv4Address
+		if address == "" {
+			address = ep.IPv6Address
+		}
+		if address == "" {
+			address = "-"
+		}
+		line := fmt.Sprintf("  %-24s %-20s %-18s %s", ep.Container, address, ep.MacAddress, strings.Join(ep.Aliases, ", "))
+		if ep.State != "running" {
+			line += styles.Warning.Render(" (" + ep.State + ")")
+		}
+		if i == m.networkSelected {
+			line = styles.Selected.Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	if m.statusMessage != "" {
+		b.WriteString("\n")
+		b.WriteString(styles.Info.Render("  " + m.statusMessage))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", 60))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render(common.ShortHelp(
+		common.Item("navigate", common.ActUp, common.ActDown),
+		common.Item("connect", common.ActConnect),
+		common.Item("disconnect", common.ActDisconnect),
+		common.Item("copy", common.ActCopy),
+		common.Item("refresh", common.ActRefresh),
+		common.Item("back", common.ActCancel),
+	)))
+	return b.String()
+}
+
+// renderConnectPicker renders the containers that can be connected.
+func (m Model) renderConnectPicker() string {
+	var b strings.Builder
+	b.WriteString(styles.Section.Render("Connect container"))
+	b.WriteString("\n")
+	for i, c := range m.connectChoices {
+		line := fmt.Sprintf("  %-32s %s", c.Name, c.State)
+		if i == m.connectCursor {
+			line = styles.Selected.Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.Help.Render("  " + common.ShortHelp(
+		common.Item("connect", common.ActChoose),
+		common.Item("cancel", common.ActCancel),
+	)))
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,174 @@
+package analyze
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bsisduck/octo/internal/tui/common"
+	"github.com/bsisduck/octo/internal/tui/palette"
+)
+
+// paletteRunMsg runs a palette command: it selects the entry, if any, and
+// runs the action on it, as its key would.
+type paletteRunMsg struct {
+	entry  string // entryRef of the entry to act on; "" for the view
+	action common.Action
+}
+
+// entryRef identifies an entry across refreshes, project headers included.
+func entryRef(e ResourceEntry) string {
+	if e.IsProjectHeader {
+		return "project/" + e.ProjectName
+	}
+	return entryKey(e)
+}
+
+// Commands returns the palette commands of the view: the actions on the
+// marked entries, on the selection, on every other entry by name, then the
+// view's own.
+func (m Model) Commands() []palette.Command {
+	if m.loading || m.err != nil || m.viewMode != viewList {
+		return nil
+	}
+
+	var cmds []palette.Command
+	if n := len(m.markedEntries()); n > 0 {
+		for _, a := range []common.Action{common.ActStart, common.ActStop, common.ActRestart, common.ActDelete} {
+			cmds = append(cmds, command(fmt.Sprintf("%s %d marked", a, n), "", a))
+		}
+	}
+
+	// Category rows open their view, which only the overview does.
+	overview := m.filterType == ResourceAll
+	if selected := m.selectedEntry(); m.canOperateOnSelected() || (overview && selected.IsCategory) {
+		cmds = append(cmds, m.entryCommands(selected)...)
+	}
+	selected := entryRef(m.selectedEntry())
+	for _, e := range m.entries {
+		if entryRef(e) != selected && (overview || !e.IsCategory) {
+			cmds = append(cmds, m.entryCommands(e)...)
+		}
+	}
+
+	if m.hasProjects() {
+		cmds = append(cmds,
+			palette.Command{Title: "fold all projects", Msg: foldAllMsg{fold: true}},
+			palette.Command{Title: "unfold all projects", Msg: foldAllMsg{fold: false}},
+		)
+	}
+	return append(cmds,
+		command("filter", "", common.ActFilter),
+		command("mark all", "", common.ActMarkAll),
+		command("cycle sort order", "", common.ActSort),
+		command("reverse sort order", "", common.ActSortReverse),
+		command("choose columns", "", common.ActColumns),
+		command("refresh", "", common.ActRefresh),
+	)
+}
+
+// command creates a palette command running action a on the entry ref,
+// showing the action's key.
+func command(title, ref string, a common.Action) palette.Command {
+	return palette.Command{
+		Title: title,
+		Key:   common.Keys.Primary(a),
+		Msg:   paletteRunMsg{entry: ref, action: a},
+	}
+}
+
+// entryCommands lists the actions on one entry, titled with its name.
+func (m Model) entryCommands(e ResourceEntry) []palette.Command {
+	ref := entryRef(e)
+
+	switch {
+	case e.IsCategory:
+		return []palette.Command{command("open "+strings.ToLower(e.Type.String()), ref, common.ActOpen)}
+	case e.IsProjectHeader:
+		fold := "fold project "
+		if m.collapsed[e.ProjectName] {
+			fold = "unfold project "
+		}
+		return []palette.Command{
+			command(fold+e.ProjectName, ref, common.ActOpen),
+			command("start project "+e.ProjectName, ref, common.ActStart),
+			command("stop project "+e.ProjectName, ref, common.ActStop),
+			command("restart project "+e.ProjectName, ref, common.ActRestart),
+		}
+	case !e.Selectable:
+		return nil
+	}
+
+	var cmds []palette.Command
+	switch e.Type {
+	case ResourceContainers:
+		cmds = append(cmds,
+			command("start "+e.Name, ref, common.ActStart),
+			command("stop "+e.Name, ref, common.ActStop),
+			command("restart "+e.Name, ref, common.ActRestart),
+			command("logs "+e.Name, ref, common.ActLogs),
+		)
+		if !e.IsUnused {
+			cmds = append(cmds, command("shell "+e.Name, ref, common.ActShell))
+		}
+		cmds = append(cmds, command("delete "+e.Name, ref, common.ActDelete))
+	case ResourceNetworks:
+		cmds = append(cmds,
+			command("inspect network "+e.Name, ref, common.ActOpen),
+			command("delete "+e.Name, ref, common.ActDelete),
+		)
+	case ResourceImages, ResourceVolumes:
+		cmds = append(cmds, command("delete "+e.Name, ref, common.ActDelete))
+	case ResourceLogs:
+		cmds = append(cmds, command("truncate log "+e.Name, ref, common.ActDelete))
+	}
+	if graphID(e) != "" {
+		cmds = append(cmds, command("relations of "+e.Name, ref, common.ActRelations))
+	}
+	if e.ID != "" {
+		cmds = append(cmds, command("copy id "+e.Name, ref, common.ActCopyID))
+	}
+	return append(cmds, command("copy details "+e.Name, ref, common.ActCopy))
+}
+
+// runCommand runs a palette command on its entry. Marks are set aside so
+// that the action applies to that entry alone.
+func (m Model) runCommand(msg paletteRunMsg) (tea.Model, tea.Cmd) {
+	if msg.entry == "" {
+		next, cmd, _ := m.do(msg.action)
+		return next, cmd
+	}
+
+	idx := indexOfRef(m.visibleEntries(), msg.entry)
+	if idx < 0 && m.filterText != "" {
+		m.filterText = ""
+		m.filteredEntries = nil
+		idx = indexOfRef(m.visibleEntries(), msg.entry)
+	}
+	if i := indexOfRef(m.entries, msg.entry); idx < 0 && i >= 0 && m.folded(m.entries[i]) {
+		m.toggleProject(m.entries[i].ComposeProject)
+		idx = indexOfRef(m.visibleEntries(), msg.entry)
+	}
+	if idx < 0 {
+		m.statusMessage = "No longer listed; press r to refresh"
+		return m, nil
+	}
+	m.selected = idx
+	m.moveSelection(0)
+
+	marked := m.marked
+	m.marked = nil
+	next, cmd, _ := m.do(msg.action)
+	next.marked = marked
+	return next, cmd
+}
+
+func indexOfRef(entries []ResourceEntry, ref string) int {
+	for i, e := range entries {
+		if entryRef(e) == ref {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,264 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/graph"
+	"github.com/bsisduck/octo/internal/tui/common"
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+// graphLoadedMsg carries the relations of all resources, for the
+// relationships view rooted at root.
+type graphLoadedMsg struct {
+	graph *graph.Graph
+	root  string
+	err   error
+}
+
+// graphID returns the graph node of an entry, or "" if it has none.
+// Containers and images are known by ID, volumes and networks by name.
+func graphID(e ResourceEntry) string {
+	if e.IsCategory || e.IsProjectHeader {
+		return ""
+	}
+	switch e.Type {
+	case ResourceContainers:
+		return graph.ID(graph.KindContainer, e.ID)
+	case ResourceImages:
+		return graph.ID(graph.KindImage, e.ID)
+	case ResourceVolumes:
+		return graph.ID(graph.KindVolume, e.Name)
+	case ResourceNetworks:
+		return graph.ID(graph.KindNetwork, e.Name)
+	}
+	return ""
+}
+
+// openRelations switches to the relationships view of an entry.
+func (m *Model) openRelations(e ResourceEntry) tea.Cmd {
+	m.viewMode = viewGraph
+	m.graph = nil
+	m.graphRoot = graphID(e)
+	m.graphHistory = nil
+	m.graphSelected = 0
+	return m.loadGraph()
+}
+
+// loadGraph relates the resources of the daemon.
+func (m Model) loadGraph() tea.Cmd {
+	root := m.graphRoot
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), docker.TimeoutList)
+		defer cancel()
+		g, err := graph.Load(ctx, m.docker)
+		return graphLoadedMsg{graph: g, root: root, err: err}
+	}
+}
+
+// relationRow is a line of the relationships view. Rows without a node
+// are headings.
+type relationRow struct {
+	node     graph.Node
+	depth    int
+	relation string // how the container of the row, or above it, uses the resource
+	heading  string
+}
+
+// relationRows lays out the root, the containers using it with what else
+// they use, and what it uses with who else uses that.
+func (m Model) relationRows() []relationRow {
+	root, ok := m.graph.Node(m.graphRoot)
+	if !ok {
+		return nil
+	}
+	rows := []relationRow{{node: root}}
+
+	if users := m.graph.UsedBy(root.ID); len(users) > 0 {
+		rows = append(rows, relationRow{heading: "Used by"})
+		for _, u := range users {
+			rows = append(rows, relationRow{node: u, depth: 1, relation: relation(u.ID, root.ID)})
+			for _, other := range m.graph.Uses(u.ID) {
+				if other.ID != root.ID {
+					rows = append(rows, relationRow{node: other, depth: 2, relation: relation(u.ID, other.ID)})
+				}
+			}
+		}
+	}
+	if uses := m.graph.Uses(root.ID); len(uses) > 0 {
+		rows = append(rows, relationRow{heading: "Uses"})
+		for _, u := range uses {
+			rows = append(rows, relationRow{node: u, depth: 1, relation: relation(root.ID, u.ID)})
+			for _, other := range m.graph.UsedBy(u.ID) {
+				if other.ID != root.ID {
+					rows = append(rows, relationRow{node: other, depth: 2, relation: "also " + relation(other.ID, u.ID)})
+				}
+			}
+		}
+	}
+	return rows
+}
+
+// relation names how a container uses a resource.
+func relation(from, to string) string {
+	return graph.Edge{From: from, To: to}.Relation()
+}
+
+// moveRelation moves the selection of the relationships view by delta
+// rows, skipping headings.
+func (m *Model) moveRelation(delta int) {
+	rows := m.relationRows()
+	for i := m.graphSelected + delta; i >= 0 && i < len(rows); i += delta {
+		if rows[i].heading == "" {
+			m.graphSelected = i
+			return
+		}
+	}
+}
+
+// reroot shows the relationships of a node, remembering the current root
+// to go back to.
+func (m *Model) reroot(id string) {
+	if id == m.graphRoot {
+		return
+	}
+	m.graphHistory = append(m.graphHistory, m.graphRoot)
+	m.graphRoot = id
+	m.graphSelected = 0
+}
+
+// closeRelations returns to the resource list.
+func (m *Model) closeRelations() {
+	m.viewMode = viewList
+	m.graph = nil
+	m.graphHistory = nil
+}
+
+// updateRelationsView handles keys in the relationships view.
+func (m Model) updateRelationsView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := common.Keys
+	if m.graph == nil {
+		if keys.Is(msg, common.ActCancel) || keys.Is(msg, common.ActQuit) {
+			m.closeRelations()
+		}
+		return m, nil
+	}
+	switch {
+	case keys.Is(msg, common.ActCancel), keys.Is(msg, common.ActQuit), keys.Is(msg, common.ActRelations):
+		m.closeRelations()
+	case keys.Is(msg, common.ActUp):
+		m.moveRelation(-1)
+	case keys.Is(msg, common.ActDown):
+		m.moveRelation(1)
+	case keys.Is(msg, common.ActOpen):
+		if rows := m.relationRows(); m.graphSelected < len(rows) {
+			m.reroot(rows[m.graphSelected].node.ID)
+		}
+	case keys.Is(msg, common.ActBack):
+		if n := len(m.graphHistory); n > 0 {
+			m.graphRoot = m.graphHistory[n-1]
+			m.graphHistory = m.graphHistory[:n-1]
+			m.graphSelected = 0
+		} else {
+			m.closeRelations()
+		}
+	case keys.Is(msg, common.ActRefresh):
+		m.graph = nil
+		return m, m.loadGraph()
+	}
+	return m, nil
+}
+
+// renderRelationsView renders the relationships of the root resource.
+func (m Model) renderRelationsView() string {
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("Relationships"))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", 60))
+	b.WriteString("\n\n")
+
+	if m.graph == nil {
+		b.WriteString(styles.Info.Render("Loading relationships..."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	rows := m.relationRows()
+	if len(rows) == 0 {
+		b.WriteString(styles.Warning.Render("No longer exists; press r to refresh"))
+		b.WriteString("\n")
+	}
+	if len(rows) == 1 {
+		rows = append(rows, relationRow{heading: "Nothing uses it, and it uses nothing"})
+	}
+	for i, r := range rows {
+		if r.heading != "" {
+			b.WriteString("\n")
+			b.WriteString(styles.Section.Render(r.heading))
+			b.WriteString("\n")
+			continue
+		}
+		line := renderRelationRow(r)
+		if i == m.graphSelected {
+			line = styles.Selected.Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", 60))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render(common.ShortHelp(
+		common.Item("navigate", common.ActUp, common.ActDown),
+		common.Item("relations of selected", common.ActOpen),
+		common.Item("previous", common.ActBack),
+		common.Item("refresh", common.ActRefresh),
+		common.Item("close", common.ActCancel),
+	)))
+	return b.String()
+}
+
+// renderRelationRow renders a resource with its kind, state and relation.
+func renderRelationRow(r relationRow) string {
+	indent := strings.Repeat("    ", r.depth)
+	if r.depth == 2 {
+		indent = "      └ "
+	}
+	line := fmt.Sprintf("%s%s %s", indent, styles.Label.Render("["+string(r.node.Kind)+"]"), r.node.Name)
+	switch r.node.State {
+	case "":
+	case "running":
+		line += styles.Success.Render(" (running)")
+	default:
+		line += styles.Warning.Render(" (" + r.node.State + ")")
+	}
+	if r.relation != "" {
+		line += styles.Label.Render("  " + r.relation)
+	}
+	return line
+}
+
+// referenceWarnings describes the containers using a resource about to be
+// deleted. Relations that cannot be loaded are left out: the daemon still
+// refuses to remove what is in use.
+func referenceWarnings(ctx context.Context, svc docker.DockerService, e ResourceEntry) []string {
+	id := graphID(e)
+	if id == "" || e.Type == ResourceContainers {
+		return nil
+	}
+	g, err := graph.Load(ctx, svc)
+	if err != nil {
+		return nil
+	}
+	var warnings []string
+	for _, c := range g.UsedBy(id) {
+		warnings = append(warnings, fmt.Sprintf("Used by container %s (%s)", c.Name, c.State))
+	}
+	return warnings
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/tui/common"
+	"github.com/bsisduck/octo/internal/tui/logs"
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+// containersMsg carries the containers the logs tab offers.
+type containersMsg struct {
+	containers []docker.ContainerInfo
+	err        error
+}
+
+// logsTab picks a container and shows its logs in a logs.Model.
+type logsTab struct {
+	docker     docker.DockerService
+	containers []docker.ContainerInfo
+	selected   int
+	err        error
+	viewer     *logs.Model
+	size       tea.WindowSizeMsg
+}
+
+func newLogsTab(service docker.DockerService) logsTab {
+	return logsTab{docker: service}
+}
+
+func (t logsTab) Init() tea.Cmd {
+	svc := t.docker
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), docker.TimeoutList)
+		defer cancel()
+		containers, err := svc.ListContainers(ctx, true)
+		return containersMsg{containers: containers, err: err}
+	}
+}
+
+// CapturingInput reports whether the log viewer has a prompt open.
+func (t logsTab) CapturingInput() bool {
+	return t.viewer != nil && t.viewer.CapturingInput()
+}
+
+// Back closes the log viewer and returns to the container list.
+func (t logsTab) Back() (tea.Model, tea.Cmd, bool) {
+	if t.viewer == nil {
+		return t, nil, false
+	}
+	t.viewer = nil
+	return t, t.Init(), true
+}
+
+// Stop ends the stream of the open viewer.
+func (t logsTab) Stop() {
+	if t.viewer != nil {
+		t.viewer.Stop()
+	}
+}
+
+func (t logsTab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		t.size = size
+	}
+	if open, ok := msg.(openLogsMsg); ok {
+		t.Stop()
+		return t.open(open.id, open.name)
+	}
+	if t.viewer != nil {
+		model, cmd := t.viewer.Update(msg)
+		viewer := model.(logs.Model)
+		t.viewer = &viewer
+		return t, cmd
+	}
+
+	switch msg := msg.(type) {
+	case containersMsg:
+		t.containers, t.err = msg.containers, msg.err
+		t.selected = min(t.selected, max(len(t.containers)-1, 0))
+	case tea.KeyMsg:
+		keys := common.Keys
+		switch {
+		case keys.Is(msg, common.ActQuit), keys.Is(msg, common.ActCancel):
+			return t, tea.Quit
+		case keys.Is(msg, common.ActUp):
+			if t.selected > 0 {
+				t.selected--
+			}
+		case keys.Is(msg, common.ActDown):
+			if t.selected < len(t.containers)-1 {
+				t.selected++
+			}
+		case keys.Is(msg, common.ActRefresh):
+			return t, t.Init()
+		case keys.Is(msg, common.ActOpen), keys.Is(msg, common.ActLogs):
+			if t.selected < len(t.containers) {
+				c := t.containers[t.selected]
+				return t.open(c.ID, c.Name)
+			}
+		}
+	}
+	return t, nil
+}
+
+// open shows the logs of a container.
+func (t logsTab) open(id, name string) (tea.Model, tea.Cmd) {
+	model, _ := logs.New(t.docker, id, name).Update(t.size)
+	viewer := model.(logs.Model)
+	t.viewer = &viewer
+	return t, viewer.Init()
+}
+
+func (t logsTab) View() string {
+	if t.viewer != nil {
+		return t.viewer.View()
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("🐙 Octo Logs"))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", 60))
+	b.WriteString("\n\n")
+	switch {
+	case t.err != nil:
+		b.WriteString(styles.Error.Render(fmt.Sprintf("Error: %v", t.err)))
+		b.WriteString("\n")
+	case len(t.containers) == 0:
+		b.WriteString(styles.Info.Render("  No containers"))
+		b.WriteString("\n")
+	}
+	for i, c := range t.containers {
+		line := fmt.Sprintf("  %-32s %s", c.Name, c.Status)
+		switch {
+		case i == t.selected:
+			line = styles.Selected.Render(line)
+		case c.State != "running":
+			line = styles.Help.Render(line)
+		default:
+			line = styles.Normal.Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", 60))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render(common.ShortHelp(
+		common.Item("navigate", common.ActUp, common.ActDown),
+		common.Item("show logs", common.ActOpen),
+		common.Item("refresh", common.ActRefresh),
+		common.Item("back", common.ActQuit),
+	)))
+	return b.String()
+}
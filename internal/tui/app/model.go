@@ -0,0 +1,412 @@
+// Package app is octo's interactive shell: one Bubble Tea program with a tab
+// per view, all sharing one Docker service, and the menu as landing page.
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bsisduck/octo/internal/config"
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/hostdisk"
+	"github.com/bsisduck/octo/internal/tui/analyze"
+	"github.com/bsisduck/octo/internal/tui/common"
+	"github.com/bsisduck/octo/internal/tui/events"
+	"github.com/bsisduck/octo/internal/tui/menu"
+	"github.com/bsisduck/octo/internal/tui/palette"
+	"github.com/bsisduck/octo/internal/tui/status"
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+// Tab identifies a view of the app. Tabs are switched with their number.
+type Tab int
+
+const (
+	TabHome Tab = iota
+	TabStatus
+	TabContainers
+	TabImages
+	TabVolumes
+	TabNetworks
+	TabLogs
+	TabEvents
+)
+
+var tabTitles = []string{"Home", "Status", "Containers", "Images", "Volumes", "Networks", "Logs", "Events"}
+
+func (t Tab) String() string {
+	if int(t) < len(tabTitles) {
+		return tabTitles[t]
+	}
+	return fmt.Sprintf("Tab %d", int(t))
+}
+
+// tabBarHeight is the number of lines above the active view.
+const tabBarHeight = 2
+
+// Options configures the views.
+type Options struct {
+	// HostDisk enables the host disk section of the status tab.
+	HostDisk *hostdisk.Options
+	// Analyze and PersistAnalyze are the saved view settings of the
+	// resource tabs, see analyze.Options.
+	Analyze        config.AnalyzeConfig
+	PersistAnalyze func(config.AnalyzeConfig) error
+}
+
+// tabMsg is a message produced by a tab's command, routed back to that tab
+// only. gen drops messages of a tab that was reset meanwhile.
+type tabMsg struct {
+	tab Tab
+	gen int
+	msg tea.Msg
+}
+
+// inputCapturer is implemented by views with text inputs; number keys are
+// typed into them rather than switching tabs.
+type inputCapturer interface {
+	CapturingInput() bool
+}
+
+// stopper is implemented by views holding streams to close on exit.
+type stopper interface {
+	Stop()
+}
+
+// backer is implemented by views with an inner view: quitting the inner view
+// returns to the outer one instead of leaving the tab.
+type backer interface {
+	Back() (tea.Model, tea.Cmd, bool)
+}
+
+// Model is the root model of the interactive app.
+type Model struct {
+	docker       docker.DockerService
+	opts         Options
+	tabs         []tea.Model
+	started      []bool
+	gen          []int
+	active       Tab
+	width        int
+	height       int
+	chosenAction string
+	palette      *palette.Model
+	pruneConfirm *pruneConfirmMsg
+	status       string // shown below the tab bar until the next key
+}
+
+// New creates the app on a Docker service shared by every tab; wrap it in
+// a docker.CachedService so tabs refreshing together share results.
+func New(service docker.DockerService, opts Options) Model {
+	m := Model{
+		docker:  service,
+		opts:    opts,
+		tabs:    make([]tea.Model, len(tabTitles)),
+		started: make([]bool, len(tabTitles)),
+		gen:     make([]int, len(tabTitles)),
+	}
+	for t := range m.tabs {
+		m.tabs[t] = m.newTab(Tab(t))
+	}
+	m.started[TabHome] = true
+	return m
+}
+
+// newTab creates the view of a tab.
+func (m Model) newTab(t Tab) tea.Model {
+	analyzeTab := func(typeFilter string) tea.Model {
+		return analyze.New(m.docker, analyze.Options{
+			TypeFilter: typeFilter,
+			FixedType:  true,
+			View:       m.opts.Analyze,
+			Persist:    m.opts.PersistAnalyze,
+		})
+	}
+	switch t {
+	case TabStatus:
+		s := status.New(m.docker, true)
+		if m.opts.HostDisk != nil {
+			s = s.WithHostDisk(*m.opts.HostDisk)
+		}
+		return s
+	case TabContainers:
+		return analyzeTab("containers")
+	case TabImages:
+		return analyzeTab("images")
+	case TabVolumes:
+		return analyzeTab("volumes")
+	case TabNetworks:
+		return analyzeTab("networks")
+	case TabLogs:
+		return newLogsTab(m.docker)
+	case TabEvents:
+		return events.New(m.docker)
+	default:
+		return menu.NewEmbedded(m.docker)
+	}
+}
+
+// ChosenAction returns the command picked on the landing page that runs
+// outside the app (cleanup, prune, diagnose), or "".
+func (m Model) ChosenAction() string {
+	return m.chosenAction
+}
+
+// Active returns the tab shown.
+func (m Model) Active() Tab {
+	return m.active
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.wrap(TabHome, m.tabs[TabHome].Init())
+}
+
+// wrap tags the messages of a tab's command with the tab, so that tabs of
+// the same kind do not receive each other's data. Bubble Tea's own
+// messages pass through, except quits, which close the tab, not the app.
+func (m Model) wrap(t Tab, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	gen := m.gen[t]
+	return func() tea.Msg {
+		msg := cmd()
+		switch msg := msg.(type) {
+		case nil:
+			return nil
+		case tea.BatchMsg:
+			cmds := make(tea.BatchMsg, len(msg))
+			for i, c := range msg {
+				cmds[i] = m.wrap(t, c)
+			}
+			return cmds
+		case tea.QuitMsg:
+			return tabMsg{tab: t, gen: gen, msg: msg}
+		}
+		if reflect.TypeOf(msg).PkgPath() == reflect.TypeOf(tea.QuitMsg{}).PkgPath() {
+			return msg
+		}
+		return tabMsg{tab: t, gen: gen, msg: msg}
+	}
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		var cmds []tea.Cmd
+		for t := range m.tabs {
+			var cmd tea.Cmd
+			m.tabs[t], cmd = m.tabs[t].Update(m.tabSize())
+			cmds = append(cmds, m.wrap(Tab(t), cmd))
+		}
+		return m, tea.Batch(cmds...)
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m.quit()
+		}
+		m.status = ""
+		if m.pruneConfirm != nil {
+			return m.updatePruneConfirm(msg)
+		}
+		if m.palette != nil {
+			p, cmd := m.palette.Update(msg)
+			m.palette = &p
+			return m, cmd
+		}
+		// Keys that could be typed into a text input are only the app's
+		// when the tab in front has none open.
+		typing := false
+		if c, ok := m.tabs[m.active].(inputCapturer); ok {
+			typing = c.CapturingInput()
+		}
+		if typing && msg.Type == tea.KeyRunes {
+			return m.forward(m.active, msg)
+		}
+		keys := common.Keys
+		switch key := msg.String(); {
+		case keys.Is(msg, common.ActPalette):
+			return m.openPalette()
+		case typing:
+			// Tab and the like move within the input
+		case keys.Is(msg, common.ActNextTab):
+			return m.switchTo((m.active + 1) % Tab(len(m.tabs)))
+		case keys.Is(msg, common.ActPrevTab):
+			return m.switchTo((m.active + Tab(len(m.tabs)) - 1) % Tab(len(m.tabs)))
+		case len(key) == 1 && key[0] >= '0' && int(key[0]-'0') < len(m.tabs):
+			return m.switchTo(Tab(key[0] - '0'))
+		}
+		return m.forward(m.active, msg)
+
+	case tea.MouseMsg:
+		if msg.Y < tabBarHeight || m.palette != nil || m.pruneConfirm != nil {
+			return m, nil
+		}
+		msg.Y -= tabBarHeight
+		return m.forward(m.active, msg)
+
+	case tabMsg:
+		if msg.gen != m.gen[msg.tab] {
+			return m, nil // from a tab that was reset
+		}
+		if _, ok := msg.msg.(tea.QuitMsg); ok {
+			return m.closeTab(msg.tab)
+		}
+		return m.forward(msg.tab, msg.msg)
+
+	case palette.ChosenMsg:
+		m.palette = nil
+		return m.Update(msg.Command.Msg)
+
+	case palette.ClosedMsg:
+		m.palette = nil
+		return m, nil
+
+	case paletteContainersMsg:
+		if m.palette != nil {
+			p := m.palette.Add(containerCommands(msg.containers)...)
+			m.palette = &p
+		}
+		return m, nil
+
+	case switchTabMsg:
+		return m.switchTo(msg.tab)
+
+	case openLogsMsg:
+		model, startCmd := m.switchTo(TabLogs)
+		m = model.(Model)
+		model, openCmd := m.forward(TabLogs, msg)
+		return model, tea.Batch(startCmd, openCmd)
+
+	case quitAppMsg:
+		return m.quit()
+
+	case pruneMsg:
+		return m, m.planPrune(msg.kind)
+
+	case pruneConfirmMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Failed to prepare prune: %v", msg.err)
+		} else {
+			m.pruneConfirm = &msg
+		}
+		return m, nil
+
+	case pruneDoneMsg:
+		m.status = pruneStatus(msg)
+		return m, nil
+	}
+
+	// Untagged messages, such as the end of an exec session, belong to the
+	// tab in front.
+	return m.forward(m.active, msg)
+}
+
+// forward updates one tab and acts on a landing page choice.
+func (m Model) forward(t Tab, msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.tabs[t], cmd = m.tabs[t].Update(msg)
+	if t != TabHome {
+		return m, m.wrap(t, cmd)
+	}
+
+	home := m.tabs[TabHome].(menu.Model)
+	action := home.ChosenAction()
+	if action == "" {
+		return m, m.wrap(t, cmd)
+	}
+	m.tabs[TabHome] = home.ClearChoice()
+	switch action {
+	case "status":
+		return m.switchTo(TabStatus)
+	case "analyze":
+		return m.switchTo(TabContainers)
+	case "logs":
+		return m.switchTo(TabLogs)
+	case "events":
+		return m.switchTo(TabEvents)
+	}
+	m.chosenAction = action
+	return m.quit()
+}
+
+// switchTo shows a tab, starting it the first time.
+func (m Model) switchTo(t Tab) (tea.Model, tea.Cmd) {
+	m.active = t
+	if m.started[t] {
+		return m, nil
+	}
+	m.started[t] = true
+	return m, m.wrap(t, m.tabs[t].Init())
+}
+
+// closeTab handles a tab quitting: a view with an inner view goes back to
+// the outer one; other tabs are reset and the landing page is shown. On the
+// landing page, quitting leaves the app.
+func (m Model) closeTab(t Tab) (tea.Model, tea.Cmd) {
+	if t == TabHome {
+		return m.quit()
+	}
+	if b, ok := m.tabs[t].(backer); ok {
+		if model, cmd, handled := b.Back(); handled {
+			m.tabs[t] = model
+			m.gen[t]++
+			return m, m.wrap(t, cmd)
+		}
+	}
+	if s, ok := m.tabs[t].(stopper); ok {
+		s.Stop()
+	}
+	m.gen[t]++
+	m.tabs[t], _ = m.newTab(t).Update(m.tabSize())
+	m.started[t] = false
+	if m.active == t {
+		m.active = TabHome
+	}
+	return m, nil
+}
+
+// quit stops the streams of every tab and leaves the app.
+func (m Model) quit() (tea.Model, tea.Cmd) {
+	for _, tab := range m.tabs {
+		if s, ok := tab.(stopper); ok {
+			s.Stop()
+		}
+	}
+	return m, tea.Quit
+}
+
+// tabSize is the window size left to a tab below the tab bar.
+func (m Model) tabSize() tea.WindowSizeMsg {
+	return tea.WindowSizeMsg{Width: m.width, Height: max(m.height-tabBarHeight, 0)}
+}
+
+func (m Model) View() string {
+	var b strings.Builder
+	for i, title := range tabTitles {
+		label := fmt.Sprintf(" %d %s ", i, title)
+		if Tab(i) == m.active {
+			b.WriteString(styles.Selected.Render(label))
+		} else {
+			b.WriteString(styles.Help.Render(label))
+		}
+	}
+	b.WriteString("\n")
+	if m.status != "" {
+		b.WriteString(styles.Info.Render(m.status))
+	}
+	b.WriteString("\n")
+	switch {
+	case m.pruneConfirm != nil:
+		b.WriteString(common.RenderConfirmation(m.pruneConfirm.info, "prune"))
+	case m.palette != nil:
+		b.WriteString(m.palette.View())
+	default:
+		b.WriteString(m.tabs[m.active].View())
+	}
+	return b.String()
+}
@@ -0,0 +1,261 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/tui/menu"
+)
+
+func key(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// run executes cmd, one level of batches deep, and feeds the messages back
+// into m. Commands that do not finish quickly, such as ticks, are dropped.
+func run(m Model, cmd tea.Cmd) Model {
+	if cmd == nil {
+		return m
+	}
+	var msgs []tea.Msg
+	collect := func(c tea.Cmd) {
+		if c == nil {
+			return
+		}
+		ch := make(chan tea.Msg, 1)
+		go func() { ch <- c() }()
+		select {
+		case msg := <-ch:
+			if msg != nil {
+				msgs = append(msgs, msg)
+			}
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	collect(cmd)
+	if len(msgs) == 1 {
+		if batch, ok := msgs[0].(tea.BatchMsg); ok {
+			msgs = nil
+			for _, c := range batch {
+				collect(c)
+			}
+		}
+	}
+	for _, msg := range msgs {
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func testService() *docker.MockDockerService {
+	return &docker.MockDockerService{
+		ListContainersFn: func(ctx context.Context, all bool) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{{ID: "c1", Name: "web", State: "running", Status: "Up 2 minutes"}}, nil
+		},
+		ListImagesFn: func(ctx context.Context, all bool) ([]docker.ImageInfo, error) {
+			return []docker.ImageInfo{{ID: "i1", Repository: "nginx", Tag: "latest"}}, nil
+		},
+	}
+}
+
+// TestApp_SwitchesTabsWithNumbers tests number keys start and show tabs
+func TestApp_SwitchesTabsWithNumbers(t *testing.T) {
+	m := New(testService(), Options{})
+	m = run(m, m.Init())
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	assert.Contains(t, m.View(), "Quick Stats")
+
+	updated, cmd := m.Update(key("2"))
+	m = run(updated.(Model), cmd)
+	assert.Equal(t, TabContainers, m.Active())
+	view := m.View()
+	assert.Contains(t, view, "Octo Analyzer - Containers")
+	assert.Contains(t, view, "web")
+
+	// The images tab has not been started; its data is not the containers'
+	updated, _ = m.Update(key("3"))
+	m = updated.(Model)
+	assert.Contains(t, m.View(), "Loading Docker resources")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	assert.Equal(t, TabContainers, updated.(Model).Active())
+}
+
+// TestApp_NumbersTypeIntoFilters tests number keys reach a tab's text input
+func TestApp_NumbersTypeIntoFilters(t *testing.T) {
+	m := New(testService(), Options{})
+	updated, cmd := m.Update(key("2"))
+	m = run(updated.(Model), cmd)
+
+	updated, _ = m.Update(key("/"))
+	updated, _ = updated.(Model).Update(key("5"))
+	m = updated.(Model)
+	assert.Equal(t, TabContainers, m.Active())
+	assert.Contains(t, m.View(), "Filter: 5")
+}
+
+// TestApp_QuitClosesTab tests quitting a tab returns to Home and resets it
+func TestApp_QuitClosesTab(t *testing.T) {
+	m := New(testService(), Options{})
+	updated, cmd := m.Update(key("2"))
+	m = run(updated.(Model), cmd)
+	stale := m.wrap(TabContainers, func() tea.Msg { return tea.KeyMsg{} })
+
+	updated, cmd = m.Update(key("q"))
+	m = run(updated.(Model), cmd)
+	assert.Equal(t, TabHome, m.Active())
+	assert.False(t, m.started[TabContainers])
+
+	// Messages of the closed tab are dropped
+	updated, next := m.Update(stale())
+	assert.Nil(t, next)
+	assert.Equal(t, m.gen, updated.(Model).gen)
+
+	// Quitting Home leaves the app
+	_, cmd = m.Update(key("q"))
+	require.NotNil(t, cmd)
+	msg := cmd()
+	tm, ok := msg.(tabMsg)
+	require.True(t, ok)
+	_, cmd = m.Update(tm)
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+}
+
+// TestApp_LandingPageChoices tests menu items open tabs or leave the app
+func TestApp_LandingPageChoices(t *testing.T) {
+	m := New(testService(), Options{})
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter}) // Status
+	m = updated.(Model)
+	assert.Equal(t, TabStatus, m.Active())
+	assert.Empty(t, m.tabs[TabHome].(menu.Model).ChosenAction())
+
+	updated, _ = m.Update(key("0"))
+	m = updated.(Model)
+	for i := 0; i < 4; i++ { // Cleanup
+		updated, _ = m.Update(key("j"))
+		m = updated.(Model)
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, "cleanup", updated.(Model).ChosenAction())
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+}
+
+// TestApp_LogsTab tests picking a container and going back to the list
+func TestApp_LogsTab(t *testing.T) {
+	m := New(testService(), Options{})
+	updated, cmd := m.Update(key("6"))
+	m = run(updated.(Model), cmd)
+	assert.Contains(t, m.View(), "web")
+
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = run(updated.(Model), cmd)
+	assert.Contains(t, m.View(), "Logs: web")
+	assert.Contains(t, m.View(), "test log line 1")
+
+	updated, cmd = m.Update(key("q"))
+	m = run(updated.(Model), cmd)
+	assert.Equal(t, TabLogs, m.Active())
+	assert.Contains(t, m.View(), "enter: show logs")
+}
+
+// typeText types s into the app, key by key.
+func typeText(m Model, s string) Model {
+	for _, r := range s {
+		msg := key(string(r))
+		if r == ' ' {
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	return m
+}
+
+// choose runs the best match of the open palette and returns the model and
+// command the chosen command produced.
+func choose(t *testing.T, m Model) (Model, tea.Cmd) {
+	t.Helper()
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	updated, cmd = updated.(Model).Update(cmd())
+	return updated.(Model), cmd
+}
+
+// TestApp_PaletteRunsTabCommands tests the palette acts on a tab's resources
+func TestApp_PaletteRunsTabCommands(t *testing.T) {
+	svc := testService()
+	var stopped []string
+	svc.StopContainerFn = func(_ context.Context, id string) error {
+		stopped = append(stopped, id)
+		return nil
+	}
+	m := New(svc, Options{})
+	updated, cmd := m.Update(key("2"))
+	m = run(updated.(Model), cmd)
+
+	updated, cmd = m.Update(key(":"))
+	m = run(updated.(Model), cmd)
+	m = typeText(m, "stop web")
+	require.NotEmpty(t, m.palette.Matches())
+	best := m.palette.Matches()[0]
+	assert.Equal(t, "stop web", best.Title)
+	assert.Equal(t, "t", best.Key)
+	assert.Contains(t, m.View(), "stop web")
+
+	m, cmd = choose(t, m)
+	require.NotNil(t, cmd)
+	cmd()
+	assert.Nil(t, m.palette)
+	assert.Equal(t, []string{"c1"}, stopped)
+}
+
+// TestApp_PaletteOpensLogs tests logs of a container open from any tab
+func TestApp_PaletteOpensLogs(t *testing.T) {
+	m := New(testService(), Options{})
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = run(updated.(Model), cmd)
+	m = typeText(m, "logs web")
+
+	m, cmd = choose(t, m)
+	m = run(m, cmd)
+	assert.Equal(t, TabLogs, m.Active())
+	assert.Contains(t, m.View(), "Logs: web")
+}
+
+// TestApp_PalettePrunes tests prunes from the palette are confirmed first
+func TestApp_PalettePrunes(t *testing.T) {
+	svc := testService()
+	pruned := false
+	svc.PruneImagesDryRunFn = func(context.Context, bool) (docker.ConfirmationInfo, error) {
+		return docker.ConfirmationInfo{Title: "Prune 3 dangling images?", Tier: docker.TierModerate}, nil
+	}
+	svc.PruneImagesFn = func(context.Context, bool) (uint64, error) {
+		pruned = true
+		return 2048, nil
+	}
+	m := New(svc, Options{})
+	updated, cmd := m.Update(key(":"))
+	m = run(updated.(Model), cmd)
+	m = typeText(m, "prune images")
+
+	m, cmd = choose(t, m)
+	m = run(m, cmd)
+	assert.Contains(t, m.View(), "Prune 3 dangling images?")
+	assert.False(t, pruned)
+
+	updated, cmd = m.Update(key("y"))
+	m = run(updated.(Model), cmd)
+	assert.True(t, pruned)
+	assert.Nil(t, m.pruneConfirm)
+	assert.Contains(t, m.View(), "reclaimed 2.0 kB")
+}
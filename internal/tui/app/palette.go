@@ -0,0 +1,202 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/tui/common"
+	"github.com/bsisduck/octo/internal/tui/palette"
+	"github.com/bsisduck/octo/internal/ui/format"
+)
+
+// commander is implemented by views offering palette commands.
+type commander interface {
+	Commands() []palette.Command
+}
+
+// switchTabMsg shows a tab.
+type switchTabMsg struct {
+	tab Tab
+}
+
+// openLogsMsg opens the logs of a container in the logs tab.
+type openLogsMsg struct {
+	id   string
+	name string
+}
+
+// quitAppMsg leaves the app.
+type quitAppMsg struct{}
+
+// paletteContainersMsg carries the containers named by palette commands.
+type paletteContainersMsg struct {
+	containers []docker.ContainerInfo
+}
+
+// openPalette opens the palette on the commands of the tab in front, then
+// the app's. Commands naming containers are added once they are listed.
+func (m Model) openPalette() (tea.Model, tea.Cmd) {
+	var cmds []palette.Command
+	if c, ok := m.tabs[m.active].(commander); ok {
+		for _, cmd := range c.Commands() {
+			cmd.Msg = tabMsg{tab: m.active, gen: m.gen[m.active], msg: cmd.Msg}
+			cmds = append(cmds, cmd)
+		}
+	}
+	for t, title := range tabTitles {
+		cmds = append(cmds, palette.Command{
+			Title: "go to " + title,
+			Key:   fmt.Sprint(t),
+			Msg:   switchTabMsg{tab: Tab(t)},
+		})
+	}
+	for k := range pruneTitles {
+		cmds = append(cmds, palette.Command{Title: pruneTitles[k], Msg: pruneMsg{kind: pruneKind(k)}})
+	}
+	cmds = append(cmds, palette.Command{Title: "quit", Key: "ctrl+c", Msg: quitAppMsg{}})
+
+	p := palette.New(cmds)
+	m.palette = &p
+	svc := m.docker
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), docker.TimeoutList)
+		defer cancel()
+		containers, _ := svc.ListContainers(ctx, true)
+		return paletteContainersMsg{containers: containers}
+	}
+}
+
+// containerCommands are the app's commands on containers by name.
+func containerCommands(containers []docker.ContainerInfo) []palette.Command {
+	var cmds []palette.Command
+	for _, c := range containers {
+		cmds = append(cmds, palette.Command{
+			Title: "logs " + c.Name,
+			Key:   fmt.Sprintf("%d, %s", TabLogs, common.Keys.Primary(common.ActOpen)),
+			Msg:   openLogsMsg{id: c.ID, name: c.Name},
+		})
+	}
+	return cmds
+}
+
+// pruneKind is a kind of resource the palette prunes.
+type pruneKind int
+
+const (
+	pruneContainers pruneKind = iota
+	pruneImages
+	pruneVolumes
+	pruneNetworks
+)
+
+var pruneTitles = []string{
+	"prune stopped containers",
+	"prune dangling images",
+	"prune unused volumes",
+	"prune unused networks",
+}
+
+// pruneMsg asks for the confirmation of a prune.
+type pruneMsg struct {
+	kind pruneKind
+}
+
+// pruneConfirmMsg carries what a prune would remove.
+type pruneConfirmMsg struct {
+	kind pruneKind
+	info docker.ConfirmationInfo
+	err  error
+}
+
+// pruneDoneMsg reports a prune.
+type pruneDoneMsg struct {
+	kind      pruneKind
+	reclaimed uint64
+	err       error
+}
+
+// pruneDryRun describes a prune without removing anything.
+func pruneDryRun(ctx context.Context, svc docker.DockerService, kind pruneKind) (docker.ConfirmationInfo, error) {
+	switch kind {
+	case pruneImages:
+		return svc.PruneImagesDryRun(ctx, false)
+	case pruneVolumes:
+		return svc.PruneVolumesDryRun(ctx)
+	case pruneNetworks:
+		return svc.PruneNetworksDryRun(ctx)
+	default:
+		return svc.PruneContainersDryRun(ctx)
+	}
+}
+
+// prune removes the resources of a kind and returns the space reclaimed.
+func prune(ctx context.Context, svc docker.DockerService, kind pruneKind) (uint64, error) {
+	switch kind {
+	case pruneImages:
+		return svc.PruneImages(ctx, false)
+	case pruneVolumes:
+		return svc.PruneVolumes(ctx)
+	case pruneNetworks:
+		return 0, svc.PruneNetworks(ctx)
+	default:
+		return svc.PruneContainers(ctx)
+	}
+}
+
+// planPrune is Phase 1: a dry run for the confirmation dialog.
+func (m Model) planPrune(kind pruneKind) tea.Cmd {
+	svc := m.docker
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), docker.TimeoutList)
+		defer cancel()
+		info, err := pruneDryRun(ctx, svc, kind)
+		return pruneConfirmMsg{kind: kind, info: info, err: err}
+	}
+}
+
+// executePrune is Phase 2, after confirmation. The dry run is repeated and
+// the prune aborted if the safety tier changed meanwhile.
+func (m Model) executePrune(plan pruneConfirmMsg) tea.Cmd {
+	svc := m.docker
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), docker.TimeoutRemove)
+		defer cancel()
+		current, err := pruneDryRun(ctx, svc, plan.kind)
+		if err != nil {
+			return pruneDoneMsg{kind: plan.kind, err: err}
+		}
+		if current.Tier != plan.info.Tier {
+			return pruneDoneMsg{kind: plan.kind, err: fmt.Errorf("resource state changed from %s to %s - operation aborted for safety", plan.info.Tier, current.Tier)}
+		}
+		reclaimed, err := prune(ctx, svc, plan.kind)
+		return pruneDoneMsg{kind: plan.kind, reclaimed: reclaimed, err: err}
+	}
+}
+
+// updatePruneConfirm handles keys while a prune awaits confirmation.
+func (m Model) updatePruneConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y", "enter":
+		plan := *m.pruneConfirm
+		m.pruneConfirm = nil
+		m.status = pruneTitles[plan.kind] + "..."
+		return m, m.executePrune(plan)
+	case "n", "N", "esc", "q":
+		m.pruneConfirm = nil
+	}
+	return m, nil
+}
+
+// pruneStatus reports a finished prune.
+func pruneStatus(msg pruneDoneMsg) string {
+	if msg.err != nil {
+		return fmt.Sprintf("%s failed: %v", pruneTitles[msg.kind], msg.err)
+	}
+	if msg.kind == pruneNetworks {
+		return "Pruned unused networks"
+	}
+	return fmt.Sprintf("Pruned, reclaimed %s", format.Size(msg.reclaimed))
+}
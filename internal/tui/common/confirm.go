@@ -0,0 +1,55 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+// RenderConfirmation renders a detailed confirmation dialog with safety tier colors
+func RenderConfirmation(info docker.ConfirmationInfo, action string) string {
+	var b strings.Builder
+
+	// Tier-colored title
+	tierStyle := styles.TierStyle(int(info.Tier))
+	b.WriteString(tierStyle.Render(fmt.Sprintf("⚠ %s\n", info.Title)))
+
+	// Description
+	b.WriteString(styles.Info.Render(fmt.Sprintf("   %s\n", info.Description)))
+
+	// Resources list
+	if len(info.Resources) > 0 {
+		b.WriteString(styles.Help.Render("   Resources:\n"))
+		for _, r := range info.Resources {
+			b.WriteString(styles.Help.Render(fmt.Sprintf("     • %s\n", r)))
+		}
+	}
+
+	// Reversibility status
+	if info.Reversible {
+		b.WriteString(styles.Success.Render("   ✓ Reversible\n"))
+		b.WriteString(styles.Help.Render(fmt.Sprintf("   %s\n", info.UndoInstructions)))
+	} else {
+		b.WriteString(tierStyle.Render("   ✗ NOT REVERSIBLE - Data will be permanently lost\n"))
+		b.WriteString(styles.Help.Render(fmt.Sprintf("   %s\n", info.UndoInstructions)))
+	}
+
+	// Warnings
+	if len(info.Warnings) > 0 {
+		b.WriteString(styles.Warning.Render("   ⚠ Warnings:\n"))
+		for _, w := range info.Warnings {
+			b.WriteString(styles.Warning.Render(fmt.Sprintf("     • %s\n", w)))
+		}
+	}
+
+	// Safety tier indicator
+	b.WriteString(tierStyle.Render(fmt.Sprintf("   Safety Level: %s\n", info.Tier.String())))
+
+	// Confirmation prompt
+	b.WriteString("\n")
+	b.WriteString(styles.DeleteConfirm.Render(fmt.Sprintf("   Confirm %s? [y] Yes  [n] No", action)))
+
+	return b.String()
+}
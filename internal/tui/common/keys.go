@@ -1,4 +1,336 @@
 package common
 
-// Common key constants can go here if needed.
-// For now, most models handle keys directly.
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+// Action names what a key does. Views look keys up by action, so bindings
+// can be changed in the config file without touching the views. Several
+// actions may share a key as long as no view offers both.
+type Action string
+
+const (
+	ActUp          Action = "up"
+	ActDown        Action = "down"
+	ActTop         Action = "top"
+	ActBottom      Action = "bottom"
+	ActOpen        Action = "open"
+	ActBack        Action = "back"
+	ActChoose      Action = "choose"
+	ActQuit        Action = "quit"
+	ActCancel      Action = "cancel"
+	ActHelp        Action = "help"
+	ActPalette     Action = "palette"
+	ActNextTab     Action = "next_tab"
+	ActPrevTab     Action = "prev_tab"
+	ActFilter      Action = "filter"
+	ActRegexFilter Action = "regex_filter"
+	ActRefresh     Action = "refresh"
+	ActStart       Action = "start"
+	ActStop        Action = "stop"
+	ActRestart     Action = "restart"
+	ActLogs        Action = "logs"
+	ActShell       Action = "shell"
+	ActCopy        Action = "copy"
+	ActCopyID      Action = "copy_id"
+	ActDelete      Action = "delete"
+	ActMark        Action = "mark"
+	ActMarkAll     Action = "mark_all"
+	ActSort        Action = "sort"
+	ActSortReverse Action = "sort_reverse"
+	ActColumns     Action = "columns"
+	ActFollow      Action = "follow"
+	ActClear       Action = "clear"
+	ActAlert       Action = "alert"
+	ActClearAlert  Action = "clear_alert"
+	ActStats       Action = "stats"
+	ActExport      Action = "export"
+	ActVersion     Action = "version"
+	ActRelations   Action = "relations"
+	ActConnect     Action = "connect"
+	ActDisconnect  Action = "disconnect"
+)
+
+// actionHelp describes each action in the help overlay.
+var actionHelp = map[Action]string{
+	ActUp:          "Move up",
+	ActDown:        "Move down",
+	ActTop:         "Go to top",
+	ActBottom:      "Go to bottom",
+	ActOpen:        "Open category or network, fold or unfold project",
+	ActBack:        "Go back to the overview",
+	ActChoose:      "Run selected item",
+	ActQuit:        "Quit",
+	ActCancel:      "Close or clear; quit when nothing is open",
+	ActHelp:        "Show this help",
+	ActPalette:     "Command palette",
+	ActNextTab:     "Next tab",
+	ActPrevTab:     "Previous tab",
+	ActFilter:      "Filter",
+	ActRegexFilter: "Filter by regular expression",
+	ActRefresh:     "Refresh",
+	ActStart:       "Start container or project",
+	ActStop:        "Stop container or project",
+	ActRestart:     "Restart container or project",
+	ActLogs:        "Show logs",
+	ActShell:       "Open a shell in the container",
+	ActCopy:        "Copy details",
+	ActCopyID:      "Copy ID",
+	ActDelete:      "Delete (truncate, for logs)",
+	ActMark:        "Mark or unmark",
+	ActMarkAll:     "Mark all visible",
+	ActSort:        "Cycle sort field",
+	ActSortReverse: "Reverse sort order",
+	ActColumns:     "Choose columns",
+	ActFollow:      "Follow new output",
+	ActClear:       "Clear",
+	ActAlert:       "Add alert pattern",
+	ActClearAlert:  "Dismiss alerts",
+	ActStats:       "Toggle stats panel",
+	ActExport:      "Export",
+	ActVersion:     "Show version",
+	ActRelations:   "Show what uses the resource, and what it uses",
+	ActConnect:     "Connect a container to the network",
+	ActDisconnect:  "Disconnect the container from the network",
+}
+
+// KeyMap binds actions to keys, named as tea.KeyMsg.String names them
+// ("k", "ctrl+r", "enter", " " for space).
+type KeyMap map[Action][]string
+
+// defaultKeys are octo's own bindings, arrows plus vim-style letters.
+func defaultKeys() KeyMap {
+	return KeyMap{
+		ActUp:          {"up", "k"},
+		ActDown:        {"down", "j"},
+		ActTop:         {"g"},
+		ActBottom:      {"G"},
+		ActOpen:        {"enter", "right"},
+		ActBack:        {"h", "left"},
+		ActChoose:      {"enter", " "},
+		ActQuit:        {"q", "ctrl+c"},
+		ActCancel:      {"esc"},
+		ActHelp:        {"?"},
+		ActPalette:     {":", "ctrl+p"},
+		ActNextTab:     {"tab"},
+		ActPrevTab:     {"shift+tab"},
+		ActFilter:      {"/"},
+		ActRegexFilter: {"ctrl+r"},
+		ActRefresh:     {"r"},
+		ActStart:       {"s"},
+		ActStop:        {"t"},
+		ActRestart:     {"r"},
+		ActLogs:        {"l"},
+		ActShell:       {"x"},
+		ActCopy:        {"y"},
+		ActCopyID:      {"Y"},
+		ActDelete:      {"d", "delete", "backspace"},
+		ActMark:        {" "},
+		ActMarkAll:     {"a"},
+		ActSort:        {"o"},
+		ActSortReverse: {"O"},
+		ActColumns:     {"c"},
+		ActFollow:      {"f"},
+		ActClear:       {"c"},
+		ActAlert:       {"a"},
+		ActClearAlert:  {"A"},
+		ActStats:       {"s"},
+		ActExport:      {"e"},
+		ActVersion:     {"v"},
+		ActRelations:   {"R"},
+		ActConnect:     {"C"},
+		ActDisconnect:  {"D"},
+	}
+}
+
+// emacsKeys moves navigation to control keys; ctrl+p moves up, so the
+// palette is on M-x.
+func emacsKeys() KeyMap {
+	k := defaultKeys()
+	k[ActUp] = []string{"up", "ctrl+p"}
+	k[ActDown] = []string{"down", "ctrl+n"}
+	k[ActTop] = []string{"alt+<", "home"}
+	k[ActBottom] = []string{"alt+>", "end"}
+	k[ActOpen] = []string{"enter", "ctrl+f", "right"}
+	k[ActBack] = []string{"ctrl+b", "left"}
+	k[ActCancel] = []string{"esc", "ctrl+g"}
+	k[ActPalette] = []string{"alt+x"}
+	k[ActFilter] = []string{"ctrl+s", "/"}
+	return k
+}
+
+// Presets are the named keymaps the config file can start from. The default
+// bindings are vim-style already; "vim" names them explicitly.
+var Presets = map[string]func() KeyMap{
+	"default": defaultKeys,
+	"vim":     defaultKeys,
+	"emacs":   emacsKeys,
+}
+
+// Keys is the keymap of the TUI; SetKeyMap replaces it at startup.
+var Keys = defaultKeys()
+
+// SetKeyMap makes k the keymap of every view.
+func SetKeyMap(k KeyMap) {
+	Keys = k
+}
+
+// NewKeyMap builds a keymap from a preset ("" for the default) and
+// bindings replacing the keys of single actions. "space" may be written for
+// the space bar. ctrl+c always quits.
+func NewKeyMap(preset string, bindings map[string][]string) (KeyMap, error) {
+	if preset == "" {
+		preset = "default"
+	}
+	build, ok := Presets[preset]
+	if !ok {
+		return nil, fmt.Errorf("unknown key preset %q (choose: default, vim, emacs)", preset)
+	}
+	k := build()
+	for name, keys := range bindings {
+		a := Action(name)
+		if _, ok := actionHelp[a]; !ok {
+			return nil, fmt.Errorf("unknown key action %q (known: %s)", name, strings.Join(ActionNames(), ", "))
+		}
+		normalized := make([]string, len(keys))
+		for i, key := range keys {
+			if key == "space" {
+				key = " "
+			}
+			normalized[i] = key
+		}
+		k[a] = normalized
+	}
+	// A program that cannot be left is worse than a lost binding.
+	if !slices.Contains(k[ActQuit], "ctrl+c") {
+		k[ActQuit] = append(k[ActQuit], "ctrl+c")
+	}
+	return k, nil
+}
+
+// Is reports whether msg is one of the keys of action a.
+func (k KeyMap) Is(msg tea.KeyMsg, a Action) bool {
+	key := msg.String()
+	for _, bound := range k[a] {
+		if bound == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Primary returns the first key of action a as shown to the user.
+func (k KeyMap) Primary(a Action) string {
+	if keys := k[a]; len(keys) > 0 {
+		return displayKey(keys[0])
+	}
+	return ""
+}
+
+// displayKey is the name of a key in help text.
+func displayKey(key string) string {
+	switch key {
+	case " ":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	}
+	return key
+}
+
+// HelpItem is one entry of a footer: a label and the actions it covers.
+type HelpItem struct {
+	Label   string
+	Actions []Action
+}
+
+// Item creates a footer entry.
+func Item(label string, actions ...Action) HelpItem {
+	return HelpItem{Label: label, Actions: actions}
+}
+
+// ShortHelp renders a footer from the primary keys of the items' actions,
+// e.g. "↑/↓: navigate | /: filter".
+func ShortHelp(items ...HelpItem) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		var keys []string
+		for _, a := range item.Actions {
+			if key := Keys.Primary(a); key != "" {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) > 0 {
+			parts = append(parts, strings.Join(keys, "/")+": "+item.Label)
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+// HelpSection groups the actions of a view in the help overlay.
+type HelpSection struct {
+	Title   string
+	Actions []Action
+}
+
+// RenderHelp renders the help overlay of a view from the keymap: every
+// key of every action, by section.
+func RenderHelp(title string, sections ...HelpSection) string {
+	var b strings.Builder
+	b.WriteString(styles.Title.Render(title + " - Keys"))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", 60))
+	b.WriteString("\n")
+
+	width := 0
+	keyList := make(map[Action]string)
+	for _, s := range sections {
+		for _, a := range s.Actions {
+			var names []string
+			for _, key := range Keys[a] {
+				names = append(names, displayKey(key))
+			}
+			keyList[a] = strings.Join(names, ", ")
+			width = max(width, utf8.RuneCountInString(keyList[a]))
+		}
+	}
+	for _, s := range sections {
+		b.WriteString("\n")
+		b.WriteString(styles.Section.Render(s.Title))
+		b.WriteString("\n")
+		for _, a := range s.Actions {
+			keys := keyList[a]
+			if keys == "" {
+				keys = "(unbound)"
+			}
+			fmt.Fprintf(&b, "  %s  %s\n", styles.Label.Render(fmt.Sprintf("%-*s", width, keys)), actionHelp[a])
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("Press any key to close"))
+	return b.String()
+}
+
+// ActionNames lists the actions that can be bound, sorted.
+func ActionNames() []string {
+	names := make([]string, 0, len(actionHelp))
+	for a := range actionHelp {
+		names = append(names, string(a))
+	}
+	sort.Strings(names)
+	return names
+}
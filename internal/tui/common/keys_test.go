@@ -0,0 +1,60 @@
+package common
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// TestNewKeyMap tests presets and bindings from the config file
+func TestNewKeyMap(t *testing.T) {
+	k, err := NewKeyMap("", nil)
+	require.NoError(t, err)
+	assert.True(t, k.Is(runes("j"), ActDown))
+	assert.True(t, k.Is(tea.KeyMsg{Type: tea.KeyDown}, ActDown))
+	assert.True(t, k.Is(tea.KeyMsg{Type: tea.KeyCtrlP}, ActPalette))
+
+	k, err = NewKeyMap("emacs", map[string][]string{"stop": {"T"}, "mark": {"space"}, "quit": {"Q"}})
+	require.NoError(t, err)
+	assert.True(t, k.Is(tea.KeyMsg{Type: tea.KeyCtrlN}, ActDown))
+	assert.True(t, k.Is(tea.KeyMsg{Type: tea.KeyCtrlP}, ActUp))
+	assert.False(t, k.Is(tea.KeyMsg{Type: tea.KeyCtrlP}, ActPalette))
+	assert.True(t, k.Is(runes("T"), ActStop))
+	assert.False(t, k.Is(runes("t"), ActStop))
+	assert.True(t, k.Is(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}, ActMark))
+	assert.True(t, k.Is(runes("Q"), ActQuit))
+	assert.True(t, k.Is(tea.KeyMsg{Type: tea.KeyCtrlC}, ActQuit), "ctrl+c always quits")
+
+	_, err = NewKeyMap("nano", nil)
+	assert.ErrorContains(t, err, `unknown key preset "nano"`)
+	_, err = NewKeyMap("", map[string][]string{"explode": {"x"}})
+	assert.ErrorContains(t, err, `unknown key action "explode"`)
+}
+
+// TestHelpFollowsKeyMap tests the footer and the help overlay show the
+// bindings in effect
+func TestHelpFollowsKeyMap(t *testing.T) {
+	defer SetKeyMap(Keys)
+	k, err := NewKeyMap("", map[string][]string{"stop": {"T", "ctrl+t"}, "filter": {}})
+	require.NoError(t, err)
+	SetKeyMap(k)
+
+	assert.Equal(t, "↑/↓: navigate | T: stop", ShortHelp(
+		Item("navigate", ActUp, ActDown),
+		Item("stop", ActStop),
+		Item("filter", ActFilter),
+	))
+
+	help := RenderHelp("Test", HelpSection{Title: "Containers", Actions: []Action{ActStop, ActFilter, ActMark}})
+	assert.Contains(t, help, "Containers")
+	assert.Contains(t, help, "T, ctrl+t")
+	assert.Contains(t, help, "Stop container or project")
+	assert.Contains(t, help, "(unbound)")
+	assert.Contains(t, help, "space")
+}
@@ -0,0 +1,27 @@
+package common
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// WheelLines is how many lines a notch of the mouse wheel scrolls a text
+// view such as logs. Lists move their selection by one entry instead.
+const WheelLines = 3
+
+// Wheel returns -1 for a wheel notch up, 1 for a notch down and 0 for any
+// other mouse event.
+func Wheel(msg tea.MouseMsg) int {
+	if msg.Action != tea.MouseActionPress {
+		return 0
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return -1
+	case tea.MouseButtonWheelDown:
+		return 1
+	}
+	return 0
+}
+
+// LeftClick reports whether msg is a press of the left button.
+func LeftClick(msg tea.MouseMsg) bool {
+	return msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft
+}
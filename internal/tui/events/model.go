@@ -0,0 +1,285 @@
+// Package events provides a Bubble Tea model following the Docker daemon's
+// event stream.
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/tui/common"
+	"github.com/bsisduck/octo/internal/tui/palette"
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+// MaxEvents is how many events are kept; older ones are dropped.
+const MaxEvents = 1000
+
+// streamStartedMsg carries a newly opened event stream.
+type streamStartedMsg struct {
+	eventCh <-chan docker.Event
+	errCh   <-chan error
+	cancel  func()
+}
+
+// EventMsg carries one streamed event.
+type EventMsg struct {
+	Event docker.Event
+}
+
+// StreamErrMsg signals the stream ended, with the error if it broke.
+type StreamErrMsg struct {
+	Err error
+}
+
+// Model shows daemon events as they happen, newest at the bottom.
+type Model struct {
+	docker    docker.DockerService
+	events    []docker.Event
+	offset    int
+	following bool
+	width     int
+	height    int
+	err       error
+	showHelp  bool
+
+	eventCh <-chan docker.Event
+	errCh   <-chan error
+	cancel  func()
+}
+
+// New creates an events model.
+func New(service docker.DockerService) Model {
+	return Model{docker: service, following: true}
+}
+
+// Init opens the event stream.
+func (m Model) Init() tea.Cmd {
+	svc := m.docker
+	return func() tea.Msg {
+		eventCh, errCh, cancel := svc.StreamEvents(context.Background())
+		return streamStartedMsg{eventCh: eventCh, errCh: errCh, cancel: cancel}
+	}
+}
+
+// next reads the next event or error from the stream.
+func (m Model) next() tea.Cmd {
+	if m.eventCh == nil {
+		return nil
+	}
+	eventCh, errCh := m.eventCh, m.errCh
+	return func() tea.Msg {
+		select {
+		case ev, ok := <-eventCh:
+			if !ok {
+				return StreamErrMsg{}
+			}
+			return EventMsg{Event: ev}
+		case err := <-errCh:
+			return StreamErrMsg{Err: err}
+		}
+	}
+}
+
+// Stop closes the event stream.
+func (m Model) Stop() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+}
+
+// actions are the actions of the view, in the order a shared key tries them.
+var actions = []common.Action{
+	common.ActQuit, common.ActCancel, common.ActHelp, common.ActUp, common.ActDown,
+	common.ActTop, common.ActBottom, common.ActFollow, common.ActClear, common.ActRefresh,
+}
+
+// actionMsg runs an action picked in the command palette.
+type actionMsg struct {
+	action common.Action
+}
+
+// Commands returns the palette commands of the view.
+func (m Model) Commands() []palette.Command {
+	command := func(title string, a common.Action) palette.Command {
+		return palette.Command{Title: title, Key: common.Keys.Primary(a), Msg: actionMsg{action: a}}
+	}
+	cmds := []palette.Command{
+		command("follow new events", common.ActFollow),
+		command("clear events", common.ActClear),
+	}
+	if m.eventCh == nil {
+		cmds = append(cmds, command("reconnect event stream", common.ActRefresh))
+	}
+	return cmds
+}
+
+// do runs an action.
+func (m Model) do(a common.Action) (tea.Model, tea.Cmd) {
+	switch a {
+	case common.ActQuit, common.ActCancel:
+		m.Stop()
+		return m, tea.Quit
+	case common.ActHelp:
+		m.showHelp = true
+	case common.ActUp:
+		if m.offset > 0 {
+			m.offset--
+		}
+		m.following = false
+	case common.ActDown:
+		maxOffset := max(len(m.events)-m.viewportHeight(), 0)
+		if m.offset < maxOffset {
+			m.offset++
+		}
+		m.following = m.offset >= maxOffset
+	case common.ActTop:
+		m.offset = 0
+		m.following = false
+	case common.ActBottom, common.ActFollow:
+		m.following = true
+		m.scrollToBottom()
+	case common.ActClear:
+		m.events = nil
+		m.offset = 0
+	case common.ActRefresh:
+		if m.eventCh == nil {
+			return m, m.Init()
+		}
+	}
+	return m, nil
+}
+
+func (m Model) viewportHeight() int {
+	return max(m.height-5, 5) // header + footer
+}
+
+func (m *Model) scrollToBottom() {
+	m.offset = max(len(m.events)-m.viewportHeight(), 0)
+}
+
+// Update handles stream messages and key events.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if m.following {
+			m.scrollToBottom()
+		}
+
+	case streamStartedMsg:
+		m.eventCh, m.errCh, m.cancel = msg.eventCh, msg.errCh, msg.cancel
+		m.err = nil
+		return m, m.next()
+
+	case EventMsg:
+		m.events = append(m.events, msg.Event)
+		if len(m.events) > MaxEvents {
+			m.events = m.events[len(m.events)-MaxEvents:]
+		}
+		if m.following {
+			m.scrollToBottom()
+		}
+		return m, m.next()
+
+	case StreamErrMsg:
+		m.Stop()
+		m.eventCh, m.errCh, m.cancel = nil, nil, nil
+		m.err = msg.Err
+
+	case actionMsg:
+		return m.do(msg.action)
+
+	case tea.KeyMsg:
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		for _, a := range actions {
+			if common.Keys.Is(msg, a) {
+				return m.do(a)
+			}
+		}
+	}
+	return m, nil
+}
+
+// View renders the event list.
+func (m Model) View() string {
+	if m.showHelp {
+		return common.RenderHelp("Octo Events",
+			common.HelpSection{Title: "Navigation", Actions: []common.Action{common.ActUp, common.ActDown, common.ActTop, common.ActBottom, common.ActFollow}},
+			common.HelpSection{Title: "Events", Actions: []common.Action{common.ActClear, common.ActRefresh}},
+			common.HelpSection{Title: "General", Actions: []common.Action{common.ActHelp, common.ActQuit, common.ActCancel}},
+		)
+	}
+	var b strings.Builder
+
+	title := "🐙 Octo Events"
+	if m.following {
+		title += " [FOLLOWING]"
+	}
+	b.WriteString(styles.Title.Render(title))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", 60))
+	b.WriteString("\n")
+
+	if len(m.events) == 0 {
+		b.WriteString(styles.Info.Render("  Waiting for events..."))
+		b.WriteString("\n")
+	}
+	end := min(m.offset+m.viewportHeight(), len(m.events))
+	for _, ev := range m.events[m.offset:end] {
+		b.WriteString(renderEvent(ev))
+		b.WriteString("\n")
+	}
+
+	if m.err != nil {
+		b.WriteString("\n")
+		b.WriteString(styles.Error.Render(fmt.Sprintf("Stream error: %v (r: reconnect)", m.err)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(strings.Repeat("─", 60))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render(common.ShortHelp(
+		common.Item("scroll", common.ActUp, common.ActDown),
+		common.Item("top/bottom", common.ActTop, common.ActBottom),
+		common.Item("follow", common.ActFollow),
+		common.Item("clear", common.ActClear),
+		common.Item("help", common.ActHelp),
+		common.Item("quit", common.ActQuit),
+	)))
+	return b.String()
+}
+
+// renderEvent renders one event, colored by what it means for the resource.
+func renderEvent(ev docker.Event) string {
+	actor := ev.Name
+	if id := ev.ID; id != "" && id != ev.Name {
+		if len(id) > 12 {
+			id = id[:12]
+		}
+		if actor == "" {
+			actor = id
+		} else {
+			actor += " (" + id + ")"
+		}
+	}
+	line := fmt.Sprintf("%s  %-9s %-14s %s", ev.Time.Format(time.TimeOnly), ev.Type, ev.Action, actor)
+	action, _, _ := strings.Cut(ev.Action, ":")
+	switch action {
+	case "die", "kill", "oom", "destroy", "delete", "remove":
+		return styles.Error.Render(line)
+	case "stop", "pause", "health_status":
+		return styles.Warning.Render(line)
+	case "start", "create", "restart", "unpause", "pull":
+		return styles.Success.Render(line)
+	}
+	return styles.Normal.Render(line)
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// TestEvents_StreamsEvents tests events are read from the stream and rendered
+func TestEvents_StreamsEvents(t *testing.T) {
+	eventCh := make(chan docker.Event, 2)
+	errCh := make(chan error, 1)
+	cancelled := false
+	mock := &docker.MockDockerService{
+		StreamEventsFn: func(ctx context.Context) (<-chan docker.Event, <-chan error, func()) {
+			return eventCh, errCh, func() { cancelled = true }
+		},
+	}
+	m := New(mock)
+	msg := m.Init()()
+	updated, cmd := m.Update(msg)
+	require.NotNil(t, cmd)
+
+	at := time.Date(2024, 5, 1, 12, 30, 0, 0, time.Local)
+	eventCh <- docker.Event{Time: at, Type: "container", Action: "die", ID: "0123456789abcdef", Name: "web"}
+	updated, cmd = updated.(Model).Update(cmd())
+	require.NotNil(t, cmd)
+
+	view := updated.(Model).View()
+	assert.Contains(t, view, "12:30:00")
+	assert.Contains(t, view, "die")
+	assert.Contains(t, view, "web (0123456789ab)")
+
+	errCh <- errors.New("daemon went away")
+	updated, _ = updated.(Model).Update(cmd())
+	assert.True(t, cancelled)
+	assert.Contains(t, updated.(Model).View(), "Stream error: daemon went away")
+}
+
+// TestEvents_KeepsMaxEvents tests old events are dropped
+func TestEvents_KeepsMaxEvents(t *testing.T) {
+	var m tea.Model = New(&docker.MockDockerService{})
+	for i := 0; i < MaxEvents+5; i++ {
+		m, _ = m.Update(EventMsg{Event: docker.Event{Action: "start"}})
+	}
+	assert.Len(t, m.(Model).events, MaxEvents)
+}
+
+// TestEvents_QuitStopsStream tests q cancels the stream
+func TestEvents_QuitStopsStream(t *testing.T) {
+	cancelled := false
+	m := New(&docker.MockDockerService{})
+	m.cancel = func() { cancelled = true }
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+	assert.True(t, cancelled)
+}
@@ -6,12 +6,18 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/logfile"
+	"github.com/bsisduck/octo/internal/logwatch"
+	"github.com/bsisduck/octo/internal/tui/common"
+	"github.com/bsisduck/octo/internal/ui/format"
 	"github.com/bsisduck/octo/internal/ui/styles"
 )
 
@@ -33,6 +39,12 @@ type StreamErrMsg struct {
 	Err error
 }
 
+// StreamEventMsg reports a disconnect, reconnect or container switch of the
+// followed stream.
+type StreamEventMsg struct {
+	Event docker.FollowEvent
+}
+
 // exportDoneMsg carries the result of the export operation.
 type exportDoneMsg struct {
 	path  string
@@ -43,16 +55,34 @@ type exportDoneMsg struct {
 // clearStatusMsg clears the status message after a timeout.
 type clearStatusMsg struct{}
 
+// alertDispatchMsg carries the result of running an alert's file/exec actions.
+type alertDispatchMsg struct {
+	err error
+}
+
+// statsTickMsg refreshes the stats panel while it is shown.
+type statsTickMsg struct{}
+
+// bellDoneMsg ends the frame carrying the terminal bell.
+type bellDoneMsg struct{}
+
+// bellDuration is how long the bell stays in the view: long enough for the
+// renderer to draw a frame with it.
+const bellDuration = 100 * time.Millisecond
+
 // Model is a Bubble Tea model for viewing container logs with follow,
-// search, and export functionality, backed by a ring buffer.
+// search, and export functionality, backed by a LineStore (a ring buffer,
+// or a disk-spilling buffer for long scrollback).
 type Model struct {
 	docker        docker.DockerService
 	containerID   string
 	containerName string
 
-	buffer    *RingBuffer
-	viewLines []string // current visible lines (from buffer, possibly filtered)
-	offset    int      // scroll position in viewLines
+	buffer    LineStore
+	viewIndex []int64 // sequence numbers of lines matching the filter
+	filtered  bool    // whether viewIndex is in use
+	scanned   int64   // sequence number up to which viewIndex is built
+	offset    int     // scroll position in the view
 	width     int
 	height    int
 
@@ -63,24 +93,78 @@ type Model struct {
 	useRegex      bool // regex vs text search toggle
 	compiledRegex *regexp.Regexp
 
+	exporting    bool   // currently typing the export file name
+	exportPath   string // export destination being edited
+	exportPreset int    // index into logfile.Presets
+
+	alerts      *logwatch.Watcher // registered alert rules
+	addingAlert bool              // currently typing an alert pattern
+	alertInput  string            // alert pattern being edited
+	alertBanner string            // most recent alert, shown until dismissed
+	alertCount  int               // alerts fired since the banner was dismissed
+	bell        bool              // ring the terminal bell with the next frames
+
+	stats     *logwatch.Stats // rate/volume statistics of streamed entries
+	showStats bool            // stats panel visible
+	showHelp  bool            // key help overlay visible
+
 	err               error
 	statusMessage     string
 	truncationWarning string
 
-	logCh       <-chan docker.LogEntry // persistent stream channel
-	errCh       <-chan error           // persistent stream error channel
-	logCancelFn func()                 // cancel for active stream goroutine
+	logCh       <-chan docker.LogEntry    // persistent stream channel
+	eventCh     <-chan docker.FollowEvent // reconnect/switch notifications
+	logCancelFn func()                    // cancel for active stream goroutine
+	lastSeen    time.Time                 // newest initial entry; the stream resumes after it
 }
 
-// New creates a logs model for the given container.
+// New creates a logs model for the given container, keeping the last
+// DefaultCapacity lines in memory.
 func New(service docker.DockerService, containerID, containerName string) Model {
+	m, _ := NewWithOptions(service, containerID, containerName, BufferOptions{})
+	return m
+}
+
+// NewWithOptions creates a logs model with a configured line store. When the
+// spill file cannot be created the model falls back to an in-memory buffer
+// and the error is returned alongside it.
+func NewWithOptions(service docker.DockerService, containerID, containerName string, opts BufferOptions) (Model, error) {
+	buffer, err := newLineStore(opts)
 	return Model{
 		docker:        service,
 		containerID:   containerID,
 		containerName: containerName,
-		buffer:        NewRingBuffer(DefaultCapacity),
+		buffer:        buffer,
 		following:     true,
+		alerts:        logwatch.NewWatcher(),
+		stats:         logwatch.NewStats(logwatch.DefaultStatsWindow),
+	}, err
+}
+
+// WithAlerts registers alert rules that are checked against streamed entries.
+func (m Model) WithAlerts(rules ...logwatch.Rule) Model {
+	for _, r := range rules {
+		m.alerts.Add(r)
+	}
+	return m
+}
+
+// Close releases the line store, removing any spill file.
+func (m Model) Close() error {
+	return m.buffer.Close()
+}
+
+// Stop ends the log stream and releases the line store.
+func (m Model) Stop() {
+	if m.logCancelFn != nil {
+		m.logCancelFn()
 	}
+	_ = m.Close()
+}
+
+// CapturingInput reports whether keys are being typed into a prompt.
+func (m Model) CapturingInput() bool {
+	return m.filtering || m.exporting || m.addingAlert
 }
 
 // Init starts the initial log fetch.
@@ -98,83 +182,134 @@ func (m Model) fetchInitialLogs() tea.Cmd {
 	}
 }
 
-// startStream begins following the container log stream.
+// startStream begins following the container log stream. The stream
+// reconnects on its own when the daemon restarts or the container is
+// recreated, resuming after the last initial entry without duplicates.
 func (m *Model) startStream() tea.Cmd {
 	ctx := context.Background()
-	logCh, errCh, cancel := m.docker.StreamContainerLogs(ctx, m.containerID)
+	logCh, eventCh, cancel := docker.FollowContainerLogs(ctx, m.docker, m.containerID, docker.FollowOptions{
+		Since:        m.lastSeen,
+		TrackService: true,
+	})
 	m.logCh = logCh
-	m.errCh = errCh
+	m.eventCh = eventCh
 	m.logCancelFn = cancel
-
-	return func() tea.Msg {
-		select {
-		case entry, ok := <-logCh:
-			if !ok {
-				return StreamErrMsg{Err: nil}
-			}
-			return StreamLogMsg{Entry: entry}
-		case err := <-errCh:
-			return StreamErrMsg{Err: err}
-		}
-	}
+	return m.continueStream()
 }
 
-// continueStream reads the next entry from the active stream.
+// continueStream reads the next entry or event from the active stream.
 func (m Model) continueStream() tea.Cmd {
 	if m.logCh == nil {
 		return nil
 	}
+	logCh, eventCh := m.logCh, m.eventCh
 	return func() tea.Msg {
 		select {
-		case entry, ok := <-m.logCh:
+		case entry, ok := <-logCh:
 			if !ok {
 				return StreamErrMsg{Err: nil}
 			}
 			return StreamLogMsg{Entry: entry}
-		case err := <-m.errCh:
-			return StreamErrMsg{Err: err}
+		case ev, ok := <-eventCh:
+			if !ok {
+				return StreamErrMsg{Err: nil}
+			}
+			return StreamEventMsg{Event: ev}
 		}
 	}
 }
 
-func formatLogLine(e docker.LogEntry) string {
-	ts := e.Timestamp.Format("2006-01-02 15:04:05")
-	return fmt.Sprintf("%s  %-6s  %s", ts, e.Stream, e.Content)
+// matchesFilter reports whether a rendered line passes the active filter.
+func (m Model) matchesFilter(line string) bool {
+	if m.filterText == "" {
+		return true
+	}
+	if m.useRegex && m.compiledRegex != nil {
+		return m.compiledRegex.MatchString(line)
+	}
+	// Plain text search (case-insensitive)
+	return strings.Contains(strings.ToLower(line), strings.ToLower(m.filterText))
 }
 
-// refreshViewLines rebuilds viewLines from the buffer, applying filter if active.
+// scanChunk is how many lines are read from the store at a time when
+// filtering, which bounds memory use for disk-backed stores.
+const scanChunk = 4096
+
+// refreshViewLines rebuilds the view from the buffer, applying filter if active.
 func (m *Model) refreshViewLines() {
-	lines := m.buffer.Lines()
-	if lines == nil {
-		m.viewLines = nil
-		return
-	}
+	m.viewIndex = nil
+	m.scanned = m.buffer.Dropped()
+	m.extendView()
+}
 
-	if m.filterText == "" {
-		m.viewLines = lines
+// extendView brings the view up to date with lines appended since the last
+// call: lines that have been dropped are pruned and new lines are matched
+// against the filter. Without a filter the view is the store itself.
+func (m *Model) extendView() {
+	base := m.buffer.Dropped()
+	end := base + int64(m.buffer.Len())
+	m.filtered = m.filterText != ""
+	if !m.filtered {
+		m.viewIndex = nil
+		m.scanned = end
 		return
 	}
 
-	if m.useRegex && m.compiledRegex != nil {
-		var filtered []string
-		for _, line := range lines {
-			if m.compiledRegex.MatchString(line) {
-				filtered = append(filtered, line)
+	if n := sort.Search(len(m.viewIndex), func(i int) bool { return m.viewIndex[i] >= base }); n > 0 {
+		m.viewIndex = m.viewIndex[n:]
+	}
+	if m.scanned < base {
+		m.scanned = base
+	}
+	for m.scanned < end {
+		start := int(m.scanned - base)
+		lines := m.buffer.Slice(start, min(start+scanChunk, int(end-base)))
+		if len(lines) == 0 {
+			break
+		}
+		for i, line := range lines {
+			if m.matchesFilter(line) {
+				m.viewIndex = append(m.viewIndex, m.scanned+int64(i))
 			}
 		}
-		m.viewLines = filtered
-		return
+		m.scanned += int64(len(lines))
 	}
+}
 
-	// Plain text search (case-insensitive)
-	query := strings.ToLower(m.filterText)
-	var filtered []string
-	for _, line := range lines {
-		if strings.Contains(strings.ToLower(line), query) {
-			filtered = append(filtered, line)
+// viewLen returns the number of lines in the (possibly filtered) view.
+func (m Model) viewLen() int {
+	if !m.filtered {
+		return m.buffer.Len()
+	}
+	return len(m.viewIndex)
+}
+
+// visibleLines returns view lines [start, end), paging them in from the
+// store. Filtered lines dropped since the view was built are skipped.
+func (m Model) visibleLines(start, end int) []string {
+	if start < 0 {
+		start = 0
+	}
+	if end > m.viewLen() {
+		end = m.viewLen()
+	}
+	if start >= end {
+		return nil
+	}
+	if !m.filtered {
+		return m.buffer.Slice(start, end)
+	}
+
+	base := m.buffer.Dropped()
+	lines := make([]string, 0, end-start)
+	for _, seq := range m.viewIndex[start:end] {
+		if seq < base {
+			continue
 		}
+		i := int(seq - base)
+		lines = append(lines, m.buffer.Slice(i, i+1)...)
 	}
-	m.viewLines = filtered
+	return lines
 }
 
 // updateTruncationWarning updates the truncation warning based on dropped lines.
@@ -190,6 +325,15 @@ func (m *Model) updateTruncationWarning() {
 // viewportHeight returns the number of log lines that fit in the viewport.
 func (m Model) viewportHeight() int {
 	h := m.height - 7 // header + truncation + filter + footer + padding
+	if m.exporting || m.addingAlert {
+		h-- // export/alert prompt
+	}
+	if m.alertBanner != "" {
+		h-- // alert banner
+	}
+	if m.showStats {
+		h -= 2 // stats panel
+	}
 	if h < 5 {
 		h = 5
 	}
@@ -198,13 +342,25 @@ func (m Model) viewportHeight() int {
 
 // scrollToBottom moves offset to show the latest lines.
 func (m *Model) scrollToBottom() {
-	maxOffset := len(m.viewLines) - m.viewportHeight()
+	maxOffset := m.viewLen() - m.viewportHeight()
 	if maxOffset < 0 {
 		maxOffset = 0
 	}
 	m.offset = maxOffset
 }
 
+// scroll moves the view by delta lines. Scrolling up stops following;
+// reaching the bottom follows again.
+func (m *Model) scroll(delta int) {
+	maxOffset := max(0, m.viewLen()-m.viewportHeight())
+	m.offset = min(max(m.offset+delta, 0), maxOffset)
+	if delta < 0 {
+		m.following = false
+	} else if m.offset >= maxOffset {
+		m.following = true
+	}
+}
+
 // Update handles messages and key events.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -220,7 +376,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		for _, entry := range msg.Entries {
-			m.buffer.Append(formatLogLine(entry))
+			m.buffer.AppendEntry(entry, logfile.FormatLine(entry))
+			if entry.Timestamp.After(m.lastSeen) {
+				m.lastSeen = entry.Timestamp
+			}
 		}
 		m.refreshViewLines()
 		if m.following {
@@ -230,14 +389,42 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.startStream()
 
 	case StreamLogMsg:
-		m.buffer.Append(formatLogLine(msg.Entry))
-		m.refreshViewLines()
+		m.buffer.AppendEntry(msg.Entry, logfile.FormatLine(msg.Entry))
+		m.stats.Add(msg.Entry)
+		m.extendView()
 		m.updateTruncationWarning()
+		alertCmd := m.checkAlerts(msg.Entry)
 		if m.following {
 			m.scrollToBottom()
 		}
 		// Continue reading stream
-		return m, m.continueStream()
+		return m, tea.Batch(m.continueStream(), alertCmd)
+
+	case bellDoneMsg:
+		m.bell = false
+		return m, nil
+
+	case alertDispatchMsg:
+		if msg.err != nil {
+			m.statusMessage = msg.err.Error()
+			return m, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+				return clearStatusMsg{}
+			})
+		}
+		return m, nil
+
+	case StreamEventMsg:
+		m.statusMessage = msg.Event.String()
+		cmds := []tea.Cmd{m.continueStream()}
+		switch msg.Event.Kind {
+		case docker.FollowSwitched:
+			m.containerID = msg.Event.ContainerID
+		case docker.FollowReconnected:
+			cmds = append(cmds, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+				return clearStatusMsg{}
+			}))
+		}
+		return m, tea.Batch(cmds...)
 
 	case StreamErrMsg:
 		if msg.Err != nil {
@@ -263,8 +450,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.statusMessage = ""
 		return m, nil
 
+	case statsTickMsg:
+		if !m.showStats {
+			return m, nil
+		}
+		return m, tickStats()
+
 	case tea.KeyMsg:
 		return m.handleKeyMsg(msg)
+
+	case tea.MouseMsg:
+		if d := common.Wheel(msg); d != 0 && !m.showHelp {
+			m.scroll(d * common.WheelLines)
+		}
+		return m, nil
 	}
 
 	return m, nil
@@ -272,12 +471,133 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleKeyMsg dispatches key events based on current mode.
 func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.showHelp {
+		m.showHelp = false
+		return m, nil
+	}
+	if m.exporting {
+		return m.handleExportKey(msg)
+	}
+	if m.addingAlert {
+		return m.handleAlertKey(msg)
+	}
 	if m.filtering {
 		return m.handleFilterKey(msg)
 	}
 	return m.handleNormalKey(msg)
 }
 
+// handleAlertKey handles key events while typing a new alert pattern.
+// Patterns are regular expressions, or "level:<name>" for a level threshold.
+func (m Model) handleAlertKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.addingAlert = false
+		if strings.TrimSpace(m.alertInput) == "" {
+			return m, nil
+		}
+		rule, err := logwatch.ParseRule(m.alertInput)
+		if err != nil {
+			m.statusMessage = err.Error()
+		} else {
+			m.alerts.Add(rule)
+			m.statusMessage = fmt.Sprintf("Alert registered: %s", rule.Name)
+		}
+		return m, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+			return clearStatusMsg{}
+		})
+
+	case tea.KeyEscape:
+		m.addingAlert = false
+		m.alertInput = ""
+		return m, nil
+
+	case tea.KeyBackspace:
+		m.alertInput = trimLastRune(m.alertInput)
+		return m, nil
+
+	case tea.KeyRunes, tea.KeySpace:
+		m.alertInput += string(msg.Runes)
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// checkAlerts evaluates a streamed entry against the registered rules,
+// updating the banner and returning commands for the bell and any
+// file/exec actions of the fired rules.
+func (m *Model) checkAlerts(entry docker.LogEntry) tea.Cmd {
+	if m.alerts == nil {
+		return nil
+	}
+	fired := m.alerts.Check(entry)
+	if len(fired) == 0 {
+		return nil
+	}
+	m.alertCount += len(fired)
+	m.alertBanner = fired[len(fired)-1].String()
+
+	m.bell = true
+	cmds := []tea.Cmd{tea.Tick(bellDuration, func(time.Time) tea.Msg { return bellDoneMsg{} })}
+	for _, a := range fired {
+		if a.Rule.Exec == "" && a.Rule.File == "" {
+			continue
+		}
+		container := m.containerName
+		cmds = append(cmds, func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), logwatch.DispatchTimeout)
+			defer cancel()
+			return alertDispatchMsg{err: logwatch.Dispatch(ctx, a, container)}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleExportKey handles key events while editing the export file name.
+// Tab cycles through logfile.Presets, swapping the file extension to match.
+func (m Model) handleExportKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.exporting = false
+		path := strings.TrimSpace(m.exportPath)
+		if path == "" {
+			return m, nil
+		}
+		return m, m.exportLogs(path)
+
+	case tea.KeyEscape:
+		m.exporting = false
+		m.exportPath = ""
+		return m, nil
+
+	case tea.KeyTab:
+		current := logfile.Presets[m.exportPreset]
+		m.exportPreset = (m.exportPreset + 1) % len(logfile.Presets)
+		next := logfile.Presets[m.exportPreset]
+		if strings.HasSuffix(m.exportPath, current.Extension) {
+			m.exportPath = strings.TrimSuffix(m.exportPath, current.Extension) + next.Extension
+		}
+		return m, nil
+
+	case tea.KeyBackspace:
+		m.exportPath = trimLastRune(m.exportPath)
+		return m, nil
+
+	case tea.KeyRunes, tea.KeySpace:
+		m.exportPath += string(msg.Runes)
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// trimLastRune removes the last character of a prompt's input.
+func trimLastRune(s string) string {
+	_, size := utf8.DecodeLastRuneInString(s)
+	return s[:len(s)-size]
+}
+
 // handleFilterKey handles key events while in filter input mode.
 func (m Model) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
@@ -310,9 +630,7 @@ func (m Model) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyBackspace:
-		if len(m.filterText) > 0 {
-			m.filterText = m.filterText[:len(m.filterText)-1]
-		}
+		m.filterText = trimLastRune(m.filterText)
 		return m, nil
 
 	case tea.KeyRunes:
@@ -325,111 +643,187 @@ func (m Model) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleNormalKey handles key events in normal (non-filter) mode.
 func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "up", "k":
-		if m.offset > 0 {
-			m.offset--
-		}
-		m.following = false
+	keys := common.Keys
+	switch {
+	case keys.Is(msg, common.ActUp):
+		m.scroll(-1)
 		return m, nil
 
-	case "down", "j":
-		maxOffset := len(m.viewLines) - m.viewportHeight()
-		if maxOffset < 0 {
-			maxOffset = 0
-		}
-		if m.offset < maxOffset {
-			m.offset++
-		}
-		// If scrolled to very bottom, re-enable following
-		if m.offset >= maxOffset {
-			m.following = true
-		}
+	case keys.Is(msg, common.ActDown):
+		m.scroll(1)
 		return m, nil
 
-	case "g":
+	case keys.Is(msg, common.ActTop):
 		m.offset = 0
 		m.following = false
 		return m, nil
 
-	case "G":
+	case keys.Is(msg, common.ActBottom):
 		m.scrollToBottom()
 		m.following = true
 		return m, nil
 
-	case "f":
+	case keys.Is(msg, common.ActFollow):
 		m.following = !m.following
 		if m.following {
 			m.scrollToBottom()
 		}
 		return m, nil
 
-	case "/":
+	case keys.Is(msg, common.ActFilter):
 		m.filtering = true
 		m.filterText = ""
 		m.useRegex = false
 		m.compiledRegex = nil
 		return m, nil
 
-	case "ctrl+r":
+	case keys.Is(msg, common.ActRegexFilter):
 		m.filtering = true
 		m.filterText = ""
 		m.useRegex = !m.useRegex
 		m.compiledRegex = nil
 		return m, nil
 
-	case "e":
-		return m, m.exportLogs()
+	case keys.Is(msg, common.ActAlert):
+		m.addingAlert = true
+		m.alertInput = ""
+		return m, nil
 
-	case "q", "esc":
-		if m.logCancelFn != nil {
-			m.logCancelFn()
-			m.logCancelFn = nil
+	case keys.Is(msg, common.ActClearAlert):
+		m.alertBanner = ""
+		m.alertCount = 0
+		return m, nil
+
+	case keys.Is(msg, common.ActStats):
+		m.showStats = !m.showStats
+		if m.showStats {
+			return m, tickStats()
 		}
+		return m, nil
+
+	case keys.Is(msg, common.ActExport):
+		m.exporting = true
+		m.exportPreset = 0
+		m.exportPath = m.defaultExportPath(logfile.Presets[0])
+		return m, nil
+
+	case keys.Is(msg, common.ActHelp):
+		m.showHelp = true
+		return m, nil
+
+	case keys.Is(msg, common.ActQuit), keys.Is(msg, common.ActCancel):
+		m.Stop()
+		m.logCancelFn = nil
 		return m, tea.Quit
 	}
 
 	return m, nil
 }
 
-// exportLogs writes all buffered lines to ~/.octo/logs/{containerID}.log.
-func (m Model) exportLogs() tea.Cmd {
-	return func() tea.Msg {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return exportDoneMsg{err: fmt.Errorf("home dir: %w", err)}
-		}
+// tickStats schedules the next stats panel refresh; rates decay while the
+// stream is quiet, so the panel is redrawn even without new entries.
+func tickStats() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return statsTickMsg{}
+	})
+}
 
-		dir := filepath.Join(home, ".octo", "logs")
-		if mkdirErr := os.MkdirAll(dir, 0o755); mkdirErr != nil {
-			return exportDoneMsg{err: fmt.Errorf("create dir: %w", mkdirErr)}
-		}
+// renderStats renders the two-line stats panel: rates and stderr ratio,
+// then a per-second sparkline with the level histogram.
+func (m Model) renderStats() string {
+	snap := m.stats.Snapshot()
+	rates := fmt.Sprintf("Rate (%s): %.1f lines/s | %s/s | stderr %.0f%%",
+		snap.Window, snap.LinesPerSec, format.Size(uint64(snap.BytesPerSec)), snap.StderrRatio*100)
+	levels := snap.LevelSummary()
+	if levels == "" {
+		levels = "no entries"
+	}
+	return styles.Info.Render(rates) + "\n" +
+		styles.StatValue.Render(format.Sparkline(snap.Rate)) + "  " + styles.Help.Render(levels)
+}
 
-		path := filepath.Join(dir, m.containerID+".log")
-		lines := m.buffer.Lines()
+// defaultExportPath suggests a timestamped file under ~/.octo/logs so that
+// repeated exports never overwrite each other.
+func (m Model) defaultExportPath(preset logfile.Preset) string {
+	name := m.containerName
+	if name == "" {
+		name = m.containerID
+	}
+	file := logfile.DefaultName(name, preset, time.Now())
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return file
+	}
+	return filepath.Join(home, ".octo", "logs", file)
+}
 
-		f, err := os.Create(path)
+// exportLogs writes the current view (respecting any active filter) to path.
+// The encoding is inferred from the file extension: .ndjson for NDJSON with
+// timestamp/stream fields, a .gz suffix for gzip, plain text otherwise.
+// The store is read in chunks so disk-backed scrollback is never loaded
+// into memory at once.
+func (m Model) exportLogs(path string) tea.Cmd {
+	buffer := m.buffer
+	filter := m.matchesFilter
+	total := buffer.Len()
+
+	return func() tea.Msg {
+		if strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+		w, err := logfile.Create(path, logfile.OptionsFromPath(path))
 		if err != nil {
-			return exportDoneMsg{err: fmt.Errorf("create file: %w", err)}
+			return exportDoneMsg{err: err}
 		}
-		defer func() { _ = f.Close() }()
-
-		for _, line := range lines {
-			if _, err := fmt.Fprintln(f, line); err != nil {
-				return exportDoneMsg{err: fmt.Errorf("write: %w", err)}
+		count := 0
+		for start := 0; start < total; start += scanChunk {
+			end := min(start+scanChunk, total)
+			lines := buffer.Slice(start, end)
+			entries := buffer.EntrySlice(start, end)
+			for i := range min(len(lines), len(entries)) {
+				if !filter(lines[i]) {
+					continue
+				}
+				if err := w.Write(entries[i]); err != nil {
+					_ = w.Close()
+					return exportDoneMsg{err: err}
+				}
+				count++
 			}
 		}
-
-		return exportDoneMsg{path: path, count: len(lines)}
+		if err := w.Close(); err != nil {
+			return exportDoneMsg{err: err}
+		}
+		return exportDoneMsg{path: path, count: count}
 	}
 }
 
 // View renders the logs viewer.
 func (m Model) View() string {
+	if m.bell {
+		// The bell goes out with the frame, through the renderer that owns
+		// the terminal.
+		return "\a" + m.view()
+	}
+	return m.view()
+}
+
+func (m Model) view() string {
 	if m.err != nil {
 		return fmt.Sprintf("Error: %v\n\nPress 'q' to quit.", m.err)
 	}
 
+	if m.showHelp {
+		return common.RenderHelp("Logs",
+			common.HelpSection{Title: "Navigation", Actions: []common.Action{common.ActUp, common.ActDown, common.ActTop, common.ActBottom, common.ActFollow}},
+			common.HelpSection{Title: "Search", Actions: []common.Action{common.ActFilter, common.ActRegexFilter}},
+			common.HelpSection{Title: "Tools", Actions: []common.Action{common.ActStats, common.ActAlert, common.ActClearAlert, common.ActExport}},
+			common.HelpSection{Title: "General", Actions: []common.Action{common.ActHelp, common.ActQuit, common.ActCancel}},
+		)
+	}
+
 	var b strings.Builder
 
 	// Header
@@ -443,6 +837,19 @@ func (m Model) View() string {
 	b.WriteString(strings.Repeat("\u2500", 60))
 	b.WriteString("\n")
 
+	// Alert banner
+	if m.alertBanner != "" {
+		banner := fmt.Sprintf("\U0001F514 ALERT (%d): %s  [%s: dismiss]", m.alertCount, m.alertBanner, common.Keys.Primary(common.ActClearAlert))
+		b.WriteString(styles.DeleteConfirm.Render(banner))
+		b.WriteString("\n")
+	}
+
+	// Stats panel
+	if m.showStats {
+		b.WriteString(m.renderStats())
+		b.WriteString("\n")
+	}
+
 	// Truncation warning
 	if m.truncationWarning != "" {
 		b.WriteString(styles.Warning.Render("\u26a0 " + m.truncationWarning))
@@ -462,24 +869,29 @@ func (m Model) View() string {
 		b.WriteString("\n")
 	}
 
+	// Alert prompt
+	if m.addingAlert {
+		prompt := fmt.Sprintf("Alert on (regex or level:<name>): %s\u2588", m.alertInput)
+		b.WriteString(styles.Info.Render(prompt))
+		b.WriteString("\n")
+	}
+
+	// Export prompt
+	if m.exporting {
+		prompt := fmt.Sprintf("Export to: %s\u2588 [%s]  (tab: format | enter: save | esc: cancel)",
+			m.exportPath, logfile.Presets[m.exportPreset].Name)
+		b.WriteString(styles.Info.Render(prompt))
+		b.WriteString("\n")
+	}
+
 	// Log lines viewport
 	viewport := m.viewportHeight()
 
-	if len(m.viewLines) == 0 {
+	if m.viewLen() == 0 {
 		b.WriteString(styles.Info.Render("  No log entries"))
 		b.WriteString("\n")
 	} else {
-		start := m.offset
-		if start < 0 {
-			start = 0
-		}
-		end := start + viewport
-		if end > len(m.viewLines) {
-			end = len(m.viewLines)
-		}
-
-		for i := start; i < end; i++ {
-			line := m.viewLines[i]
+		for _, line := range m.visibleLines(m.offset, m.offset+viewport) {
 			// Color stderr lines differently
 			if strings.Contains(line, "stderr") {
 				line = styles.Error.Render(line)
@@ -501,9 +913,18 @@ func (m Model) View() string {
 	// Footer
 	b.WriteString(strings.Repeat("\u2500", 60))
 	b.WriteString("\n")
-	b.WriteString(styles.Help.Render(
-		"\u2191\u2193/jk: scroll | g/G: top/bottom | f: follow | /: filter | ctrl+r: regex | e: export | q: back",
-	))
+	b.WriteString(styles.Help.Render(common.ShortHelp(
+		common.Item("scroll", common.ActUp, common.ActDown),
+		common.Item("top/bottom", common.ActTop, common.ActBottom),
+		common.Item("follow", common.ActFollow),
+		common.Item("filter", common.ActFilter),
+		common.Item("regex", common.ActRegexFilter),
+		common.Item("stats", common.ActStats),
+		common.Item("alert", common.ActAlert),
+		common.Item("export", common.ActExport),
+		common.Item("help", common.ActHelp),
+		common.Item("back", common.ActQuit),
+	)))
 
 	return b.String()
 }
@@ -3,6 +3,9 @@ package logs
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -74,12 +77,12 @@ func TestLogsModelScrolling(t *testing.T) {
 	model, _ := m.Update(InitialLogsMsg{Entries: entries})
 	m = model.(Model)
 
-	if len(m.viewLines) != 50 {
-		t.Fatalf("viewLines count = %d, want 50", len(m.viewLines))
+	if m.viewLen() != 50 {
+		t.Fatalf("viewLines count = %d, want 50", m.viewLen())
 	}
 
 	// Following should have scrolled to bottom
-	expectedBottomOffset := len(m.viewLines) - m.viewportHeight()
+	expectedBottomOffset := m.viewLen() - m.viewportHeight()
 	if m.offset != expectedBottomOffset {
 		t.Errorf("offset = %d after init, want %d (bottom)", m.offset, expectedBottomOffset)
 	}
@@ -124,6 +127,38 @@ func TestLogsModelScrolling(t *testing.T) {
 	}
 }
 
+func TestLogsModelMouseWheel(t *testing.T) {
+	entries := makeEntries(50, "stdout")
+	m := New(mockService(entries), "abc123", "test-container")
+	m.width = 80
+	m.height = 20
+
+	model, _ := m.Update(InitialLogsMsg{Entries: entries})
+	m = model.(Model)
+	bottom := m.offset
+
+	model, _ = m.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelUp})
+	m = model.(Model)
+	if m.following {
+		t.Error("expected following=false after wheel up")
+	}
+	if m.offset != bottom-3 {
+		t.Errorf("offset = %d after wheel up, want %d", m.offset, bottom-3)
+	}
+
+	// Scrolling past the bottom stops there and follows again
+	for i := 0; i < 3; i++ {
+		model, _ = m.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown})
+		m = model.(Model)
+	}
+	if m.offset != bottom {
+		t.Errorf("offset = %d after wheel down, want %d", m.offset, bottom)
+	}
+	if !m.following {
+		t.Error("expected following=true at the bottom")
+	}
+}
+
 func TestLogsModelFollowMode(t *testing.T) {
 	entries := makeEntries(5, "stdout")
 	mock := mockService(entries)
@@ -191,7 +226,7 @@ func TestLogsModelFilter(t *testing.T) {
 	model, _ := m.Update(InitialLogsMsg{Entries: entries})
 	m = model.(Model)
 
-	totalLines := len(m.viewLines)
+	totalLines := m.viewLen()
 	if totalLines != 10 {
 		t.Fatalf("viewLines = %d, want 10", totalLines)
 	}
@@ -221,15 +256,15 @@ func TestLogsModelFilter(t *testing.T) {
 	}
 
 	// Should have fewer lines (only those matching "error")
-	if len(m.viewLines) >= totalLines {
-		t.Errorf("filtered viewLines = %d, should be less than %d", len(m.viewLines), totalLines)
+	if m.viewLen() >= totalLines {
+		t.Errorf("filtered viewLines = %d, should be less than %d", m.viewLen(), totalLines)
 	}
-	if len(m.viewLines) == 0 {
+	if m.viewLen() == 0 {
 		t.Error("filtered viewLines should not be empty")
 	}
 
 	// Each filtered line should contain "error"
-	for i, line := range m.viewLines {
+	for i, line := range m.visibleLines(0, m.viewLen()) {
 		if !containsCaseInsensitive(line, "error") {
 			t.Errorf("filtered line %d = %q, does not contain 'error'", i, line)
 		}
@@ -316,11 +351,11 @@ func TestLogsModelRegexFilter(t *testing.T) {
 	}
 
 	// Should have filtered results
-	if len(m.viewLines) == 0 {
+	if m.viewLen() == 0 {
 		t.Error("expected some filtered results for 'err.*timeout' pattern")
 	}
-	if len(m.viewLines) >= 10 {
-		t.Errorf("expected fewer than 10 filtered lines, got %d", len(m.viewLines))
+	if m.viewLen() >= 10 {
+		t.Errorf("expected fewer than 10 filtered lines, got %d", m.viewLen())
 	}
 }
 
@@ -427,3 +462,195 @@ func TestLogsModelStreamError(t *testing.T) {
 		t.Errorf("statusMessage = %q, expected to contain 'Stream error'", m.statusMessage)
 	}
 }
+
+func TestLogsModelExportFilteredView(t *testing.T) {
+	entries := []docker.LogEntry{
+		{Timestamp: testTime, Stream: "stdout", Content: "request ok"},
+		{Timestamp: testTime.Add(time.Second), Stream: "stderr", Content: "request failed"},
+		{Timestamp: testTime.Add(2 * time.Second), Stream: "stdout", Content: "shutdown"},
+	}
+	m := New(mockService(entries), "abc123", "web")
+	m.width = 80
+	m.height = 30
+	model, _ := m.Update(InitialLogsMsg{Entries: entries})
+	m = model.(Model)
+	m.filterText = "request"
+	m.refreshViewLines()
+
+	// 'e' opens the prompt with a timestamped default name
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	m = model.(Model)
+	if !m.exporting {
+		t.Fatal("expected exporting=true after 'e'")
+	}
+	if !strings.HasPrefix(filepath.Base(m.exportPath), "web-") || !strings.HasSuffix(m.exportPath, ".log") {
+		t.Errorf("default export path = %q, want web-<timestamp>.log", m.exportPath)
+	}
+
+	// Tab switches to NDJSON and swaps the extension
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = model.(Model)
+	if !strings.HasSuffix(m.exportPath, ".ndjson") {
+		t.Errorf("export path after tab = %q, want .ndjson suffix", m.exportPath)
+	}
+
+	m.exportPath = filepath.Join(t.TempDir(), "out.ndjson")
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(Model)
+	if m.exporting || cmd == nil {
+		t.Fatal("expected enter to close the prompt and return an export command")
+	}
+	done, ok := cmd().(exportDoneMsg)
+	if !ok || done.err != nil {
+		t.Fatalf("export failed: %+v", done)
+	}
+	if done.count != 2 {
+		t.Errorf("exported %d lines, want 2 (filtered view)", done.count)
+	}
+	data, err := os.ReadFile(done.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"stream":"stderr"`) || strings.Contains(string(data), "shutdown") {
+		t.Errorf("unexpected NDJSON export:\n%s", data)
+	}
+}
+
+func TestLogsModelAlerts(t *testing.T) {
+	m := New(mockService(nil), "abc123", "web")
+	m.width = 80
+	m.height = 30
+
+	// Register a pattern through the 'a' prompt
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = model.(Model)
+	if !m.addingAlert {
+		t.Fatal("expected addingAlert=true after 'a'")
+	}
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("panic|OOM")})
+	m = model.(Model)
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(Model)
+	if m.alerts.Len() != 1 {
+		t.Fatalf("registered rules = %d, want 1", m.alerts.Len())
+	}
+
+	// A non-matching entry leaves the banner empty
+	model, _ = m.Update(StreamLogMsg{Entry: docker.LogEntry{Timestamp: testTime, Stream: "stdout", Content: "ok"}})
+	m = model.(Model)
+	if m.alertBanner != "" {
+		t.Errorf("unexpected banner %q", m.alertBanner)
+	}
+
+	// A matching entry raises the banner and rings the bell
+	model, cmd := m.Update(StreamLogMsg{Entry: docker.LogEntry{Timestamp: testTime, Stream: "stderr", Content: "OOM killed"}})
+	m = model.(Model)
+	if !strings.Contains(m.alertBanner, "OOM killed") || m.alertCount != 1 {
+		t.Errorf("banner = %q count = %d, want OOM alert", m.alertBanner, m.alertCount)
+	}
+	if !strings.Contains(m.View(), "ALERT (1)") {
+		t.Error("View should render the alert banner")
+	}
+	if cmd == nil {
+		t.Fatal("expected commands for stream continuation and bell")
+	}
+	if !strings.HasPrefix(m.View(), "\a") {
+		t.Error("View should carry the bell after an alert")
+	}
+	model, _ = m.Update(bellDoneMsg{})
+	m = model.(Model)
+	if strings.Contains(m.View(), "\a") {
+		t.Error("the bell should ring once")
+	}
+
+	// 'A' dismisses the banner
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	m = model.(Model)
+	if m.alertBanner != "" || m.alertCount != 0 {
+		t.Error("expected 'A' to dismiss the alert banner")
+	}
+}
+
+func TestLogsModelStatsPanel(t *testing.T) {
+	mock := mockService(nil)
+	m := New(mock, "abc123", "test-container")
+	m.width = 80
+	m.height = 30
+	heightWithout := m.viewportHeight()
+
+	for _, e := range []docker.LogEntry{
+		{Timestamp: testTime, Stream: "stdout", Content: "INFO ready"},
+		{Timestamp: testTime, Stream: "stderr", Content: "ERROR failed"},
+	} {
+		model, _ := m.Update(StreamLogMsg{Entry: e})
+		m = model.(Model)
+	}
+
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m = model.(Model)
+	if !m.showStats {
+		t.Fatal("'s' should show the stats panel")
+	}
+	if cmd == nil {
+		t.Error("showing stats should schedule a refresh tick")
+	}
+	if m.viewportHeight() != heightWithout-2 {
+		t.Errorf("viewportHeight() = %d, want %d", m.viewportHeight(), heightWithout-2)
+	}
+
+	view := m.View()
+	for _, want := range []string{"lines/s", "stderr 50%", "error 1", "info 1"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("stats panel missing %q", want)
+		}
+	}
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m = model.(Model)
+	if _, cmd = m.Update(statsTickMsg{}); cmd != nil {
+		t.Error("hidden stats panel should stop ticking")
+	}
+}
+
+func TestLogsModelStreamEvents(t *testing.T) {
+	m := New(mockService(nil), "abc123", "test-container")
+	m.width = 80
+	m.height = 30
+
+	model, _ := m.Update(StreamEventMsg{Event: docker.FollowEvent{
+		Kind: docker.FollowDisconnected, ContainerID: "abc123", Attempt: 2, Delay: time.Second,
+	}})
+	m = model.(Model)
+	if !containsStr(m.statusMessage, "reconnecting in 1s (attempt 2)") {
+		t.Errorf("statusMessage = %q, want reconnect notice", m.statusMessage)
+	}
+
+	model, _ = m.Update(StreamEventMsg{Event: docker.FollowEvent{Kind: docker.FollowSwitched, ContainerID: "def456"}})
+	m = model.(Model)
+	if m.containerID != "def456" {
+		t.Errorf("containerID = %q, want def456 after service recreation", m.containerID)
+	}
+	if !containsStr(m.statusMessage, "now following def456") {
+		t.Errorf("statusMessage = %q, want switch notice", m.statusMessage)
+	}
+}
+
+func TestLogsModelBackspaceTrimsRune(t *testing.T) {
+	m := New(mockService(nil), "abc123", "web")
+	m.exporting = true
+	m.exportPath = "logs-café"
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = model.(Model)
+	if m.exportPath != "logs-caf" {
+		t.Errorf("export path = %q, want %q", m.exportPath, "logs-caf")
+	}
+	m.exporting = false
+
+	m.addingAlert = true
+	m.alertInput = "错误"
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = model.(Model)
+	if m.alertInput != "错" {
+		t.Errorf("alert pattern = %q, want %q", m.alertInput, "错")
+	}
+}
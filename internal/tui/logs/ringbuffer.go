@@ -1,15 +1,22 @@
 package logs
 
-import "sync"
+import (
+	"sync"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
 
 // DefaultCapacity is the default maximum number of lines the ring buffer holds.
 const DefaultCapacity = 5000
 
 // RingBuffer is a fixed-capacity circular buffer for log lines.
 // It provides O(1) append and tracks how many lines have been dropped
-// due to overflow. All methods are safe for concurrent use.
+// due to overflow. Each line keeps the entry it was rendered from so
+// exports can preserve timestamps and streams. All methods are safe for
+// concurrent use.
 type RingBuffer struct {
 	lines    []string
+	entries  []docker.LogEntry
 	head     int   // index of the oldest element
 	count    int   // current number of stored elements
 	capacity int   // maximum number of elements
@@ -24,6 +31,7 @@ func NewRingBuffer(capacity int) *RingBuffer {
 	}
 	return &RingBuffer{
 		lines:    make([]string, capacity),
+		entries:  make([]docker.LogEntry, capacity),
 		capacity: capacity,
 	}
 }
@@ -34,18 +42,84 @@ func NewRingBuffer(capacity int) *RingBuffer {
 func (rb *RingBuffer) Append(line string) {
 	rb.mu.Lock()
 	defer rb.mu.Unlock()
+	rb.put(line, docker.LogEntry{Content: line})
+}
+
+// AppendEntry adds a log entry, storing both its rendered line and the
+// entry itself.
+func (rb *RingBuffer) AppendEntry(entry docker.LogEntry, line string) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	rb.put(line, entry)
+}
 
+// appendEvict is AppendEntry that also returns the pair pushed out of a full
+// buffer, so a SpillBuffer can move it to disk.
+func (rb *RingBuffer) appendEvict(entry docker.LogEntry, line string) (string, docker.LogEntry, bool) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	return rb.put(line, entry)
+}
+
+// put stores a line/entry pair and returns the pair it evicted, if any.
+// Callers must hold rb.mu.
+func (rb *RingBuffer) put(line string, entry docker.LogEntry) (evictedLine string, evictedEntry docker.LogEntry, evicted bool) {
 	if rb.count < rb.capacity {
 		// Buffer not full yet -- write at (head + count) mod capacity
 		idx := (rb.head + rb.count) % rb.capacity
 		rb.lines[idx] = line
+		rb.entries[idx] = entry
 		rb.count++
-	} else {
-		// Buffer full -- overwrite oldest at head
-		rb.lines[rb.head] = line
-		rb.head = (rb.head + 1) % rb.capacity
-		rb.dropped++
+		return "", docker.LogEntry{}, false
+	}
+	// Buffer full -- overwrite oldest at head
+	evictedLine, evictedEntry = rb.lines[rb.head], rb.entries[rb.head]
+	rb.lines[rb.head] = line
+	rb.entries[rb.head] = entry
+	rb.head = (rb.head + 1) % rb.capacity
+	rb.dropped++
+	return evictedLine, evictedEntry, true
+}
+
+// Slice returns lines [start, end) in chronological order, clamped to the
+// stored range.
+func (rb *RingBuffer) Slice(start, end int) []string {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	start, end = clampRange(start, end, rb.count)
+	result := make([]string, end-start)
+	for i := start; i < end; i++ {
+		result[i-start] = rb.lines[(rb.head+i)%rb.capacity]
+	}
+	return result
+}
+
+// EntrySlice returns entries [start, end), parallel to Slice.
+func (rb *RingBuffer) EntrySlice(start, end int) []docker.LogEntry {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	start, end = clampRange(start, end, rb.count)
+	result := make([]docker.LogEntry, end-start)
+	for i := start; i < end; i++ {
+		result[i-start] = rb.entries[(rb.head+i)%rb.capacity]
 	}
+	return result
+}
+
+// clampRange limits [start, end) to [0, n).
+func clampRange(start, end, n int) (int, int) {
+	if start < 0 {
+		start = 0
+	}
+	if end > n {
+		end = n
+	}
+	if start > end {
+		start = end
+	}
+	return start, end
 }
 
 // AppendBatch adds multiple lines efficiently with a single lock acquisition.
@@ -54,15 +128,7 @@ func (rb *RingBuffer) AppendBatch(lines []string) {
 	defer rb.mu.Unlock()
 
 	for _, line := range lines {
-		if rb.count < rb.capacity {
-			idx := (rb.head + rb.count) % rb.capacity
-			rb.lines[idx] = line
-			rb.count++
-		} else {
-			rb.lines[rb.head] = line
-			rb.head = (rb.head + 1) % rb.capacity
-			rb.dropped++
-		}
+		rb.put(line, docker.LogEntry{Content: line})
 	}
 }
 
@@ -83,6 +149,23 @@ func (rb *RingBuffer) Lines() []string {
 	return result
 }
 
+// Entries returns the stored entries in chronological order, parallel to Lines.
+// A new slice is allocated; the caller owns the returned data.
+func (rb *RingBuffer) Entries() []docker.LogEntry {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	if rb.count == 0 {
+		return nil
+	}
+
+	result := make([]docker.LogEntry, rb.count)
+	for i := 0; i < rb.count; i++ {
+		result[i] = rb.entries[(rb.head+i)%rb.capacity]
+	}
+	return result
+}
+
 // Len returns the current number of stored lines.
 func (rb *RingBuffer) Len() int {
 	rb.mu.Lock()
@@ -108,6 +191,7 @@ func (rb *RingBuffer) Clear() {
 	// Zero out the slice to allow GC of old strings
 	for i := range rb.lines {
 		rb.lines[i] = ""
+		rb.entries[i] = docker.LogEntry{}
 	}
 }
 
@@ -115,3 +199,8 @@ func (rb *RingBuffer) Clear() {
 func (rb *RingBuffer) Capacity() int {
 	return rb.capacity
 }
+
+// Close is a no-op; it satisfies LineStore.
+func (rb *RingBuffer) Close() error {
+	return nil
+}
@@ -0,0 +1,262 @@
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+// DefaultMaxSpillLines is the default number of lines a SpillBuffer keeps on disk.
+const DefaultMaxSpillLines = 1_000_000
+
+// compactThreshold is the amount of dead space at the start of the spill file
+// that triggers rewriting it without the dropped lines.
+const compactThreshold = 16 << 20
+
+// spillRecord is the on-disk form of one line, stored as a JSON object per
+// line so records can be split on newlines.
+type spillRecord struct {
+	Line      string    `json:"l"`
+	Timestamp time.Time `json:"t"`
+	Stream    string    `json:"s,omitempty"`
+	Content   string    `json:"c"`
+}
+
+// SpillBuffer keeps the most recent lines in an in-memory RingBuffer and
+// moves lines evicted from it to a temporary file, indexed by byte offset,
+// so older lines can be paged back in on demand. Once more than maxSpill
+// lines are on disk the oldest are dropped. All methods are safe for
+// concurrent use.
+type SpillBuffer struct {
+	mu       sync.Mutex
+	hot      *RingBuffer
+	file     *os.File
+	w        *bufio.Writer
+	offsets  []int64 // file offset of each retained on-disk line, oldest first
+	fileEnd  int64   // offset where the next record is written
+	maxSpill int
+	dropped  int64
+	err      error // first write error; lines that failed to spill are dropped
+	closed   bool
+}
+
+// NewSpillBuffer creates a spill buffer holding capacity lines in memory and
+// up to maxSpill lines in a temporary file created in dir.
+func NewSpillBuffer(capacity, maxSpill int, dir string) (*SpillBuffer, error) {
+	if maxSpill <= 0 {
+		maxSpill = DefaultMaxSpillLines
+	}
+	f, err := os.CreateTemp(dir, "octo-logs-*.ndjson")
+	if err != nil {
+		return nil, fmt.Errorf("creating log spill file: %w", err)
+	}
+	return &SpillBuffer{
+		hot:      NewRingBuffer(capacity),
+		file:     f,
+		w:        bufio.NewWriter(f),
+		maxSpill: maxSpill,
+	}, nil
+}
+
+// AppendEntry adds a log entry. The oldest in-memory line is written to disk
+// when memory is full.
+func (sb *SpillBuffer) AppendEntry(entry docker.LogEntry, line string) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	evictedLine, evictedEntry, evicted := sb.hot.appendEvict(entry, line)
+	if !evicted {
+		return
+	}
+	if err := sb.spill(evictedLine, evictedEntry); err != nil {
+		if sb.err == nil {
+			sb.err = err
+		}
+		// The line is lost. Everything older goes with it so that
+		// Dropped()+i stays a valid sequence number for line i.
+		sb.dropOldest(len(sb.offsets))
+		sb.dropped++
+		return
+	}
+	if over := len(sb.offsets) - sb.maxSpill; over > 0 {
+		sb.dropOldest(over)
+	}
+}
+
+// spill appends one record to the file. Callers must hold sb.mu.
+func (sb *SpillBuffer) spill(line string, entry docker.LogEntry) error {
+	data, err := json.Marshal(spillRecord{
+		Line:      line,
+		Timestamp: entry.Timestamp,
+		Stream:    entry.Stream,
+		Content:   entry.Content,
+	})
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if _, err := sb.w.Write(data); err != nil {
+		return fmt.Errorf("writing log spill file: %w", err)
+	}
+	sb.offsets = append(sb.offsets, sb.fileEnd)
+	sb.fileEnd += int64(len(data))
+	return nil
+}
+
+// dropOldest forgets the n oldest on-disk lines, compacting the file once
+// enough dead space has accumulated. Callers must hold sb.mu.
+func (sb *SpillBuffer) dropOldest(n int) {
+	if n <= 0 {
+		return
+	}
+	sb.offsets = sb.offsets[n:]
+	sb.dropped += int64(n)
+
+	dead := sb.fileEnd
+	if len(sb.offsets) > 0 {
+		dead = sb.offsets[0]
+	}
+	if dead >= compactThreshold && dead > sb.fileEnd/2 {
+		if err := sb.compact(dead); err != nil && sb.err == nil {
+			sb.err = err
+		}
+	}
+}
+
+// compact rewrites the spill file without the first dead bytes. Callers
+// must hold sb.mu.
+func (sb *SpillBuffer) compact(dead int64) error {
+	if err := sb.w.Flush(); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(sb.file.Name()), "octo-logs-*.ndjson")
+	if err != nil {
+		return fmt.Errorf("compacting log spill file: %w", err)
+	}
+	if _, err := io.Copy(f, io.NewSectionReader(sb.file, dead, sb.fileEnd-dead)); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("compacting log spill file: %w", err)
+	}
+
+	old := sb.file
+	sb.file = f
+	sb.w = bufio.NewWriter(f)
+	sb.fileEnd -= dead
+	offsets := make([]int64, len(sb.offsets))
+	for i, off := range sb.offsets {
+		offsets[i] = off - dead
+	}
+	sb.offsets = offsets
+	_ = old.Close()
+	_ = os.Remove(old.Name())
+	return nil
+}
+
+// readDisk loads on-disk records [start, end). Callers must hold sb.mu.
+func (sb *SpillBuffer) readDisk(start, end int) []spillRecord {
+	if start >= end {
+		return nil
+	}
+	if err := sb.w.Flush(); err != nil {
+		sb.err = err
+		return nil
+	}
+	from, to := sb.offsets[start], sb.fileEnd
+	if end < len(sb.offsets) {
+		to = sb.offsets[end]
+	}
+	buf := make([]byte, to-from)
+	if _, err := sb.file.ReadAt(buf, from); err != nil {
+		sb.err = fmt.Errorf("reading log spill file: %w", err)
+		return nil
+	}
+
+	records := make([]spillRecord, 0, end-start)
+	for _, raw := range bytes.Split(bytes.TrimSuffix(buf, []byte("\n")), []byte("\n")) {
+		var r spillRecord
+		if err := json.Unmarshal(raw, &r); err != nil {
+			r = spillRecord{Line: string(raw), Content: string(raw)}
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+// Slice returns lines [start, end), reading the on-disk part from the file.
+func (sb *SpillBuffer) Slice(start, end int) []string {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	disk := len(sb.offsets)
+	start, end = clampRange(start, end, disk+sb.hot.Len())
+	result := make([]string, 0, end-start)
+	for _, r := range sb.readDisk(start, min(end, disk)) {
+		result = append(result, r.Line)
+	}
+	return append(result, sb.hot.Slice(max(start-disk, 0), end-disk)...)
+}
+
+// EntrySlice returns entries [start, end), parallel to Slice.
+func (sb *SpillBuffer) EntrySlice(start, end int) []docker.LogEntry {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	disk := len(sb.offsets)
+	start, end = clampRange(start, end, disk+sb.hot.Len())
+	result := make([]docker.LogEntry, 0, end-start)
+	for _, r := range sb.readDisk(start, min(end, disk)) {
+		result = append(result, docker.LogEntry{Timestamp: r.Timestamp, Stream: r.Stream, Content: r.Content})
+	}
+	return append(result, sb.hot.EntrySlice(max(start-disk, 0), end-disk)...)
+}
+
+// Len returns the number of retained lines, in memory and on disk.
+func (sb *SpillBuffer) Len() int {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return len(sb.offsets) + sb.hot.Len()
+}
+
+// Dropped returns the number of lines that are no longer retained anywhere.
+func (sb *SpillBuffer) Dropped() int64 {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.dropped
+}
+
+// Capacity returns the total number of lines the buffer can retain.
+func (sb *SpillBuffer) Capacity() int {
+	return sb.hot.Capacity() + sb.maxSpill
+}
+
+// Err returns the first error encountered writing or reading the spill file.
+func (sb *SpillBuffer) Err() error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.err
+}
+
+// Close closes and removes the spill file. Calling it again is a no-op.
+func (sb *SpillBuffer) Close() error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	if sb.closed {
+		return nil
+	}
+	sb.closed = true
+	name := sb.file.Name()
+	err := sb.file.Close()
+	if rmErr := os.Remove(name); err == nil && rmErr != nil && !os.IsNotExist(rmErr) {
+		err = rmErr
+	}
+	return err
+}
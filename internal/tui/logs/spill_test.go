@@ -0,0 +1,123 @@
+package logs
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bsisduck/octo/internal/docker"
+)
+
+func TestSpillBufferPagesFromDisk(t *testing.T) {
+	dir := t.TempDir()
+	sb, err := NewSpillBuffer(10, 100, dir)
+	if err != nil {
+		t.Fatalf("NewSpillBuffer: %v", err)
+	}
+
+	for i := 0; i < 50; i++ {
+		sb.AppendEntry(docker.LogEntry{Timestamp: testTime, Stream: "stderr", Content: fmt.Sprintf("c%d", i)}, fmt.Sprintf("line-%d", i))
+	}
+
+	if sb.Len() != 50 {
+		t.Fatalf("Len() = %d, want 50", sb.Len())
+	}
+	if sb.Dropped() != 0 {
+		t.Errorf("Dropped() = %d, want 0", sb.Dropped())
+	}
+
+	// Range spanning the disk/memory boundary (40 on disk, 10 in memory)
+	lines := sb.Slice(35, 45)
+	if len(lines) != 10 {
+		t.Fatalf("Slice(35, 45) returned %d lines, want 10", len(lines))
+	}
+	for i, line := range lines {
+		if want := fmt.Sprintf("line-%d", 35+i); line != want {
+			t.Errorf("lines[%d] = %q, want %q", i, line, want)
+		}
+	}
+
+	entries := sb.EntrySlice(0, 1)
+	if len(entries) != 1 || entries[0].Content != "c0" || entries[0].Stream != "stderr" || !entries[0].Timestamp.Equal(testTime) {
+		t.Errorf("EntrySlice(0, 1) = %+v, want the first entry round-tripped from disk", entries)
+	}
+	if err := sb.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+
+	if err := sb.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) != 0 {
+		t.Errorf("spill file not removed on Close: %v", files)
+	}
+}
+
+func TestSpillBufferDropsBeyondMaxSpill(t *testing.T) {
+	sb, err := NewSpillBuffer(5, 10, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSpillBuffer: %v", err)
+	}
+	defer sb.Close()
+
+	for i := 0; i < 30; i++ {
+		sb.AppendEntry(docker.LogEntry{Content: fmt.Sprintf("%d", i)}, fmt.Sprintf("line-%d", i))
+	}
+
+	if sb.Len() != 15 {
+		t.Errorf("Len() = %d, want 15", sb.Len())
+	}
+	if sb.Dropped() != 15 {
+		t.Errorf("Dropped() = %d, want 15", sb.Dropped())
+	}
+	if sb.Capacity() != 15 {
+		t.Errorf("Capacity() = %d, want 15", sb.Capacity())
+	}
+	if first := sb.Slice(0, 1); len(first) != 1 || first[0] != "line-15" {
+		t.Errorf("oldest retained line = %v, want line-15", first)
+	}
+}
+
+func TestLogsModelSpillScrollback(t *testing.T) {
+	mock := mockService(nil)
+	m, err := NewWithOptions(mock, "abc123", "test-container", BufferOptions{
+		Capacity:    20,
+		SpillToDisk: true,
+		SpillDir:    t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("NewWithOptions: %v", err)
+	}
+	defer m.buffer.Close()
+	m.width = 80
+	m.height = 30
+
+	model, _ := m.Update(InitialLogsMsg{Entries: makeEntries(200, "stdout")})
+	m = model.(Model)
+
+	if m.viewLen() != 200 {
+		t.Fatalf("viewLen() = %d, want 200", m.viewLen())
+	}
+	if m.truncationWarning != "" {
+		t.Errorf("unexpected truncation warning %q", m.truncationWarning)
+	}
+
+	// Scroll to the top: the oldest lines come back from disk
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	m = model.(Model)
+	visible := m.visibleLines(m.offset, m.offset+1)
+	if len(visible) != 1 || !containsCaseInsensitive(visible[0], "log line 0") {
+		t.Errorf("top line = %v, want log line 0", visible)
+	}
+
+	// Filtering spans both disk and memory
+	m.filterText = "line 1"
+	m.refreshViewLines()
+	// "log line 1", "log line 10".."19", "log line 100".."199"
+	if m.viewLen() != 111 {
+		t.Errorf("filtered viewLen() = %d, want 111", m.viewLen())
+	}
+}
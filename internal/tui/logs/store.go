@@ -0,0 +1,49 @@
+package logs
+
+import "github.com/bsisduck/octo/internal/docker"
+
+// LineStore holds the rendered log lines (and their entries) shown by the
+// logs viewer. Indices are positions among the currently retained lines,
+// oldest first; Dropped counts lines that are no longer retrievable, so
+// Dropped()+i is a stable sequence number for line i.
+type LineStore interface {
+	AppendEntry(entry docker.LogEntry, line string)
+	Len() int
+	Slice(start, end int) []string
+	EntrySlice(start, end int) []docker.LogEntry
+	Dropped() int64
+	Capacity() int
+	Close() error
+}
+
+var (
+	_ LineStore = (*RingBuffer)(nil)
+	_ LineStore = (*SpillBuffer)(nil)
+)
+
+// BufferOptions configures the line store backing a logs Model.
+type BufferOptions struct {
+	// Capacity is the number of lines kept in memory (DefaultCapacity when zero).
+	Capacity int
+	// SpillToDisk moves lines evicted from memory to a temporary file so
+	// they stay scrollable.
+	SpillToDisk bool
+	// SpillDir is where the temporary file is created (os.TempDir when empty).
+	SpillDir string
+	// MaxSpillLines caps the lines kept on disk (DefaultMaxSpillLines when zero).
+	MaxSpillLines int
+}
+
+// newLineStore builds the store described by opts. If the spill file cannot
+// be created it falls back to an in-memory ring buffer and returns the error
+// so the caller can report it.
+func newLineStore(opts BufferOptions) (LineStore, error) {
+	if !opts.SpillToDisk {
+		return NewRingBuffer(opts.Capacity), nil
+	}
+	sb, err := NewSpillBuffer(opts.Capacity, opts.MaxSpillLines, opts.SpillDir)
+	if err != nil {
+		return NewRingBuffer(opts.Capacity), err
+	}
+	return sb, nil
+}
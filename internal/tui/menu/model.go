@@ -9,10 +9,12 @@ import (
 	"github.com/dustin/go-humanize"
 
 	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/tui/common"
 	"github.com/bsisduck/octo/internal/ui/styles"
 )
 
 type Model struct {
+	service      docker.DockerService // shared service when embedded in the app
 	selected     int
 	chosenAction string
 	items        []item
@@ -26,6 +28,7 @@ type Model struct {
 	running      int
 	images       int
 	volumes      int
+	showHelp     bool
 }
 
 type item struct {
@@ -58,18 +61,46 @@ func New() Model {
 	}
 }
 
+// NewEmbedded creates the menu as the landing page of the tabbed app. It
+// uses the app's Docker service and lists the app's views with the commands.
+func NewEmbedded(service docker.DockerService) Model {
+	return Model{
+		service: service,
+		items: []item{
+			{title: "Status", subtitle: "Monitor system health", action: "status"},
+			{title: "Analyze", subtitle: "Explore resource usage", action: "analyze"},
+			{title: "Logs", subtitle: "Follow container logs", action: "logs"},
+			{title: "Events", subtitle: "Watch Docker events", action: "events"},
+			{title: "Cleanup", subtitle: "Smart cleanup with safety", action: "cleanup"},
+			{title: "Prune", subtitle: "Deep cleanup all unused", action: "prune"},
+			{title: "Diagnose", subtitle: "Check Docker health", action: "diagnose"},
+		},
+	}
+}
+
+// ClearChoice forgets the chosen action, once the app has acted on it.
+func (m Model) ClearChoice() Model {
+	m.chosenAction = ""
+	return m
+}
+
 // ChosenAction returns the action selected by the user
 func (m Model) ChosenAction() string {
 	return m.chosenAction
 }
 
 func (m Model) Init() tea.Cmd {
+	service := m.service
 	return func() tea.Msg {
-		client, err := docker.NewClient()
-		if err != nil {
-			return InitMsg{DockerOK: false, Err: err}
+		client := service
+		if client == nil {
+			c, err := docker.NewClient()
+			if err != nil {
+				return InitMsg{DockerOK: false, Err: err}
+			}
+			defer func() { _ = c.Close() }()
+			client = c
 		}
-		defer func() { _ = client.Close() }()
 
 		ctx := context.Background()
 
@@ -130,48 +161,39 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c", "esc":
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		keys := common.Keys
+		switch {
+		case keys.Is(msg, common.ActQuit), keys.Is(msg, common.ActCancel):
 			return m, tea.Quit
-		case "up", "k":
+		case keys.Is(msg, common.ActUp):
 			if m.selected > 0 {
 				m.selected--
 			}
-		case "down", "j":
+		case keys.Is(msg, common.ActDown):
 			if m.selected < len(m.items)-1 {
 				m.selected++
 			}
-		case "enter", " ":
+		case keys.Is(msg, common.ActChoose):
 			if m.selected >= 0 && m.selected < len(m.items) {
 				m.chosenAction = m.items[m.selected].action
 			}
 			return m, tea.Quit
-		case "1":
-			m.selected = 0
-			m.chosenAction = m.items[0].action
-			return m, tea.Quit
-		case "2":
-			m.selected = 1
-			m.chosenAction = m.items[1].action
-			return m, tea.Quit
-		case "3":
-			m.selected = 2
-			m.chosenAction = m.items[2].action
-			return m, tea.Quit
-		case "4":
-			m.selected = 3
-			m.chosenAction = m.items[3].action
-			return m, tea.Quit
-		case "5":
-			m.selected = 4
-			m.chosenAction = m.items[4].action
-			return m, tea.Quit
-		case "v":
+		case keys.Is(msg, common.ActVersion):
 			m.chosenAction = "version"
 			return m, tea.Quit
-		case "?", "h":
-			// Help - just quit
-			return m, tea.Quit
+		case keys.Is(msg, common.ActHelp):
+			m.showHelp = true
+		default:
+			// Items are also chosen by their number
+			if key := msg.String(); len(key) == 1 && key >= "1" && key <= "5" {
+				m.selected = int(key[0] - '1')
+				m.chosenAction = m.items[m.selected].action
+				return m, tea.Quit
+			}
 		}
 
 	case tea.WindowSizeMsg:
@@ -193,6 +215,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) View() string {
+	if m.showHelp {
+		actions := []common.Action{common.ActUp, common.ActDown, common.ActChoose}
+		if m.service == nil {
+			actions = append(actions, common.ActVersion)
+		}
+		return common.RenderHelp("Octo",
+			common.HelpSection{Title: "Menu", Actions: actions},
+			common.HelpSection{Title: "General", Actions: []common.Action{common.ActHelp, common.ActQuit, common.ActCancel}},
+		)
+	}
+
 	var b strings.Builder
 
 	// Logo
@@ -262,7 +295,23 @@ func (m Model) View() string {
 	b.WriteString("\n")
 	b.WriteString(strings.Repeat("─", 50))
 	b.WriteString("\n")
-	b.WriteString(styles.Help.Render("  ↑↓/jk/click: navigate | Enter/1-5: select | v: version | q: quit"))
+	if m.service != nil {
+		b.WriteString(styles.Help.Render("  " + common.ShortHelp(
+			common.Item("navigate", common.ActUp, common.ActDown),
+			common.Item("open", common.ActChoose),
+			common.Item("switch tabs (or 0-7)", common.ActNextTab),
+			common.Item("help", common.ActHelp),
+			common.Item("quit", common.ActQuit),
+		)))
+	} else {
+		b.WriteString(styles.Help.Render("  " + common.ShortHelp(
+			common.Item("navigate", common.ActUp, common.ActDown),
+			common.Item("select (or 1-5)", common.ActChoose),
+			common.Item("version", common.ActVersion),
+			common.Item("help", common.ActHelp),
+			common.Item("quit", common.ActQuit),
+		)))
+	}
 	b.WriteString("\n")
 
 	// Note about using commands directly
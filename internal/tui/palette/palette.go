@@ -0,0 +1,225 @@
+// Package palette is the command palette of the TUI: a prompt that
+// fuzzy-finds the actions a view offers and shows their keybindings.
+package palette
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bsisduck/octo/internal/tui/common"
+	"github.com/bsisduck/octo/internal/ui/styles"
+)
+
+// maxResults is the number of matches shown at once.
+const maxResults = 10
+
+// Command is an action offered by a view.
+type Command struct {
+	// Title is what the user types to find it, e.g. "stop project web".
+	Title string
+	// Key is the keybinding doing the same thing, shown next to the
+	// title; empty if there is none.
+	Key string
+	// Msg is delivered to the view offering the command when it is run.
+	Msg tea.Msg
+}
+
+// ChosenMsg reports the command picked in the palette.
+type ChosenMsg struct {
+	Command Command
+}
+
+// ClosedMsg reports that the palette was dismissed.
+type ClosedMsg struct{}
+
+// Model is an open palette.
+type Model struct {
+	commands []Command
+	query    string
+	matches  []Command
+	selected int
+}
+
+// New opens a palette on commands, listed in order of relevance to the
+// view: with an empty query they are shown as given. Commands with a
+// title already offered are dropped.
+func New(commands []Command) Model {
+	seen := make(map[string]bool, len(commands))
+	var unique []Command
+	for _, c := range commands {
+		if !seen[c.Title] {
+			seen[c.Title] = true
+			unique = append(unique, c)
+		}
+	}
+	m := Model{commands: unique}
+	m.match()
+	return m
+}
+
+// Add offers more commands, such as ones known after a fetch.
+func (m Model) Add(commands ...Command) Model {
+	return New(slices.Concat(m.commands, commands)).withQuery(m.query)
+}
+
+func (m Model) withQuery(query string) Model {
+	m.query = query
+	m.match()
+	return m
+}
+
+// Query returns the text typed.
+func (m Model) Query() string {
+	return m.query
+}
+
+// Matches returns the commands matching the query, best first.
+func (m Model) Matches() []Command {
+	return m.matches
+}
+
+// match ranks the commands against the query.
+func (m *Model) match() {
+	type scored struct {
+		cmd   Command
+		score int
+	}
+	var found []scored
+	for _, c := range m.commands {
+		if s, ok := Score(m.query, c.Title); ok {
+			found = append(found, scored{c, s})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+	m.matches = make([]Command, 0, len(found))
+	for _, f := range found {
+		m.matches = append(m.matches, f.cmd)
+	}
+	m.selected = 0
+}
+
+// Score reports how well query matches title. Every word of the query must
+// appear in the title with its letters in order, not necessarily adjacent;
+// letters at word starts, runs of adjacent letters and short titles score
+// higher.
+func Score(query, title string) (int, bool) {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return 0, true
+	}
+	title = strings.ToLower(title)
+	total := -len(title) / 8
+	for _, word := range words {
+		best, ok := -1, false
+		for start := range title {
+			if title[start] != word[0] {
+				continue
+			}
+			if s, found := scoreFrom(word, title, start); found && s > best {
+				best, ok = s, true
+			}
+		}
+		if !ok {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
+
+// scoreFrom matches word as a subsequence of title from start on.
+func scoreFrom(word, title string, start int) (int, bool) {
+	score, prev, w := 0, -2, 0
+	for i := start; i < len(title) && w < len(word); i++ {
+		if title[i] != word[w] {
+			continue
+		}
+		score++
+		if i == 0 || strings.ContainsRune(" -_/.:", rune(title[i-1])) {
+			score += 3
+		}
+		if i == prev+1 {
+			score += 2
+		}
+		prev = i
+		w++
+	}
+	return score, w == len(word)
+}
+
+func (m Model) Update(msg tea.KeyMsg) (Model, tea.Cmd) {
+	// Bound keys that type nothing also work here, such as ctrl+n/ctrl+p
+	// to move in the emacs keymap.
+	keys := common.Keys
+	bound := func(a common.Action) bool {
+		return msg.Type != tea.KeyRunes && msg.Type != tea.KeySpace && keys.Is(msg, a)
+	}
+	switch {
+	case msg.Type == tea.KeyEsc, bound(common.ActCancel), bound(common.ActPalette):
+		return m, func() tea.Msg { return ClosedMsg{} }
+	case msg.Type == tea.KeyEnter:
+		if m.selected < len(m.matches) {
+			chosen := m.matches[m.selected]
+			return m, func() tea.Msg { return ChosenMsg{Command: chosen} }
+		}
+		return m, nil
+	case msg.Type == tea.KeyUp, bound(common.ActUp):
+		if m.selected > 0 {
+			m.selected--
+		}
+		return m, nil
+	case msg.Type == tea.KeyDown, msg.Type == tea.KeyTab, bound(common.ActDown):
+		if m.selected < len(m.matches)-1 {
+			m.selected++
+		}
+		return m, nil
+	case msg.Type == tea.KeyBackspace:
+		if m.query != "" {
+			runes := []rune(m.query)
+			return m.withQuery(string(runes[:len(runes)-1])), nil
+		}
+	case msg.Type == tea.KeyRunes, msg.Type == tea.KeySpace:
+		return m.withQuery(m.query + string(msg.Runes)), nil
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	var b strings.Builder
+	b.WriteString(styles.Info.Render(": " + m.query + "█"))
+	b.WriteString("\n\n")
+	if len(m.matches) == 0 {
+		b.WriteString(styles.Help.Render("  No matching commands"))
+		b.WriteString("\n")
+	}
+
+	width := 0
+	for _, c := range m.matches {
+		width = max(width, len(c.Title))
+	}
+	offset := max(0, m.selected-maxResults+1)
+	for i := offset; i < len(m.matches) && i < offset+maxResults; i++ {
+		c := m.matches[i]
+		line := fmt.Sprintf("  %-*s", width, c.Title)
+		if i == m.selected {
+			b.WriteString(styles.Selected.Render(line))
+		} else {
+			b.WriteString(styles.Normal.Render(line))
+		}
+		if c.Key != "" {
+			b.WriteString(styles.Help.Render("  " + c.Key))
+		}
+		b.WriteString("\n")
+	}
+	if n := len(m.matches); n > maxResults {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("  %d of %d", min(offset+maxResults, n), n)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("type to search | ↑↓: select | enter: run | esc: close"))
+	return b.String()
+}
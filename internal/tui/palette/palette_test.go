@@ -0,0 +1,81 @@
+package palette
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func titles(cmds []Command) []string {
+	var out []string
+	for _, c := range cmds {
+		out = append(out, c.Title)
+	}
+	return out
+}
+
+func typeQuery(m Model, s string) Model {
+	for _, r := range s {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return m
+}
+
+// TestScore tests fuzzy matching of query words against titles
+func TestScore(t *testing.T) {
+	tests := []struct {
+		query, title string
+		match        bool
+	}{
+		{"", "stop web", true},
+		{"stop web", "stop web", true},
+		{"web stop", "stop project web", true},
+		{"stp wb", "stop web", true},
+		{"prune images", "prune dangling images", true},
+		{"LOGS api", "logs api-1", true},
+		{"stop db", "stop web", false},
+		{"pots", "stop", false},
+	}
+	for _, tt := range tests {
+		_, ok := Score(tt.query, tt.title)
+		assert.Equal(t, tt.match, ok, "%q in %q", tt.query, tt.title)
+	}
+
+	exact, _ := Score("stop web", "stop web")
+	scattered, _ := Score("stop web", "stop project web-worker")
+	assert.Greater(t, exact, scattered)
+	wordStart, _ := Score("api", "logs api")
+	inside, _ := Score("api", "logs rapid")
+	assert.Greater(t, wordStart, inside)
+}
+
+// TestPalette_FiltersAndChooses tests typing narrows the list and enter runs
+func TestPalette_FiltersAndChooses(t *testing.T) {
+	m := New([]Command{
+		{Title: "restart web", Key: "r", Msg: "restart"},
+		{Title: "stop web", Key: "t", Msg: "stop"},
+		{Title: "stop web", Key: "t", Msg: "duplicate"},
+		{Title: "logs api", Key: "l", Msg: "logs"},
+	})
+	assert.Equal(t, []string{"restart web", "stop web", "logs api"}, titles(m.Matches()))
+
+	m = typeQuery(m, "stweb")
+	assert.Equal(t, []string{"stop web", "restart web"}, titles(m.Matches()))
+	assert.Contains(t, m.View(), "stop web")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	assert.Equal(t, "restart", cmd().(ChosenMsg).Command.Msg)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, "stwe", m.Query())
+	m = m.Add(Command{Title: "stop west"})
+	assert.Equal(t, "stwe", m.Query())
+	assert.Contains(t, titles(m.Matches()), "stop west")
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.IsType(t, ClosedMsg{}, cmd())
+}
@@ -7,8 +7,11 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/hostdisk"
+	"github.com/bsisduck/octo/internal/tui/common"
 	"github.com/bsisduck/octo/internal/ui/format"
 	"github.com/bsisduck/octo/internal/ui/styles"
 )
@@ -20,12 +23,15 @@ type Model struct {
 	width       int
 	height      int
 	cancelFetch context.CancelFunc
+	diskOpts    *hostdisk.Options // nil leaves the host disk unmeasured
+	showHelp    bool
 
 	// Cached data
 	containers    []docker.ContainerInfo
 	images        []docker.ImageInfo
 	volumes       []docker.VolumeInfo
 	diskUsage     *docker.DiskUsageInfo
+	hostDisk      *hostdisk.Report
 	serverVersion string
 	osInfo        string
 	warnings      []string
@@ -44,6 +50,7 @@ type DataMsg struct {
 	Images        []docker.ImageInfo
 	Volumes       []docker.VolumeInfo
 	DiskUsage     *docker.DiskUsageInfo
+	HostDisk      *hostdisk.Report
 	ServerVersion string
 	OsInfo        string
 	Err           error
@@ -56,6 +63,13 @@ func New(client docker.DockerService, watch bool) Model {
 	}
 }
 
+// WithHostDisk enables the host disk section, measuring the filesystem of
+// the Docker data root on each refresh when the daemon is local.
+func (m Model) WithHostDisk(opts hostdisk.Options) Model {
+	m.diskOpts = &opts
+	return m
+}
+
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(m.fetchData(), tickStatus())
 }
@@ -103,11 +117,22 @@ func (m *Model) fetchData() tea.Cmd {
 			warnings = append(warnings, fmt.Sprintf("server info: %v", err))
 		}
 
+		var hostDisk *hostdisk.Report
+		if root, ok := hostdisk.DataRoot(info); ok && m.diskOpts != nil {
+			report, err := hostdisk.Measure(root, *m.diskOpts, time.Now())
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("host disk: %v", err))
+			} else {
+				hostDisk = &report
+			}
+		}
+
 		return DataMsg{
 			Containers:    containers,
 			Images:        images,
 			Volumes:       volumes,
 			DiskUsage:     diskUsage,
+			HostDisk:      hostDisk,
 			ServerVersion: info.ServerVersion,
 			OsInfo:        fmt.Sprintf("%s (%s)", info.OperatingSystem, info.Architecture),
 			Warnings:      warnings,
@@ -118,15 +143,21 @@ func (m *Model) fetchData() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "esc", "ctrl+c":
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		keys := common.Keys
+		switch {
+		case keys.Is(msg, common.ActQuit), keys.Is(msg, common.ActCancel):
 			if m.cancelFetch != nil {
 				m.cancelFetch()
 			}
-			_ = m.client.Close()
 			return m, tea.Quit
-		case "r":
+		case keys.Is(msg, common.ActRefresh):
 			return m, m.fetchData()
+		case keys.Is(msg, common.ActHelp):
+			m.showHelp = true
 		}
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -141,6 +172,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.images = msg.Images
 			m.volumes = msg.Volumes
 			m.diskUsage = msg.DiskUsage
+			m.hostDisk = msg.HostDisk
 			m.serverVersion = msg.ServerVersion
 			m.osInfo = msg.OsInfo
 			m.warnings = msg.Warnings
@@ -174,6 +206,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) View() string {
+	if m.showHelp {
+		return common.RenderHelp("Status",
+			common.HelpSection{Title: "General", Actions: []common.Action{common.ActRefresh, common.ActHelp, common.ActQuit, common.ActCancel}},
+		)
+	}
 	if m.err != nil {
 		return fmt.Sprintf("Error: %v\nPress 'q' to quit.", m.err)
 	}
@@ -229,6 +266,11 @@ func (m Model) View() string {
 		fmt.Fprintf(&b, "  %s %s\n", styles.Label.Render("Build Cache:"), styles.Value.Render(format.Size(uint64(m.diskUsage.BuildCache))))
 	}
 
+	if m.hostDisk != nil {
+		b.WriteString("\n")
+		b.WriteString(m.renderHostDisk())
+	}
+
 	// Warnings
 	if len(m.warnings) > 0 {
 		b.WriteString("\n")
@@ -244,7 +286,40 @@ func (m Model) View() string {
 	b.WriteString(strings.Repeat("─", 50))
 	b.WriteString("\n")
 	b.WriteString(styles.Help.Render(
-		fmt.Sprintf("Last updated: %s | Press 'r' to refresh, 'q' to quit", m.lastUpdated.Format("15:04:05"))))
+		fmt.Sprintf("Last updated: %s | %s", m.lastUpdated.Format("15:04:05"), common.ShortHelp(
+			common.Item("refresh", common.ActRefresh),
+			common.Item("help", common.ActHelp),
+			common.Item("quit", common.ActQuit),
+		))))
 
 	return b.String()
 }
+
+func (m Model) renderHostDisk() string {
+	hd := m.hostDisk
+	var b strings.Builder
+	b.WriteString(styles.Section.Render("Host Disk"))
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "  %s %s\n", styles.Label.Render("Path:"), styles.Value.Render(hd.Path))
+	fmt.Fprintf(&b, "  %s %s\n", styles.Label.Render("Free:"), levelStyle(hd.SpaceLevel).Render(
+		fmt.Sprintf("%s of %s (%.0f%% used)", format.Size(hd.Avail), format.Size(hd.Total), hd.UsedPercent())))
+	if hd.Inodes > 0 {
+		fmt.Fprintf(&b, "  %s %s\n", styles.Label.Render("Inodes:"), levelStyle(hd.InodeLevel).Render(
+			fmt.Sprintf("%.0f%% used", hd.InodesUsedPercent())))
+	}
+	if f := hd.Forecast; f != nil {
+		fmt.Fprintf(&b, "  %s %s\n", styles.Label.Render("Full in:"), levelStyle(hd.SpaceLevel).Render(
+			fmt.Sprintf("~%.0f days (+%s/day)", f.DaysLeft, format.Size(uint64(f.GrowthPerDay)))))
+	}
+	return b.String()
+}
+
+func levelStyle(l hostdisk.Level) lipgloss.Style {
+	switch l {
+	case hostdisk.LevelError:
+		return styles.Error
+	case hostdisk.LevelWarn:
+		return styles.Warning
+	}
+	return styles.Success
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/bsisduck/octo/internal/docker"
+	"github.com/bsisduck/octo/internal/hostdisk"
 )
 
 // TestStatus_NewCreatesModel tests New creates a model
@@ -160,6 +161,27 @@ func TestStatus_ViewShowsServerVersion(t *testing.T) {
 	assert.Contains(t, view, "Linux")
 }
 
+// TestStatus_ViewShowsHostDisk tests View renders the host disk section
+func TestStatus_ViewShowsHostDisk(t *testing.T) {
+	mock := &docker.MockDockerService{}
+	m := New(mock, false)
+
+	assert.NotContains(t, m.View(), "Host Disk", "hidden for remote daemons")
+
+	updated, _ := m.Update(DataMsg{HostDisk: &hostdisk.Report{
+		Usage:      hostdisk.Usage{Path: "/var/lib/docker", Total: 100e9, Free: 10e9, Avail: 10e9, Inodes: 1000, InodesFree: 900},
+		SpaceLevel: hostdisk.LevelWarn,
+		Forecast:   &hostdisk.Forecast{GrowthPerDay: 1e9, DaysLeft: 10},
+	}})
+	view := updated.(Model).View()
+
+	assert.Contains(t, view, "Host Disk")
+	assert.Contains(t, view, "/var/lib/docker")
+	assert.Contains(t, view, "90% used")
+	assert.Contains(t, view, "10% used", "inodes")
+	assert.Contains(t, view, "~10 days")
+}
+
 // TestStatus_UpdateWithWindowSize tests window size handling
 func TestStatus_UpdateWithWindowSize(t *testing.T) {
 	mock := &docker.MockDockerService{}
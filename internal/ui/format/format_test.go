@@ -119,3 +119,15 @@ func TestFormatText(t *testing.T) {
 		t.Errorf("FormatText(noColor=true) = %q, want %q", got, "Red")
 	}
 }
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]int{0, 1, 4, 8}); got != "▁▂▅█" {
+		t.Errorf("Sparkline() = %q, want %q", got, "▁▂▅█")
+	}
+	if got := Sparkline([]int{0, 0}); got != "▁▁" {
+		t.Errorf("Sparkline(zeros) = %q, want %q", got, "▁▁")
+	}
+	if got := Sparkline(nil); got != "" {
+		t.Errorf("Sparkline(nil) = %q, want empty", got)
+	}
+}
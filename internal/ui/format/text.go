@@ -22,3 +22,24 @@ func FormatText(text string, noColor bool) string {
 	}
 	return text
 }
+
+// sparkTicks are the block characters used by Sparkline, lowest first.
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a single line of block characters scaled to
+// the largest value. Zero renders as the lowest tick.
+func Sparkline(values []int) string {
+	peak := 0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	out := make([]rune, len(values))
+	for i, v := range values {
+		idx := 0
+		if peak > 0 && v > 0 {
+			idx = (v*(len(sparkTicks)-1) + peak - 1) / peak
+		}
+		out[i] = sparkTicks[idx]
+	}
+	return string(out)
+}
@@ -5,73 +5,137 @@ import (
 	"github.com/muesli/termenv"
 )
 
-// Color palette -- single source of truth
+// Color palette -- single source of truth, set by Apply
 var (
-	ColorPrimary     = lipgloss.Color("69")
-	ColorSuccess     = lipgloss.Color("42")
-	ColorWarning     = lipgloss.Color("214")
-	ColorError       = lipgloss.Color("196")
-	ColorMuted       = lipgloss.Color("241")
-	ColorText        = lipgloss.Color("255")
-	ColorHighlight   = lipgloss.Color("62")
-	ColorHighlightBg = lipgloss.Color("237")
-	ColorNormal      = lipgloss.Color("252")
+	ColorPrimary     lipgloss.Color
+	ColorSuccess     lipgloss.Color
+	ColorWarning     lipgloss.Color
+	ColorError       lipgloss.Color
+	ColorMuted       lipgloss.Color
+	ColorText        lipgloss.Color
+	ColorHighlight   lipgloss.Color
+	ColorHighlightBg lipgloss.Color
+	ColorNormal      lipgloss.Color
+	ColorDanger      lipgloss.Color
 )
 
 // Title styles
 var (
+	Title           lipgloss.Style
+	TitleWithMargin lipgloss.Style
+	Logo            lipgloss.Style
+	Tagline         lipgloss.Style
+)
+
+// Section and category styles
+var (
+	Section  lipgloss.Style
+	Category lipgloss.Style
+)
+
+// Status and state styles
+var (
+	Success lipgloss.Style
+	Running lipgloss.Style
+	Warning lipgloss.Style
+	Warn    lipgloss.Style
+	Error   lipgloss.Style
+	Stopped lipgloss.Style
+	Info    lipgloss.Style
+)
+
+// Label and value styles
+var (
+	Label          lipgloss.Style
+	LabelWithWidth lipgloss.Style
+	StatLabel      lipgloss.Style
+	Value          lipgloss.Style
+	StatValue      lipgloss.Style
+	Size           lipgloss.Style
+)
+
+// Selection and interaction styles
+var (
+	Selected        lipgloss.Style
+	Normal          lipgloss.Style
+	SelectedAnalyze lipgloss.Style
+	NormalAnalyze   lipgloss.Style
+	Unused          lipgloss.Style
+)
+
+// Help and instructional styles
+var (
+	Help          lipgloss.Style
+	Subtitle      lipgloss.Style
+	DeleteConfirm lipgloss.Style
+)
+
+// Tier-specific styles for safety confirmation dialogs
+var (
+	TierInformationalStyle   lipgloss.Style
+	TierLowRiskStyle         lipgloss.Style
+	TierModerateStyle        lipgloss.Style
+	TierHighRiskStyle        lipgloss.Style
+	TierBulkDestructiveStyle lipgloss.Style
+)
+
+func init() {
+	Apply(Theme{Palette: Dark})
+}
+
+// Apply makes t the theme of every style: the styles are rebuilt from its
+// palette, then its style overrides are laid on top. Call it at startup,
+// before anything is rendered; t is expected to be valid (see Validate).
+func Apply(t Theme) {
+	p := t.Palette
+	ColorPrimary = lipgloss.Color(p.Primary)
+	ColorSuccess = lipgloss.Color(p.Success)
+	ColorWarning = lipgloss.Color(p.Warning)
+	ColorError = lipgloss.Color(p.Error)
+	ColorMuted = lipgloss.Color(p.Muted)
+	ColorText = lipgloss.Color(p.Text)
+	ColorHighlight = lipgloss.Color(p.Highlight)
+	ColorHighlightBg = lipgloss.Color(p.HighlightBg)
+	ColorNormal = lipgloss.Color(p.Normal)
+	ColorDanger = lipgloss.Color(p.Danger)
+
 	Title = lipgloss.NewStyle().Bold(true).Foreground(ColorPrimary)
 
 	TitleWithMargin = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(ColorPrimary).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(ColorPrimary).
+		MarginBottom(1)
 
 	Logo = lipgloss.NewStyle().Foreground(ColorPrimary)
 
 	Tagline = lipgloss.NewStyle().
 		Foreground(ColorMuted).
 		Italic(true)
-)
 
-// Section and category styles
-var (
 	Section = lipgloss.NewStyle().Bold(true).Foreground(ColorSuccess)
 
 	Category = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(ColorSuccess).
-			PaddingLeft(1)
-)
+		Bold(true).
+		Foreground(ColorSuccess).
+		PaddingLeft(1)
 
-// Status and state styles
-var (
 	Success = lipgloss.NewStyle().Foreground(ColorSuccess)
-
 	Running = lipgloss.NewStyle().Foreground(ColorSuccess)
-
 	Warning = lipgloss.NewStyle().Foreground(ColorWarning)
-
 	Warn = lipgloss.NewStyle().Bold(true).Foreground(ColorWarning)
-
 	Error = lipgloss.NewStyle().Foreground(ColorError)
-
 	Stopped = lipgloss.NewStyle().Foreground(ColorWarning)
-
 	Info = lipgloss.NewStyle().Foreground(ColorMuted)
-)
 
-// Label and value styles
-var (
 	Label = lipgloss.NewStyle().Foreground(ColorMuted)
 
 	LabelWithWidth = lipgloss.NewStyle().
-			Foreground(ColorMuted).
-			Width(14)
+		Foreground(ColorMuted).
+		Width(14)
 
 	StatLabel = lipgloss.NewStyle().
-			Foreground(ColorMuted).
-			Width(12)
+		Foreground(ColorMuted).
+		Width(12)
 
 	Value = lipgloss.NewStyle().Foreground(ColorText)
 
@@ -81,63 +145,61 @@ var (
 		Foreground(ColorMuted).
 		Width(10).
 		Align(lipgloss.Right)
-)
 
-// Selection and interaction styles
-var (
 	Selected = lipgloss.NewStyle().
-			Foreground(ColorText).
-			Background(ColorHighlight).
-			Bold(true).
-			Padding(0, 1)
+		Foreground(ColorText).
+		Background(ColorHighlight).
+		Bold(true).
+		Padding(0, 1)
 
 	Normal = lipgloss.NewStyle().
 		Foreground(ColorNormal).
 		Padding(0, 1)
 
 	SelectedAnalyze = lipgloss.NewStyle().
-			Background(ColorHighlightBg).
-			Foreground(ColorText)
+		Background(ColorHighlightBg).
+		Foreground(ColorText)
 
 	NormalAnalyze = lipgloss.NewStyle().PaddingLeft(3)
 
 	Unused = lipgloss.NewStyle().Foreground(ColorWarning)
-)
 
-// Help and instructional styles
-var (
 	Help = lipgloss.NewStyle().Foreground(ColorMuted)
 
 	Subtitle = lipgloss.NewStyle().Foreground(ColorMuted)
 
 	DeleteConfirm = lipgloss.NewStyle().
-			Foreground(ColorError).
-			Bold(true)
-)
+		Foreground(ColorError).
+		Bold(true)
 
-// Tier-specific styles for safety confirmation dialogs
-var (
 	TierInformationalStyle = lipgloss.NewStyle().
-				Foreground(ColorPrimary).
-				Bold(true)
+		Foreground(ColorPrimary).
+		Bold(true)
 
 	TierLowRiskStyle = lipgloss.NewStyle().
-				Foreground(ColorSuccess).
-				Bold(true)
+		Foreground(ColorSuccess).
+		Bold(true)
 
 	TierModerateStyle = lipgloss.NewStyle().
-				Foreground(ColorWarning).
-				Bold(true)
+		Foreground(ColorWarning).
+		Bold(true)
 
 	TierHighRiskStyle = lipgloss.NewStyle().
-				Foreground(ColorError).
-				Bold(true)
+		Foreground(ColorError).
+		Bold(true)
 
 	TierBulkDestructiveStyle = lipgloss.NewStyle().
-					Foreground(ColorError).
-					Bold(true).
-					Background(lipgloss.Color("52")) // Dark red background
-)
+		Foreground(ColorError).
+		Bold(true).
+		Background(ColorDanger)
+
+	named := namedStyles()
+	for name, spec := range t.Styles {
+		if s, ok := named[name]; ok {
+			*s = spec.apply(*s)
+		}
+	}
+}
 
 // TierStyle returns the appropriate style for a safety tier
 func TierStyle(tier int) lipgloss.Style {
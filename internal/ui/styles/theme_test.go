@@ -0,0 +1,83 @@
+package styles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveTheme_Builtins(t *testing.T) {
+	for name, want := range map[string]Palette{"dark": Dark, "light": Light, "high-contrast": HighContrast} {
+		theme, err := ResolveTheme(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, theme.Palette, name)
+		assert.NoError(t, theme.Validate(), name)
+	}
+
+	theme, err := ResolveTheme("auto")
+	require.NoError(t, err)
+	assert.Contains(t, []Palette{Dark, Light}, theme.Palette)
+
+	_, err = ResolveTheme("solarized")
+	assert.ErrorContains(t, err, `unknown theme "solarized"`)
+}
+
+func TestParseTheme(t *testing.T) {
+	theme, err := ParseTheme([]byte(`base: light
+palette:
+  primary: "#268bd2"
+styles:
+  selected:
+    background: "#073642"
+    bold: false
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "#268bd2", theme.Palette.Primary)
+	assert.Equal(t, Light.Success, theme.Palette.Success)
+
+	defer Apply(Theme{Palette: Dark})
+	Apply(theme)
+	assert.Equal(t, lipgloss.Color("#268bd2"), Title.GetForeground())
+	assert.Equal(t, lipgloss.Color("#073642"), Selected.GetBackground())
+	assert.False(t, Selected.GetBold())
+	assert.Equal(t, lipgloss.Color(Light.Text), Selected.GetForeground())
+}
+
+func TestParseTheme_Errors(t *testing.T) {
+	for data, want := range map[string]string{
+		"base: sepia\n":                         `unknown base theme "sepia"`,
+		"palette:\n  primary: blue\n":           `invalid color "blue" for primary`,
+		"palette:\n  accent: \"1\"\n":           "field accent not found",
+		"styles:\n  banner: {bold: true}\n":     `unknown style "banner"`,
+		"styles:\n  title: {foreground: 300}\n": `invalid color "300" in style title`,
+	} {
+		_, err := ParseTheme([]byte(data))
+		assert.ErrorContains(t, err, want, data)
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mine.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("base: high-contrast\n"), 0o644))
+
+	theme, err := ResolveTheme(path)
+	require.NoError(t, err)
+	assert.Equal(t, HighContrast, theme.Palette)
+
+	_, err = ResolveTheme(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "reading theme")
+}
+
+func TestWithColors(t *testing.T) {
+	theme, err := Theme{Palette: Dark}.WithColors(map[string]string{"danger": "#5f0000"})
+	require.NoError(t, err)
+	assert.Equal(t, "#5f0000", theme.Palette.Danger)
+	assert.Equal(t, "52", Dark.Danger, "built-in palette unchanged")
+
+	_, err = Theme{Palette: Dark}.WithColors(map[string]string{"accent": "1"})
+	assert.ErrorContains(t, err, `unknown theme color "accent"`)
+}
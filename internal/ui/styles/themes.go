@@ -0,0 +1,330 @@
+package styles
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// Palette holds the colors of a theme. A color is an ANSI 256 number
+// ("69") or a hex value ("#5f87ff"); hex colors render in truecolor where
+// the terminal supports it and are approximated elsewhere.
+type Palette struct {
+	Primary     string `yaml:"primary"`      // titles and logo
+	Success     string `yaml:"success"`      // sections, running state
+	Warning     string `yaml:"warning"`      // warnings, stopped and unused resources
+	Error       string `yaml:"error"`        // errors and delete confirmations
+	Muted       string `yaml:"muted"`        // labels and help text
+	Text        string `yaml:"text"`         // values and selected text
+	Highlight   string `yaml:"highlight"`    // background of selected menu items
+	HighlightBg string `yaml:"highlight_bg"` // background of selected rows
+	Normal      string `yaml:"normal"`       // unselected menu items
+	Danger      string `yaml:"danger"`       // background of bulk destructive warnings
+}
+
+// Built-in palettes.
+var (
+	// Dark is octo's original palette, for dark terminals.
+	Dark = Palette{
+		Primary:     "69",
+		Success:     "42",
+		Warning:     "214",
+		Error:       "196",
+		Muted:       "241",
+		Text:        "255",
+		Highlight:   "62",
+		HighlightBg: "237",
+		Normal:      "252",
+		Danger:      "52",
+	}
+
+	// Light keeps the hues of Dark at contrasts readable on light
+	// backgrounds.
+	Light = Palette{
+		Primary:     "26",
+		Success:     "28",
+		Warning:     "130",
+		Error:       "160",
+		Muted:       "243",
+		Text:        "232",
+		Highlight:   "153",
+		HighlightBg: "254",
+		Normal:      "236",
+		Danger:      "224",
+	}
+
+	// HighContrast sticks to the 16 basic colors, which every terminal
+	// draws at full intensity.
+	HighContrast = Palette{
+		Primary:     "14",
+		Success:     "10",
+		Warning:     "11",
+		Error:       "9",
+		Muted:       "7",
+		Text:        "15",
+		Highlight:   "4",
+		HighlightBg: "4",
+		Normal:      "15",
+		Danger:      "0",
+	}
+)
+
+// builtinThemes are the themes selectable by name.
+var builtinThemes = map[string]Palette{
+	"dark":          Dark,
+	"light":         Light,
+	"high-contrast": HighContrast,
+}
+
+// ThemeNames lists the themes selectable by name, "auto" included.
+func ThemeNames() []string {
+	names := []string{"auto"}
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// StyleSpec overrides the attributes of a named style; unset fields keep
+// the theme's.
+type StyleSpec struct {
+	Foreground string `yaml:"foreground"`
+	Background string `yaml:"background"`
+	Bold       *bool  `yaml:"bold"`
+	Italic     *bool  `yaml:"italic"`
+	Underline  *bool  `yaml:"underline"`
+	Faint      *bool  `yaml:"faint"`
+}
+
+func (s StyleSpec) apply(st lipgloss.Style) lipgloss.Style {
+	if s.Foreground != "" {
+		st = st.Foreground(lipgloss.Color(s.Foreground))
+	}
+	if s.Background != "" {
+		st = st.Background(lipgloss.Color(s.Background))
+	}
+	if s.Bold != nil {
+		st = st.Bold(*s.Bold)
+	}
+	if s.Italic != nil {
+		st = st.Italic(*s.Italic)
+	}
+	if s.Underline != nil {
+		st = st.Underline(*s.Underline)
+	}
+	if s.Faint != nil {
+		st = st.Faint(*s.Faint)
+	}
+	return st
+}
+
+// Theme is a palette plus overrides of single styles, keyed by the names
+// StyleNames lists.
+type Theme struct {
+	Palette Palette
+	Styles  map[string]StyleSpec
+}
+
+// themeFile is the YAML form of a theme: a built-in theme to start from
+// and what to change.
+type themeFile struct {
+	Base    string               `yaml:"base"` // auto (default), dark, light or high-contrast
+	Palette Palette              `yaml:"palette"`
+	Styles  map[string]StyleSpec `yaml:"styles"`
+}
+
+// namedStyles maps the names used in theme files to the styles.
+func namedStyles() map[string]*lipgloss.Style {
+	return map[string]*lipgloss.Style{
+		"title":                 &Title,
+		"title_with_margin":     &TitleWithMargin,
+		"logo":                  &Logo,
+		"tagline":               &Tagline,
+		"section":               &Section,
+		"category":              &Category,
+		"success":               &Success,
+		"running":               &Running,
+		"warning":               &Warning,
+		"warn":                  &Warn,
+		"error":                 &Error,
+		"stopped":               &Stopped,
+		"info":                  &Info,
+		"label":                 &Label,
+		"label_with_width":      &LabelWithWidth,
+		"stat_label":            &StatLabel,
+		"value":                 &Value,
+		"stat_value":            &StatValue,
+		"size":                  &Size,
+		"selected":              &Selected,
+		"normal":                &Normal,
+		"selected_row":          &SelectedAnalyze,
+		"row":                   &NormalAnalyze,
+		"unused":                &Unused,
+		"help":                  &Help,
+		"subtitle":              &Subtitle,
+		"delete_confirm":        &DeleteConfirm,
+		"tier_informational":    &TierInformationalStyle,
+		"tier_low_risk":         &TierLowRiskStyle,
+		"tier_moderate":         &TierModerateStyle,
+		"tier_high_risk":        &TierHighRiskStyle,
+		"tier_bulk_destructive": &TierBulkDestructiveStyle,
+	}
+}
+
+// StyleNames lists the styles a theme can override, sorted.
+func StyleNames() []string {
+	var names []string
+	for name := range namedStyles() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveTheme returns the theme selected by name: "auto" or "" picks
+// dark or light from the terminal's background, a built-in name picks that
+// theme, and anything else is read as the path of a theme file.
+func ResolveTheme(name string) (Theme, error) {
+	if p, ok := builtin(name); ok {
+		return Theme{Palette: p}, nil
+	}
+	if !strings.ContainsAny(name, `/\`) && !strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml") {
+		return Theme{}, fmt.Errorf("unknown theme %q (choose: %s, or the path of a theme file)", name, strings.Join(ThemeNames(), ", "))
+	}
+	return LoadTheme(name)
+}
+
+// builtin returns the palette of a built-in theme or "auto".
+func builtin(name string) (Palette, bool) {
+	if name == "" || name == "auto" {
+		if lipgloss.HasDarkBackground() {
+			return Dark, true
+		}
+		return Light, true
+	}
+	p, ok := builtinThemes[name]
+	return p, ok
+}
+
+// LoadTheme reads a theme file.
+func LoadTheme(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, fmt.Errorf("reading theme: %w", err)
+	}
+	t, err := ParseTheme(data)
+	if err != nil {
+		return Theme{}, fmt.Errorf("theme %s: %w", path, err)
+	}
+	return t, nil
+}
+
+// ParseTheme parses a theme file: its palette colors replace those of its
+// base theme and its styles are laid over the result.
+func ParseTheme(data []byte) (Theme, error) {
+	var f themeFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return Theme{}, err
+	}
+	base, ok := builtin(f.Base)
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown base theme %q (choose: %s)", f.Base, strings.Join(ThemeNames(), ", "))
+	}
+	t := Theme{Palette: base.merge(f.Palette), Styles: f.Styles}
+	if err := t.Validate(); err != nil {
+		return Theme{}, err
+	}
+	return t, nil
+}
+
+// merge returns p with the colors set in o replacing its own.
+func (p Palette) merge(o Palette) Palette {
+	dst, src := p.colors(), o.colors()
+	for i := range dst {
+		if *src[i].value != "" {
+			*dst[i].value = *src[i].value
+		}
+	}
+	return p
+}
+
+type namedColor struct {
+	name  string
+	value *string
+}
+
+func (p *Palette) colors() []namedColor {
+	return []namedColor{
+		{"primary", &p.Primary},
+		{"success", &p.Success},
+		{"warning", &p.Warning},
+		{"error", &p.Error},
+		{"muted", &p.Muted},
+		{"text", &p.Text},
+		{"highlight", &p.Highlight},
+		{"highlight_bg", &p.HighlightBg},
+		{"normal", &p.Normal},
+		{"danger", &p.Danger},
+	}
+}
+
+// WithColors returns t with palette colors replaced by name, as the config
+// file's theme.colors sets them.
+func (t Theme) WithColors(colors map[string]string) (Theme, error) {
+	byName := make(map[string]*string)
+	for _, c := range t.Palette.colors() {
+		byName[c.name] = c.value
+	}
+	for name, value := range colors {
+		dst, ok := byName[name]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown theme color %q", name)
+		}
+		*dst = value
+	}
+	return t, t.Validate()
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor reports whether c is an ANSI 256 number or a hex color.
+func validColor(c string) bool {
+	if hexColor.MatchString(c) {
+		return true
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// Validate checks the colors and style names of t.
+func (t Theme) Validate() error {
+	for _, c := range t.Palette.colors() {
+		if !validColor(*c.value) {
+			return fmt.Errorf("invalid color %q for %s (use 0-255 or #rrggbb)", *c.value, c.name)
+		}
+	}
+	named := namedStyles()
+	for name, spec := range t.Styles {
+		if _, ok := named[name]; !ok {
+			return fmt.Errorf("unknown style %q (known: %s)", name, strings.Join(StyleNames(), ", "))
+		}
+		for _, c := range []string{spec.Foreground, spec.Background} {
+			if c != "" && !validColor(c) {
+				return fmt.Errorf("invalid color %q in style %s (use 0-255 or #rrggbb)", c, name)
+			}
+		}
+	}
+	return nil
+}
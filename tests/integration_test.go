@@ -21,6 +21,27 @@ func TestOctoVersion(t *testing.T) {
 	}
 }
 
+func TestOctoBadThemeKeepsWorking(t *testing.T) {
+	cmd := exec.Command("../bin/octo", "version", "--theme", "no-such-theme")
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("octo version with a bad theme failed: %v", err)
+	}
+	if !strings.Contains(string(output), "Octo version") {
+		t.Errorf("Expected 'Octo version' in output, got: %s", output)
+	}
+
+	// Styled commands warn and fall back to the default theme.
+	cmd = exec.Command("../bin/octo", "rightsize", "--window", "0", "--theme", "no-such-theme")
+	output, _ = cmd.CombinedOutput()
+	if !strings.Contains(string(output), "using the default theme") {
+		t.Errorf("Expected a theme warning, got: %s", output)
+	}
+	if !strings.Contains(string(output), "--window and --interval must be positive") {
+		t.Errorf("Expected the command to run past the theme, got: %s", output)
+	}
+}
+
 func TestOctoHelp(t *testing.T) {
 	cmd := exec.Command("../bin/octo", "--help")
 	output, err := cmd.Output()